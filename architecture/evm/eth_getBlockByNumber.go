@@ -96,7 +96,7 @@ func enforceHighestBlock(ctx context.Context, network common.Network, nq *common
 	if !ok {
 		return nr, re
 	}
-	if bnp != "latest" && bnp != "finalized" {
+	if bnp != "latest" && bnp != "finalized" && bnp != "safe" {
 		return nr, re
 	}
 
@@ -158,8 +158,13 @@ func enforceHighestBlock(ctx context.Context, network common.Network, nq *common
 		} else {
 			return nr, re
 		}
-	case "finalized":
-		highestBlockNumber := network.EvmHighestFinalizedBlockNumber(ctx)
+	case "finalized", "safe":
+		var highestBlockNumber int64
+		if bnp == "safe" {
+			highestBlockNumber = network.EvmHighestSafeBlockNumber(ctx)
+		} else {
+			highestBlockNumber = network.EvmHighestFinalizedBlockNumber(ctx)
+		}
 		_, respBlockNumber, err := ExtractBlockReferenceFromResponse(ctx, nr)
 		if err != nil {
 			return nil, err
@@ -170,8 +175,8 @@ func enforceHighestBlock(ctx context.Context, network common.Network, nq *common
 				Interface("highestBlockNumber", highestBlockNumber).
 				Interface("respBlockNumber", respBlockNumber).
 				Interface("err", err).
-				Msg("enforcing highest finalized block")
-			if respBlockNumber > 0 {
+				Msg("enforcing highest " + bnp + " block")
+			if respBlockNumber > 0 && bnp == "finalized" {
 				// When extracted block number is 0, it mostly means response is actually a json-rpc error
 				// therefore we better fetch the highest block number again.
 				ups := nr.Upstream()
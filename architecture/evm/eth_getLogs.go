@@ -1,10 +1,12 @@
 package evm
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -226,7 +228,7 @@ func upstreamPreForward_eth_getLogs(ctx context.Context, n common.Network, u com
 				Msg("eth_getLogs block range exceeded, splitting")
 
 			nrq.SetCompositeType(common.CompositeTypeLogsSplitProactive)
-			mergedResponse, err := executeGetLogsSubRequests(ctx, n, u, nrq, subRequests, nrq.Directives().SkipCacheRead)
+			mergedResponse, err := executeGetLogsSubRequests(ctx, n, u, nrq, subRequests, nrq.Directives().SkipCacheRead, cfg.JsonRpc.ShouldStream("eth_getLogs"))
 			if err != nil {
 				return true, nil, err
 			}
@@ -265,7 +267,7 @@ func upstreamPostForward_eth_getLogs(ctx context.Context, n common.Network, u co
 					return rs, re
 				}
 				rq.SetCompositeType(common.CompositeTypeLogsSplitOnError)
-				mergedResponse, err := executeGetLogsSubRequests(ctx, n, u, rq, subRequests, skipCacheRead)
+				mergedResponse, err := executeGetLogsSubRequests(ctx, n, u, rq, subRequests, skipCacheRead, cfg.JsonRpc.ShouldStream("eth_getLogs"))
 				if err != nil {
 					logger.Warn().Err(err).Object("request", rq).Msg("could not execute eth_getLogs sub-requests, returning original response")
 					return rs, re
@@ -297,17 +299,98 @@ func upstreamPostForward_eth_getLogs(ctx context.Context, n common.Network, u co
 	return rs, re
 }
 
+// getLogsSubResult is the slot of a sub-request, done is closed once its response or error is set.
+type getLogsSubResult struct {
+	done     chan struct{}
+	response *common.JsonRpcResponse
+	err      error
+}
+
+func (s *getLogsSubResult) isDone() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
 type GetLogsMultiResponseWriter struct {
-	responses []*common.JsonRpcResponse
+	// slots are kept in block order, each one is written as soon as it and all the ones before it are done
+	slots  []*getLogsSubResult
+	cancel context.CancelFunc
+
+	// mu is held while writing, stateMu only guards the materialized result
+	mu           sync.Mutex
+	stateMu      sync.RWMutex
+	materialized []byte
+	tees         common.ResultTees
 }
 
+var _ common.StreamingResultWriter = &GetLogsMultiResponseWriter{}
+
 func NewGetLogsMultiResponseWriter(responses []*common.JsonRpcResponse) *GetLogsMultiResponseWriter {
-	return &GetLogsMultiResponseWriter{
-		responses: responses,
+	g := newPendingGetLogsMultiResponseWriter(len(responses), nil)
+	for i, response := range responses {
+		g.resolve(i, response, nil)
 	}
+	return g
+}
+
+// newPendingGetLogsMultiResponseWriter creates a writer for sub-requests that are still in flight,
+// cancel is called to abort them when the result is released without being written.
+func newPendingGetLogsMultiResponseWriter(size int, cancel context.CancelFunc) *GetLogsMultiResponseWriter {
+	g := &GetLogsMultiResponseWriter{
+		slots:  make([]*getLogsSubResult, size),
+		cancel: cancel,
+	}
+	for i := range g.slots {
+		g.slots[i] = &getLogsSubResult{done: make(chan struct{})}
+	}
+	return g
+}
+
+func (g *GetLogsMultiResponseWriter) resolve(i int, response *common.JsonRpcResponse, err error) {
+	g.slots[i].response = response
+	g.slots[i].err = err
+	close(g.slots[i].done)
 }
 
 func (g *GetLogsMultiResponseWriter) WriteTo(w io.Writer, trimSides bool) (n int64, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.materialized != nil {
+		data := g.materialized
+		if trimSides {
+			data = data[1 : len(data)-1]
+		}
+		nn, err := w.Write(data)
+		return int64(nn), err
+	}
+
+	// Tees receive the whole array even when the sides are trimmed for the client
+	g.tees.Begin()
+	tw := &teeWriter{w: w, tees: &g.tees}
+	if trimSides {
+		g.tees.Write([]byte{'['})
+	}
+	flusher, _ := w.(http.Flusher)
+	n, err = g.writeTo(tw, trimSides, flusher)
+	if err != nil {
+		g.tees.Abort()
+		return n, err
+	}
+	if trimSides {
+		g.tees.Write([]byte{']'})
+	}
+	g.tees.Finish()
+	return n, nil
+}
+
+// writeTo waits for each sub-request in block order and writes its logs as soon as it is done,
+// flushing after each one (when possible) so that the client receives them without waiting for the slowest range.
+func (g *GetLogsMultiResponseWriter) writeTo(w io.Writer, trimSides bool, flusher http.Flusher) (n int64, err error) {
 	// Write opening bracket
 	if !trimSides {
 		nn, err := w.Write([]byte{'['})
@@ -318,7 +401,12 @@ func (g *GetLogsMultiResponseWriter) WriteTo(w io.Writer, trimSides bool) (n int
 	}
 
 	first := true
-	for _, response := range g.responses {
+	for _, slot := range g.slots {
+		<-slot.done
+		if slot.err != nil {
+			return n, slot.err
+		}
+		response := slot.response
 		if response == nil || response.IsResultEmptyish() {
 			continue // Skip empty results
 		}
@@ -339,6 +427,9 @@ func (g *GetLogsMultiResponseWriter) WriteTo(w io.Writer, trimSides bool) (n int
 			return n + nw, err
 		}
 		n += nw
+		if flusher != nil {
+			flusher.Flush()
+		}
 	}
 
 	if !trimSides {
@@ -350,16 +441,53 @@ func (g *GetLogsMultiResponseWriter) WriteTo(w io.Writer, trimSides bool) (n int
 	return n, nil
 }
 
-func (g *GetLogsMultiResponseWriter) IsResultEmptyish() bool {
-	if len(g.responses) == 0 {
-		return true
+// IsStreaming returns true when any of the sub-requests is still in flight or its response is still being read from its upstream.
+func (g *GetLogsMultiResponseWriter) IsStreaming() bool {
+	g.stateMu.RLock()
+	defer g.stateMu.RUnlock()
+	if g.materialized != nil {
+		return false
+	}
+	for _, slot := range g.slots {
+		if !slot.isDone() || slot.response.IsStreaming() {
+			return true
+		}
 	}
+	return false
+}
 
-	for _, response := range g.responses {
-		if response == nil {
-			continue
+func (g *GetLogsMultiResponseWriter) Materialize() ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.materialized != nil {
+		return g.materialized, nil
+	}
+	buf := bytes.NewBuffer(nil)
+	if _, err := g.writeTo(buf, false, nil); err != nil {
+		g.tees.Abort()
+		return nil, err
+	}
+	g.stateMu.Lock()
+	g.materialized = buf.Bytes()
+	g.stateMu.Unlock()
+	g.tees.Deliver(g.materialized)
+	return g.materialized, nil
+}
+
+func (g *GetLogsMultiResponseWriter) OnComplete(maxSize int, fn func(result []byte)) bool {
+	return g.tees.Register(maxSize, fn)
+}
+
+// IsResultEmptyish waits for sub-requests in block order until one of them has logs. A failed
+// sub-request counts as empty, its error is returned when the result is written.
+// IsResultEmptyish does not wait for pending sub-requests: the result is only known to be empty once every
+// sub-request succeeded with no logs, failed or still pending ones are not considered empty.
+func (g *GetLogsMultiResponseWriter) IsResultEmptyish() bool {
+	for _, slot := range g.slots {
+		if !slot.isDone() || slot.err != nil {
+			return false
 		}
-		if !response.IsResultEmptyish() {
+		if slot.response != nil && !slot.response.IsResultEmptyish() {
 			return false
 		}
 	}
@@ -367,10 +495,14 @@ func (g *GetLogsMultiResponseWriter) IsResultEmptyish() bool {
 	return true
 }
 
+// Size returns the total size of the sub-responses received so far, it is only an estimate until all of them are done.
 func (g *GetLogsMultiResponseWriter) Size(ctx ...context.Context) (int, error) {
 	size := 0
-	for _, response := range g.responses {
-		s, err := response.Size(ctx...)
+	for _, slot := range g.slots {
+		if !slot.isDone() || slot.response == nil {
+			continue
+		}
+		s, err := slot.response.Size(ctx...)
 		if err != nil {
 			return 0, err
 		}
@@ -379,6 +511,35 @@ func (g *GetLogsMultiResponseWriter) Size(ctx ...context.Context) (int, error) {
 	return size, nil
 }
 
+// Close aborts the sub-requests still in flight and releases streamed sub-responses when the result is not going to be written.
+func (g *GetLogsMultiResponseWriter) Close() error {
+	if g.cancel != nil {
+		g.cancel()
+	}
+	if !g.mu.TryLock() {
+		// Being written, the writer releases each sub-response as it consumes it
+		return nil
+	}
+	defer g.mu.Unlock()
+	for _, slot := range g.slots {
+		if slot.isDone() {
+			slot.response.CloseStream()
+		}
+	}
+	return nil
+}
+
+// teeWriter copies everything written to the client into result tees.
+type teeWriter struct {
+	w    io.Writer
+	tees *common.ResultTees
+}
+
+func (t *teeWriter) Write(p []byte) (int, error) {
+	t.tees.Write(p)
+	return t.w.Write(p)
+}
+
 type ethGetLogsSubRequest struct {
 	fromBlock int64
 	toBlock   int64
@@ -502,137 +663,130 @@ func extractBlockRange(filter map[string]interface{}) (fromBlock, toBlock int64,
 	return fromBlock, toBlock, nil
 }
 
-func executeGetLogsSubRequests(ctx context.Context, n common.Network, u common.Upstream, r *common.NormalizedRequest, subRequests []ethGetLogsSubRequest, skipCacheRead bool) (*common.JsonRpcResponse, error) {
+// executeGetLogsSubRequests sends sub-requests concurrently and merges their logs in block order. When stream is
+// true it returns right away a response whose result writes each sub-range as soon as it (and the ones before it)
+// completes; errors of sub-requests are then returned when the result is written, which cuts the response short
+// once streaming started. Otherwise it waits for all sub-requests so that any error fails the whole request.
+func executeGetLogsSubRequests(ctx context.Context, n common.Network, u common.Upstream, r *common.NormalizedRequest, subRequests []ethGetLogsSubRequest, skipCacheRead bool, stream bool) (*common.JsonRpcResponse, error) {
 	logger := u.Logger().With().Str("method", "eth_getLogs").Interface("id", r.ID()).Logger()
 
-	wg := sync.WaitGroup{}
-	responses := make([]*common.JsonRpcResponse, 0)
-	errs := make([]error, 0)
-	mu := sync.Mutex{}
+	// Sub-requests outlive the upstream attempt which returns the (still pending) merged response: they follow
+	// the attempt (client disconnects, hedges, timeouts) until that response is handed over, then only the
+	// client request while the body is streamed, and they are aborted once the response is released.
+	subCtx, cancelSubCtx := context.WithCancel(context.WithoutCancel(ctx))
+	stopFollowingAttempt := context.AfterFunc(ctx, cancelSubCtx)
+	stopFollowingRequest := func() bool { return false }
+	if lifetime, ok := common.RequestLifetime(ctx); ok && stream {
+		stopFollowingRequest = context.AfterFunc(lifetime, cancelSubCtx)
+	}
+	cancel := func() {
+		stopFollowingAttempt()
+		stopFollowingRequest()
+		cancelSubCtx()
+	}
+	writer := newPendingGetLogsMultiResponseWriter(len(subRequests), cancel)
+	recordFailure := func(i int, err error) {
+		telemetry.MetricUpstreamEvmGetLogsSplitFailure.WithLabelValues(
+			n.ProjectId(),
+			u.VendorName(),
+			u.NetworkId(),
+			u.Id(),
+		).Inc()
+		writer.resolve(i, nil, err)
+	}
 
 	// TODO should we make this semaphore configurable?
 	semaphore := make(chan struct{}, 200)
-	for _, sr := range subRequests {
-		wg.Add(1)
-		// Acquire semaphore token (blocks if at capacity)
-		semaphore <- struct{}{}
-		go func(req ethGetLogsSubRequest) {
-			defer wg.Done()
-			defer func() {
-				// Release semaphore token when done
-				<-semaphore
-			}()
-
-			srq, err := BuildGetLogsRequest(req.fromBlock, req.toBlock, req.address, req.topics)
-			logger.Debug().
-				Object("request", srq).
-				Msg("executing eth_getLogs sub-request")
+	go func() {
+		wg := sync.WaitGroup{}
+		for i, sr := range subRequests {
+			wg.Add(1)
+			// Acquire semaphore token (blocks if at capacity)
+			semaphore <- struct{}{}
+			go func(i int, req ethGetLogsSubRequest) {
+				defer wg.Done()
+				defer func() {
+					// Release semaphore token when done
+					<-semaphore
+				}()
+
+				srq, err := BuildGetLogsRequest(req.fromBlock, req.toBlock, req.address, req.topics)
+				logger.Debug().
+					Object("request", srq).
+					Msg("executing eth_getLogs sub-request")
 
-			if err != nil {
-				mu.Lock()
-				telemetry.MetricUpstreamEvmGetLogsSplitFailure.WithLabelValues(
-					n.ProjectId(),
-					u.VendorName(),
-					u.NetworkId(),
-					u.Id(),
-				).Inc()
-				errs = append(errs, err)
-				mu.Unlock()
-				return
-			}
+				if err != nil {
+					recordFailure(i, err)
+					return
+				}
 
-			sbnrq := common.NewNormalizedRequestFromJsonRpcRequest(srq)
-			dr := r.Directives().Clone()
-			dr.SkipCacheRead = skipCacheRead
-			// TODO dr.UseUpstream = u.Config().Id should we force this (or opposite of it)?
-			sbnrq.SetDirectives(dr)
-			sbnrq.SetNetwork(n)
-			sbnrq.SetParentRequestId(r.ID())
-
-			rs, re := n.Forward(ctx, sbnrq)
-			if re != nil {
-				mu.Lock()
-				telemetry.MetricUpstreamEvmGetLogsSplitFailure.WithLabelValues(
-					n.ProjectId(),
-					u.VendorName(),
-					u.NetworkId(),
-					u.Id(),
-				).Inc()
-				errs = append(errs, re)
-				mu.Unlock()
-				return
-			}
+				sbnrq := common.NewNormalizedRequestFromJsonRpcRequest(srq)
+				dr := r.Directives().Clone()
+				dr.SkipCacheRead = skipCacheRead
+				// TODO dr.UseUpstream = u.Config().Id should we force this (or opposite of it)?
+				sbnrq.SetDirectives(dr)
+				sbnrq.SetNetwork(n)
+				sbnrq.SetParentRequestId(r.ID())
+
+				rs, re := n.Forward(subCtx, sbnrq)
+				if re != nil {
+					recordFailure(i, re)
+					return
+				}
 
-			jrr, err := rs.JsonRpcResponse(ctx)
-			if err != nil {
-				mu.Lock()
-				telemetry.MetricUpstreamEvmGetLogsSplitFailure.WithLabelValues(
-					n.ProjectId(),
-					u.VendorName(),
-					u.NetworkId(),
-					u.Id(),
-				).Inc()
-				errs = append(errs, err)
-				mu.Unlock()
-				return
-			}
+				jrr, err := rs.JsonRpcResponse(subCtx)
+				if err != nil {
+					recordFailure(i, err)
+					return
+				}
 
-			if jrr == nil {
-				mu.Lock()
-				telemetry.MetricUpstreamEvmGetLogsSplitFailure.WithLabelValues(
-					n.ProjectId(),
-					u.VendorName(),
-					u.NetworkId(),
-					u.Id(),
-				).Inc()
-				errs = append(errs, fmt.Errorf("unexpected empty json-rpc response %v", rs))
-				mu.Unlock()
-				return
-			}
+				if jrr == nil {
+					recordFailure(i, fmt.Errorf("unexpected empty json-rpc response %v", rs))
+					return
+				}
+
+				if jrr.Error != nil {
+					recordFailure(i, jrr.Error)
+					return
+				}
 
-			if jrr.Error != nil {
-				mu.Lock()
-				telemetry.MetricUpstreamEvmGetLogsSplitFailure.WithLabelValues(
+				telemetry.MetricUpstreamEvmGetLogsSplitSuccess.WithLabelValues(
 					n.ProjectId(),
 					u.VendorName(),
 					u.NetworkId(),
 					u.Id(),
 				).Inc()
-				errs = append(errs, jrr.Error)
-				mu.Unlock()
-				return
+				writer.resolve(i, jrr, nil)
+			}(i, sr)
+		}
+		wg.Wait()
+	}()
+
+	if !stream {
+		var errs []error
+		for _, slot := range writer.slots {
+			<-slot.done
+			if slot.err != nil {
+				errs = append(errs, slot.err)
 			}
-
-			mu.Lock()
-			telemetry.MetricUpstreamEvmGetLogsSplitSuccess.WithLabelValues(
-				n.ProjectId(),
-				u.VendorName(),
-				u.NetworkId(),
-				u.Id(),
-			).Inc()
-			responses = append(responses, jrr)
-			mu.Unlock()
-		}(sr)
-	}
-	wg.Wait()
-
-	if len(errs) > 0 {
-		return nil, errors.Join(errs...)
+		}
+		if len(errs) > 0 {
+			cancel()
+			return nil, errors.Join(errs...)
+		}
 	}
 
-	mergedResponse := mergeEthGetLogsResults(responses)
+	mergedResponse := &common.JsonRpcResponse{}
+	mergedResponse.SetResultWriter(writer)
 	jrq, _ := r.JsonRpcRequest()
 	err := mergedResponse.SetID(jrq.ID)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	return mergedResponse, nil
-}
+	// Handed over to the caller, the attempt context is cancelled as soon as we return
+	stopFollowingAttempt()
 
-func mergeEthGetLogsResults(responses []*common.JsonRpcResponse) *common.JsonRpcResponse {
-	writer := NewGetLogsMultiResponseWriter(responses)
-	jrr := &common.JsonRpcResponse{}
-	jrr.SetResultWriter(writer)
-	return jrr
+	return mergedResponse, nil
 }
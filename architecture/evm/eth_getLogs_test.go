@@ -6,7 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/util"
@@ -272,7 +275,7 @@ func TestExecuteGetLogsSubRequests(t *testing.T) {
 			ctx := context.Background()
 			req := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x2","address":"0x123","topics":["0xabc"]}],"id":1}`))
 
-			result, err := executeGetLogsSubRequests(ctx, mockNetwork, mockUpstream, req, tt.subRequests, false)
+			result, err := executeGetLogsSubRequests(ctx, mockNetwork, mockUpstream, req, tt.subRequests, false, false)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -836,7 +839,7 @@ func TestExecuteGetLogsSubRequests_WithNestedSplits(t *testing.T) {
 				subJrr, err = executeGetLogsSubRequests(ctx, mockNetwork, mockUpstream, req, []ethGetLogsSubRequest{
 					{fromBlock: 0x1, toBlock: 0x2, address: []interface{}{"0x123", "0x456"}, topics: []interface{}{"0xabc", "0xdef"}},
 					{fromBlock: 0x3, toBlock: 0x4, address: []interface{}{"0x123", "0x456"}, topics: []interface{}{"0xabc", "0xdef"}},
-				}, false)
+				}, false, false)
 				if err != nil {
 					return nil, err
 				}
@@ -844,7 +847,7 @@ func TestExecuteGetLogsSubRequests_WithNestedSplits(t *testing.T) {
 				subJrr, err = executeGetLogsSubRequests(ctx, mockNetwork, mockUpstream, req, []ethGetLogsSubRequest{
 					{fromBlock: 0x5, toBlock: 0x6, address: []interface{}{"0x123", "0x456"}, topics: []interface{}{"0xabc", "0xdef"}},
 					{fromBlock: 0x7, toBlock: 0x8, address: []interface{}{"0x123", "0x456"}, topics: []interface{}{"0xabc", "0xdef"}},
-				}, false)
+				}, false, false)
 				if err != nil {
 					return nil, err
 				}
@@ -869,7 +872,7 @@ func TestExecuteGetLogsSubRequests_WithNestedSplits(t *testing.T) {
 	jrr, err := executeGetLogsSubRequests(context.Background(), mockNetwork, mockUpstream, req, []ethGetLogsSubRequest{
 		{fromBlock: 0x1, toBlock: 0x4, address: []interface{}{"0x123", "0x456"}, topics: []interface{}{"0xabc", "0xdef"}},
 		{fromBlock: 0x5, toBlock: 0x8, address: []interface{}{"0x123", "0x456"}, topics: []interface{}{"0xabc", "0xdef"}},
-	}, false)
+	}, false, false)
 
 	// Verify results
 	assert.NoError(t, err)
@@ -897,3 +900,237 @@ func createTestRequest(filter interface{}) *common.NormalizedRequest {
 	jrq := common.NewJsonRpcRequest("eth_getLogs", params)
 	return common.NewNormalizedRequestFromJsonRpcRequest(jrq)
 }
+
+func TestGetLogsMultiResponseWriter_WithStreamedSubResponses(t *testing.T) {
+	streamed := func(result string) *common.JsonRpcResponse {
+		body := `{"jsonrpc":"2.0","id":1,"result":` + result + `}`
+		jrr, _, err := common.NewStreamedJsonRpcResponse(io.NopCloser(strings.NewReader(body)), 40, len(body))
+		assert.NoError(t, err)
+		assert.NotNil(t, jrr, "expected a streamed response")
+		return jrr
+	}
+
+	t.Run("WritesInOrderAndTeesFullResult", func(t *testing.T) {
+		writer := NewGetLogsMultiResponseWriter([]*common.JsonRpcResponse{
+			streamed(`[{"logIndex":"0x1","data":"0xaaaaaaaa"}]`),
+			{Result: []byte(`[]`)},
+			streamed(`[{"logIndex":"0x2","data":"0xbbbbbbbb"},{"logIndex":"0x3","data":"0xcccccccc"}]`),
+		})
+		assert.True(t, writer.IsStreaming())
+
+		teed := make(chan []byte, 1)
+		assert.True(t, writer.OnComplete(0, func(result []byte) { teed <- result }))
+
+		var buf bytes.Buffer
+		_, err := writer.WriteTo(&buf, true)
+		assert.NoError(t, err)
+		expected := `{"logIndex":"0x1","data":"0xaaaaaaaa"},{"logIndex":"0x2","data":"0xbbbbbbbb"},{"logIndex":"0x3","data":"0xcccccccc"}`
+		assert.Equal(t, expected, buf.String())
+
+		select {
+		case result := <-teed:
+			assert.Equal(t, "["+expected+"]", string(result))
+		case <-time.After(time.Second):
+			t.Fatal("expected tee to receive the merged result")
+		}
+	})
+
+	t.Run("Materialize", func(t *testing.T) {
+		writer := NewGetLogsMultiResponseWriter([]*common.JsonRpcResponse{
+			streamed(`[{"logIndex":"0x1","data":"0xaaaaaaaa"}]`),
+			streamed(`[{"logIndex":"0x2","data":"0xbbbbbbbb"}]`),
+		})
+		result, err := writer.Materialize()
+		assert.NoError(t, err)
+		assert.Equal(t, `[{"logIndex":"0x1","data":"0xaaaaaaaa"},{"logIndex":"0x2","data":"0xbbbbbbbb"}]`, string(result))
+		assert.False(t, writer.IsStreaming())
+
+		var buf bytes.Buffer
+		_, err = writer.WriteTo(&buf, false)
+		assert.NoError(t, err)
+		assert.Equal(t, string(result), buf.String())
+	})
+}
+
+func TestExecuteGetLogsSubRequests_KeepsBlockOrder(t *testing.T) {
+	mockNetwork := new(mockNetwork)
+	mockUpstream := new(mockEvmUpstream)
+	mockNetwork.On("ProjectId").Return("test")
+	mockUpstream.On("Id").Return("rpc1")
+	mockUpstream.On("NetworkId").Return("evm:123")
+	mockUpstream.On("VendorName").Return("test")
+
+	fromBlockIs := func(fb string) interface{} {
+		return mock.MatchedBy(func(r *common.NormalizedRequest) bool {
+			jrq, err := r.JsonRpcRequest()
+			if err != nil {
+				return false
+			}
+			filter, ok := jrq.Params[0].(map[string]interface{})
+			return ok && filter["fromBlock"] == fb
+		})
+	}
+	// The first range completes last, yet its logs must come first
+	mockNetwork.On("Forward", mock.Anything, fromBlockIs("0x1")).
+		After(50*time.Millisecond).
+		Return(common.NewNormalizedResponse().WithJsonRpcResponse(&common.JsonRpcResponse{Result: []byte(`["log1"]`)}), nil)
+	mockNetwork.On("Forward", mock.Anything, fromBlockIs("0x3")).
+		Return(common.NewNormalizedResponse().WithJsonRpcResponse(&common.JsonRpcResponse{Result: []byte(`["log2"]`)}), nil)
+
+	req := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x4"}],"id":1}`))
+	result, err := executeGetLogsSubRequests(context.Background(), mockNetwork, mockUpstream, req, []ethGetLogsSubRequest{
+		{fromBlock: 1, toBlock: 2},
+		{fromBlock: 3, toBlock: 4},
+	}, false, false)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = result.WriteResultTo(&buf, false)
+	assert.NoError(t, err)
+	assert.Equal(t, `["log1","log2"]`, buf.String())
+}
+
+func TestExecuteGetLogsSubRequests_StreamsCompletedRangesFirst(t *testing.T) {
+	mockNetwork := new(mockNetwork)
+	mockUpstream := new(mockEvmUpstream)
+	mockNetwork.On("ProjectId").Return("test")
+	mockUpstream.On("Id").Return("rpc1")
+	mockUpstream.On("NetworkId").Return("evm:123")
+	mockUpstream.On("VendorName").Return("test")
+
+	fromBlockIs := func(fb string) interface{} {
+		return mock.MatchedBy(func(r *common.NormalizedRequest) bool {
+			jrq, err := r.JsonRpcRequest()
+			if err != nil {
+				return false
+			}
+			filter, ok := jrq.Params[0].(map[string]interface{})
+			return ok && filter["fromBlock"] == fb
+		})
+	}
+	release := make(chan time.Time)
+	mockNetwork.On("Forward", mock.Anything, fromBlockIs("0x1")).
+		Return(common.NewNormalizedResponse().WithJsonRpcResponse(&common.JsonRpcResponse{Result: []byte(`["log1"]`)}), nil)
+	mockNetwork.On("Forward", mock.Anything, fromBlockIs("0x3")).
+		WaitUntil(release).
+		Return(common.NewNormalizedResponse().WithJsonRpcResponse(&common.JsonRpcResponse{Result: []byte(`["log2"]`)}), nil)
+
+	req := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x4"}],"id":1}`))
+	result, err := executeGetLogsSubRequests(context.Background(), mockNetwork, mockUpstream, req, []ethGetLogsSubRequest{
+		{fromBlock: 1, toBlock: 2},
+		{fromBlock: 3, toBlock: 4},
+	}, false, true)
+	assert.NoError(t, err)
+	assert.True(t, result.IsStreaming())
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := result.WriteResultTo(pw, false)
+		pw.CloseWithError(err)
+	}()
+
+	// The first range is received while the second one is still in flight
+	head := make([]byte, len(`["log1"`))
+	_, err = io.ReadFull(pr, head)
+	assert.NoError(t, err)
+	assert.Equal(t, `["log1"`, string(head))
+
+	close(release)
+	rest, err := io.ReadAll(pr)
+	assert.NoError(t, err)
+	assert.Equal(t, `,"log2"]`, string(rest))
+}
+
+func TestExecuteGetLogsSubRequests_StreamedFailureSurfacesOnWrite(t *testing.T) {
+	mockNetwork := new(mockNetwork)
+	mockUpstream := new(mockEvmUpstream)
+	mockNetwork.On("ProjectId").Return("test")
+	mockUpstream.On("Id").Return("rpc1")
+	mockUpstream.On("NetworkId").Return("evm:123")
+	mockUpstream.On("VendorName").Return("test")
+	mockNetwork.On("Forward", mock.Anything, mock.Anything).
+		Return(common.NewNormalizedResponse().WithJsonRpcResponse(&common.JsonRpcResponse{Result: []byte(`["log1"]`)}), nil).Once()
+	mockNetwork.On("Forward", mock.Anything, mock.Anything).
+		Return(nil, errors.New("failed")).Once()
+
+	req := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x4"}],"id":1}`))
+	result, err := executeGetLogsSubRequests(context.Background(), mockNetwork, mockUpstream, req, []ethGetLogsSubRequest{
+		{fromBlock: 1, toBlock: 2},
+		{fromBlock: 3, toBlock: 4},
+	}, false, true)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = result.WriteResultTo(&buf, false)
+	assert.ErrorContains(t, err, "failed")
+}
+
+func TestExecuteGetLogsSubRequests_StreamedSubRequestsFollowClientRequest(t *testing.T) {
+	mockNetwork := new(mockNetwork)
+	mockUpstream := new(mockEvmUpstream)
+	mockNetwork.On("ProjectId").Return("test")
+	mockUpstream.On("Id").Return("rpc1")
+	mockUpstream.On("NetworkId").Return("evm:123")
+	mockUpstream.On("VendorName").Return("test")
+	subCtxs := make(chan context.Context, 1)
+	mockNetwork.On("Forward", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			subCtxs <- ctx
+			<-ctx.Done()
+		}).
+		Return(nil, errors.New("cancelled"))
+
+	lifetime, cancelLifetime := context.WithCancel(context.Background())
+	defer cancelLifetime()
+	attemptCtx, cancelAttempt := context.WithCancel(common.WithRequestLifetime(lifetime, lifetime))
+
+	req := common.NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x2"}],"id":1}`))
+	result, err := executeGetLogsSubRequests(attemptCtx, mockNetwork, mockUpstream, req, []ethGetLogsSubRequest{
+		{fromBlock: 1, toBlock: 2},
+	}, false, true)
+	assert.NoError(t, err)
+	defer result.CloseStream()
+
+	subCtx := <-subCtxs
+
+	// The attempt ends right after handing over the response, the body is still to be streamed
+	cancelAttempt()
+	select {
+	case <-subCtx.Done():
+		t.Fatal("sub-request must not be cancelled with the attempt once the response is handed over")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The client went away
+	cancelLifetime()
+	select {
+	case <-subCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("sub-request must be cancelled with the client request")
+	}
+}
+
+func TestGetLogsMultiResponseWriter_IsResultEmptyish(t *testing.T) {
+	emptyResponse := &common.JsonRpcResponse{Result: []byte(`[]`)}
+
+	t.Run("EmptyOnceAllSubRequestsSucceededWithoutLogs", func(t *testing.T) {
+		writer := newPendingGetLogsMultiResponseWriter(2, nil)
+		writer.resolve(0, emptyResponse, nil)
+		writer.resolve(1, emptyResponse, nil)
+		assert.True(t, writer.IsResultEmptyish())
+	})
+
+	t.Run("NotEmptyWhenASubRequestFailed", func(t *testing.T) {
+		writer := newPendingGetLogsMultiResponseWriter(2, nil)
+		writer.resolve(0, emptyResponse, nil)
+		writer.resolve(1, nil, errors.New("failed"))
+		assert.False(t, writer.IsResultEmptyish())
+	})
+
+	t.Run("DoesNotWaitForPendingSubRequests", func(t *testing.T) {
+		writer := newPendingGetLogsMultiResponseWriter(2, nil)
+		writer.resolve(0, emptyResponse, nil)
+		assert.False(t, writer.IsResultEmptyish())
+	})
+}
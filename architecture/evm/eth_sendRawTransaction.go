@@ -0,0 +1,65 @@
+package evm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"golang.org/x/crypto/sha3"
+)
+
+// TransactionHashFromRawTransaction computes the hash of a signed raw transaction (keccak256 of its
+// encoded bytes) which is the same for legacy and typed (EIP-2718) transactions.
+func TransactionHashFromRawTransaction(rawTx string) (string, error) {
+	raw, err := hexutil.Decode(rawTx)
+	if err != nil {
+		return "", fmt.Errorf("invalid raw transaction hex: %w", err)
+	}
+	if len(raw) == 0 {
+		return "", fmt.Errorf("raw transaction is empty")
+	}
+	h := sha3.NewLegacyKeccak256()
+	h.Write(raw)
+	return hexutil.Encode(h.Sum(nil)), nil
+}
+
+// IsTransactionAlreadyKnownError detects errors returned by different clients when the exact same
+// transaction is already in their mempool (or already imported).
+func IsTransactionAlreadyKnownError(err error) bool {
+	if err == nil {
+		return false
+	}
+	txt := strings.ToLower(err.Error())
+	return strings.Contains(txt, "already known") ||
+		strings.Contains(txt, "alreadyknown") ||
+		strings.Contains(txt, "known transaction") ||
+		strings.Contains(txt, "already imported") ||
+		strings.Contains(txt, "already exists") ||
+		strings.Contains(txt, "already in mempool") ||
+		strings.Contains(txt, "already in the txpool")
+}
+
+// IsNonceTooLowError detects errors returned when the nonce of the transaction has already been used,
+// which might be because this very transaction has already been mined.
+func IsNonceTooLowError(err error) bool {
+	if err == nil {
+		return false
+	}
+	txt := strings.ToLower(err.Error())
+	return strings.Contains(txt, "nonce too low") ||
+		strings.Contains(txt, "noncetoolow") ||
+		strings.Contains(txt, "nonce is too low") ||
+		strings.Contains(txt, "oldnonce")
+}
+
+func BuildGetTransactionByHashRequest(txHash string) (*common.JsonRpcRequest, error) {
+	jrq := common.NewJsonRpcRequest("eth_getTransactionByHash", []interface{}{txHash})
+	err := jrq.SetID(util.RandomID())
+	if err != nil {
+		return nil, err
+	}
+
+	return jrq, nil
+}
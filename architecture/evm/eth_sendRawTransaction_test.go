@@ -0,0 +1,39 @@
+package evm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/erpc/erpc/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionHashFromRawTransaction(t *testing.T) {
+	hash, err := TransactionHashFromRawTransaction("0x00")
+	require.NoError(t, err)
+	assert.Equal(t, "0xbc36789e7a1e281436464229828f817d6612f7b477d66591ff96a9e064bcc98a", hash)
+
+	_, err = TransactionHashFromRawTransaction("0x")
+	assert.Error(t, err)
+
+	_, err = TransactionHashFromRawTransaction("not-hex")
+	assert.Error(t, err)
+}
+
+func TestBroadcastErrorDetection(t *testing.T) {
+	alreadyKnown := common.NewErrEndpointServerSideException(
+		common.NewErrJsonRpcExceptionInternal(-32000, common.JsonRpcErrorCallException, "already known", nil, nil),
+		nil,
+		500,
+	)
+	assert.True(t, IsTransactionAlreadyKnownError(alreadyKnown))
+	assert.True(t, IsTransactionAlreadyKnownError(errors.New("Transaction with the same hash was already imported.")))
+	assert.False(t, IsTransactionAlreadyKnownError(errors.New("insufficient funds for gas * price + value")))
+	assert.False(t, IsTransactionAlreadyKnownError(nil))
+
+	assert.True(t, IsNonceTooLowError(errors.New("nonce too low: next nonce 5, tx nonce 4")))
+	assert.True(t, IsNonceTooLowError(errors.New("OldNonce")))
+	assert.False(t, IsNonceTooLowError(alreadyKnown))
+	assert.False(t, IsNonceTooLowError(nil))
+}
@@ -0,0 +1,326 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/telemetry"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	headSubscriptionMinReconnectDelay = 500 * time.Millisecond
+	headSubscriptionDialTimeout       = 10 * time.Second
+)
+
+type wsJsonRpcMessage struct {
+	Id     interface{}                         `json:"id,omitempty"`
+	Method string                              `json:"method,omitempty"`
+	Result json.RawMessage                     `json:"result,omitempty"`
+	Error  *common.ErrJsonRpcExceptionExternal `json:"error,omitempty"`
+	Params *struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params,omitempty"`
+}
+
+// evmHeadSubscription keeps an eth_subscribe("newHeads") subscription open on the upstream websocket
+// endpoint while this instance leads the upstream state polling, reconnecting with an exponential backoff.
+// It is healthy while heads keep arriving.
+type evmHeadSubscription struct {
+	projectId  string
+	upstream   common.Upstream
+	logger     *zerolog.Logger
+	endpoint   string
+	headers    map[string]string
+	cfg        *common.EvmHeadSubscriptionConfig
+	onHead     func(head *common.EvmBlockHead)
+	leadership data.LeaderElection
+
+	connected  atomic.Bool
+	lastHeadAt atomic.Int64 // unix nanoseconds
+
+	// Heads are handed over to onHead off the read loop, only the newest one is kept when it lags behind
+	pendingHead atomic.Pointer[common.EvmBlockHead]
+	headSignal  chan struct{}
+
+	leadershipSignal chan struct{}
+	cancelMu         sync.Mutex
+	cancelSubscribe  context.CancelFunc
+}
+
+func newEvmHeadSubscription(
+	projectId string,
+	logger *zerolog.Logger,
+	up common.Upstream,
+	cfg *common.EvmHeadSubscriptionConfig,
+	leadership data.LeaderElection,
+	onHead func(head *common.EvmBlockHead),
+) *evmHeadSubscription {
+	upsCfg := up.Config()
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = headSubscriptionEndpointFromHttp(upsCfg.Endpoint)
+	}
+	var headers map[string]string
+	if upsCfg.JsonRpc != nil {
+		headers = upsCfg.JsonRpc.Headers
+	}
+	lg := logger.With().Str("subscription", "newHeads").Str("wsEndpoint", util.RedactEndpoint(endpoint)).Logger()
+	s := &evmHeadSubscription{
+		projectId:        projectId,
+		upstream:         up,
+		logger:           &lg,
+		endpoint:         endpoint,
+		headers:          headers,
+		cfg:              cfg,
+		onHead:           onHead,
+		leadership:       leadership,
+		headSignal:       make(chan struct{}, 1),
+		leadershipSignal: make(chan struct{}, 1),
+	}
+	leadership.OnLeadershipChange(func(isLeader bool) {
+		if !isLeader {
+			s.cancelMu.Lock()
+			if s.cancelSubscribe != nil {
+				s.cancelSubscribe()
+			}
+			s.cancelMu.Unlock()
+		}
+		select {
+		case s.leadershipSignal <- struct{}{}:
+		default:
+		}
+	})
+	return s
+}
+
+// headSubscriptionEndpointFromHttp switches the scheme of an http(s) endpoint to ws(s), which is
+// where most providers serve websocket connections.
+func headSubscriptionEndpointFromHttp(endpoint string) string {
+	if strings.HasPrefix(endpoint, "https://") {
+		return "wss://" + strings.TrimPrefix(endpoint, "https://")
+	}
+	if strings.HasPrefix(endpoint, "http://") {
+		return "ws://" + strings.TrimPrefix(endpoint, "http://")
+	}
+	return endpoint
+}
+
+// healthy returns true when the subscription is connected and the last head is not older than the stale timeout
+func (s *evmHeadSubscription) healthy() bool {
+	if s == nil || !s.connected.Load() {
+		return false
+	}
+	last := s.lastHeadAt.Load()
+	return last > 0 && time.Since(time.Unix(0, last)) < s.cfg.StaleTimeout.Duration()
+}
+
+func (s *evmHeadSubscription) run(ctx context.Context) {
+	go s.deliverHeads(ctx)
+
+	delay := headSubscriptionMinReconnectDelay
+	maxDelay := s.cfg.MaxReconnectDelay.Duration()
+	for {
+		// Followers receive heads from the leader through shared state, only the leader subscribes
+		if !s.leadership.IsLeader() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.leadershipSignal:
+				continue
+			}
+		}
+
+		startedAt := time.Now()
+		err := s.subscribeWhileLeader(ctx)
+		if s.connected.Swap(false) {
+			s.recordEvent("disconnected")
+		}
+		if ctx.Err() != nil {
+			s.logger.Debug().Msg("stopping head subscription due to app context interruption")
+			return
+		}
+		if !s.leadership.IsLeader() {
+			s.logger.Debug().Msg("closing head subscription as this instance is no longer the leader")
+			continue
+		}
+		// Reset the backoff when the previous connection was healthy for a while
+		if time.Since(startedAt) > s.cfg.StaleTimeout.Duration() {
+			delay = headSubscriptionMinReconnectDelay
+		}
+		s.logger.Warn().Err(err).Dur("reconnectIn", delay).Msg("head subscription interrupted, falling back to polling until reconnected")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxDelay)
+	}
+}
+
+// subscribeWhileLeader subscribes until the connection breaks or this instance steps down.
+func (s *evmHeadSubscription) subscribeWhileLeader(ctx context.Context) error {
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.cancelMu.Lock()
+	s.cancelSubscribe = cancel
+	s.cancelMu.Unlock()
+	defer func() {
+		s.cancelMu.Lock()
+		s.cancelSubscribe = nil
+		s.cancelMu.Unlock()
+	}()
+	// Leadership might have been lost before the cancel func was set
+	if !s.leadership.IsLeader() {
+		return nil
+	}
+	return s.subscribe(subCtx)
+}
+
+// deliverHeads calls onHead for the newest received head, so that a slow onHead never blocks reading
+// from the websocket (which would make the subscription look stale).
+func (s *evmHeadSubscription) deliverHeads(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.headSignal:
+			if head := s.pendingHead.Swap(nil); head != nil {
+				s.onHead(head)
+			}
+		}
+	}
+}
+
+func (s *evmHeadSubscription) subscribe(ctx context.Context) error {
+	wsCfg, err := websocket.NewConfig(s.endpoint, s.origin())
+	if err != nil {
+		return err
+	}
+	if len(s.headers) > 0 {
+		wsCfg.Header = http.Header{}
+		for k, v := range s.headers {
+			wsCfg.Header.Set(k, v)
+		}
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, headSubscriptionDialTimeout)
+	conn, err := wsCfg.DialContext(dialCtx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	if err := websocket.JSON.Send(conn, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_subscribe",
+		"params":  []interface{}{"newHeads"},
+	}); err != nil {
+		return fmt.Errorf("failed to send eth_subscribe: %w", err)
+	}
+
+	subscriptionId := ""
+	staleTimeout := s.cfg.StaleTimeout.Duration()
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(staleTimeout)); err != nil {
+			return err
+		}
+		msg := &wsJsonRpcMessage{}
+		if err := websocket.JSON.Receive(conn, msg); err != nil {
+			return err
+		}
+
+		if subscriptionId == "" {
+			if msg.Error != nil {
+				return fmt.Errorf("eth_subscribe rejected: %d %s", msg.Error.Code, msg.Error.Message)
+			}
+			if msg.Id == nil || len(msg.Result) == 0 {
+				continue
+			}
+			if err := json.Unmarshal(msg.Result, &subscriptionId); err != nil || subscriptionId == "" {
+				return fmt.Errorf("unexpected eth_subscribe result: %s", string(msg.Result))
+			}
+			s.connected.Store(true)
+			s.recordEvent("connected")
+			s.logger.Info().Str("subscriptionId", subscriptionId).Msg("subscribed to new heads of upstream")
+			continue
+		}
+
+		if msg.Method != "eth_subscription" || msg.Params == nil || msg.Params.Subscription != subscriptionId {
+			continue
+		}
+		head, err := parseEvmBlockHead(msg.Params.Result)
+		if err != nil {
+			s.logger.Debug().Err(err).RawJSON("head", msg.Params.Result).Msg("ignoring unparsable head")
+			continue
+		}
+		s.lastHeadAt.Store(time.Now().UnixNano())
+		s.recordEvent("head")
+		s.pendingHead.Store(head)
+		select {
+		case s.headSignal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *evmHeadSubscription) origin() string {
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return "http://localhost"
+	}
+	scheme := "http"
+	if u.Scheme == "wss" {
+		scheme = "https"
+	}
+	return scheme + "://" + u.Host
+}
+
+func (s *evmHeadSubscription) recordEvent(event string) {
+	telemetry.MetricUpstreamHeadSubscriptionEvents.WithLabelValues(
+		s.projectId,
+		s.upstream.VendorName(),
+		s.upstream.NetworkId(),
+		s.upstream.Id(),
+		event,
+	).Inc()
+}
+
+func parseEvmBlockHead(raw json.RawMessage) (*common.EvmBlockHead, error) {
+	var header struct {
+		Number    string `json:"number"`
+		Hash      string `json:"hash"`
+		Timestamp string `json:"timestamp"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, err
+	}
+	number, err := common.HexToInt64(header.Number)
+	if err != nil {
+		return nil, err
+	}
+	head := &common.EvmBlockHead{Number: number, Hash: header.Hash}
+	if header.Timestamp != "" {
+		if ts, err := common.HexToInt64(header.Timestamp); err == nil {
+			head.Timestamp = ts
+		}
+	}
+	return head, nil
+}
@@ -0,0 +1,273 @@
+package evm
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/health"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// newHeadsServer accepts eth_subscribe("newHeads") and pushes the given heads, then keeps the
+// connection open until dropped via the returned func.
+func newHeadsServer(t *testing.T, heads ...string) (*httptest.Server, *atomic.Int32, func()) {
+	t.Helper()
+	var connections atomic.Int32
+	var mu sync.Mutex
+	var conns []*websocket.Conn
+	srv := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		connections.Add(1)
+		mu.Lock()
+		conns = append(conns, conn)
+		mu.Unlock()
+
+		var req map[string]interface{}
+		if err := websocket.JSON.Receive(conn, &req); err != nil {
+			return
+		}
+		if req["method"] != "eth_subscribe" {
+			return
+		}
+		_ = websocket.Message.Send(conn, `{"jsonrpc":"2.0","id":1,"result":"0xsub"}`)
+		for _, head := range heads {
+			_ = websocket.Message.Send(conn, `{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"0xsub","result":`+head+`}}`)
+		}
+		var ignored string
+		for websocket.Message.Receive(conn, &ignored) == nil {
+		}
+	}))
+	drop := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, c := range conns {
+			c.Close()
+		}
+		conns = nil
+	}
+	t.Cleanup(srv.Close)
+	return srv, &connections, drop
+}
+
+// switchableElection notifies leadership changes to callbacks like the real election does
+type switchableElection struct {
+	leader    atomic.Bool
+	mu        sync.Mutex
+	callbacks []func(bool)
+}
+
+func newSwitchableElection(leader bool) *switchableElection {
+	e := &switchableElection{}
+	e.leader.Store(leader)
+	return e
+}
+
+func (e *switchableElection) IsLeader() bool { return e.leader.Load() }
+func (e *switchableElection) Release()       {}
+
+func (e *switchableElection) OnLeadershipChange(cb func(bool)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.callbacks = append(e.callbacks, cb)
+}
+
+func (e *switchableElection) set(leader bool) {
+	e.leader.Store(leader)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, cb := range e.callbacks {
+		cb(leader)
+	}
+}
+
+func newHeadSubscriptionTestUpstream(endpoint string) *mockEvmUpstream {
+	up := &mockEvmUpstream{}
+	up.On("Config").Return(&common.UpstreamConfig{
+		Id:       "rpc1",
+		Endpoint: endpoint,
+		Evm:      &common.EvmUpstreamConfig{ChainId: 1},
+	})
+	up.On("NetworkId").Return("evm:1")
+	up.On("Id").Return("rpc1")
+	up.On("VendorName").Return("test")
+	return up
+}
+
+func TestEvmHeadSubscription(t *testing.T) {
+	t.Run("DerivesWebsocketEndpointFromHttp", func(t *testing.T) {
+		assert.Equal(t, "wss://eth.example.com/v2/key", headSubscriptionEndpointFromHttp("https://eth.example.com/v2/key"))
+		assert.Equal(t, "ws://localhost:8545", headSubscriptionEndpointFromHttp("http://localhost:8545"))
+	})
+
+	t.Run("PushesHeadsAndReconnectsAfterDisconnect", func(t *testing.T) {
+		srv, connections, drop := newHeadsServer(t,
+			`{"number":"0x10","hash":"0xaa","timestamp":"0x5"}`,
+			`{"number":"0x11","hash":"0xbb","timestamp":"0x6"}`,
+		)
+		cfg := &common.EvmHeadSubscriptionConfig{Endpoint: "ws" + strings.TrimPrefix(srv.URL, "http")}
+		require.NoError(t, cfg.SetDefaults(common.Duration(time.Second)))
+		cfg.MaxReconnectDelay = common.Duration(100 * time.Millisecond)
+
+		var mu sync.Mutex
+		var heads []*common.EvmBlockHead
+		up := newHeadSubscriptionTestUpstream("http://rpc1.localhost")
+		sub := newEvmHeadSubscription("test", &log.Logger, up, cfg, newSwitchableElection(true), func(head *common.EvmBlockHead) {
+			mu.Lock()
+			heads = append(heads, head)
+			mu.Unlock()
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go sub.run(ctx)
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(heads) > 0 && heads[len(heads)-1].Number == 0x11
+		}, 2*time.Second, 10*time.Millisecond)
+		mu.Lock()
+		assert.Equal(t, &common.EvmBlockHead{Number: 0x11, Hash: "0xbb", Timestamp: 6}, heads[len(heads)-1])
+		mu.Unlock()
+		assert.True(t, sub.healthy())
+
+		drop()
+		require.Eventually(t, func() bool { return !sub.healthy() }, 2*time.Second, 10*time.Millisecond)
+		require.Eventually(t, func() bool { return connections.Load() >= 2 && sub.healthy() }, 3*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("IsNotHealthyWhenHeadsStopArriving", func(t *testing.T) {
+		srv, _, _ := newHeadsServer(t, `{"number":"0x1"}`)
+		cfg := &common.EvmHeadSubscriptionConfig{
+			Endpoint:     "ws" + strings.TrimPrefix(srv.URL, "http"),
+			StaleTimeout: common.Duration(100 * time.Millisecond),
+		}
+		require.NoError(t, cfg.SetDefaults(common.Duration(time.Second)))
+
+		received := make(chan struct{}, 1)
+		sub := newEvmHeadSubscription("test", &log.Logger, newHeadSubscriptionTestUpstream("http://rpc1.localhost"), cfg, newSwitchableElection(true), func(*common.EvmBlockHead) {
+			received <- struct{}{}
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go sub.run(ctx)
+
+		select {
+		case <-received:
+		case <-time.After(2 * time.Second):
+			t.Fatal("expected a head")
+		}
+		assert.True(t, sub.healthy())
+		assert.Eventually(t, func() bool { return !sub.healthy() }, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestEvmHeadSubscription_OnlySubscribesWhileLeader(t *testing.T) {
+	srv, connections, _ := newHeadsServer(t, `{"number":"0x1"}`)
+	cfg := &common.EvmHeadSubscriptionConfig{Endpoint: "ws" + strings.TrimPrefix(srv.URL, "http")}
+	require.NoError(t, cfg.SetDefaults(common.Duration(time.Second)))
+
+	election := newSwitchableElection(false)
+	sub := newEvmHeadSubscription("test", &log.Logger, newHeadSubscriptionTestUpstream("http://rpc1.localhost"), cfg, election, func(*common.EvmBlockHead) {})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sub.run(ctx)
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(0), connections.Load(), "followers must not subscribe")
+
+	election.set(true)
+	require.Eventually(t, func() bool { return sub.healthy() }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), connections.Load())
+
+	election.set(false)
+	require.Eventually(t, func() bool { return !sub.connected.Load() }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(1), connections.Load(), "must not reconnect once stepped down")
+}
+
+func TestEvmHeadSubscription_SlowHeadHandlerDoesNotBlockReads(t *testing.T) {
+	srv, _, _ := newHeadsServer(t,
+		`{"number":"0x10"}`,
+		`{"number":"0x11"}`,
+		`{"number":"0x12"}`,
+	)
+	cfg := &common.EvmHeadSubscriptionConfig{Endpoint: "ws" + strings.TrimPrefix(srv.URL, "http")}
+	require.NoError(t, cfg.SetDefaults(common.Duration(time.Second)))
+
+	unblock := make(chan struct{})
+	var handling atomic.Int64
+	handled := make(chan int64, 3)
+	sub := newEvmHeadSubscription("test", &log.Logger, newHeadSubscriptionTestUpstream("http://rpc1.localhost"), cfg, newSwitchableElection(true), func(head *common.EvmBlockHead) {
+		handling.Store(head.Number)
+		<-unblock
+		handled <- head.Number
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sub.run(ctx)
+
+	// Every head is read while the handler is still stuck on an earlier one
+	require.Eventually(t, func() bool {
+		if head := sub.pendingHead.Load(); head != nil && head.Number == 0x12 {
+			return true
+		}
+		return handling.Load() == 0x12
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, sub.healthy())
+
+	// Heads received in the meantime are coalesced, so the newest one is handled last
+	close(unblock)
+	var last int64
+	require.Eventually(t, func() bool {
+		for {
+			select {
+			case n := <-handled:
+				last = n
+			default:
+				return last == 0x12
+			}
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Empty(t, handled)
+}
+
+func TestEvmStatePoller_SkipsLatestBlockPollingWhileSubscribed(t *testing.T) {
+	srv, _, _ := newHeadsServer(t, `{"number":"0x64"}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ssr, err := data.NewSharedStateRegistry(ctx, &log.Logger, &common.SharedStateConfig{
+		Connector: &common.ConnectorConfig{
+			Driver: "memory",
+			Memory: &common.MemoryConnectorConfig{MaxItems: 1000, MaxTotalSize: "10MB"},
+		},
+	})
+	require.NoError(t, err)
+
+	up := newHeadSubscriptionTestUpstream("http://rpc1.localhost")
+	poller := NewEvmStatePoller("test", ctx, &log.Logger, up, health.NewTracker(&log.Logger, "test", time.Minute), ssr)
+
+	cfg := &common.EvmHeadSubscriptionConfig{Endpoint: "ws" + strings.TrimPrefix(srv.URL, "http")}
+	require.NoError(t, cfg.SetDefaults(common.Duration(time.Second)))
+	poller.headSubscription = newEvmHeadSubscription("test", &log.Logger, up, cfg, poller.leadership, func(head *common.EvmBlockHead) {
+		poller.SuggestLatestBlock(head.Number)
+	})
+	go poller.headSubscription.run(ctx)
+
+	require.Eventually(t, func() bool { return poller.LatestBlock() == 100 }, 2*time.Second, 10*time.Millisecond)
+
+	// The upstream mock has no Forward expectation, so any polling attempt would fail the test
+	latest, err := poller.PollLatestBlockNumber(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), latest)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/erpc/erpc/common"
@@ -72,6 +73,12 @@ type EvmStatePoller struct {
 	finalizedBlockSuccessfulOnce bool
 	finalizedBlockShared         data.CounterInt64SharedVariable
 
+	// Same as finalized, certain networks and nodes do not support "safe" tag.
+	skipSafeCheck           bool
+	safeBlockFailureCount   int
+	safeBlockSuccessfulOnce bool
+	safeBlockShared         data.CounterInt64SharedVariable
+
 	// The reason to not use latestBlockTimestamp is to avoid thundering herd,
 	// when a node is actively syncing and timestamp is always way in the past.
 	skipLatestBlockCheck      bool
@@ -79,6 +86,22 @@ type EvmStatePoller struct {
 	latestBlockSuccessfulOnce bool
 	latestBlockShared         data.CounterInt64SharedVariable
 
+	// Hash and timestamp of the last head seen from this upstream, used to detect upstreams on a different fork
+	latestBlockHead atomic.Pointer[common.EvmBlockHead]
+
+	// When set, latest block is pushed by a newHeads subscription and polling is reduced while it is healthy
+	headSubscription *evmHeadSubscription
+
+	// Only the elected instance polls periodically, others receive block numbers, head and syncing state via shared state
+	leadership            data.LeaderElection
+	syncingStateShared    *data.SharedValue[common.EvmSyncingState]
+	latestBlockHeadShared *data.SharedValue[common.EvmBlockHead]
+
+	// Heads to share are coalesced so only the newest one is written, off the polling and subscription goroutines
+	pendingHeadPublish atomic.Pointer[common.EvmBlockHead]
+	headPublishSignal  chan struct{}
+	headPublisherOnce  sync.Once
+
 	stateMu sync.RWMutex
 }
 
@@ -95,17 +118,34 @@ func NewEvmStatePoller(
 
 	lbs := sharedState.GetCounterInt64(fmt.Sprintf("latestBlock/%s", common.UniqueUpstreamKey(up)), DefaultToleratedBlockHeadRollback)
 	fbs := sharedState.GetCounterInt64(fmt.Sprintf("finalizedBlock/%s", common.UniqueUpstreamKey(up)), DefaultToleratedBlockHeadRollback)
+	sbs := sharedState.GetCounterInt64(fmt.Sprintf("safeBlock/%s", common.UniqueUpstreamKey(up)), DefaultToleratedBlockHeadRollback)
 
 	e := &EvmStatePoller{
-		projectId:            projectId,
-		appCtx:               appCtx,
-		logger:               &lg,
-		upstream:             up,
-		tracker:              tracker,
-		latestBlockShared:    lbs,
-		finalizedBlockShared: fbs,
+		projectId:             projectId,
+		appCtx:                appCtx,
+		logger:                &lg,
+		upstream:              up,
+		tracker:               tracker,
+		latestBlockShared:     lbs,
+		finalizedBlockShared:  fbs,
+		safeBlockShared:       sbs,
+		leadership:            sharedState.GetLeaderElection(fmt.Sprintf("statePoller/%s", common.UniqueUpstreamKey(up))),
+		syncingStateShared:    data.NewSharedValue[common.EvmSyncingState](sharedState, fmt.Sprintf("syncingState/%s", common.UniqueUpstreamKey(up))),
+		latestBlockHeadShared: data.NewSharedValue[common.EvmBlockHead](sharedState, fmt.Sprintf("latestBlockHead/%s", common.UniqueUpstreamKey(up))),
+		headPublishSignal:     make(chan struct{}, 1),
 	}
 
+	e.syncingStateShared.OnValue(func(state common.EvmSyncingState) {
+		if !e.leadership.IsLeader() {
+			e.SetSyncingState(state)
+		}
+	})
+	e.latestBlockHeadShared.OnValue(func(head common.EvmBlockHead) {
+		if !e.leadership.IsLeader() && head.Hash != "" {
+			e.latestBlockHead.Store(&head)
+		}
+	})
+
 	lbs.OnValue(func(value int64) {
 		e.tracker.SetLatestBlockNumber(e.upstream, value)
 	})
@@ -119,6 +159,9 @@ func NewEvmStatePoller(
 	fbs.OnLargeRollback(func(currentVal, newVal int64) {
 		e.tracker.RecordBlockHeadLargeRollback(e.upstream, "finalized", currentVal, newVal)
 	})
+	sbs.OnLargeRollback(func(currentVal, newVal int64) {
+		e.tracker.RecordBlockHeadLargeRollback(e.upstream, "safe", currentVal, newVal)
+	})
 
 	return e
 }
@@ -143,15 +186,36 @@ func (e *EvmStatePoller) Bootstrap(ctx context.Context) error {
 	e.logger.Debug().Msgf("bootstrapping evm state poller to track upstream latest/finalized blocks and syncing states")
 	e.Enabled = true
 
+	var subscribedInterval time.Duration
+	if cfg.Evm != nil && cfg.Evm.HeadSubscription != nil {
+		subscribedInterval = cfg.Evm.HeadSubscription.PollerInterval.Duration()
+		e.headSubscription = newEvmHeadSubscription(e.projectId, e.logger, e.upstream, cfg.Evm.HeadSubscription, e.leadership, func(head *common.EvmBlockHead) {
+			e.latestBlockHead.Store(head)
+			e.publishLatestBlockHead(head)
+			e.SuggestLatestBlock(head.Number)
+		})
+		go e.headSubscription.run(e.appCtx)
+	}
+
 	go (func() {
 		ticker := time.NewTicker(interval.Duration())
 		defer ticker.Stop()
+		lastPolledAt := time.Now()
 		for {
 			select {
 			case <-e.appCtx.Done():
 				e.logger.Debug().Msg("shutting down evm state poller due to app context interruption")
 				return
 			case <-ticker.C:
+				// While new heads are pushed only finalized block and syncing state are polled, less often
+				if e.headSubscription.healthy() && time.Since(lastPolledAt) < subscribedInterval {
+					continue
+				}
+				// Followers keep their state fresh from what the elected instance publishes
+				if !e.leadership.IsLeader() {
+					continue
+				}
+				lastPolledAt = time.Now()
 				timeout := 10 * time.Second
 				nctx, cancel := context.WithTimeout(e.appCtx, timeout)
 				err := e.Poll(nctx)
@@ -226,6 +290,19 @@ func (e *EvmStatePoller) Poll(ctx context.Context) error {
 		}
 	}()
 
+	// Fetch safe block (if upstream supports)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := e.PollSafeBlockNumber(ctx)
+		if err != nil {
+			e.logger.Debug().Err(err).Msg("failed to get safe block number in evm state poller")
+			ermu.Lock()
+			errs = append(errs, err)
+			ermu.Unlock()
+		}
+	}()
+
 	// Fetch "syncing" state
 	wg.Add(1)
 	go func() {
@@ -272,6 +349,11 @@ func (e *EvmStatePoller) Poll(ctx context.Context) error {
 
 		e.logger.Debug().Bool("syncingResult", syncing).Msg("fetched syncing state")
 
+		// Registered before taking stateMu so it runs after the state is updated and the lock is released
+		defer func() {
+			e.publishSyncingState(ctx, e.SyncingState())
+		}()
+
 		e.stateMu.Lock()
 		defer e.stateMu.Unlock()
 		if syncing {
@@ -319,6 +401,9 @@ func (e *EvmStatePoller) PollLatestBlockNumber(ctx context.Context) (int64, erro
 		e.logger.Trace().Msg("skipping latest block number poll as it is not supported by the upstream")
 		return 0, nil
 	}
+	if e.headSubscription.healthy() {
+		return e.latestBlockShared.GetValue(), nil
+	}
 	dbi := e.debounceInterval
 	if dbi == 0 {
 		// We must have some debounce interval to avoid thundering herd
@@ -340,8 +425,8 @@ func (e *EvmStatePoller) PollLatestBlockNumber(ctx context.Context) (int64, erro
 			e.upstream.NetworkId(),
 			e.upstream.Id(),
 		).Inc()
-		blockNum, err := e.fetchBlock(ctx, "latest")
-		if err != nil || blockNum == 0 {
+		head, err := e.fetchBlock(ctx, "latest")
+		if err != nil || head == nil {
 			if err == nil ||
 				common.HasErrorCode(err,
 					common.ErrCodeUpstreamRequestSkipped,
@@ -375,15 +460,26 @@ func (e *EvmStatePoller) PollLatestBlockNumber(ctx context.Context) (int64, erro
 		e.latestBlockFailureCount = 0
 		e.stateMu.Unlock()
 
+		e.latestBlockHead.Store(head)
+		e.publishLatestBlockHead(head)
 		e.logger.Debug().
-			Int64("blockNumber", blockNum).
+			Int64("blockNumber", head.Number).
+			Str("blockHash", head.Hash).
 			Msg("fetched latest block from upstream")
-		return blockNum, nil
+		return head.Number, nil
 	})
 }
 
+func (e *EvmStatePoller) Shutdown() {
+	e.leadership.Release()
+	e.latestBlockShared.Release()
+	e.finalizedBlockShared.Release()
+	e.safeBlockShared.Release()
+	e.syncingStateShared.Release()
+	e.latestBlockHeadShared.Release()
+}
+
 func (e *EvmStatePoller) SuggestLatestBlock(blockNumber int64) {
-	// TODO after subscription epic this method should be called for every new block received from this upstream
 	e.latestBlockShared.TryUpdate(e.appCtx, blockNumber)
 }
 
@@ -391,6 +487,50 @@ func (e *EvmStatePoller) LatestBlock() int64 {
 	return e.latestBlockShared.GetValue()
 }
 
+// LatestBlockHead returns the last head fetched from (or pushed by) this upstream, its number might be behind
+// LatestBlock() when a higher value was suggested or received from the shared state.
+func (e *EvmStatePoller) LatestBlockHead() *common.EvmBlockHead {
+	if head := e.latestBlockHead.Load(); head != nil {
+		return head
+	}
+	return &common.EvmBlockHead{Number: e.latestBlockShared.GetValue()}
+}
+
+// publishLatestBlockHead shares the head (hash and timestamp included) fetched by the elected instance,
+// as followers do not poll and would otherwise only know the latest block number. It never blocks: the head
+// is handed to a background publisher that only writes the newest one.
+func (e *EvmStatePoller) publishLatestBlockHead(head *common.EvmBlockHead) {
+	if head == nil || head.Hash == "" || !e.leadership.IsLeader() {
+		return
+	}
+	e.headPublisherOnce.Do(func() {
+		go e.runHeadPublisher()
+	})
+	e.pendingHeadPublish.Store(head)
+	select {
+	case e.headPublishSignal <- struct{}{}:
+	default:
+	}
+}
+
+func (e *EvmStatePoller) runHeadPublisher() {
+	for {
+		select {
+		case <-e.appCtx.Done():
+			return
+		case <-e.headPublishSignal:
+			head := e.pendingHeadPublish.Swap(nil)
+			if head == nil || !e.leadership.IsLeader() {
+				continue
+			}
+			// Only the leader writes this value, so a plain versioned write is enough (no distributed lock)
+			if err := e.latestBlockHeadShared.Publish(e.appCtx, *head); err != nil {
+				e.logger.Warn().Err(err).Msg("failed to publish latest block head to shared state")
+			}
+		}
+	}
+}
+
 func (e *EvmStatePoller) PollFinalizedBlockNumber(ctx context.Context) (int64, error) {
 	if e.shouldSkipFinalizedCheck() {
 		return 0, nil
@@ -417,8 +557,8 @@ func (e *EvmStatePoller) PollFinalizedBlockNumber(ctx context.Context) (int64, e
 		).Inc()
 
 		// Actually fetch from upstream
-		blockNum, err := e.fetchBlock(ctx, "finalized")
-		if err != nil || blockNum == 0 {
+		head, err := e.fetchBlock(ctx, "finalized")
+		if err != nil || head == nil {
 			if err == nil ||
 				common.HasErrorCode(err,
 					common.ErrCodeUpstreamRequestSkipped,
@@ -452,12 +592,12 @@ func (e *EvmStatePoller) PollFinalizedBlockNumber(ctx context.Context) (int64, e
 		e.stateMu.Unlock()
 
 		e.logger.Debug().
-			Int64("blockNumber", blockNum).
+			Int64("blockNumber", head.Number).
 			Msg("fetched finalized block")
 
-		e.tracker.SetFinalizedBlockNumber(e.upstream, blockNum)
+		e.tracker.SetFinalizedBlockNumber(e.upstream, head.Number)
 
-		return blockNum, nil
+		return head.Number, nil
 	})
 }
 
@@ -469,6 +609,71 @@ func (e *EvmStatePoller) FinalizedBlock() int64 {
 	return e.finalizedBlockShared.GetValue()
 }
 
+// PollSafeBlockNumber fetches the "safe" block number, which is between finalized and latest blocks
+// on chains that support it (i.e. unlikely to be re-orged but not finalized yet).
+func (e *EvmStatePoller) PollSafeBlockNumber(ctx context.Context) (int64, error) {
+	if e.shouldSkipSafeCheck() {
+		return 0, nil
+	}
+	ctx, span := common.StartDetailSpan(ctx, "EvmStatePoller.PollSafeBlockNumber",
+		trace.WithAttributes(
+			attribute.String("upstream.id", e.upstream.Id()),
+			attribute.String("network.id", e.upstream.NetworkId()),
+		),
+	)
+	defer span.End()
+	dbi := e.debounceInterval
+	if dbi == 0 {
+		// We must have some debounce interval to avoid thundering herd
+		dbi = 1 * time.Second
+	}
+	return e.safeBlockShared.TryUpdateIfStale(ctx, dbi, func(ctx context.Context) (int64, error) {
+		e.logger.Trace().Msg("fetching safe block number for evm state poller")
+		head, err := e.fetchBlock(ctx, "safe")
+		if err != nil || head == nil {
+			if err == nil ||
+				common.HasErrorCode(err,
+					common.ErrCodeUpstreamRequestSkipped,
+					common.ErrCodeUpstreamMethodIgnored,
+					common.ErrCodeEndpointUnsupported,
+					common.ErrCodeEndpointMissingData,
+				) || common.IsClientError(err) {
+				e.stateMu.Lock()
+				// Only skip after multiple consecutive failures if we've never had a success
+				if !e.safeBlockSuccessfulOnce {
+					e.safeBlockFailureCount++
+					if e.safeBlockFailureCount >= 10 {
+						e.skipSafeCheck = true
+						e.logger.Warn().Err(err).Msgf("upstream does not support fetching safe block number in evm state poller after %d consecutive failures, will give up", e.safeBlockFailureCount)
+					} else {
+						e.logger.Debug().Err(err).Msgf("upstream does not seem to support fetching safe block number in evm state poller after %d consecutive failures, will retry again", e.safeBlockFailureCount)
+					}
+				}
+				e.stateMu.Unlock()
+				return 0, nil
+			} else {
+				e.logger.Warn().Err(err).Msg("failed to get safe block number in evm state poller")
+				return 0, err
+			}
+		}
+
+		e.stateMu.Lock()
+		e.safeBlockSuccessfulOnce = true
+		e.safeBlockFailureCount = 0
+		e.stateMu.Unlock()
+
+		e.logger.Debug().
+			Int64("blockNumber", head.Number).
+			Msg("fetched safe block")
+
+		return head.Number, nil
+	})
+}
+
+func (e *EvmStatePoller) SafeBlock() int64 {
+	return e.safeBlockShared.GetValue()
+}
+
 func (e *EvmStatePoller) IsBlockFinalized(blockNumber int64) (bool, error) {
 	finalizedBlock := e.finalizedBlockShared.GetValue()
 	latestBlock := e.latestBlockShared.GetValue()
@@ -533,6 +738,18 @@ func (e *EvmStatePoller) SetSyncingState(state common.EvmSyncingState) {
 	e.stateMu.Unlock()
 }
 
+// publishSyncingState shares the syncing state with other instances when it changes, as it is otherwise
+// only known to whichever instance polled eth_syncing.
+func (e *EvmStatePoller) publishSyncingState(ctx context.Context, state common.EvmSyncingState) {
+	current, version, err := e.syncingStateShared.Get()
+	if err == nil && version > 0 && current == state {
+		return
+	}
+	if _, err := e.syncingStateShared.CompareAndSwap(ctx, version, state); err != nil {
+		e.logger.Warn().Err(err).Msg("failed to publish syncing state to shared state")
+	}
+}
+
 func (e *EvmStatePoller) IsObjectNull() bool {
 	return e == nil || e.upstream == nil
 }
@@ -550,29 +767,37 @@ func (e *EvmStatePoller) shouldSkipFinalizedCheck() bool {
 	return e.skipFinalizedCheck
 }
 
-func (e *EvmStatePoller) fetchBlock(ctx context.Context, blockTag string) (int64, error) {
+func (e *EvmStatePoller) shouldSkipSafeCheck() bool {
+	e.stateMu.RLock()
+	defer e.stateMu.RUnlock()
+
+	return e.skipSafeCheck
+}
+
+// fetchBlock returns nil (without error) when the upstream returns an empty block
+func (e *EvmStatePoller) fetchBlock(ctx context.Context, blockTag string) (*common.EvmBlockHead, error) {
 	pr := common.NewNormalizedRequest([]byte(
 		fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"eth_getBlockByNumber","params":["%s",false]}`, util.RandomID(), blockTag),
 	))
 	resp, err := e.upstream.Forward(ctx, pr, true)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	jrr, err := resp.JsonRpcResponse()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	if jrr == nil || jrr.Error != nil {
-		return 0, jrr.Error
+		return nil, jrr.Error
 	}
 
 	if util.IsBytesEmptyish(jrr.Result) {
-		return 0, nil
+		return nil, nil
 	}
 
 	numberStr, err := jrr.PeekStringByPath(ctx, "number")
 	if err != nil {
-		return 0, &common.BaseError{
+		return nil, &common.BaseError{
 			Code:    "ErrEvmStatePoller",
 			Message: "cannot get block number from block data",
 			Details: map[string]interface{}{
@@ -583,10 +808,19 @@ func (e *EvmStatePoller) fetchBlock(ctx context.Context, blockTag string) (int64
 	}
 	blockNum, err := common.HexToInt64(numberStr)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	if blockNum == 0 {
+		return nil, nil
+	}
+
+	head := &common.EvmBlockHead{Number: blockNum}
+	head.Hash, _ = jrr.PeekStringByPath(ctx, "hash")
+	if ts, err := jrr.PeekStringByPath(ctx, "timestamp"); err == nil {
+		head.Timestamp, _ = common.HexToInt64(ts)
 	}
 
-	return blockNum, nil
+	return head, nil
 }
 
 func (e *EvmStatePoller) fetchSyncingState(ctx context.Context) (bool, error) {
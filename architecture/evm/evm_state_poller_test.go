@@ -0,0 +1,134 @@
+package evm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/health"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *mockEvmUpstream) Forward(ctx context.Context, nq *common.NormalizedRequest, skipSyncingCheck bool) (*common.NormalizedResponse, error) {
+	args := m.Called(ctx, nq, skipSyncingCheck)
+	var resp *common.NormalizedResponse
+	if r := args.Get(0); r != nil {
+		resp = r.(*common.NormalizedResponse)
+	}
+	return resp, args.Error(1)
+}
+
+func matchBlockTag(tag string) interface{} {
+	return mock.MatchedBy(func(nq *common.NormalizedRequest) bool {
+		body := string(nq.Body())
+		return strings.Contains(body, "eth_getBlockByNumber") && strings.Contains(body, `"`+tag+`"`)
+	})
+}
+
+func mockedBlockResponse(t *testing.T, result interface{}) *common.NormalizedResponse {
+	t.Helper()
+	jrr, err := common.NewJsonRpcResponse(1, result, nil)
+	require.NoError(t, err)
+	return common.NewNormalizedResponse().WithJsonRpcResponse(jrr)
+}
+
+func newTestEvmStatePoller(t *testing.T, ctx context.Context, up *mockEvmUpstream) *EvmStatePoller {
+	t.Helper()
+	ssr, err := data.NewSharedStateRegistry(ctx, &log.Logger, &common.SharedStateConfig{
+		Connector: &common.ConnectorConfig{
+			Driver: "memory",
+			Memory: &common.MemoryConnectorConfig{MaxItems: 1000, MaxTotalSize: "10MB"},
+		},
+	})
+	require.NoError(t, err)
+	return NewEvmStatePoller("test", ctx, &log.Logger, up, health.NewTracker(&log.Logger, "test", time.Minute), ssr)
+}
+
+func TestEvmStatePoller_TracksSafeBlockAndHeadHash(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	up := newHeadSubscriptionTestUpstream("http://rpc1.localhost")
+	up.On("Forward", mock.Anything, matchBlockTag("latest"), true).
+		Return(mockedBlockResponse(t, map[string]interface{}{"number": "0x64", "hash": "0xaaa", "timestamp": "0x5f5e100"}), nil)
+	up.On("Forward", mock.Anything, matchBlockTag("safe"), true).
+		Return(mockedBlockResponse(t, map[string]interface{}{"number": "0x60"}), nil)
+	up.On("Forward", mock.Anything, matchBlockTag("finalized"), true).
+		Return(mockedBlockResponse(t, map[string]interface{}{"number": "0x50"}), nil)
+	up.On("Forward", mock.Anything, mock.Anything, true).
+		Return(mockedBlockResponse(t, false), nil)
+
+	poller := newTestEvmStatePoller(t, ctx, up)
+	require.NoError(t, poller.Poll(ctx))
+
+	assert.Equal(t, int64(0x64), poller.LatestBlock())
+	assert.Equal(t, int64(0x60), poller.SafeBlock())
+	assert.Equal(t, int64(0x50), poller.FinalizedBlock())
+	assert.Equal(t, &common.EvmBlockHead{Number: 0x64, Hash: "0xaaa", Timestamp: 100000000}, poller.LatestBlockHead())
+}
+
+func TestEvmStatePoller_StopsPollingUnsupportedSafeTag(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	up := newHeadSubscriptionTestUpstream("http://rpc1.localhost")
+	up.On("Forward", mock.Anything, matchBlockTag("safe"), true).
+		Return(nil, common.NewErrEndpointUnsupported(nil))
+
+	poller := newTestEvmStatePoller(t, ctx, up)
+	poller.debounceInterval = time.Millisecond
+	for i := 0; i < 12; i++ {
+		time.Sleep(2 * time.Millisecond)
+		_, err := poller.PollSafeBlockNumber(ctx)
+		require.NoError(t, err)
+	}
+
+	assert.True(t, poller.shouldSkipSafeCheck())
+	assert.Equal(t, int64(0), poller.SafeBlock())
+	up.AssertNumberOfCalls(t, "Forward", 10)
+}
+
+type followerElection struct{}
+
+func (followerElection) IsLeader() bool                { return false }
+func (followerElection) OnLeadershipChange(func(bool)) {}
+func (followerElection) Release()                      {}
+
+func TestEvmStatePoller_FollowersReceiveHeadFromLeader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	up := newHeadSubscriptionTestUpstream("http://rpc1.localhost")
+	up.On("Forward", mock.Anything, matchBlockTag("latest"), true).
+		Return(mockedBlockResponse(t, map[string]interface{}{"number": "0x64", "hash": "0xaaa", "timestamp": "0x5f5e100"}), nil)
+	up.On("Forward", mock.Anything, mock.Anything, true).
+		Return(mockedBlockResponse(t, false), nil)
+
+	ssr, err := data.NewSharedStateRegistry(ctx, &log.Logger, &common.SharedStateConfig{
+		Connector: &common.ConnectorConfig{
+			Driver: "memory",
+			Memory: &common.MemoryConnectorConfig{MaxItems: 1000, MaxTotalSize: "10MB"},
+		},
+	})
+	require.NoError(t, err)
+	leader := NewEvmStatePoller("test", ctx, &log.Logger, up, health.NewTracker(&log.Logger, "test", time.Minute), ssr)
+	follower := NewEvmStatePoller("test", ctx, &log.Logger, up, health.NewTracker(&log.Logger, "test", time.Minute), ssr)
+	follower.leadership = followerElection{}
+
+	_, err = leader.PollLatestBlockNumber(ctx)
+	require.NoError(t, err)
+
+	// The follower never polls yet knows the hash and timestamp of the head
+	expected := &common.EvmBlockHead{Number: 0x64, Hash: "0xaaa", Timestamp: 100000000}
+	require.Eventually(t, func() bool {
+		head := follower.LatestBlockHead()
+		return head.Hash == expected.Hash
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, expected, follower.LatestBlockHead())
+}
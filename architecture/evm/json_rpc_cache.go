@@ -34,6 +34,10 @@ type EvmJsonRpcCache struct {
 
 const (
 	JsonRpcCacheContext common.ContextKey = "jsonRpcCache"
+
+	// streamedResultDefaultMaxCacheSize caps the copy of a streamed result kept for caching when the policy has
+	// no maxItemSize, so that piping a huge result to the client never buffers it whole in memory.
+	streamedResultDefaultMaxCacheSize = 64 * 1024 * 1024
 )
 
 func NewEvmJsonRpcCache(ctx context.Context, logger *zerolog.Logger, cfg *common.CacheConfig) (*EvmJsonRpcCache, error) {
@@ -440,6 +444,17 @@ func (c *EvmJsonRpcCache) Set(ctx context.Context, req *common.NormalizedRequest
 			Msg("caching the response")
 	}
 
+	// Results composed by a writer (e.g. merged eth_getLogs sub-requests) are only buffered for cache here
+	result := rpcResp.Result
+	if size, _ := rpcResp.Size(); len(result) == 0 && size > 0 && !rpcResp.IsStreaming() {
+		buf := bytes.NewBuffer(nil)
+		if _, err := rpcResp.WriteResultTo(buf, false); err != nil {
+			common.SetTraceSpanError(span, err)
+			return err
+		}
+		result = buf.Bytes()
+	}
+
 	wg := sync.WaitGroup{}
 	errs := []error{}
 	errsMu := sync.Mutex{}
@@ -487,82 +502,42 @@ func (c *EvmJsonRpcCache) Set(ctx context.Context, req *common.NormalizedRequest
 				return
 			}
 
-			// Compress the value before storing if compression is enabled
-			valueToStore := rpcResp.Result // Already []byte
-			telemetry.MetricCacheSetOriginalBytes.WithLabelValues(
-				c.projectId,
-				req.NetworkId(),
-				rpcReq.Method,
-				connector.Id(),
-				policy.String(),
-				ttl.String(),
-			).Add(float64(len(valueToStore)))
-
-			if c.compressionEnabled && len(valueToStore) >= c.compressionThreshold {
-				compressedValue, isCompressed := c.compressValueBytes(valueToStore)
-				if isCompressed {
-					originalSize := len(valueToStore)
-					compressedSize := len(compressedValue)
-					savings := float64(originalSize-compressedSize) / float64(originalSize) * 100
-					lg.Debug().
-						Int("originalSize", originalSize).
-						Int("compressedSize", compressedSize).
-						Float64("savings", savings).
-						Msg("compressed cache value")
-					telemetry.MetricCacheSetCompressedBytes.WithLabelValues(
+			if rpcResp.IsStreaming() {
+				// The result is being piped to the client, so store the copy teed from the stream once it is fully
+				// written, results bigger than the policy max size are not buffered at all.
+				maxSize := policy.MaxSize()
+				if maxSize <= 0 {
+					maxSize = streamedResultDefaultMaxCacheSize
+				}
+				registered := rpcResp.OnResultComplete(maxSize, func(result []byte) {
+					if !policy.MatchesSizeLimits(len(result)) {
+						lg.Debug().Int("size", len(result)).Msg("skip caching because streamed response size does not match policy limits")
+						return
+					}
+					setCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+					defer cancel()
+					if err := c.storeResult(setCtx, lg, req, rpcReq, policy, pk, rk, result, start); err != nil {
+						lg.Warn().Err(err).Msg("could not store streamed response in cache")
+					}
+				})
+				if !registered {
+					telemetry.MetricCacheSetSkippedTotal.WithLabelValues(
 						c.projectId,
 						req.NetworkId(),
 						rpcReq.Method,
 						connector.Id(),
 						policy.String(),
 						ttl.String(),
-					).Add(float64(compressedSize))
-					valueToStore = compressedValue
+					).Inc()
 				}
+				return
 			}
 
-			ctx, cancel := context.WithTimeoutCause(ctx, 5*time.Second, errors.New("evm json-rpc cache driver timeout during set"))
-			defer cancel()
-			err = connector.Set(ctx, pk, rk, valueToStore, ttl)
+			err = c.storeResult(ctx, lg, req, rpcReq, policy, pk, rk, result, start)
 			if err != nil {
 				errsMu.Lock()
 				errs = append(errs, err)
 				errsMu.Unlock()
-				telemetry.MetricCacheSetErrorTotal.WithLabelValues(
-					c.projectId,
-					req.NetworkId(),
-					rpcReq.Method,
-					connector.Id(),
-					policy.String(),
-					ttl.String(),
-					common.ErrorSummary(err),
-				).Inc()
-				telemetry.MetricCacheSetErrorDuration.WithLabelValues(
-					c.projectId,
-					req.NetworkId(),
-					rpcReq.Method,
-					connector.Id(),
-					policy.String(),
-					ttl.String(),
-					common.ErrorSummary(err),
-				).Observe(time.Since(start).Seconds())
-			} else {
-				telemetry.MetricCacheSetSuccessTotal.WithLabelValues(
-					c.projectId,
-					req.NetworkId(),
-					rpcReq.Method,
-					connector.Id(),
-					policy.String(),
-					ttl.String(),
-				).Inc()
-				telemetry.MetricCacheSetSuccessDuration.WithLabelValues(
-					c.projectId,
-					req.NetworkId(),
-					rpcReq.Method,
-					connector.Id(),
-					policy.String(),
-					ttl.String(),
-				).Observe(time.Since(start).Seconds())
 			}
 		}(policy)
 	}
@@ -583,6 +558,97 @@ func (c *EvmJsonRpcCache) Set(ctx context.Context, req *common.NormalizedRequest
 	return nil
 }
 
+// storeResult writes a result to the connector of a policy, compressing it when enabled.
+func (c *EvmJsonRpcCache) storeResult(
+	ctx context.Context,
+	lg zerolog.Logger,
+	req *common.NormalizedRequest,
+	rpcReq *common.JsonRpcRequest,
+	policy *data.CachePolicy,
+	pk, rk string,
+	result []byte,
+	start time.Time,
+) error {
+	connector := policy.GetConnector()
+	ttl := policy.GetTTL()
+
+	// Compress the value before storing if compression is enabled
+	valueToStore := result
+	telemetry.MetricCacheSetOriginalBytes.WithLabelValues(
+		c.projectId,
+		req.NetworkId(),
+		rpcReq.Method,
+		connector.Id(),
+		policy.String(),
+		ttl.String(),
+	).Add(float64(len(valueToStore)))
+
+	if c.compressionEnabled && len(valueToStore) >= c.compressionThreshold {
+		compressedValue, isCompressed := c.compressValueBytes(valueToStore)
+		if isCompressed {
+			originalSize := len(valueToStore)
+			compressedSize := len(compressedValue)
+			savings := float64(originalSize-compressedSize) / float64(originalSize) * 100
+			lg.Debug().
+				Int("originalSize", originalSize).
+				Int("compressedSize", compressedSize).
+				Float64("savings", savings).
+				Msg("compressed cache value")
+			telemetry.MetricCacheSetCompressedBytes.WithLabelValues(
+				c.projectId,
+				req.NetworkId(),
+				rpcReq.Method,
+				connector.Id(),
+				policy.String(),
+				ttl.String(),
+			).Add(float64(compressedSize))
+			valueToStore = compressedValue
+		}
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, 5*time.Second, errors.New("evm json-rpc cache driver timeout during set"))
+	defer cancel()
+	err := connector.Set(ctx, pk, rk, valueToStore, ttl)
+	if err != nil {
+		telemetry.MetricCacheSetErrorTotal.WithLabelValues(
+			c.projectId,
+			req.NetworkId(),
+			rpcReq.Method,
+			connector.Id(),
+			policy.String(),
+			ttl.String(),
+			common.ErrorSummary(err),
+		).Inc()
+		telemetry.MetricCacheSetErrorDuration.WithLabelValues(
+			c.projectId,
+			req.NetworkId(),
+			rpcReq.Method,
+			connector.Id(),
+			policy.String(),
+			ttl.String(),
+			common.ErrorSummary(err),
+		).Observe(time.Since(start).Seconds())
+	} else {
+		telemetry.MetricCacheSetSuccessTotal.WithLabelValues(
+			c.projectId,
+			req.NetworkId(),
+			rpcReq.Method,
+			connector.Id(),
+			policy.String(),
+			ttl.String(),
+		).Inc()
+		telemetry.MetricCacheSetSuccessDuration.WithLabelValues(
+			c.projectId,
+			req.NetworkId(),
+			rpcReq.Method,
+			connector.Id(),
+			policy.String(),
+			ttl.String(),
+		).Observe(time.Since(start).Seconds())
+	}
+	return err
+}
+
 func (c *EvmJsonRpcCache) IsObjectNull() bool {
 	return c == nil || c.logger == nil
 }
@@ -716,14 +782,20 @@ func shouldCacheResponse(
 		return false, nil
 	}
 
-	// Check if the response size is within the limits
-	if !policy.MatchesSizeLimits(len(rpcResp.Result)) {
-		lg.Debug().Int("size", len(rpcResp.Result)).Msg("skip caching because response size does not match policy limits")
-		return false, nil
+	// Check if the response size is within the limits, for streamed results it is checked once fully read
+	if !rpcResp.IsStreaming() {
+		size, err := rpcResp.Size()
+		if err != nil {
+			return false, err
+		}
+		if !policy.MatchesSizeLimits(size) {
+			lg.Debug().Int("size", size).Msg("skip caching because response size does not match policy limits")
+			return false, nil
+		}
 	}
 
 	// Check if we should cache empty results
-	isEmpty := resp == nil || rpcResp == nil || rpcResp.Result == nil || resp.IsObjectNull() || resp.IsResultEmptyish()
+	isEmpty := resp == nil || rpcResp == nil || resp.IsObjectNull() || resp.IsResultEmptyish()
 	switch policy.EmptyState() {
 	case common.CacheEmptyBehaviorIgnore:
 		return !isEmpty, nil
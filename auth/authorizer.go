@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/erpc/erpc/common"
@@ -19,7 +20,7 @@ type Authorizer struct {
 }
 
 // NewAuthorizer creates a new Authorizer based on the provided configuration
-func NewAuthorizer(logger *zerolog.Logger, projectId string, cfg *common.AuthStrategyConfig, rateLimitersRegistry *upstream.RateLimitersRegistry) (*Authorizer, error) {
+func NewAuthorizer(appCtx context.Context, logger *zerolog.Logger, projectId string, cfg *common.AuthStrategyConfig, rateLimitersRegistry *upstream.RateLimitersRegistry) (*Authorizer, error) {
 	if cfg == nil {
 		return nil, common.NewErrInvalidConfig("auth strategy config is nil")
 	}
@@ -32,12 +33,15 @@ func NewAuthorizer(logger *zerolog.Logger, projectId string, cfg *common.AuthStr
 		if cfg.Secret == nil {
 			return nil, common.NewErrInvalidConfig("secret strategy config is nil")
 		}
-		strategy = NewSecretStrategy(cfg.Secret)
+		strategy, err = NewSecretStrategy(appCtx, logger, projectId, cfg.Secret)
+		if err != nil {
+			return nil, err
+		}
 	case common.AuthTypeJwt:
 		if cfg.Jwt == nil {
 			return nil, common.NewErrInvalidConfig("JWT strategy config is nil")
 		}
-		strategy, err = NewJwtStrategy(cfg.Jwt)
+		strategy, err = NewJwtStrategy(logger, cfg.Jwt)
 		if err != nil {
 			return nil, err
 		}
@@ -54,11 +58,25 @@ func NewAuthorizer(logger *zerolog.Logger, projectId string, cfg *common.AuthStr
 		if err != nil {
 			return nil, err
 		}
+	case common.AuthTypeWebhook:
+		if cfg.Webhook == nil {
+			return nil, common.NewErrInvalidConfig("webhook strategy config is nil")
+		}
+		strategy = NewWebhookStrategy(logger, projectId, cfg.Webhook)
+	case common.AuthTypeMtls:
+		if cfg.Mtls == nil {
+			return nil, common.NewErrInvalidConfig("mTLS strategy config is nil")
+		}
+		strategy, err = NewMtlsStrategy(cfg.Mtls)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, common.NewErrInvalidConfig(fmt.Sprintf("unknown auth strategy type: %s", cfg.Type))
 	}
 
 	return &Authorizer{
+		projectId:            projectId,
 		logger:               logger,
 		cfg:                  cfg,
 		strategy:             strategy,
@@ -103,12 +121,12 @@ func (a *Authorizer) shouldApplyToMethod(method string) bool {
 	return shouldApply
 }
 
-func (a *Authorizer) acquireRateLimitPermit(method string) error {
-	if a.cfg.RateLimitBudget == "" {
+func (a *Authorizer) acquireRateLimitPermit(method string, budget string) error {
+	if budget == "" {
 		return nil
 	}
 
-	rlb, errNetLimit := a.rateLimitersRegistry.GetBudget(a.cfg.RateLimitBudget)
+	rlb, errNetLimit := a.rateLimitersRegistry.GetBudget(budget)
 	if errNetLimit != nil {
 		return errNetLimit
 	}
@@ -136,7 +154,7 @@ func (a *Authorizer) acquireRateLimitPermit(method string) error {
 				return common.NewErrAuthRateLimitRuleExceeded(
 					a.projectId,
 					string(a.cfg.Type),
-					a.cfg.RateLimitBudget,
+					budget,
 					fmt.Sprintf("%+v", rule.Config),
 				)
 			} else {
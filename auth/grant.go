@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/erpc/erpc/common"
+)
+
+const grantContextKey common.ContextKey = "authGrant"
+
+// grantOverrideClaim holds authorization metadata a strategy resolved itself (e.g. secret key or webhook
+// decision), which takes precedence over the strategy config when building the grant.
+const grantOverrideClaim = "erpc:grantOverride"
+
+type grantOverride interface {
+	applyTo(grant *Grant)
+}
+
+// Grant is the authorization resolved for an authenticated consumer, either from the strategy
+// config or derived from verified claims (e.g. a JWT "plan" claim mapped to a tier).
+type Grant struct {
+	Strategy common.AuthType
+	// Owner identifies the consumer (e.g. owner label of a secret key, JWT subject or SIWE address) in logs, traces and metrics
+	Owner           string
+	RateLimitBudget string
+	AllowedNetworks []string
+	AllowedMethods  []string
+	MaxBatchSize    int
+	// MaxBatchConcurrency bounds how many batch items of this consumer are processed at the same time, across its requests
+	MaxBatchConcurrency int
+	// Metadata is attached to logs and traces of the request (e.g. returned by an authorization webhook)
+	Metadata map[string]string
+}
+
+func WithGrant(ctx context.Context, grant *Grant) context.Context {
+	if grant == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, grantContextKey, grant)
+}
+
+func GrantFromContext(ctx context.Context) *Grant {
+	if ctx == nil {
+		return nil
+	}
+	if g, ok := ctx.Value(grantContextKey).(*Grant); ok {
+		return g
+	}
+	return nil
+}
+
+// AllowsMethod checks the method against allowed method patterns, empty list means all methods are allowed.
+func (g *Grant) AllowsMethod(method string) bool {
+	if g == nil || len(g.AllowedMethods) == 0 {
+		return true
+	}
+	for _, pattern := range g.AllowedMethods {
+		if match, err := common.WildcardMatch(pattern, method); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsNetwork checks the network id (e.g. evm:42161) against allowed network patterns,
+// plain chain ids (e.g. 42161) are treated as evm networks. Empty list means all networks are allowed.
+func (g *Grant) AllowsNetwork(networkId string) bool {
+	if g == nil || len(g.AllowedNetworks) == 0 {
+		return true
+	}
+	for _, pattern := range g.AllowedNetworks {
+		if !strings.Contains(pattern, ":") {
+			pattern = fmt.Sprintf("%s:%s", common.ArchitectureEvm, pattern)
+		}
+		if match, err := common.WildcardMatch(pattern, networkId); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
+
+// Check enforces method and batch size limits of the grant for the given payload.
+func (g *Grant) Check(ap *AuthPayload) error {
+	if g == nil {
+		return nil
+	}
+	if !g.AllowsMethod(ap.Method) {
+		return common.NewErrAuthForbidden(string(g.Strategy), fmt.Sprintf("method %s is not allowed for this consumer", ap.Method))
+	}
+	if g.MaxBatchSize > 0 && ap.BatchSize > g.MaxBatchSize {
+		return common.NewErrAuthForbidden(string(g.Strategy), fmt.Sprintf("batch size %d exceeds the maximum of %d allowed for this consumer", ap.BatchSize, g.MaxBatchSize))
+	}
+	return nil
+}
+
+// CheckNetwork enforces network limits of the grant, used on the forward path once the network is resolved.
+func (g *Grant) CheckNetwork(networkId string) error {
+	if !g.AllowsNetwork(networkId) {
+		return common.NewErrAuthForbidden(string(g.Strategy), fmt.Sprintf("network %s is not allowed for this consumer", networkId))
+	}
+	return nil
+}
+
+// resolveGrant builds the grant from strategy config, then tier (looked up by the tier claim value),
+// then claims carrying values directly, each layer overriding the previous one.
+func resolveGrant(cfg *common.AuthStrategyConfig, claims map[string]interface{}) (*Grant, error) {
+	grant := &Grant{
+		Strategy:        cfg.Type,
+		RateLimitBudget: cfg.RateLimitBudget,
+	}
+	if o, ok := claims[grantOverrideClaim].(grantOverride); ok {
+		o.applyTo(grant)
+	}
+	if grant.Owner == "" {
+		// Verified identities double as owner so consumers are distinguishable in logs and metrics
+		switch cfg.Type {
+		case common.AuthTypeJwt:
+			grant.Owner, _ = claimString(claims, "sub")
+		case common.AuthTypeSiwe:
+			grant.Owner, _ = claimString(claims, "address")
+		}
+	}
+	az := cfg.Authorization
+	if az == nil {
+		return grant, nil
+	}
+
+	if az.TierClaim != "" {
+		tierName, ok := claimString(claims, az.TierClaim)
+		if !ok {
+			return nil, common.NewErrAuthUnauthorized(string(cfg.Type), fmt.Sprintf("the '%s' tier claim is missing", az.TierClaim))
+		}
+		tier, ok := az.Tiers[tierName]
+		if !ok {
+			return nil, common.NewErrAuthUnauthorized(string(cfg.Type), fmt.Sprintf("the '%s' tier is not defined", tierName))
+		}
+		if tier.RateLimitBudget != "" {
+			grant.RateLimitBudget = tier.RateLimitBudget
+		}
+		grant.AllowedNetworks = tier.AllowedNetworks
+		grant.AllowedMethods = tier.AllowedMethods
+		grant.MaxBatchSize = tier.MaxBatchSize
+		grant.MaxBatchConcurrency = tier.MaxBatchConcurrency
+	}
+
+	if az.RateLimitBudgetClaim != "" {
+		if v, ok := claimString(claims, az.RateLimitBudgetClaim); ok && v != "" {
+			grant.RateLimitBudget = v
+		}
+	}
+	if az.AllowedNetworksClaim != "" {
+		if v, ok := claimStrings(claims, az.AllowedNetworksClaim); ok {
+			grant.AllowedNetworks = v
+		}
+	}
+	if az.AllowedMethodsClaim != "" {
+		if v, ok := claimStrings(claims, az.AllowedMethodsClaim); ok {
+			grant.AllowedMethods = v
+		}
+	}
+	if az.MaxBatchSizeClaim != "" {
+		if v, ok := claimString(claims, az.MaxBatchSizeClaim); ok {
+			size, err := strconv.Atoi(v)
+			if err != nil || size < 0 {
+				return nil, common.NewErrAuthUnauthorized(string(cfg.Type), fmt.Sprintf("the '%s' claim must be a non-negative integer", az.MaxBatchSizeClaim))
+			}
+			grant.MaxBatchSize = size
+		}
+	}
+	if az.MaxBatchConcurrencyClaim != "" {
+		if v, ok := claimString(claims, az.MaxBatchConcurrencyClaim); ok {
+			concurrency, err := strconv.Atoi(v)
+			if err != nil || concurrency < 0 {
+				return nil, common.NewErrAuthUnauthorized(string(cfg.Type), fmt.Sprintf("the '%s' claim must be a non-negative integer", az.MaxBatchConcurrencyClaim))
+			}
+			grant.MaxBatchConcurrency = concurrency
+		}
+	}
+
+	return grant, nil
+}
+
+func claimString(claims map[string]interface{}, name string) (string, bool) {
+	v, ok := claims[name]
+	if !ok || v == nil {
+		return "", false
+	}
+	return claimValueToString(v), true
+}
+
+// claimStrings accepts either a list claim or a comma/space separated string (similar to OAuth "scope").
+func claimStrings(claims map[string]interface{}, name string) ([]string, bool) {
+	v, ok := claims[name]
+	if !ok || v == nil {
+		return nil, false
+	}
+	switch t := v.(type) {
+	case []string:
+		return t, true
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if item != nil {
+				out = append(out, claimValueToString(item))
+			}
+		}
+		return out, true
+	default:
+		return strings.FieldsFunc(claimValueToString(v), func(r rune) bool {
+			return r == ',' || r == ' '
+		}), true
+	}
+}
+
+func claimValueToString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(t)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
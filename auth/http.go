@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"crypto/tls"
 	"encoding/base64"
 	"errors"
 	"net/http"
@@ -10,7 +11,7 @@ import (
 	"github.com/erpc/erpc/common"
 )
 
-func NewPayloadFromHttp(method string, remoteAddr string, headers http.Header, args url.Values) (*AuthPayload, error) {
+func NewPayloadFromHttp(method string, remoteAddr string, headers http.Header, args url.Values, tlsState *tls.ConnectionState) (*AuthPayload, error) {
 	ap := &AuthPayload{
 		Method: method,
 	}
@@ -79,6 +80,15 @@ func NewPayloadFromHttp(method string, remoteAddr string, headers http.Header, a
 		}
 	}
 
+	// Client certificate is kept regardless of other credentials so that mtls strategies can be
+	// combined with (or fall back to) any other strategy.
+	if tlsState != nil && len(tlsState.PeerCertificates) > 0 {
+		ap.Mtls = &MtlsPayload{
+			Certificates: tlsState.PeerCertificates,
+			Identity:     certificateIdentity(tlsState.PeerCertificates[0]),
+		}
+	}
+
 	// Add IP-based authentication
 	if ap.Type == "" {
 		xff := headers.Get("X-Forwarded-For")
@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+)
+
+const jwksFetchTimeout = 10 * time.Second
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// jwksKeySet caches public keys of a remote JWKS endpoint. Keys are refreshed in the background
+// once older than refreshInterval, or on demand when a token carries an unknown "kid" (at most
+// once per minRefreshInterval). Failed fetches keep the previously cached keys.
+type jwksKeySet struct {
+	url                string
+	logger             *zerolog.Logger
+	client             *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]interface{}
+	lastFetch   time.Time
+	lastAttempt time.Time
+
+	refreshMu  sync.Mutex
+	refreshing bool
+}
+
+func newJwksKeySet(logger *zerolog.Logger, url string, refreshInterval, minRefreshInterval time.Duration) *jwksKeySet {
+	lg := logger.With().Str("jwksUrl", url).Logger()
+	return &jwksKeySet{
+		url:                url,
+		logger:             &lg,
+		client:             &http.Client{Timeout: jwksFetchTimeout},
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: minRefreshInterval,
+		keys:               make(map[string]interface{}),
+	}
+}
+
+// lookup returns the cached key for the given kid, fetching the key set again if the kid is unknown
+// and the last attempt is older than minRefreshInterval (e.g. right after the IdP rotated its keys).
+func (j *jwksKeySet) lookup(ctx context.Context, kid string) (interface{}, bool) {
+	j.refreshIfStale()
+
+	j.mu.RLock()
+	key, ok := j.keys[kid]
+	j.mu.RUnlock()
+	if ok {
+		return key, true
+	}
+
+	if !j.canRefresh() {
+		return nil, false
+	}
+	j.refresh(ctx)
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	key, ok = j.keys[kid]
+	return key, ok
+}
+
+// all returns a snapshot of every cached key, used when the token carries no "kid".
+func (j *jwksKeySet) all() []interface{} {
+	j.refreshIfStale()
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	keys := make([]interface{}, 0, len(j.keys))
+	for _, key := range j.keys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (j *jwksKeySet) canRefresh() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.lastAttempt.IsZero() || time.Since(j.lastAttempt) >= j.minRefreshInterval
+}
+
+func (j *jwksKeySet) refreshIfStale() {
+	j.mu.RLock()
+	stale := j.lastFetch.IsZero() || time.Since(j.lastFetch) >= j.refreshInterval
+	j.mu.RUnlock()
+	if !stale || !j.canRefresh() {
+		return
+	}
+
+	j.refreshMu.Lock()
+	if j.refreshing {
+		j.refreshMu.Unlock()
+		return
+	}
+	j.refreshing = true
+	j.refreshMu.Unlock()
+
+	go func() {
+		defer func() {
+			j.refreshMu.Lock()
+			j.refreshing = false
+			j.refreshMu.Unlock()
+		}()
+		j.refresh(context.Background())
+	}()
+}
+
+func (j *jwksKeySet) refresh(ctx context.Context) {
+	j.mu.Lock()
+	// Another caller might have refreshed in the meantime
+	if !j.lastAttempt.IsZero() && time.Since(j.lastAttempt) < j.minRefreshInterval {
+		j.mu.Unlock()
+		return
+	}
+	j.lastAttempt = time.Now()
+	j.mu.Unlock()
+
+	keys, err := j.fetch(ctx)
+	if err != nil {
+		j.logger.Warn().Err(err).Msg("failed to refresh JWKS keys, will keep using previously cached keys")
+		return
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.lastFetch = time.Now()
+	j.mu.Unlock()
+	j.logger.Debug().Int("keys", len(keys)).Msg("refreshed JWKS keys")
+}
+
+func (j *jwksKeySet) fetch(ctx context.Context) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from JWKS endpoint", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var set jsonWebKeySet
+	if err := common.SonicCfg.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS response: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			j.logger.Debug().Err(err).Str("kid", jwk.Kid).Msg("skipping unsupported JWKS key")
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS endpoint returned no usable signing keys")
+	}
+
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URLInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeBase64URLInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported EC curve: %s", k.Crv)
+		}
+		x, err := decodeBase64URLInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := decodeBase64URLInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("EC point is not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+func decodeBase64URLInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("value is empty")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -1,6 +1,10 @@
 package auth
 
-import "github.com/erpc/erpc/common"
+import (
+	"crypto/x509"
+
+	"github.com/erpc/erpc/common"
+)
 
 type AuthPayload struct {
 	Method  string
@@ -9,6 +13,13 @@ type AuthPayload struct {
 	Jwt     *JwtPayload
 	Siwe    *SiwePayload
 	Network *NetworkPayload
+	// Mtls is set alongside other credentials whenever the client presented a TLS certificate
+	Mtls *MtlsPayload
+
+	// BatchSize is the number of requests in the batch this request belongs to (1 for single requests)
+	BatchSize int
+	// NetworkId is set when the network is known before authentication (e.g. from the URL path)
+	NetworkId string
 }
 
 type SecretPayload struct {
@@ -28,3 +39,11 @@ type NetworkPayload struct {
 	Address        string
 	ForwardProxies []string
 }
+
+type MtlsPayload struct {
+	// Certificates is the chain presented by the client, leaf first
+	Certificates []*x509.Certificate
+	// Identity of the leaf certificate (first URI SAN such as a SPIFFE id, otherwise the subject
+	// common name), only to be trusted once the chain is verified by the mtls strategy.
+	Identity string
+}
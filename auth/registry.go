@@ -18,7 +18,7 @@ type AuthRegistry struct {
 }
 
 // NewAuthRegistry creates a new group of authorizers for a project
-func NewAuthRegistry(logger *zerolog.Logger, projectId string, cfg *common.AuthConfig, rateLimitersRegistry *upstream.RateLimitersRegistry) (*AuthRegistry, error) {
+func NewAuthRegistry(appCtx context.Context, logger *zerolog.Logger, projectId string, cfg *common.AuthConfig, rateLimitersRegistry *upstream.RateLimitersRegistry) (*AuthRegistry, error) {
 	if cfg == nil {
 		return nil, common.NewErrInvalidConfig("auth config is nil")
 	}
@@ -31,7 +31,7 @@ func NewAuthRegistry(logger *zerolog.Logger, projectId string, cfg *common.AuthC
 
 	for _, strategy := range cfg.Strategies {
 		lg := logger.With().Str("strategy", string(strategy.Type)).Logger()
-		az, err := NewAuthorizer(&lg, projectId, strategy, rateLimitersRegistry)
+		az, err := NewAuthorizer(appCtx, &lg, projectId, strategy, rateLimitersRegistry)
 		if err != nil {
 			return nil, common.NewErrInvalidConfig(fmt.Sprintf("failed to create authorizer for project %s with strategy %s: %v", projectId, strategy.Type, err))
 		}
@@ -41,15 +41,16 @@ func NewAuthRegistry(logger *zerolog.Logger, projectId string, cfg *common.AuthC
 	return r, nil
 }
 
-// Authenticate checks the authentication payload against all registered strategies
-func (r *AuthRegistry) Authenticate(ctx context.Context, method string, ap *AuthPayload) error {
+// Authenticate checks the authentication payload against all registered strategies, and returns
+// the grant (budget, networks, methods and batch size) resolved for the authenticated consumer
+func (r *AuthRegistry) Authenticate(ctx context.Context, method string, ap *AuthPayload) (*Grant, error) {
 	if ap == nil {
-		return common.NewErrAuthUnauthorized("", "auth payload is nil")
+		return nil, common.NewErrAuthUnauthorized("", "auth payload is nil")
 	}
 
 	if len(r.strategies) == 0 {
 		// If no strategies are configured, allow all requests
-		return nil
+		return nil, nil
 	}
 
 	var errs []error
@@ -63,28 +64,37 @@ func (r *AuthRegistry) Authenticate(ctx context.Context, method string, ap *Auth
 			continue
 		}
 
-		if err := az.strategy.Authenticate(ctx, ap); err != nil {
+		claims, err := az.strategy.Authenticate(ctx, ap)
+		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
 
+		grant, err := resolveGrant(az.cfg, claims)
+		if err != nil {
+			return nil, err
+		}
+		if err := grant.Check(ap); err != nil {
+			return nil, err
+		}
+
 		// If authentication is passed then apply and consume the rate limit
-		if err := az.acquireRateLimitPermit(method); err != nil {
-			return err
+		if err := az.acquireRateLimitPermit(method, grant.RateLimitBudget); err != nil {
+			return nil, err
 		}
 
 		// If a strategy succeeds, we consider the request authenticated
-		return nil
+		return grant, nil
 	}
 
 	if len(errs) == 1 {
-		return errs[0]
+		return nil, errs[0]
 	}
 
 	if len(errs) == 0 {
-		return common.NewErrAuthUnauthorized("", "no auth strategy matched make sure correct headers or query strings are provided")
+		return nil, common.NewErrAuthUnauthorized("", "no auth strategy matched make sure correct headers or query strings are provided")
 	}
 
 	// If no strategy matched or succeeded, consider the request unauthorized
-	return common.NewErrAuthUnauthorized("", errors.Join(errs...).Error())
+	return nil, common.NewErrAuthUnauthorized("", errors.Join(errs...).Error())
 }
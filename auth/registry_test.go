@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/upstream"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJwtSecret = "test-hmac-secret"
+
+func newClaimsTestRegistry(t *testing.T) *AuthRegistry {
+	t.Helper()
+	logger := zerolog.Nop()
+	rlr, err := upstream.NewRateLimitersRegistry(&common.RateLimiterConfig{
+		Budgets: []*common.RateLimitBudgetConfig{
+			{Id: "free-budget", Rules: []*common.RateLimitRuleConfig{{Method: "*", MaxCount: 2, Period: common.Duration(time.Minute)}}},
+			{Id: "pro-budget", Rules: []*common.RateLimitRuleConfig{{Method: "*", MaxCount: 1000, Period: common.Duration(time.Minute)}}},
+		},
+	}, &logger)
+	require.NoError(t, err)
+
+	cfg := &common.AuthConfig{
+		Strategies: []*common.AuthStrategyConfig{
+			{
+				Type: common.AuthTypeJwt,
+				Jwt: &common.JwtStrategyConfig{
+					VerificationKeys: map[string]string{"hmac": testJwtSecret},
+				},
+				Authorization: &common.ClaimsAuthorizationConfig{
+					TierClaim: "plan",
+					Tiers: map[string]*common.AuthorizationTierConfig{
+						"free": {
+							RateLimitBudget: "free-budget",
+							AllowedNetworks: []string{"1", "evm:10"},
+							AllowedMethods:  []string{"eth_getBalance", "eth_blockNumber"},
+							MaxBatchSize:    2,
+						},
+						"pro": {
+							RateLimitBudget: "pro-budget",
+						},
+					},
+					AllowedNetworksClaim: "networks",
+					MaxBatchSizeClaim:    "maxBatch",
+				},
+			},
+		},
+	}
+	require.NoError(t, cfg.SetDefaults())
+	for _, s := range cfg.Strategies {
+		require.NoError(t, s.Validate())
+	}
+	r, err := NewAuthRegistry(context.Background(), &logger, "test", cfg, rlr)
+	require.NoError(t, err)
+	return r
+}
+
+func newHmacPayload(t *testing.T, method string, batchSize int, claims jwt.MapClaims) *AuthPayload {
+	t.Helper()
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJwtSecret))
+	require.NoError(t, err)
+	return &AuthPayload{
+		Method:    method,
+		Type:      common.AuthTypeJwt,
+		Jwt:       &JwtPayload{Token: signed},
+		BatchSize: batchSize,
+	}
+}
+
+func TestAuthRegistry_ClaimsAuthorization(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("TierFromLookupTable", func(t *testing.T) {
+		r := newClaimsTestRegistry(t)
+
+		grant, err := r.Authenticate(ctx, "eth_getBalance", newHmacPayload(t, "eth_getBalance", 1, jwt.MapClaims{"plan": "free"}))
+		require.NoError(t, err)
+		assert.Equal(t, "free-budget", grant.RateLimitBudget)
+		assert.Equal(t, 2, grant.MaxBatchSize)
+		assert.NoError(t, grant.CheckNetwork("evm:1"))
+		assert.NoError(t, grant.CheckNetwork("evm:10"))
+		assert.True(t, common.HasErrorCode(grant.CheckNetwork("evm:42161"), common.ErrCodeAuthForbidden))
+
+		grant, err = r.Authenticate(ctx, "debug_traceTransaction", newHmacPayload(t, "debug_traceTransaction", 1, jwt.MapClaims{"plan": "pro"}))
+		require.NoError(t, err)
+		assert.Equal(t, "pro-budget", grant.RateLimitBudget)
+		assert.NoError(t, grant.CheckNetwork("evm:42161"))
+	})
+
+	t.Run("RejectsMethodAndBatchSizeOutsideOfTier", func(t *testing.T) {
+		r := newClaimsTestRegistry(t)
+
+		_, err := r.Authenticate(ctx, "eth_getLogs", newHmacPayload(t, "eth_getLogs", 1, jwt.MapClaims{"plan": "free"}))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthForbidden), err)
+
+		_, err = r.Authenticate(ctx, "eth_blockNumber", newHmacPayload(t, "eth_blockNumber", 3, jwt.MapClaims{"plan": "free"}))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthForbidden), err)
+	})
+
+	t.Run("UnknownOrMissingTierIsUnauthorized", func(t *testing.T) {
+		r := newClaimsTestRegistry(t)
+
+		_, err := r.Authenticate(ctx, "eth_blockNumber", newHmacPayload(t, "eth_blockNumber", 1, jwt.MapClaims{"plan": "enterprise"}))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthUnauthorized), err)
+
+		_, err = r.Authenticate(ctx, "eth_blockNumber", newHmacPayload(t, "eth_blockNumber", 1, jwt.MapClaims{}))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthUnauthorized), err)
+	})
+
+	t.Run("ClaimsOverrideTier", func(t *testing.T) {
+		r := newClaimsTestRegistry(t)
+
+		grant, err := r.Authenticate(ctx, "eth_blockNumber", newHmacPayload(t, "eth_blockNumber", 5, jwt.MapClaims{
+			"plan":     "free",
+			"networks": []interface{}{"evm:137"},
+			"maxBatch": 10,
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, 10, grant.MaxBatchSize)
+		assert.NoError(t, grant.CheckNetwork("evm:137"))
+		assert.Error(t, grant.CheckNetwork("evm:1"))
+	})
+
+	t.Run("BudgetFromTierIsEnforced", func(t *testing.T) {
+		r := newClaimsTestRegistry(t)
+
+		for i := 0; i < 2; i++ {
+			_, err := r.Authenticate(ctx, "eth_blockNumber", newHmacPayload(t, "eth_blockNumber", 1, jwt.MapClaims{"plan": "free"}))
+			require.NoError(t, err)
+		}
+		_, err := r.Authenticate(ctx, "eth_blockNumber", newHmacPayload(t, "eth_blockNumber", 1, jwt.MapClaims{"plan": "free"}))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthRateLimitRuleExceeded), err)
+
+		_, err = r.Authenticate(ctx, "eth_blockNumber", newHmacPayload(t, "eth_blockNumber", 1, jwt.MapClaims{"plan": "pro"}))
+		assert.NoError(t, err)
+	})
+}
+
+func TestClaimStrings(t *testing.T) {
+	v, ok := claimStrings(map[string]interface{}{"scope": "eth_call eth_getBalance,eth_chainId"}, "scope")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"eth_call", "eth_getBalance", "eth_chainId"}, v)
+
+	v, ok = claimStrings(map[string]interface{}{"chains": []interface{}{float64(1), "evm:10"}}, "chains")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1", "evm:10"}, v)
+
+	_, ok = claimStrings(map[string]interface{}{}, "chains")
+	assert.False(t, ok)
+}
@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+)
+
+const secretKeysPartitionKey = "secret-keys"
+
+// SecretKey is a hashed secret key with its metadata, stored in a keys file or a connector table.
+type SecretKey struct {
+	// Hash is the hex encoded sha256 of the secret (optionally prefixed with "sha256:").
+	Hash            string     `yaml:"hash" json:"hash"`
+	Owner           string     `yaml:"owner" json:"owner"`
+	ExpiresAt       *time.Time `yaml:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	AllowedNetworks []string   `yaml:"allowedNetworks,omitempty" json:"allowedNetworks,omitempty"`
+	AllowedMethods  []string   `yaml:"allowedMethods,omitempty" json:"allowedMethods,omitempty"`
+	RateLimitBudget string     `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget,omitempty"`
+	Revoked         bool       `yaml:"revoked,omitempty" json:"revoked,omitempty"`
+}
+
+func (k *SecretKey) applyTo(grant *Grant) {
+	grant.Owner = k.Owner
+	if k.RateLimitBudget != "" {
+		grant.RateLimitBudget = k.RateLimitBudget
+	}
+	grant.AllowedNetworks = k.AllowedNetworks
+	grant.AllowedMethods = k.AllowedMethods
+}
+
+type secretKeysFile struct {
+	Keys []*SecretKey `yaml:"keys" json:"keys"`
+}
+
+// HashSecret returns the hex encoded sha256 of the secret, which is how keys are stored and looked up.
+func HashSecret(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+func normalizeKeyHash(hash string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(hash), "sha256:"))
+}
+
+type secretKeyStore interface {
+	// lookup returns nil (without error) when no key exists for the hash
+	lookup(ctx context.Context, hash string) (*SecretKey, error)
+}
+
+// fileSecretKeyStore loads all keys in memory and reloads the file when its modification time changes,
+// so that keys can be added or revoked without a restart.
+type fileSecretKeyStore struct {
+	path    string
+	logger  *zerolog.Logger
+	mu      sync.RWMutex
+	keys    map[string]*SecretKey
+	modTime time.Time
+}
+
+func newFileSecretKeyStore(appCtx context.Context, logger *zerolog.Logger, path string, interval time.Duration) (*fileSecretKeyStore, error) {
+	lg := logger.With().Str("keysFile", path).Logger()
+	s := &fileSecretKeyStore{
+		path:   path,
+		logger: &lg,
+		keys:   make(map[string]*SecretKey),
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-appCtx.Done():
+				return
+			case <-ticker.C:
+				if err := s.reload(); err != nil {
+					s.logger.Warn().Err(err).Msg("failed to reload secret keys file, will keep using previously loaded keys")
+				}
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+func (s *fileSecretKeyStore) reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat secret keys file: %w", err)
+	}
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read secret keys file: %w", err)
+	}
+	var file secretKeysFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return fmt.Errorf("failed to parse secret keys file: %w", err)
+	}
+	keys := make(map[string]*SecretKey, len(file.Keys))
+	for i, k := range file.Keys {
+		if k == nil || k.Hash == "" {
+			return fmt.Errorf("secret keys file entry #%d is missing the hash", i)
+		}
+		keys[normalizeKeyHash(k.Hash)] = k
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.modTime = info.ModTime()
+	s.mu.Unlock()
+	s.logger.Info().Int("keys", len(keys)).Msg("loaded secret keys from file")
+
+	return nil
+}
+
+func (s *fileSecretKeyStore) lookup(_ context.Context, hash string) (*SecretKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys[hash], nil
+}
+
+type cachedSecretKey struct {
+	key      *SecretKey
+	cachedAt time.Time
+}
+
+// connectorSecretKeyStore looks up keys by hash from a connector (e.g. a shared redis or postgres table),
+// found keys are cached for the ttl which is the max delay for a revocation to take effect.
+type connectorSecretKeyStore struct {
+	connector data.Connector
+	logger    *zerolog.Logger
+	ttl       time.Duration
+	mu        sync.RWMutex
+	cache     map[string]*cachedSecretKey
+}
+
+func newConnectorSecretKeyStore(appCtx context.Context, logger *zerolog.Logger, cfg *common.ConnectorConfig, ttl time.Duration) (*connectorSecretKeyStore, error) {
+	lg := logger.With().Str("connectorId", cfg.Id).Logger()
+	connector, err := data.NewConnector(appCtx, &lg, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret keys connector: %w", err)
+	}
+	return newConnectorSecretKeyStoreWithConnector(&lg, connector, ttl), nil
+}
+
+func newConnectorSecretKeyStoreWithConnector(logger *zerolog.Logger, connector data.Connector, ttl time.Duration) *connectorSecretKeyStore {
+	return &connectorSecretKeyStore{
+		connector: connector,
+		logger:    logger,
+		ttl:       ttl,
+		cache:     make(map[string]*cachedSecretKey),
+	}
+}
+
+func (s *connectorSecretKeyStore) lookup(ctx context.Context, hash string) (*SecretKey, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[hash]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.cachedAt) < s.ttl {
+		return cached.key, nil
+	}
+
+	raw, err := s.connector.Get(ctx, data.ConnectorMainIndex, secretKeysPartitionKey, hash)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+			// Key was removed since it was cached
+			if ok {
+				s.mu.Lock()
+				delete(s.cache, hash)
+				s.mu.Unlock()
+			}
+			return nil, nil
+		}
+		if ok {
+			s.logger.Warn().Err(err).Msg("failed to refresh secret key from connector, will keep using the cached key")
+			return cached.key, nil
+		}
+		return nil, err
+	}
+
+	key := &SecretKey{}
+	if err := common.SonicCfg.Unmarshal(raw, key); err != nil {
+		return nil, fmt.Errorf("failed to parse secret key record: %w", err)
+	}
+
+	// Negative lookups are not cached so that random secrets cannot grow the cache
+	s.mu.Lock()
+	s.cache[hash] = &cachedSecretKey{key: key, cachedAt: time.Now()}
+	s.mu.Unlock()
+
+	return key, nil
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSecretKeysFile(t *testing.T, path string, content string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func newSecretKeysTestRegistry(t *testing.T, cfg *common.SecretStrategyConfig) *AuthRegistry {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	logger := zerolog.Nop()
+	authCfg := &common.AuthConfig{
+		Strategies: []*common.AuthStrategyConfig{{Type: common.AuthTypeSecret, Secret: cfg}},
+	}
+	require.NoError(t, authCfg.SetDefaults())
+	require.NoError(t, authCfg.Strategies[0].Validate())
+	r, err := NewAuthRegistry(ctx, &logger, "test", authCfg, nil)
+	require.NoError(t, err)
+	return r
+}
+
+func secretPayload(method, secret string) *AuthPayload {
+	return &AuthPayload{
+		Method: method,
+		Type:   common.AuthTypeSecret,
+		Secret: &SecretPayload{Value: secret},
+	}
+}
+
+func TestSecretStrategy_KeysFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	writeSecretKeysFile(t, path, `
+keys:
+  - hash: "sha256:`+HashSecret("alice-secret")+`"
+    owner: alice
+    allowedNetworks: ["evm:1"]
+    allowedMethods: ["eth_*"]
+  - hash: "`+HashSecret("bob-secret")+`"
+    owner: bob
+    expiresAt: "2020-01-01T00:00:00Z"
+  - hash: "`+HashSecret("carol-secret")+`"
+    owner: carol
+    revoked: true
+`, time.Now().Add(-time.Minute))
+
+	r := newSecretKeysTestRegistry(t, &common.SecretStrategyConfig{
+		Value:               "legacy-secret",
+		KeysFile:            path,
+		KeysRefreshInterval: common.Duration(20 * time.Millisecond),
+	})
+
+	t.Run("AcceptsKeyAndAttachesMetadata", func(t *testing.T) {
+		grant, err := r.Authenticate(ctx, "eth_call", secretPayload("eth_call", "alice-secret"))
+		require.NoError(t, err)
+		assert.Equal(t, "alice", grant.Owner)
+		assert.NoError(t, grant.CheckNetwork("evm:1"))
+		assert.Error(t, grant.CheckNetwork("evm:10"))
+
+		_, err = r.Authenticate(ctx, "debug_traceTransaction", secretPayload("debug_traceTransaction", "alice-secret"))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthForbidden), err)
+	})
+
+	t.Run("RejectsExpiredRevokedAndUnknownKeys", func(t *testing.T) {
+		for _, secret := range []string{"bob-secret", "carol-secret", "unknown-secret"} {
+			_, err := r.Authenticate(ctx, "eth_call", secretPayload("eth_call", secret))
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthUnauthorized), secret)
+		}
+	})
+
+	t.Run("StaticValueStillWorks", func(t *testing.T) {
+		grant, err := r.Authenticate(ctx, "eth_call", secretPayload("eth_call", "legacy-secret"))
+		require.NoError(t, err)
+		assert.Empty(t, grant.Owner)
+	})
+
+	t.Run("RevokesKeyWhenFileChanges", func(t *testing.T) {
+		writeSecretKeysFile(t, path, `
+keys:
+  - hash: "`+HashSecret("alice-secret")+`"
+    owner: alice
+    revoked: true
+`, time.Now())
+
+		assert.Eventually(t, func() bool {
+			_, err := r.Authenticate(ctx, "eth_call", secretPayload("eth_call", "alice-secret"))
+			return common.HasErrorCode(err, common.ErrCodeAuthUnauthorized)
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestConnectorSecretKeyStore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := zerolog.Nop()
+	connector, err := data.NewMemoryConnector(ctx, &logger, "secret-keys", &common.MemoryConnectorConfig{
+		MaxItems: 100, MaxTotalSize: "1MB",
+	})
+	require.NoError(t, err)
+	store := newConnectorSecretKeyStoreWithConnector(&logger, connector, 50*time.Millisecond)
+	hash := HashSecret("dave-secret")
+
+	key, err := store.lookup(ctx, hash)
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	require.NoError(t, connector.Set(ctx, secretKeysPartitionKey, hash, []byte(`{"hash":"`+hash+`","owner":"dave","rateLimitBudget":"dave-budget"}`), nil))
+	require.Eventually(t, func() bool {
+		key, err := store.lookup(ctx, hash)
+		return err == nil && key != nil && key.Owner == "dave"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Revocation takes effect once the cached key expires
+	require.NoError(t, connector.Set(ctx, secretKeysPartitionKey, hash, []byte(`{"hash":"`+hash+`","owner":"dave","revoked":true}`), nil))
+	key, err = store.lookup(ctx, hash)
+	require.NoError(t, err)
+	assert.False(t, key.Revoked)
+	assert.Eventually(t, func() bool {
+		key, err := store.lookup(ctx, hash)
+		return err == nil && key != nil && key.Revoked
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -4,5 +4,6 @@ import "context"
 
 type AuthStrategy interface {
 	Supports(ap *AuthPayload) bool
-	Authenticate(ctx context.Context, ap *AuthPayload) error
+	// Authenticate verifies the payload and returns verified claims of the consumer (if the strategy has any)
+	Authenticate(ctx context.Context, ap *AuthPayload) (map[string]interface{}, error)
 }
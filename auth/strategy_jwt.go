@@ -8,20 +8,23 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/erpc/erpc/common"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
 )
 
 type JwtStrategy struct {
-	cfg    *common.JwtStrategyConfig
-	parser *jwt.Parser
-	keys   map[string]jwt.Keyfunc
+	cfg     *common.JwtStrategyConfig
+	parser  *jwt.Parser
+	keys    map[string]jwt.Keyfunc
+	jwksSet []*jwksKeySet
 }
 
 var _ AuthStrategy = &JwtStrategy{}
 
-func NewJwtStrategy(cfg *common.JwtStrategyConfig) (*JwtStrategy, error) {
+func NewJwtStrategy(logger *zerolog.Logger, cfg *common.JwtStrategyConfig) (*JwtStrategy, error) {
 	// Parse and store verification keys
 	var keys map[string]jwt.Keyfunc = make(map[string]jwt.Keyfunc)
 	for kid, keyData := range cfg.VerificationKeys {
@@ -34,10 +37,20 @@ func NewJwtStrategy(cfg *common.JwtStrategyConfig) (*JwtStrategy, error) {
 		}
 	}
 
+	// Initial fetch failures are not fatal so that an unreachable IdP does not prevent startup,
+	// keys will be fetched again on the next token carrying an unknown kid.
+	jwksSet := make([]*jwksKeySet, 0, len(cfg.JwksUrls))
+	for _, url := range cfg.JwksUrls {
+		ks := newJwksKeySet(logger, url, time.Duration(cfg.JwksRefreshInterval), time.Duration(cfg.JwksMinRefreshInterval))
+		ks.refresh(context.Background())
+		jwksSet = append(jwksSet, ks)
+	}
+
 	return &JwtStrategy{
-		cfg:    cfg,
-		parser: jwt.NewParser(jwt.WithoutClaimsValidation()),
-		keys:   keys,
+		cfg:     cfg,
+		parser:  jwt.NewParser(jwt.WithoutClaimsValidation()),
+		keys:    keys,
+		jwksSet: jwksSet,
 	}, nil
 }
 
@@ -45,46 +58,51 @@ func (s *JwtStrategy) Supports(ap *AuthPayload) bool {
 	return ap.Type == common.AuthTypeJwt
 }
 
-func (s *JwtStrategy) Authenticate(ctx context.Context, ap *AuthPayload) error {
+func (s *JwtStrategy) Authenticate(ctx context.Context, ap *AuthPayload) (map[string]interface{}, error) {
 	token, _, err := s.parser.ParseUnverified(ap.Jwt.Token, jwt.MapClaims{})
 	if err != nil {
-		return common.NewErrAuthUnauthorized("jwt", "failed to parse JWT")
+		return nil, common.NewErrAuthUnauthorized("jwt", "failed to parse JWT")
 	}
 
 	if len(s.cfg.AllowedAlgorithms) > 0 {
 		if !contains(s.cfg.AllowedAlgorithms, token.Method.Alg()) {
-			return common.NewErrAuthUnauthorized("jwt", "unexpected signing method")
+			return nil, common.NewErrAuthUnauthorized("jwt", "unexpected signing method")
 		}
 	}
 
-	key, err := s.findVerificationKey(token)
+	key, err := s.findVerificationKey(ctx, token)
 	if err != nil {
-		return common.NewErrAuthUnauthorized("jwt", err.Error())
+		return nil, common.NewErrAuthUnauthorized("jwt", err.Error())
 	}
 
 	// Verify the signature
 	if _, err := jwt.Parse(ap.Jwt.Token, key); err != nil {
-		return common.NewErrAuthUnauthorized("jwt", "invalid signature")
+		return nil, common.NewErrAuthUnauthorized("jwt", "invalid signature")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return common.NewErrAuthUnauthorized("jwt", "invalid JWT claims")
+		return nil, common.NewErrAuthUnauthorized("jwt", "invalid JWT claims")
 	}
 
 	if err := s.validateClaims(claims); err != nil {
-		return common.NewErrAuthUnauthorized("jwt", err.Error())
+		return nil, common.NewErrAuthUnauthorized("jwt", err.Error())
 	}
 
-	return nil
+	return claims, nil
 }
 
-func (s *JwtStrategy) findVerificationKey(token *jwt.Token) (jwt.Keyfunc, error) {
+func (s *JwtStrategy) findVerificationKey(ctx context.Context, token *jwt.Token) (jwt.Keyfunc, error) {
 	kid, ok := token.Header["kid"].(string)
 	if ok {
 		if key, exists := s.keys[kid]; exists {
 			return key, nil
 		}
+		for _, ks := range s.jwksSet {
+			if key, exists := ks.lookup(ctx, kid); exists {
+				return staticKeyfunc(key), nil
+			}
+		}
 	}
 
 	// If no kid is provided or the kid doesn't match, try all keys
@@ -93,6 +111,15 @@ func (s *JwtStrategy) findVerificationKey(token *jwt.Token) (jwt.Keyfunc, error)
 			return key, nil
 		}
 	}
+	if !ok {
+		for _, ks := range s.jwksSet {
+			for _, key := range ks.all() {
+				if keyFn := staticKeyfunc(key); isCompatibleKeyType(keyFn, token.Method) {
+					return keyFn, nil
+				}
+			}
+		}
+	}
 
 	return nil, fmt.Errorf("no suitable verification key found")
 }
@@ -161,6 +188,12 @@ func parseKey(keyData interface{}) (interface{}, error) {
 	}
 }
 
+func staticKeyfunc(key interface{}) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		return key, nil
+	}
+}
+
 func isCompatibleKeyType(keyFn jwt.Keyfunc, method jwt.SigningMethod) bool {
 	key, err := keyFn(nil)
 	if err != nil {
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	keys     []map[string]string
+	down     atomic.Bool
+	requests atomic.Int32
+}
+
+func newFakeJwksServer(t *testing.T) *fakeJwksServer {
+	t.Helper()
+	s := &fakeJwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if s.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeJwksServer) setRSAKeys(keys map[string]*rsa.PrivateKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = nil
+	for kid, k := range keys {
+		s.keys = append(s.keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		})
+	}
+}
+
+func newJwksTestStrategy(t *testing.T, url string) *JwtStrategy {
+	t.Helper()
+	cfg := &common.JwtStrategyConfig{JwksUrls: []string{url}}
+	require.NoError(t, cfg.SetDefaults())
+	require.NoError(t, cfg.Validate())
+	logger := zerolog.Nop()
+	s, err := NewJwtStrategy(&logger, cfg)
+	require.NoError(t, err)
+	return s
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) *AuthPayload {
+	t.Helper()
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return &AuthPayload{Type: common.AuthTypeJwt, Jwt: &JwtPayload{Token: signed}}
+}
+
+func authenticateJwt(ctx context.Context, s *JwtStrategy, ap *AuthPayload) error {
+	_, err := s.Authenticate(ctx, ap)
+	return err
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return k
+}
+
+func TestJwtStrategy_Jwks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("VerifiesTokenByKid", func(t *testing.T) {
+		srv := newFakeJwksServer(t)
+		k1, k2 := generateRSAKey(t), generateRSAKey(t)
+		srv.setRSAKeys(map[string]*rsa.PrivateKey{"kid-1": k1, "kid-2": k2})
+		s := newJwksTestStrategy(t, srv.URL)
+
+		assert.NoError(t, authenticateJwt(ctx, s, signTestToken(t, jwt.SigningMethodRS256, "kid-1", k1)))
+		assert.NoError(t, authenticateJwt(ctx, s, signTestToken(t, jwt.SigningMethodRS256, "kid-2", k2)))
+		assert.Error(t, authenticateJwt(ctx, s, signTestToken(t, jwt.SigningMethodRS256, "kid-1", k2)))
+		assert.Equal(t, int32(1), srv.requests.Load())
+	})
+
+	t.Run("RefreshesOnUnknownKidAfterRotation", func(t *testing.T) {
+		srv := newFakeJwksServer(t)
+		k1, k2 := generateRSAKey(t), generateRSAKey(t)
+		srv.setRSAKeys(map[string]*rsa.PrivateKey{"kid-1": k1})
+		s := newJwksTestStrategy(t, srv.URL)
+		s.jwksSet[0].minRefreshInterval = 0
+
+		srv.setRSAKeys(map[string]*rsa.PrivateKey{"kid-2": k2})
+		assert.NoError(t, authenticateJwt(ctx, s, signTestToken(t, jwt.SigningMethodRS256, "kid-2", k2)))
+		assert.Equal(t, int32(2), srv.requests.Load())
+	})
+
+	t.Run("RateLimitsRefreshesOnUnknownKid", func(t *testing.T) {
+		srv := newFakeJwksServer(t)
+		k1, unknown := generateRSAKey(t), generateRSAKey(t)
+		srv.setRSAKeys(map[string]*rsa.PrivateKey{"kid-1": k1})
+		s := newJwksTestStrategy(t, srv.URL)
+
+		for i := 0; i < 10; i++ {
+			assert.Error(t, authenticateJwt(ctx, s, signTestToken(t, jwt.SigningMethodRS256, "kid-unknown", unknown)))
+		}
+		assert.Equal(t, int32(1), srv.requests.Load())
+	})
+
+	t.Run("KeepsCachedKeysWhenIdpIsUnreachable", func(t *testing.T) {
+		srv := newFakeJwksServer(t)
+		k1 := generateRSAKey(t)
+		srv.setRSAKeys(map[string]*rsa.PrivateKey{"kid-1": k1})
+		s := newJwksTestStrategy(t, srv.URL)
+		ks := s.jwksSet[0]
+		ks.refreshInterval = 0
+		ks.minRefreshInterval = 0
+
+		srv.down.Store(true)
+		ks.refresh(ctx)
+		assert.Equal(t, int32(2), srv.requests.Load())
+		assert.NoError(t, authenticateJwt(ctx, s, signTestToken(t, jwt.SigningMethodRS256, "kid-1", k1)))
+		assert.NoError(t, authenticateJwt(ctx, s, signTestToken(t, jwt.SigningMethodRS256, "", k1)))
+	})
+
+	t.Run("StartsWhenIdpIsUnreachable", func(t *testing.T) {
+		srv := newFakeJwksServer(t)
+		k1 := generateRSAKey(t)
+		srv.setRSAKeys(map[string]*rsa.PrivateKey{"kid-1": k1})
+		srv.down.Store(true)
+		s := newJwksTestStrategy(t, srv.URL)
+		s.jwksSet[0].minRefreshInterval = 0
+		assert.Error(t, authenticateJwt(ctx, s, signTestToken(t, jwt.SigningMethodRS256, "kid-1", k1)))
+
+		srv.down.Store(false)
+		assert.NoError(t, authenticateJwt(ctx, s, signTestToken(t, jwt.SigningMethodRS256, "kid-1", k1)))
+	})
+}
+
+func TestJsonWebKey_PublicKey(t *testing.T) {
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk := &jsonWebKey{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(ec.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(ec.Y.Bytes()),
+	}
+	key, err := jwk.publicKey()
+	require.NoError(t, err)
+	assert.True(t, ec.PublicKey.Equal(key))
+
+	jwk.Crv = "P-192"
+	_, err = jwk.publicKey()
+	assert.Error(t, err)
+
+	_, err = (&jsonWebKey{Kty: "oct"}).publicKey()
+	assert.Error(t, err)
+}
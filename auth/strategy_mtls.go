@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/erpc/erpc/common"
+)
+
+type MtlsStrategy struct {
+	cfg   *common.MtlsStrategyConfig
+	roots *x509.CertPool
+}
+
+var _ AuthStrategy = &MtlsStrategy{}
+
+func NewMtlsStrategy(cfg *common.MtlsStrategyConfig) (*MtlsStrategy, error) {
+	roots := x509.NewCertPool()
+	for _, path := range cfg.CAFiles {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %w", path, err)
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse any CA certificate from %s", path)
+		}
+	}
+
+	return &MtlsStrategy{
+		cfg:   cfg,
+		roots: roots,
+	}, nil
+}
+
+func (s *MtlsStrategy) Supports(ap *AuthPayload) bool {
+	return ap.Mtls != nil && len(ap.Mtls.Certificates) > 0
+}
+
+func (s *MtlsStrategy) Authenticate(ctx context.Context, ap *AuthPayload) (map[string]interface{}, error) {
+	leaf := ap.Mtls.Certificates[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range ap.Mtls.Certificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	// The server only requests client certificates (to keep other strategies usable on the same
+	// listener), so the chain must be verified here against the CAs of this strategy.
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         s.roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil, common.NewErrAuthUnauthorized("mtls", fmt.Sprintf("client certificate verification failed: %s", err))
+	}
+
+	identity := ap.Mtls.Identity
+	if identity == "" {
+		identity = certificateIdentity(leaf)
+	}
+
+	if len(s.cfg.Rules) == 0 {
+		return map[string]interface{}{grantOverrideClaim: &mtlsMatch{identity: identity}}, nil
+	}
+	for _, rule := range s.cfg.Rules {
+		if matchesMtlsRule(rule, leaf) {
+			return map[string]interface{}{grantOverrideClaim: &mtlsMatch{identity: identity, rule: rule}}, nil
+		}
+	}
+
+	return nil, common.NewErrAuthUnauthorized("mtls", fmt.Sprintf("client certificate %s does not match any rule", identity))
+}
+
+// mtlsMatch carries the verified certificate identity (used as grant owner for logs and metrics)
+// and the matched rule into the grant.
+type mtlsMatch struct {
+	identity string
+	rule     *common.MtlsRuleConfig
+}
+
+func (m *mtlsMatch) applyTo(grant *Grant) {
+	grant.Owner = m.identity
+	if m.rule == nil {
+		return
+	}
+	if m.rule.RateLimitBudget != "" {
+		grant.RateLimitBudget = m.rule.RateLimitBudget
+	}
+	grant.AllowedNetworks = m.rule.AllowedNetworks
+	grant.AllowedMethods = m.rule.AllowedMethods
+	grant.MaxBatchSize = m.rule.MaxBatchSize
+	grant.MaxBatchConcurrency = m.rule.MaxBatchConcurrency
+}
+
+// matchesMtlsRule requires both subject and san patterns to match when both are set.
+func matchesMtlsRule(rule *common.MtlsRuleConfig, cert *x509.Certificate) bool {
+	if rule.Subject != "" {
+		if match, err := common.WildcardMatch(rule.Subject, cert.Subject.CommonName); err != nil || !match {
+			return false
+		}
+	}
+	if rule.San != "" {
+		for _, san := range certificateSans(cert) {
+			if match, err := common.WildcardMatch(rule.San, san); err == nil && match {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func certificateSans(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.URIs)+len(cert.DNSNames)+len(cert.EmailAddresses))
+	for _, u := range cert.URIs {
+		sans = append(sans, u.String())
+	}
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	return sans
+}
+
+// certificateIdentity prefers the first URI SAN (e.g. spiffe://cluster/ns/svc) which is how most
+// service meshes identify workloads, then the subject common name, then the first DNS SAN.
+func certificateIdentity(cert *x509.Certificate) string {
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return cert.SerialNumber.String()
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) writePem(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), 0600))
+	return path
+}
+
+func (ca *testCA) issue(t *testing.T, cn string, uri string, usage x509.ExtKeyUsage) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	if uri != "" {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		tpl.URIs = []*url.URL{u}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func newMtlsTestRegistry(t *testing.T, cfg *common.MtlsStrategyConfig) *AuthRegistry {
+	t.Helper()
+	logger := zerolog.Nop()
+	authCfg := &common.AuthConfig{
+		Strategies: []*common.AuthStrategyConfig{{Type: common.AuthTypeMtls, Mtls: cfg}},
+	}
+	require.NoError(t, authCfg.SetDefaults())
+	require.NoError(t, authCfg.Strategies[0].Validate())
+	r, err := NewAuthRegistry(context.Background(), &logger, "test", authCfg, nil)
+	require.NoError(t, err)
+	return r
+}
+
+func mtlsPayload(t *testing.T, method string, certs ...*x509.Certificate) *AuthPayload {
+	t.Helper()
+	ap, err := NewPayloadFromHttp(method, "10.0.0.1:1234", http.Header{}, url.Values{}, &tls.ConnectionState{PeerCertificates: certs})
+	require.NoError(t, err)
+	return ap
+}
+
+func TestMtlsStrategy(t *testing.T) {
+	ctx := context.Background()
+	ca := newTestCA(t, "internal-ca")
+	otherCa := newTestCA(t, "other-ca")
+
+	r := newMtlsTestRegistry(t, &common.MtlsStrategyConfig{
+		CAFiles: []string{ca.writePem(t)},
+		Rules: []*common.MtlsRuleConfig{
+			{
+				San:            "spiffe://prod/ns/indexer/*",
+				AllowedMethods: []string{"eth_getLogs"},
+			},
+			{
+				Subject:         "*.billing.internal",
+				AllowedNetworks: []string{"evm:1"},
+			},
+		},
+	})
+
+	t.Run("MatchesSanRuleAndAttachesIdentity", func(t *testing.T) {
+		ap := mtlsPayload(t, "eth_getLogs", ca.issue(t, "indexer", "spiffe://prod/ns/indexer/sa/worker", x509.ExtKeyUsageClientAuth))
+		assert.Equal(t, "spiffe://prod/ns/indexer/sa/worker", ap.Mtls.Identity)
+		assert.Equal(t, common.AuthTypeNetwork, ap.Type)
+
+		grant, err := r.Authenticate(ctx, "eth_getLogs", ap)
+		require.NoError(t, err)
+		assert.Equal(t, "spiffe://prod/ns/indexer/sa/worker", grant.Owner)
+
+		_, err = r.Authenticate(ctx, "eth_sendRawTransaction", mtlsPayload(t, "eth_sendRawTransaction", ca.issue(t, "indexer", "spiffe://prod/ns/indexer/sa/worker", x509.ExtKeyUsageClientAuth)))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthForbidden), err)
+	})
+
+	t.Run("MatchesSubjectRule", func(t *testing.T) {
+		grant, err := r.Authenticate(ctx, "eth_call", mtlsPayload(t, "eth_call", ca.issue(t, "api.billing.internal", "", x509.ExtKeyUsageClientAuth)))
+		require.NoError(t, err)
+		assert.Equal(t, "api.billing.internal", grant.Owner)
+		assert.Error(t, grant.CheckNetwork("evm:10"))
+	})
+
+	t.Run("RejectsUnmatchedUntrustedAndServerCertificates", func(t *testing.T) {
+		for name, cert := range map[string]*x509.Certificate{
+			"unmatched": ca.issue(t, "api.payments.internal", "", x509.ExtKeyUsageClientAuth),
+			"untrusted": otherCa.issue(t, "api.billing.internal", "", x509.ExtKeyUsageClientAuth),
+			"server":    ca.issue(t, "api.billing.internal", "", x509.ExtKeyUsageServerAuth),
+		} {
+			_, err := r.Authenticate(ctx, "eth_call", mtlsPayload(t, "eth_call", cert))
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthUnauthorized), name)
+		}
+	})
+
+	t.Run("RequiresClientCertificate", func(t *testing.T) {
+		ap, err := NewPayloadFromHttp("eth_call", "10.0.0.1:1234", http.Header{}, url.Values{}, nil)
+		require.NoError(t, err)
+		_, err = r.Authenticate(ctx, "eth_call", ap)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthUnauthorized), err)
+	})
+
+	t.Run("AcceptsAnyCertificateOfCaWithoutRules", func(t *testing.T) {
+		r := newMtlsTestRegistry(t, &common.MtlsStrategyConfig{CAFiles: []string{ca.writePem(t)}})
+		grant, err := r.Authenticate(ctx, "eth_call", mtlsPayload(t, "eth_call", ca.issue(t, "anything", "", x509.ExtKeyUsageClientAuth)))
+		require.NoError(t, err)
+		assert.Equal(t, "anything", grant.Owner)
+	})
+}
@@ -2,28 +2,79 @@ package auth
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
+	"github.com/rs/zerolog"
 )
 
 type SecretStrategy struct {
-	cfg *common.SecretStrategyConfig
+	projectId string
+	cfg       *common.SecretStrategyConfig
+	stores    []secretKeyStore
 }
 
 var _ AuthStrategy = &SecretStrategy{}
 
-func NewSecretStrategy(cfg *common.SecretStrategyConfig) *SecretStrategy {
-	return &SecretStrategy{cfg: cfg}
+func NewSecretStrategy(appCtx context.Context, logger *zerolog.Logger, projectId string, cfg *common.SecretStrategyConfig) (*SecretStrategy, error) {
+	s := &SecretStrategy{projectId: projectId, cfg: cfg}
+
+	interval := time.Duration(cfg.KeysRefreshInterval)
+	if cfg.KeysFile != "" {
+		store, err := newFileSecretKeyStore(appCtx, logger, cfg.KeysFile, interval)
+		if err != nil {
+			return nil, err
+		}
+		s.stores = append(s.stores, store)
+	}
+	if cfg.KeysConnector != nil {
+		store, err := newConnectorSecretKeyStore(appCtx, logger, cfg.KeysConnector, interval)
+		if err != nil {
+			return nil, err
+		}
+		s.stores = append(s.stores, store)
+	}
+
+	return s, nil
 }
 
 func (s *SecretStrategy) Supports(ap *AuthPayload) bool {
 	return ap.Type == common.AuthTypeSecret
 }
 
-func (s *SecretStrategy) Authenticate(ctx context.Context, ap *AuthPayload) error {
-	if ap.Secret.Value != s.cfg.Value {
-		return common.NewErrAuthUnauthorized("secret", "invalid secret")
+func (s *SecretStrategy) Authenticate(ctx context.Context, ap *AuthPayload) (map[string]interface{}, error) {
+	if ap.Secret == nil {
+		return nil, common.NewErrAuthUnauthorized("secret", "missing secret payload")
+	}
+	if s.cfg.Value != "" && ap.Secret.Value == s.cfg.Value {
+		return nil, nil
+	}
+	if len(s.stores) == 0 {
+		return nil, common.NewErrAuthUnauthorized("secret", "invalid secret")
+	}
+
+	hash := HashSecret(ap.Secret.Value)
+	for _, store := range s.stores {
+		key, err := store.lookup(ctx, hash)
+		if err != nil {
+			return nil, common.NewErrAuthUnauthorized("secret", fmt.Sprintf("failed to look up secret key: %s", err))
+		}
+		if key == nil {
+			continue
+		}
+		if key.Revoked {
+			telemetry.MetricAuthSecretKeyRequestsTotal.WithLabelValues(s.projectId, key.Owner, "revoked").Inc()
+			return nil, common.NewErrAuthUnauthorized("secret", "secret key is revoked")
+		}
+		if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+			telemetry.MetricAuthSecretKeyRequestsTotal.WithLabelValues(s.projectId, key.Owner, "expired").Inc()
+			return nil, common.NewErrAuthUnauthorized("secret", "secret key is expired")
+		}
+		telemetry.MetricAuthSecretKeyRequestsTotal.WithLabelValues(s.projectId, key.Owner, "accepted").Inc()
+		return map[string]interface{}{grantOverrideClaim: key}, nil
 	}
 
-	return nil
+	return nil, common.NewErrAuthUnauthorized("secret", "invalid secret")
 }
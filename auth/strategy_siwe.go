@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/erpc/erpc/common"
 	"github.com/spruceid/siwe-go"
@@ -22,33 +23,54 @@ func (s *SiweStrategy) Supports(ap *AuthPayload) bool {
 	return ap.Type == common.AuthTypeSiwe
 }
 
-func (s *SiweStrategy) Authenticate(ctx context.Context, ap *AuthPayload) error {
+func (s *SiweStrategy) Authenticate(ctx context.Context, ap *AuthPayload) (map[string]interface{}, error) {
 	if ap.Siwe == nil {
-		return common.NewErrAuthUnauthorized("siwe", "missing SIWE payload")
+		return nil, common.NewErrAuthUnauthorized("siwe", "missing SIWE payload")
 	}
 
 	// Parse the SIWE message
 	message, err := siwe.ParseMessage(ap.Siwe.Message)
 	if err != nil {
-		return common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("failed to parse SIWE message: %s", err))
+		return nil, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("failed to parse SIWE message: %s", err))
 	}
 
 	// Verify the signature
 	if _, err := message.VerifyEIP191(ap.Siwe.Signature); err != nil {
-		return common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("failed to verify SIWE signature: %s", err))
+		return nil, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("failed to verify SIWE signature: %s", err))
 	}
 
 	// Check if the domain is allowed
 	if !s.isDomainAllowed(message.GetDomain()) {
-		return common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("domain %s is not allowed", message.GetDomain()))
+		return nil, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("domain %s is not allowed", message.GetDomain()))
 	}
 
 	// Verify the message is not expired
 	if ok, err := message.ValidNow(); !ok {
-		return common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("SIWE message expired: %s", err))
+		return nil, common.NewErrAuthUnauthorized("siwe", fmt.Sprintf("SIWE message expired: %s", err))
 	}
 
-	return nil
+	return siweClaims(message), nil
+}
+
+// siweClaims exposes verified fields of the SIWE message as claims, so that authorization can be
+// derived from them (e.g. a tier lookup table keyed by "address").
+func siweClaims(message *siwe.Message) map[string]interface{} {
+	uri := message.GetURI()
+	resources := make([]interface{}, 0, len(message.GetResources()))
+	for _, r := range message.GetResources() {
+		resources = append(resources, r.String())
+	}
+	claims := map[string]interface{}{
+		"address":   strings.ToLower(message.GetAddress().Hex()),
+		"chainId":   message.GetChainID(),
+		"domain":    message.GetDomain(),
+		"uri":       uri.String(),
+		"resources": resources,
+	}
+	if st := message.GetStatement(); st != nil {
+		claims["statement"] = *st
+	}
+	return claims
 }
 
 func (s *SiweStrategy) isDomainAllowed(domain string) bool {
@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
+	"github.com/rs/zerolog"
+)
+
+const webhookMaxCachedDecisions = 100_000
+
+// WebhookRequest is the body POSTed to the external authorization service.
+type WebhookRequest struct {
+	ProjectId string          `json:"projectId"`
+	Method    string          `json:"method"`
+	NetworkId string          `json:"networkId,omitempty"`
+	BatchSize int             `json:"batchSize,omitempty"`
+	Type      common.AuthType `json:"type"`
+	Secret    string          `json:"secret,omitempty"`
+	Jwt       string          `json:"jwt,omitempty"`
+	Siwe      *SiwePayload    `json:"siwe,omitempty"`
+	Network   *NetworkPayload `json:"network,omitempty"`
+}
+
+// WebhookDecision is the response of the external authorization service, optional fields override
+// the strategy config for the grant of this consumer.
+type WebhookDecision struct {
+	Allow               bool              `json:"allow"`
+	Reason              string            `json:"reason,omitempty"`
+	Owner               string            `json:"owner,omitempty"`
+	RateLimitBudget     string            `json:"rateLimitBudget,omitempty"`
+	AllowedNetworks     []string          `json:"allowedNetworks,omitempty"`
+	AllowedMethods      []string          `json:"allowedMethods,omitempty"`
+	MaxBatchSize        int               `json:"maxBatchSize,omitempty"`
+	MaxBatchConcurrency int               `json:"maxBatchConcurrency,omitempty"`
+	Metadata            map[string]string `json:"metadata,omitempty"`
+}
+
+func (d *WebhookDecision) applyTo(grant *Grant) {
+	grant.Owner = d.Owner
+	if d.RateLimitBudget != "" {
+		grant.RateLimitBudget = d.RateLimitBudget
+	}
+	grant.AllowedNetworks = d.AllowedNetworks
+	grant.AllowedMethods = d.AllowedMethods
+	grant.MaxBatchSize = d.MaxBatchSize
+	grant.MaxBatchConcurrency = d.MaxBatchConcurrency
+	grant.Metadata = d.Metadata
+}
+
+type cachedWebhookDecision struct {
+	decision  *WebhookDecision
+	expiresAt time.Time
+}
+
+type WebhookStrategy struct {
+	projectId string
+	logger    *zerolog.Logger
+	cfg       *common.WebhookStrategyConfig
+	client    *http.Client
+
+	mu    sync.RWMutex
+	cache map[string]*cachedWebhookDecision
+}
+
+var _ AuthStrategy = &WebhookStrategy{}
+
+func NewWebhookStrategy(logger *zerolog.Logger, projectId string, cfg *common.WebhookStrategyConfig) *WebhookStrategy {
+	return &WebhookStrategy{
+		projectId: projectId,
+		logger:    logger,
+		cfg:       cfg,
+		client:    &http.Client{Timeout: time.Duration(cfg.Timeout)},
+		cache:     make(map[string]*cachedWebhookDecision),
+	}
+}
+
+func (s *WebhookStrategy) Supports(ap *AuthPayload) bool {
+	if len(s.cfg.PayloadTypes) == 0 {
+		return true
+	}
+	for _, t := range s.cfg.PayloadTypes {
+		if t == ap.Type {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *WebhookStrategy) Authenticate(ctx context.Context, ap *AuthPayload) (map[string]interface{}, error) {
+	wr := s.buildRequest(ap)
+	cacheKey := s.cacheKey(wr)
+
+	decision, cached := s.cachedDecision(cacheKey)
+	if !cached {
+		var err error
+		decision, err = s.callService(ctx, wr)
+		if err != nil {
+			if s.cfg.FailOpen {
+				telemetry.MetricAuthWebhookDecisionsTotal.WithLabelValues(s.projectId, "failed_open", "service").Inc()
+				s.logger.Warn().Err(err).Str("method", ap.Method).Msg("authorization webhook failed, allowing request due to failOpen")
+				return nil, nil
+			}
+			telemetry.MetricAuthWebhookDecisionsTotal.WithLabelValues(s.projectId, "failed_closed", "service").Inc()
+			return nil, common.NewErrAuthUnauthorized("webhook", fmt.Sprintf("authorization service failed: %s", err))
+		}
+		s.storeDecision(cacheKey, decision)
+	}
+
+	source := "service"
+	if cached {
+		source = "cache"
+	}
+	if !decision.Allow {
+		telemetry.MetricAuthWebhookDecisionsTotal.WithLabelValues(s.projectId, "denied", source).Inc()
+		reason := decision.Reason
+		if reason == "" {
+			reason = "denied by authorization service"
+		}
+		return nil, common.NewErrAuthUnauthorized("webhook", reason)
+	}
+	telemetry.MetricAuthWebhookDecisionsTotal.WithLabelValues(s.projectId, "allowed", source).Inc()
+
+	return map[string]interface{}{grantOverrideClaim: decision}, nil
+}
+
+func (s *WebhookStrategy) buildRequest(ap *AuthPayload) *WebhookRequest {
+	wr := &WebhookRequest{
+		ProjectId: s.projectId,
+		Method:    ap.Method,
+		NetworkId: ap.NetworkId,
+		BatchSize: ap.BatchSize,
+		Type:      ap.Type,
+		Siwe:      ap.Siwe,
+		Network:   ap.Network,
+	}
+	if ap.Secret != nil {
+		wr.Secret = ap.Secret.Value
+	}
+	if ap.Jwt != nil {
+		wr.Jwt = ap.Jwt.Token
+	}
+	return wr
+}
+
+// cacheKey is a hash of the credential, method and network so that raw credentials are not kept in memory
+func (s *WebhookStrategy) cacheKey(wr *WebhookRequest) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d\x00%s\x00%s", wr.Type, wr.Method, wr.NetworkId, wr.BatchSize, wr.Secret, wr.Jwt)
+	if wr.Siwe != nil {
+		fmt.Fprintf(h, "\x00%s\x00%s", wr.Siwe.Message, wr.Siwe.Signature)
+	}
+	if wr.Network != nil {
+		fmt.Fprintf(h, "\x00%s\x00%v", wr.Network.Address, wr.Network.ForwardProxies)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *WebhookStrategy) cachedDecision(key string) (*WebhookDecision, bool) {
+	if s.cfg.CacheTtl == 0 {
+		return nil, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.decision, true
+}
+
+func (s *WebhookStrategy) storeDecision(key string, decision *WebhookDecision) {
+	if s.cfg.CacheTtl == 0 {
+		return
+	}
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= webhookMaxCachedDecisions {
+		for k, entry := range s.cache {
+			if now.After(entry.expiresAt) {
+				delete(s.cache, k)
+			}
+		}
+		// Still full of live decisions, start over rather than growing unbounded
+		if len(s.cache) >= webhookMaxCachedDecisions {
+			s.cache = make(map[string]*cachedWebhookDecision)
+		}
+	}
+	s.cache[key] = &cachedWebhookDecision{
+		decision:  decision,
+		expiresAt: now.Add(time.Duration(s.cfg.CacheTtl)),
+	}
+}
+
+func (s *WebhookStrategy) callService(ctx context.Context, wr *WebhookRequest) (*WebhookDecision, error) {
+	ctx, span := common.StartDetailSpan(ctx, "Auth.Webhook")
+	defer span.End()
+
+	body, err := common.SonicCfg.Marshal(wr)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		common.SetTraceSpanError(span, err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		common.SetTraceSpanError(span, err)
+		return nil, err
+	}
+
+	// Explicit 401/403 are treated as a deny decision, any other non-2xx status is a service failure
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		decision := &WebhookDecision{Allow: false}
+		_ = common.SonicCfg.Unmarshal(respBody, decision)
+		decision.Allow = false
+		return decision, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("unexpected status code %d from authorization service", resp.StatusCode)
+		common.SetTraceSpanError(span, err)
+		return nil, err
+	}
+
+	decision := &WebhookDecision{}
+	if err := common.SonicCfg.Unmarshal(respBody, decision); err != nil {
+		err = fmt.Errorf("failed to parse authorization service response: %w", err)
+		common.SetTraceSpanError(span, err)
+		return nil, err
+	}
+	return decision, nil
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebhookTestRegistry(t *testing.T, cfg *common.WebhookStrategyConfig) *AuthRegistry {
+	t.Helper()
+	logger := zerolog.Nop()
+	authCfg := &common.AuthConfig{
+		Strategies: []*common.AuthStrategyConfig{{Type: common.AuthTypeWebhook, Webhook: cfg}},
+	}
+	require.NoError(t, authCfg.SetDefaults())
+	require.NoError(t, authCfg.Strategies[0].Validate())
+	r, err := NewAuthRegistry(context.Background(), &logger, "test", authCfg, nil)
+	require.NoError(t, err)
+	return r
+}
+
+func TestWebhookStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	var lastRequest atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("Authorization") != "Bearer service-token" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		wr := &WebhookRequest{}
+		_ = json.NewDecoder(r.Body).Decode(wr)
+		lastRequest.Store(wr)
+		switch wr.Secret {
+		case "paid-customer":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"allow":           true,
+				"owner":           "acme",
+				"allowedNetworks": []string{"evm:1"},
+				"metadata":        map[string]string{"plan": "growth"},
+			})
+		case "unpaid-customer":
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"reason": "invoice overdue"})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"allow": false})
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &common.WebhookStrategyConfig{
+		Url:      srv.URL,
+		Headers:  map[string]string{"Authorization": "Bearer service-token"},
+		CacheTtl: common.Duration(time.Minute),
+	}
+
+	t.Run("AllowsWithOverridesAndCachesDecision", func(t *testing.T) {
+		calls.Store(0)
+		r := newWebhookTestRegistry(t, cfg)
+		ap := secretPayload("eth_call", "paid-customer")
+		ap.NetworkId = "evm:1"
+
+		grant, err := r.Authenticate(ctx, "eth_call", ap)
+		require.NoError(t, err)
+		assert.Equal(t, "acme", grant.Owner)
+		assert.Equal(t, map[string]string{"plan": "growth"}, grant.Metadata)
+		assert.Error(t, grant.CheckNetwork("evm:10"))
+
+		wr := lastRequest.Load().(*WebhookRequest)
+		assert.Equal(t, "test", wr.ProjectId)
+		assert.Equal(t, "eth_call", wr.Method)
+		assert.Equal(t, "evm:1", wr.NetworkId)
+
+		_, err = r.Authenticate(ctx, "eth_call", ap)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+
+		// Different method is a different decision
+		_, err = r.Authenticate(ctx, "eth_getBalance", secretPayload("eth_getBalance", "paid-customer"))
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("DeniesAndCachesDenyDecision", func(t *testing.T) {
+		calls.Store(0)
+		r := newWebhookTestRegistry(t, cfg)
+
+		_, err := r.Authenticate(ctx, "eth_call", secretPayload("eth_call", "unpaid-customer"))
+		require.True(t, common.HasErrorCode(err, common.ErrCodeAuthUnauthorized), err)
+		assert.Contains(t, err.Error(), "invoice overdue")
+
+		_, err = r.Authenticate(ctx, "eth_call", secretPayload("eth_call", "unknown-customer"))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthUnauthorized), err)
+
+		_, err = r.Authenticate(ctx, "eth_call", secretPayload("eth_call", "unpaid-customer"))
+		assert.Error(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("FailClosedAndFailOpen", func(t *testing.T) {
+		broken := &common.WebhookStrategyConfig{Url: srv.URL, CacheTtl: common.Duration(time.Minute)}
+		r := newWebhookTestRegistry(t, broken)
+		_, err := r.Authenticate(ctx, "eth_call", secretPayload("eth_call", "paid-customer"))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthUnauthorized), err)
+
+		broken = &common.WebhookStrategyConfig{Url: srv.URL, FailOpen: true}
+		r = newWebhookTestRegistry(t, broken)
+		grant, err := r.Authenticate(ctx, "eth_call", secretPayload("eth_call", "paid-customer"))
+		require.NoError(t, err)
+		assert.Empty(t, grant.Owner)
+	})
+
+	t.Run("OnlyConfiguredPayloadTypes", func(t *testing.T) {
+		r := newWebhookTestRegistry(t, &common.WebhookStrategyConfig{
+			Url:          srv.URL,
+			Headers:      map[string]string{"Authorization": "Bearer service-token"},
+			PayloadTypes: []common.AuthType{common.AuthTypeJwt},
+		})
+		_, err := r.Authenticate(ctx, "eth_call", secretPayload("eth_call", "paid-customer"))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAuthUnauthorized), err)
+		assert.Contains(t, err.Error(), "no auth strategy matched")
+	})
+}
@@ -41,10 +41,12 @@ type GenericHttpJsonRpcClient struct {
 	httpClient      *http.Client
 	isLogLevelTrace bool
 
-	enableGzip    bool
-	supportsBatch bool
-	batchMaxSize  int
-	batchMaxWait  time.Duration
+	enableGzip         bool
+	streamingThreshold int
+	streamingMethods   []string
+	supportsBatch      bool
+	batchMaxSize       int
+	batchMaxWait       time.Duration
 
 	batchMu       sync.Mutex
 	batchRequests map[interface{}]*batchRequest
@@ -115,6 +117,15 @@ func NewGenericHttpJsonRpcClient(
 			client.headers = jsonRpcCfg.Headers
 		}
 
+		if jsonRpcCfg.StreamingThreshold != "" {
+			threshold, err := util.ParseByteSize(jsonRpcCfg.StreamingThreshold)
+			if err != nil {
+				return nil, fmt.Errorf("invalid jsonRpc.streamingThreshold: %w", err)
+			}
+			client.streamingThreshold = threshold
+			client.streamingMethods = jsonRpcCfg.StreamingMethods
+		}
+
 		client.proxyPool = proxyPool
 	}
 
@@ -607,8 +618,18 @@ func (c *GenericHttpJsonRpcClient) sendSingleRequest(ctx context.Context, req *c
 		return nil, err
 	}
 
+	// Streamed bodies outlive this call, so the request is detached from ctx once response headers are received
+	// and is bounded by the client request lifetime and an idle timeout on body reads instead.
+	streaming := c.shouldStream(jrReq.Method)
+	reqCtx, cancelReq := ctx, context.CancelFunc(func() {})
+	stopCancelReq := func() bool { return true }
+	if streaming {
+		reqCtx, cancelReq = context.WithCancel(context.WithoutCancel(ctx))
+		stopCancelReq = context.AfterFunc(ctx, cancelReq)
+	}
+
 	reqStartTime := time.Now()
-	httpReq, err := c.prepareRequest(ctx, requestBody)
+	httpReq, err := c.prepareRequest(reqCtx, requestBody)
 	if err != nil {
 		common.SetTraceSpanError(span, err)
 		return nil, &common.BaseError{
@@ -623,8 +644,16 @@ func (c *GenericHttpJsonRpcClient) sendSingleRequest(ctx context.Context, req *c
 	}
 	c.logger.Debug().Str("host", c.Url.Host).RawJSON("request", requestBody).Interface("headers", httpReq.Header).Msg("sending json rpc POST request (single)")
 
-	resp, err := c.getHttpClient().Do(httpReq)
+	httpClient := c.getHttpClient()
+	if streaming {
+		// The overall client timeout would also cut a body that is still being streamed
+		sc := *httpClient
+		sc.Timeout = 0
+		httpClient = &sc
+	}
+	resp, err := httpClient.Do(httpReq)
 	if err != nil {
+		cancelReq()
 		cause := context.Cause(ctx)
 		if cause == nil {
 			cause = ctx.Err()
@@ -643,10 +672,31 @@ func (c *GenericHttpJsonRpcClient) sendSingleRequest(ctx context.Context, req *c
 		}
 		return nil, common.NewErrEndpointTransportFailure(c.Url, err)
 	}
+	var fallbackReader io.ReadCloser
+	if streaming && resp.StatusCode == http.StatusOK && stopCancelReq() {
+		var nr *common.NormalizedResponse
+		nr, fallbackReader, err = c.streamResponse(req, resp, cancelReq)
+		if err != nil {
+			common.SetTraceSpanError(span, err)
+			return nil, err
+		}
+		if nr != nil {
+			if lifetime, ok := common.RequestLifetime(ctx); ok {
+				// Still stop reading upstream when the client goes away or the streamed response times out
+				context.AfterFunc(lifetime, cancelReq)
+			}
+			return nr, nil
+		}
+	}
+	defer cancelReq()
 	defer resp.Body.Close()
 
 	var bodyReader io.ReadCloser = resp.Body
-	if resp.Header.Get("Content-Encoding") == "gzip" {
+	if fallbackReader != nil {
+		// Result is smaller than the streaming threshold (or not streamable), parse the already peeked body as usual
+		defer fallbackReader.Close()
+		bodyReader = fallbackReader
+	} else if resp.Header.Get("Content-Encoding") == "gzip" {
 		gzReader, err := gzip.NewReader(resp.Body)
 		if err != nil {
 			return nil, common.NewErrEndpointTransportFailure(c.Url, fmt.Errorf("cannot create gzip reader: %w", err))
@@ -668,6 +718,80 @@ func (c *GenericHttpJsonRpcClient) sendSingleRequest(ctx context.Context, req *c
 	return nr, err
 }
 
+func (c *GenericHttpJsonRpcClient) shouldStream(method string) bool {
+	if c.streamingThreshold <= 0 {
+		return false
+	}
+	for _, m := range c.streamingMethods {
+		if match, _ := common.WildcardMatch(m, method); match {
+			return true
+		}
+	}
+	return false
+}
+
+// streamResponse peeks up to the streaming threshold of the body, and when the result is bigger it returns a
+// response whose result is piped from the upstream body once written to the client. Otherwise it returns
+// a reader replaying the whole body to be parsed as usual.
+func (c *GenericHttpJsonRpcClient) streamResponse(req *common.NormalizedRequest, resp *http.Response, cancelReq context.CancelFunc) (*common.NormalizedResponse, io.ReadCloser, error) {
+	body := &idleTimeoutBody{
+		ReadCloser: resp.Body,
+		body:       resp.Body,
+		cancel:     cancelReq,
+		timer:      time.AfterFunc(streamingIdleTimeout, cancelReq),
+	}
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gzReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			body.Close()
+			return nil, nil, common.NewErrEndpointTransportFailure(c.Url, fmt.Errorf("cannot create gzip reader: %w", err))
+		}
+		body.ReadCloser = gzReader
+	}
+
+	jrr, fallback, err := common.NewStreamedJsonRpcResponse(body, c.streamingThreshold, int(resp.ContentLength))
+	if err != nil {
+		body.Close()
+		return nil, nil, common.NewErrEndpointTransportFailure(c.Url, fmt.Errorf("cannot read response body: %w", err))
+	}
+	if jrr == nil {
+		return nil, struct {
+			io.Reader
+			io.Closer
+		}{fallback, body}, nil
+	}
+
+	c.logger.Debug().Int("threshold", c.streamingThreshold).Int64("contentLength", resp.ContentLength).Msg("streaming large json-rpc result from upstream")
+	return common.NewNormalizedResponse().WithRequest(req).WithJsonRpcResponse(jrr), nil, nil
+}
+
+const streamingIdleTimeout = 60 * time.Second
+
+// idleTimeoutBody cancels the upstream request when no bytes are read for a while (e.g. the client stalled),
+// since streamed bodies are not bound to the original request timeout.
+type idleTimeoutBody struct {
+	io.ReadCloser
+	body   io.Closer
+	cancel context.CancelFunc
+	timer  *time.Timer
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.timer.Reset(streamingIdleTimeout)
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	if b.ReadCloser != b.body {
+		_ = b.ReadCloser.Close()
+	}
+	err := b.body.Close()
+	b.cancel()
+	return err
+}
+
 func (c *GenericHttpJsonRpcClient) prepareRequest(ctx context.Context, body []byte) (*http.Request, error) {
 	var bodyReader io.Reader = bytes.NewReader(body)
 
@@ -122,3 +122,8 @@ func (manager *ClientRegistry) CreateClient(appCtx context.Context, ups common.U
 
 	return newClient, clientErr
 }
+
+// RemoveClient drops the cached client of an upstream that was deregistered.
+func (manager *ClientRegistry) RemoveClient(ups common.Upstream) {
+	manager.clients.Delete(common.UniqueUpstreamKey(ups))
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"os/signal"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/erpc"
+	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/util"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
@@ -33,11 +35,14 @@ func init() {
 		return err
 	}
 
+	var logOutput io.Writer = os.Stderr
 	if logWriter := os.Getenv("LOG_WRITER"); logWriter == "console" {
-		log.Logger = zerolog.New(zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
+		logOutput = zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
 			w.TimeFormat = "04:05.000ms"
-		})).With().Timestamp().Logger()
+		})
 	}
+	// Logs are also forwarded to OTLP once enabled via logs.otlp config, the writer is a no-op until then
+	log.Logger = zerolog.New(zerolog.MultiLevelWriter(logOutput, telemetry.OtlpLogWriter)).With().Timestamp().Logger()
 
 	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
 		level, err := zerolog.ParseLevel(logLevel)
@@ -104,6 +109,64 @@ func main() {
 		}),
 	}
 
+	// Define the replay command
+	replayCmd := &cli.Command{
+		Name:      "replay",
+		Usage:     "Replay captured requests against the eRPC configuration and compare results and latency",
+		ArgsUsage: "[config file]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "capture",
+				Usage:    "JSONL capture file to replay (see project capture config)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "project",
+				Usage: "Replay every record against this project instead of the captured one",
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "Number of requests replayed at the same time",
+				Value: 10,
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Max number of records to replay (0 means all)",
+			},
+			&cli.BoolFlag{
+				Name:  "include-redacted",
+				Usage: "Also replay records whose request params were redacted",
+			},
+			&cli.BoolFlag{
+				Name:  "fail-on-mismatch",
+				Usage: "Exit with an error when any replayed result does not match the captured one",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return baseCliAction(logger, func(ctx context.Context, cfg *common.Config) error {
+				report, err := erpc.Replay(ctx, cfg, logger, &erpc.ReplayOptions{
+					File:            cmd.String("capture"),
+					ProjectId:       cmd.String("project"),
+					Concurrency:     int(cmd.Int("concurrency")),
+					IncludeRedacted: cmd.Bool("include-redacted"),
+					Limit:           int(cmd.Int("limit")),
+				})
+				if err != nil {
+					return err
+				}
+				out, err := common.SonicCfg.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(out))
+				if cmd.Bool("fail-on-mismatch") && report.Mismatched > 0 {
+					return fmt.Errorf("%d of %d replayed requests did not match the capture", report.Mismatched, report.Total)
+				}
+				return nil
+			})(ctx, cmd)
+		},
+	}
+
 	// Define the main command
 	cmd := &cli.Command{
 		Name:      "erpc",
@@ -128,6 +191,7 @@ func main() {
 		Commands: []*cli.Command{
 			startCmd,
 			validateCmd,
+			replayCmd,
 		},
 	}
 	if err := cmd.Run(ctx, os.Args); err != nil {
@@ -86,18 +86,30 @@ func (s EvmSyncingState) String() string {
 	}
 }
 
+// EvmBlockHead is the head block of an upstream as seen by its state poller
+type EvmBlockHead struct {
+	Number    int64  `json:"number"`
+	Hash      string `json:"hash,omitempty"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+}
+
 type EvmStatePoller interface {
 	Bootstrap(ctx context.Context) error
 	Poll(ctx context.Context) error
 	PollLatestBlockNumber(ctx context.Context) (int64, error)
 	PollFinalizedBlockNumber(ctx context.Context) (int64, error)
+	PollSafeBlockNumber(ctx context.Context) (int64, error)
 	SyncingState() EvmSyncingState
 	SetSyncingState(state EvmSyncingState)
 	LatestBlock() int64
+	LatestBlockHead() *EvmBlockHead
 	FinalizedBlock() int64
+	SafeBlock() int64
 	IsBlockFinalized(blockNumber int64) (bool, error)
 	SuggestFinalizedBlock(blockNumber int64)
 	SuggestLatestBlock(blockNumber int64)
 	SetNetworkConfig(cfg *EvmNetworkConfig)
+	// Shutdown releases what the poller holds in shared state (e.g. its leader election) when the upstream goes away.
+	Shutdown()
 	IsObjectNull() bool
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"maps"
 	"os"
+	"slices"
 	"time"
 
 	"strings"
@@ -37,6 +38,7 @@ type Config struct {
 	Metrics      *MetricsConfig     `yaml:"metrics,omitempty" json:"metrics"`
 	ProxyPools   []*ProxyPoolConfig `yaml:"proxyPools,omitempty" json:"proxyPools"`
 	Tracing      *TracingConfig     `yaml:"tracing,omitempty" json:"tracing"`
+	Logs         *LogsConfig        `yaml:"logs,omitempty" json:"logs"`
 }
 
 // LoadConfig loads the configuration from the specified file.
@@ -79,20 +81,33 @@ func LoadConfig(fs afero.Fs, filename string, opts *DefaultOptions) (*Config, er
 }
 
 type ServerConfig struct {
-	ListenV4            *bool           `yaml:"listenV4,omitempty" json:"listenV4"`
-	HttpHostV4          *string         `yaml:"httpHostV4,omitempty" json:"httpHostV4"`
-	ListenV6            *bool           `yaml:"listenV6,omitempty" json:"listenV6"`
-	HttpHostV6          *string         `yaml:"httpHostV6,omitempty" json:"httpHostV6"`
-	HttpPort            *int            `yaml:"httpPort,omitempty" json:"httpPort"`
-	MaxTimeout          *Duration       `yaml:"maxTimeout,omitempty" json:"maxTimeout" tstype:"Duration"`
-	ReadTimeout         *Duration       `yaml:"readTimeout,omitempty" json:"readTimeout" tstype:"Duration"`
-	WriteTimeout        *Duration       `yaml:"writeTimeout,omitempty" json:"writeTimeout" tstype:"Duration"`
-	EnableGzip          *bool           `yaml:"enableGzip,omitempty" json:"enableGzip"`
-	TLS                 *TLSConfig      `yaml:"tls,omitempty" json:"tls"`
-	Aliasing            *AliasingConfig `yaml:"aliasing" json:"aliasing"`
-	WaitBeforeShutdown  *Duration       `yaml:"waitBeforeShutdown,omitempty" json:"waitBeforeShutdown" tstype:"Duration"`
-	WaitAfterShutdown   *Duration       `yaml:"waitAfterShutdown,omitempty" json:"waitAfterShutdown" tstype:"Duration"`
-	IncludeErrorDetails *bool           `yaml:"includeErrorDetails,omitempty" json:"includeErrorDetails"`
+	ListenV4            *bool             `yaml:"listenV4,omitempty" json:"listenV4"`
+	HttpHostV4          *string           `yaml:"httpHostV4,omitempty" json:"httpHostV4"`
+	ListenV6            *bool             `yaml:"listenV6,omitempty" json:"listenV6"`
+	HttpHostV6          *string           `yaml:"httpHostV6,omitempty" json:"httpHostV6"`
+	HttpPort            *int              `yaml:"httpPort,omitempty" json:"httpPort"`
+	MaxTimeout          *Duration         `yaml:"maxTimeout,omitempty" json:"maxTimeout" tstype:"Duration"`
+	ReadTimeout         *Duration         `yaml:"readTimeout,omitempty" json:"readTimeout" tstype:"Duration"`
+	WriteTimeout        *Duration         `yaml:"writeTimeout,omitempty" json:"writeTimeout" tstype:"Duration"`
+	EnableGzip          *bool             `yaml:"enableGzip,omitempty" json:"enableGzip"`
+	TLS                 *TLSConfig        `yaml:"tls,omitempty" json:"tls"`
+	Aliasing            *AliasingConfig   `yaml:"aliasing" json:"aliasing"`
+	WaitBeforeShutdown  *Duration         `yaml:"waitBeforeShutdown,omitempty" json:"waitBeforeShutdown" tstype:"Duration"`
+	WaitAfterShutdown   *Duration         `yaml:"waitAfterShutdown,omitempty" json:"waitAfterShutdown" tstype:"Duration"`
+	IncludeErrorDetails *bool             `yaml:"includeErrorDetails,omitempty" json:"includeErrorDetails"`
+	Grpc                *GrpcServerConfig `yaml:"grpc,omitempty" json:"grpc"`
+	// MaxRequestBodySize bounds the (decompressed) size of incoming http request bodies, e.g. "16mb".
+	// Bodies are not limited when unset.
+	MaxRequestBodySize *string `yaml:"maxRequestBodySize,omitempty" json:"maxRequestBodySize"`
+}
+
+// GrpcServerConfig exposes the blockchain-data-standards RPCQueryService over gRPC, requests are
+// translated to JSON-RPC and go through the same pipeline (cache, failover, consensus) as http.
+type GrpcServerConfig struct {
+	Enabled          *bool   `yaml:"enabled,omitempty" json:"enabled"`
+	Host             *string `yaml:"host,omitempty" json:"host"`
+	Port             *int    `yaml:"port,omitempty" json:"port"`
+	DefaultProjectId string  `yaml:"defaultProjectId,omitempty" json:"defaultProjectId"`
 }
 
 type HealthCheckConfig struct {
@@ -132,6 +147,28 @@ type TracingConfig struct {
 	SampleRate float64         `yaml:"sampleRate,omitempty" json:"sampleRate"`
 	Detailed   bool            `yaml:"detailed,omitempty" json:"detailed"`
 	TLS        *TLSConfig      `yaml:"tls,omitempty" json:"tls"`
+	// TailSampling records every request in-process and decides when it ends whether to export its spans,
+	// so failed, hedged, disputed or slow requests are always captured while others follow SampleRate.
+	TailSampling *TracingTailSamplingConfig `yaml:"tailSampling,omitempty" json:"tailSampling"`
+}
+
+type TracingTailSamplingConfig struct {
+	Enabled            bool     `yaml:"enabled,omitempty" json:"enabled"`
+	LatencyThreshold   Duration `yaml:"latencyThreshold,omitempty" json:"latencyThreshold" tstype:"Duration"`
+	MaxSpansPerRequest int      `yaml:"maxSpansPerRequest,omitempty" json:"maxSpansPerRequest"`
+}
+
+// LogsConfig controls where logs are shipped besides the standard output.
+type LogsConfig struct {
+	Otlp *LogsOtlpConfig `yaml:"otlp,omitempty" json:"otlp"`
+}
+
+// LogsOtlpConfig forwards every log event to an OTLP collector, using the same connection settings as tracing.
+type LogsOtlpConfig struct {
+	Enabled  bool            `yaml:"enabled,omitempty" json:"enabled"`
+	Endpoint string          `yaml:"endpoint,omitempty" json:"endpoint"`
+	Protocol TracingProtocol `yaml:"protocol,omitempty" json:"protocol"`
+	TLS      *TLSConfig      `yaml:"tls,omitempty" json:"tls"`
 }
 
 type AdminConfig struct {
@@ -156,10 +193,19 @@ type DatabaseConfig struct {
 }
 
 type SharedStateConfig struct {
-	ClusterKey      string           `yaml:"clusterKey,omitempty" json:"clusterKey"`
-	Connector       *ConnectorConfig `yaml:"connector,omitempty" json:"connector"`
-	FallbackTimeout Duration         `yaml:"fallbackTimeout,omitempty" json:"fallbackTimeout" tstype:"Duration"`
-	LockTtl         Duration         `yaml:"lockTtl,omitempty" json:"lockTtl" tstype:"Duration"`
+	ClusterKey      string                `yaml:"clusterKey,omitempty" json:"clusterKey"`
+	Connector       *ConnectorConfig      `yaml:"connector,omitempty" json:"connector"`
+	FallbackTimeout Duration              `yaml:"fallbackTimeout,omitempty" json:"fallbackTimeout" tstype:"Duration"`
+	LockTtl         Duration              `yaml:"lockTtl,omitempty" json:"lockTtl" tstype:"Duration"`
+	LeaderElection  *LeaderElectionConfig `yaml:"leaderElection,omitempty" json:"leaderElection"`
+}
+
+// LeaderElectionConfig makes designated background jobs (e.g. upstream state polling) run on a single
+// elected instance per cluster, which publishes the results through shared state for the other instances.
+type LeaderElectionConfig struct {
+	Enabled       *bool    `yaml:"enabled,omitempty" json:"enabled"`
+	LeaseTtl      Duration `yaml:"leaseTtl,omitempty" json:"leaseTtl" tstype:"Duration"`
+	RenewInterval Duration `yaml:"renewInterval,omitempty" json:"renewInterval" tstype:"Duration"`
 }
 
 type CacheConfig struct {
@@ -333,11 +379,94 @@ type ProjectConfig struct {
 	RateLimitBudget        string                              `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget"`
 	ScoreMetricsWindowSize Duration                            `yaml:"scoreMetricsWindowSize,omitempty" json:"scoreMetricsWindowSize" tstype:"Duration"`
 	DeprecatedHealthCheck  *DeprecatedProjectHealthCheckConfig `yaml:"healthCheck,omitempty" json:"healthCheck"`
+	DisputeLog             *DisputeLogConfig                   `yaml:"disputeLog,omitempty" json:"disputeLog"`
+	Firewall               *FirewallConfig                     `yaml:"firewall,omitempty" json:"firewall"`
+	Batch                  *BatchConfig                        `yaml:"batch,omitempty" json:"batch"`
+	Capture                *CaptureConfig                      `yaml:"capture,omitempty" json:"capture"`
+}
+
+// CaptureConfig samples forwarded requests with their responses (or errors), upstream, latency and
+// finality into a JSONL file, so that traffic can be replayed via "erpc replay".
+type CaptureConfig struct {
+	// SampleRate is the ratio (0 to 1) of matching requests that are captured.
+	SampleRate float64 `yaml:"sampleRate,omitempty" json:"sampleRate"`
+	// Method pattern of requests to capture (e.g. "eth_call|eth_getLogs").
+	Method string `yaml:"method,omitempty" json:"method"`
+	// File is the path of the JSONL file records are appended to.
+	File string `yaml:"file,omitempty" json:"file"`
+	// MaxResultSize is the max size in bytes of results kept in records, bigger results are captured
+	// with their hash but without their body.
+	MaxResultSize int `yaml:"maxResultSize,omitempty" json:"maxResultSize"`
+	// BufferSize is the max number of records waiting to be written, records are dropped when full.
+	BufferSize int                        `yaml:"bufferSize,omitempty" json:"bufferSize"`
+	Redact     []*CaptureRedactRuleConfig `yaml:"redact,omitempty" json:"redact"`
+}
+
+// CaptureRedactRuleConfig replaces values at the given paths of captured requests and responses of
+// matching methods with a short hash, e.g. "params.0.from" or "result.*.input".
+type CaptureRedactRuleConfig struct {
+	Method string   `yaml:"method,omitempty" json:"method"`
+	Paths  []string `yaml:"paths,omitempty" json:"paths"`
+}
+
+// BatchConfig governs json-rpc batch requests sent to a project. Each item of a batch is still
+// authenticated, rate limited and forwarded individually.
+type BatchConfig struct {
+	// MaxSize is the max number of items in one batch, bigger batches are rejected as a whole.
+	// 0 means unlimited.
+	MaxSize int `yaml:"maxSize,omitempty" json:"maxSize"`
+	// MaxConcurrency is the max number of items of one batch processed at the same time.
+	// 0 means unlimited.
+	MaxConcurrency int `yaml:"maxConcurrency,omitempty" json:"maxConcurrency"`
+}
+
+// FirewallConfig blocks abusive request patterns before they reach upstreams. Rules are evaluated
+// in order and the first matching rule decides, requests matching no rule are allowed.
+type FirewallConfig struct {
+	Rules []*FirewallRuleConfig `yaml:"rules" json:"rules"`
+}
+
+type FirewallAction string
+
+const (
+	FirewallActionDeny  FirewallAction = "deny"
+	FirewallActionAllow FirewallAction = "allow"
+)
+
+// FirewallRuleConfig matches when all of its configured conditions match the request.
+type FirewallRuleConfig struct {
+	// Id identifies the rule in errors, logs and metrics (default: rule-<index>).
+	Id      string         `yaml:"id,omitempty" json:"id"`
+	Action  FirewallAction `yaml:"action,omitempty" json:"action"`
+	Network string         `yaml:"network,omitempty" json:"network"`
+	Method  string         `yaml:"method,omitempty" json:"method"`
+	// Params are matched the same way as cache policy params.
+	Params []interface{} `yaml:"params,omitempty" json:"params"`
+	// Contracts are address patterns matched against the "to" of call objects (e.g. eth_call,
+	// debug_traceCall) or the "address" of log filters (e.g. eth_getLogs).
+	Contracts []string `yaml:"contracts,omitempty" json:"contracts"`
+	// MissingAddress matches log filters without an address (e.g. eth_getLogs over all contracts).
+	MissingAddress bool `yaml:"missingAddress,omitempty" json:"missingAddress"`
+	// BlockRangeExceeds matches log filters spanning more than this many blocks, tags such as
+	// "latest" are resolved to the highest latest block of the network.
+	BlockRangeExceeds int64 `yaml:"blockRangeExceeds,omitempty" json:"blockRangeExceeds"`
+	// Message is returned to the client when the rule denies the request.
+	Message string `yaml:"message,omitempty" json:"message"`
+}
+
+// DisputeLogConfig persists evidence of consensus disputes (request, each participant's response hash
+// and truncated body, the winner and punished upstreams) so they can be queried via the admin API.
+type DisputeLogConfig struct {
+	Connector   *ConnectorConfig `yaml:"connector,omitempty" json:"connector"`
+	Retention   Duration         `yaml:"retention,omitempty" json:"retention" tstype:"Duration"`
+	MaxEntries  int              `yaml:"maxEntries,omitempty" json:"maxEntries"`
+	MaxBodySize *int             `yaml:"maxBodySize,omitempty" json:"maxBodySize"`
 }
 
 type NetworkDefaults struct {
 	RateLimitBudget   string                   `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget"`
 	Failsafe          []*FailsafeConfig        `yaml:"failsafe,omitempty" json:"failsafe"`
+	RetryBudget       *RetryBudgetConfig       `yaml:"retryBudget,omitempty" json:"retryBudget"`
 	SelectionPolicy   *SelectionPolicyConfig   `yaml:"selectionPolicy,omitempty" json:"selectionPolicy"`
 	DirectiveDefaults *DirectiveDefaultsConfig `yaml:"directiveDefaults,omitempty" json:"directiveDefaults"`
 	Evm               *EvmNetworkConfig        `yaml:"evm,omitempty" json:"evm" tstype:"TsEvmNetworkConfigForDefaults"`
@@ -370,6 +499,7 @@ func (n *NetworkDefaults) UnmarshalYAML(unmarshal func(interface{}) error) error
 	type oldNetworkDefaults struct {
 		RateLimitBudget   string                   `yaml:"rateLimitBudget,omitempty"`
 		Failsafe          *FailsafeConfig          `yaml:"failsafe,omitempty"`
+		RetryBudget       *RetryBudgetConfig       `yaml:"retryBudget,omitempty"`
 		SelectionPolicy   *SelectionPolicyConfig   `yaml:"selectionPolicy,omitempty"`
 		DirectiveDefaults *DirectiveDefaultsConfig `yaml:"directiveDefaults,omitempty"`
 		Evm               *EvmNetworkConfig        `yaml:"evm,omitempty"`
@@ -384,6 +514,7 @@ func (n *NetworkDefaults) UnmarshalYAML(unmarshal func(interface{}) error) error
 
 	// Convert old format to new format
 	n.RateLimitBudget = old.RateLimitBudget
+	n.RetryBudget = old.RetryBudget
 	n.SelectionPolicy = old.SelectionPolicy
 	n.DirectiveDefaults = old.DirectiveDefaults
 	n.Evm = old.Evm
@@ -655,6 +786,24 @@ type JsonRpcUpstreamConfig struct {
 	EnableGzip    *bool             `yaml:"enableGzip,omitempty" json:"enableGzip"`
 	Headers       map[string]string `yaml:"headers,omitempty" json:"headers"`
 	ProxyPool     string            `yaml:"proxyPool,omitempty" json:"proxyPool"`
+
+	// StreamingThreshold enables piping results bigger than this size (e.g. "16mb") straight from the upstream
+	// body to the client instead of buffering them in memory, only for StreamingMethods.
+	StreamingThreshold string   `yaml:"streamingThreshold,omitempty" json:"streamingThreshold"`
+	StreamingMethods   []string `yaml:"streamingMethods,omitempty" json:"streamingMethods"`
+}
+
+// ShouldStream returns true when results of the method are streamed to clients (see StreamingThreshold).
+func (c *JsonRpcUpstreamConfig) ShouldStream(method string) bool {
+	if c == nil || c.StreamingThreshold == "" {
+		return false
+	}
+	for _, m := range c.StreamingMethods {
+		if match, _ := WildcardMatch(m, method); match {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *JsonRpcUpstreamConfig) Copy() *JsonRpcUpstreamConfig {
@@ -668,6 +817,9 @@ func (c *JsonRpcUpstreamConfig) Copy() *JsonRpcUpstreamConfig {
 	if c.Headers != nil {
 		maps.Copy(copied.Headers, c.Headers)
 	}
+	if c.StreamingMethods != nil {
+		copied.StreamingMethods = slices.Clone(c.StreamingMethods)
+	}
 
 	return copied
 }
@@ -684,6 +836,8 @@ type EvmUpstreamConfig struct {
 	GetLogsMaxAllowedTopics            int64       `yaml:"getLogsMaxAllowedTopics,omitempty" json:"getLogsMaxAllowedTopics"`
 	GetLogsSplitOnError                *bool       `yaml:"getLogsSplitOnError,omitempty" json:"getLogsSplitOnError"`
 	SkipWhenSyncing                    *bool       `yaml:"skipWhenSyncing,omitempty" json:"skipWhenSyncing"`
+	// HeadSubscription tracks the latest block via eth_subscribe("newHeads") instead of polling it.
+	HeadSubscription *EvmHeadSubscriptionConfig `yaml:"headSubscription,omitempty" json:"headSubscription"`
 	// TODO: remove deprecated alias (backward compat): maps to GetLogsAutoSplittingRangeThreshold
 	GetLogsMaxBlockRange int64 `yaml:"getLogsMaxBlockRange,omitempty" json:"-"`
 }
@@ -695,10 +849,28 @@ func (c *EvmUpstreamConfig) Copy() *EvmUpstreamConfig {
 
 	copied := &EvmUpstreamConfig{}
 	*copied = *c
+	if c.HeadSubscription != nil {
+		hs := *c.HeadSubscription
+		copied.HeadSubscription = &hs
+	}
 
 	return copied
 }
 
+// EvmHeadSubscriptionConfig subscribes to new heads on a websocket endpoint of the upstream. While heads
+// are received, the state poller stops polling the latest block and polls finalized block and syncing
+// state less often. On disconnect or when heads stop arriving it falls back to regular polling.
+type EvmHeadSubscriptionConfig struct {
+	// Endpoint is the ws:// or wss:// url, by default the upstream http(s) endpoint with a ws(s) scheme.
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint"`
+	// StaleTimeout is how long without a new head before the subscription is considered broken.
+	StaleTimeout Duration `yaml:"staleTimeout,omitempty" json:"staleTimeout" tstype:"Duration"`
+	// MaxReconnectDelay caps the exponential backoff between reconnect attempts.
+	MaxReconnectDelay Duration `yaml:"maxReconnectDelay,omitempty" json:"maxReconnectDelay" tstype:"Duration"`
+	// PollerInterval replaces statePollerInterval while the subscription is healthy.
+	PollerInterval Duration `yaml:"pollerInterval,omitempty" json:"pollerInterval" tstype:"Duration"`
+}
+
 type FailsafeConfig struct {
 	MatchMethod    string                      `yaml:"matchMethod,omitempty" json:"matchMethod"`
 	MatchFinality  []DataFinalityState         `yaml:"matchFinality,omitempty" json:"matchFinality"`
@@ -765,6 +937,23 @@ func (c *RetryPolicyConfig) Copy() *RetryPolicyConfig {
 	return copied
 }
 
+// RetryBudgetConfig caps the number of retries and hedges of a network relative to the total
+// number of requests seen over a sliding window, so that outages do not turn into retry storms.
+type RetryBudgetConfig struct {
+	MaxRatio   float64  `yaml:"maxRatio" json:"maxRatio"`
+	Window     Duration `yaml:"window,omitempty" json:"window" tstype:"Duration"`
+	MinRetries int      `yaml:"minRetries,omitempty" json:"minRetries"`
+}
+
+func (c *RetryBudgetConfig) Copy() *RetryBudgetConfig {
+	if c == nil {
+		return nil
+	}
+	copied := &RetryBudgetConfig{}
+	*copied = *c
+	return copied
+}
+
 type CircuitBreakerPolicyConfig struct {
 	FailureThresholdCount    uint     `yaml:"failureThresholdCount" json:"failureThresholdCount"`
 	FailureThresholdCapacity uint     `yaml:"failureThresholdCapacity" json:"failureThresholdCapacity"`
@@ -782,8 +971,16 @@ func (c *CircuitBreakerPolicyConfig) Copy() *CircuitBreakerPolicyConfig {
 	return copied
 }
 
+// TimeoutPolicyConfig defines a static timeout (duration), or a dynamic one when quantile is set.
+// In dynamic mode the timeout is the tracked response-time quantile of the method multiplied
+// by the multiplier and clamped between minDuration and maxDuration, while duration is used
+// until enough samples are collected.
 type TimeoutPolicyConfig struct {
-	Duration Duration `yaml:"duration,omitempty" json:"duration" tstype:"Duration"`
+	Duration    Duration `yaml:"duration,omitempty" json:"duration" tstype:"Duration"`
+	Quantile    float64  `yaml:"quantile,omitempty" json:"quantile"`
+	Multiplier  float64  `yaml:"multiplier,omitempty" json:"multiplier"`
+	MinDuration Duration `yaml:"minDuration,omitempty" json:"minDuration" tstype:"Duration"`
+	MaxDuration Duration `yaml:"maxDuration,omitempty" json:"maxDuration" tstype:"Duration"`
 }
 
 func (c *TimeoutPolicyConfig) Copy() *TimeoutPolicyConfig {
@@ -837,6 +1034,14 @@ type ConsensusPolicyConfig struct {
 	LowParticipantsBehavior ConsensusLowParticipantsBehavior `yaml:"lowParticipantsBehavior,omitempty" json:"lowParticipantsBehavior"`
 	PunishMisbehavior       *PunishMisbehaviorConfig         `yaml:"punishMisbehavior,omitempty" json:"punishMisbehavior"`
 	DisputeLogLevel         string                           `yaml:"disputeLogLevel,omitempty" json:"disputeLogLevel"` // "trace", "debug", "info", "warn", "error"
+
+	// UpstreamWeights defines how many votes each upstream (by id) counts for when evaluating
+	// agreement, e.g. a trusted self-hosted node can weigh more than a public endpoint. Default is 1.
+	UpstreamWeights map[string]int `yaml:"upstreamWeights,omitempty" json:"upstreamWeights"`
+
+	// ComparisonProfiles defines per method (exact name or wildcard) which fields of the result
+	// are compared between participants, instead of comparing the whole response.
+	ComparisonProfiles map[string]*ConsensusComparisonProfileConfig `yaml:"comparisonProfiles,omitempty" json:"comparisonProfiles"`
 }
 
 func (c *ConsensusPolicyConfig) Copy() *ConsensusPolicyConfig {
@@ -850,6 +1055,44 @@ func (c *ConsensusPolicyConfig) Copy() *ConsensusPolicyConfig {
 		copied.PunishMisbehavior = c.PunishMisbehavior.Copy()
 	}
 
+	if c.UpstreamWeights != nil {
+		copied.UpstreamWeights = make(map[string]int, len(c.UpstreamWeights))
+		for k, v := range c.UpstreamWeights {
+			copied.UpstreamWeights[k] = v
+		}
+	}
+
+	if c.ComparisonProfiles != nil {
+		copied.ComparisonProfiles = make(map[string]*ConsensusComparisonProfileConfig, len(c.ComparisonProfiles))
+		for k, v := range c.ComparisonProfiles {
+			copied.ComparisonProfiles[k] = v.Copy()
+		}
+	}
+
+	return copied
+}
+
+// ConsensusComparisonProfileConfig uses dot-separated field paths (with "*" for array items,
+// e.g. "transactions.*.hash"). When includeFields is set only those fields are compared,
+// and ignoreFields are then removed from what is compared.
+type ConsensusComparisonProfileConfig struct {
+	IncludeFields []string `yaml:"includeFields,omitempty" json:"includeFields"`
+	IgnoreFields  []string `yaml:"ignoreFields,omitempty" json:"ignoreFields"`
+}
+
+func (c *ConsensusComparisonProfileConfig) Copy() *ConsensusComparisonProfileConfig {
+	if c == nil {
+		return nil
+	}
+	copied := &ConsensusComparisonProfileConfig{}
+	if c.IncludeFields != nil {
+		copied.IncludeFields = make([]string, len(c.IncludeFields))
+		copy(copied.IncludeFields, c.IncludeFields)
+	}
+	if c.IgnoreFields != nil {
+		copied.IgnoreFields = make([]string, len(c.IgnoreFields))
+		copy(copied.IgnoreFields, c.IgnoreFields)
+	}
 	return copied
 }
 
@@ -914,6 +1157,7 @@ type NetworkConfig struct {
 	Architecture      NetworkArchitecture      `yaml:"architecture" json:"architecture" tstype:"TsNetworkArchitecture"`
 	RateLimitBudget   string                   `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget"`
 	Failsafe          []*FailsafeConfig        `yaml:"failsafe,omitempty" json:"failsafe"`
+	RetryBudget       *RetryBudgetConfig       `yaml:"retryBudget,omitempty" json:"retryBudget"`
 	Evm               *EvmNetworkConfig        `yaml:"evm,omitempty" json:"evm"`
 	SelectionPolicy   *SelectionPolicyConfig   `yaml:"selectionPolicy,omitempty" json:"selectionPolicy"`
 	DirectiveDefaults *DirectiveDefaultsConfig `yaml:"directiveDefaults,omitempty" json:"directiveDefaults"`
@@ -949,6 +1193,7 @@ func (n *NetworkConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		Architecture      NetworkArchitecture      `yaml:"architecture"`
 		RateLimitBudget   string                   `yaml:"rateLimitBudget,omitempty"`
 		Failsafe          *FailsafeConfig          `yaml:"failsafe,omitempty"`
+		RetryBudget       *RetryBudgetConfig       `yaml:"retryBudget,omitempty"`
 		Evm               *EvmNetworkConfig        `yaml:"evm,omitempty"`
 		SelectionPolicy   *SelectionPolicyConfig   `yaml:"selectionPolicy,omitempty"`
 		DirectiveDefaults *DirectiveDefaultsConfig `yaml:"directiveDefaults,omitempty"`
@@ -966,6 +1211,7 @@ func (n *NetworkConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	// Convert old format to new format
 	n.Architecture = old.Architecture
 	n.RateLimitBudget = old.RateLimitBudget
+	n.RetryBudget = old.RetryBudget
 	n.Evm = old.Evm
 	n.SelectionPolicy = old.SelectionPolicy
 	n.DirectiveDefaults = old.DirectiveDefaults
@@ -995,6 +1241,24 @@ type EvmNetworkConfig struct {
 	FallbackFinalityDepth       int64               `yaml:"fallbackFinalityDepth,omitempty" json:"fallbackFinalityDepth"`
 	FallbackStatePollerDebounce Duration            `yaml:"fallbackStatePollerDebounce,omitempty" json:"fallbackStatePollerDebounce" tstype:"Duration"`
 	Integrity                   *EvmIntegrityConfig `yaml:"integrity,omitempty" json:"integrity"`
+
+	// TransactionBroadcast (opt-in) submits eth_sendRawTransaction to multiple upstreams in parallel
+	// instead of the regular single-winner forwarding.
+	TransactionBroadcast *EvmTransactionBroadcastConfig `yaml:"transactionBroadcast,omitempty" json:"transactionBroadcast"`
+}
+
+// EvmTransactionBroadcastConfig defines how signed transactions are broadcasted to upstreams.
+// The first successful submission is returned, while "already known" (or "nonce too low" for the same
+// transaction hash) responses are also considered a success since the transaction is already in the mempool.
+type EvmTransactionBroadcastConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// MaxUpstreams limits how many of the (score-sorted) upstreams receive the transaction, 0 means all.
+	MaxUpstreams int `yaml:"maxUpstreams,omitempty" json:"maxUpstreams"`
+
+	// Upstreams (ids or wildcards) always receive the transaction on top of MaxUpstreams,
+	// e.g. private or MEV-protected endpoints.
+	Upstreams []string `yaml:"upstreams,omitempty" json:"upstreams"`
 }
 
 type EvmIntegrityConfig struct {
@@ -1073,6 +1337,8 @@ const (
 	AuthTypeJwt     AuthType = "jwt"
 	AuthTypeSiwe    AuthType = "siwe"
 	AuthTypeNetwork AuthType = "network"
+	AuthTypeWebhook AuthType = "webhook"
+	AuthTypeMtls    AuthType = "mtls"
 )
 
 type AuthConfig struct {
@@ -1084,22 +1350,69 @@ type AuthStrategyConfig struct {
 	AllowMethods    []string `yaml:"allowMethods,omitempty" json:"allowMethods,omitempty"`
 	RateLimitBudget string   `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget,omitempty"`
 
+	// Authorization derives per-consumer budget, networks, methods and batch size from verified
+	// claims (only supported for jwt and siwe strategies), so one strategy can serve many tiers.
+	Authorization *ClaimsAuthorizationConfig `yaml:"authorization,omitempty" json:"authorization,omitempty"`
+
 	Type    AuthType               `yaml:"type" json:"type" tstype:"TsAuthType"`
 	Network *NetworkStrategyConfig `yaml:"network,omitempty" json:"network,omitempty"`
 	Secret  *SecretStrategyConfig  `yaml:"secret,omitempty" json:"secret,omitempty"`
 	Jwt     *JwtStrategyConfig     `yaml:"jwt,omitempty" json:"jwt,omitempty"`
 	Siwe    *SiweStrategyConfig    `yaml:"siwe,omitempty" json:"siwe,omitempty"`
+	Webhook *WebhookStrategyConfig `yaml:"webhook,omitempty" json:"webhook,omitempty"`
+	Mtls    *MtlsStrategyConfig    `yaml:"mtls,omitempty" json:"mtls,omitempty"`
+}
+
+type ClaimsAuthorizationConfig struct {
+	// TierClaim is the claim whose value is looked up in Tiers (e.g. "plan").
+	TierClaim string                              `yaml:"tierClaim,omitempty" json:"tierClaim,omitempty"`
+	Tiers     map[string]*AuthorizationTierConfig `yaml:"tiers,omitempty" json:"tiers,omitempty"`
+
+	// Claims that directly carry the authorization values, these take precedence over the tier.
+	RateLimitBudgetClaim string `yaml:"rateLimitBudgetClaim,omitempty" json:"rateLimitBudgetClaim,omitempty"`
+	AllowedNetworksClaim string `yaml:"allowedNetworksClaim,omitempty" json:"allowedNetworksClaim,omitempty"`
+	AllowedMethodsClaim  string `yaml:"allowedMethodsClaim,omitempty" json:"allowedMethodsClaim,omitempty"`
+	MaxBatchSizeClaim    string `yaml:"maxBatchSizeClaim,omitempty" json:"maxBatchSizeClaim,omitempty"`
+	// MaxBatchConcurrencyClaim carries the max number of batch items of the consumer processed at the same time.
+	MaxBatchConcurrencyClaim string `yaml:"maxBatchConcurrencyClaim,omitempty" json:"maxBatchConcurrencyClaim,omitempty"`
+}
+
+type AuthorizationTierConfig struct {
+	RateLimitBudget string   `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget,omitempty"`
+	AllowedNetworks []string `yaml:"allowedNetworks,omitempty" json:"allowedNetworks,omitempty"`
+	AllowedMethods  []string `yaml:"allowedMethods,omitempty" json:"allowedMethods,omitempty"`
+	MaxBatchSize    int      `yaml:"maxBatchSize,omitempty" json:"maxBatchSize,omitempty"`
+	// MaxBatchConcurrency bounds batch items of this consumer processed at the same time, across its requests.
+	MaxBatchConcurrency int `yaml:"maxBatchConcurrency,omitempty" json:"maxBatchConcurrency,omitempty"`
 }
 
 type SecretStrategyConfig struct {
 	Value string `yaml:"value" json:"value"`
+
+	// KeysFile is a yaml/json file of hashed secret keys with per-key metadata, reloaded when changed.
+	KeysFile string `yaml:"keysFile,omitempty" json:"keysFile,omitempty"`
+	// KeysConnector is a data connector holding hashed secret keys (one record per key hash).
+	KeysConnector *ConnectorConfig `yaml:"keysConnector,omitempty" json:"keysConnector,omitempty"`
+	// KeysRefreshInterval is how often the keys file is checked for changes, and how long keys
+	// looked up from the connector are cached, i.e. max delay for a revocation to take effect.
+	KeysRefreshInterval Duration `yaml:"keysRefreshInterval,omitempty" json:"keysRefreshInterval,omitempty" tstype:"Duration"`
 }
 
 // custom json marshaller to redact the secret value
 func (s *SecretStrategyConfig) MarshalJSON() ([]byte, error) {
-	return sonic.Marshal(map[string]string{
+	out := map[string]interface{}{
 		"value": "REDACTED",
-	})
+	}
+	if s.KeysFile != "" {
+		out["keysFile"] = s.KeysFile
+	}
+	if s.KeysConnector != nil {
+		out["keysConnector"] = s.KeysConnector
+	}
+	if s.KeysRefreshInterval != 0 {
+		out["keysRefreshInterval"] = s.KeysRefreshInterval
+	}
+	return sonic.Marshal(out)
 }
 
 type JwtStrategyConfig struct {
@@ -1108,6 +1421,64 @@ type JwtStrategyConfig struct {
 	AllowedAlgorithms []string          `yaml:"allowedAlgorithms" json:"allowedAlgorithms"`
 	RequiredClaims    []string          `yaml:"requiredClaims" json:"requiredClaims"`
 	VerificationKeys  map[string]string `yaml:"verificationKeys" json:"verificationKeys"`
+
+	// JwksUrls are JSON Web Key Set endpoints of identity providers, keys are fetched and
+	// looked up by "kid" so that signing key rotations do not require a config change.
+	JwksUrls []string `yaml:"jwksUrls,omitempty" json:"jwksUrls"`
+	// JwksRefreshInterval is how often cached JWKS keys are refreshed in the background.
+	JwksRefreshInterval Duration `yaml:"jwksRefreshInterval,omitempty" json:"jwksRefreshInterval" tstype:"Duration"`
+	// JwksMinRefreshInterval is the minimum time between two fetches of the same JWKS,
+	// used to rate-limit refreshes triggered by tokens carrying an unknown "kid".
+	JwksMinRefreshInterval Duration `yaml:"jwksMinRefreshInterval,omitempty" json:"jwksMinRefreshInterval" tstype:"Duration"`
+}
+
+type WebhookStrategyConfig struct {
+	// Url of the external authorization service, the auth payload, method and network are POSTed as JSON.
+	Url     string            `yaml:"url" json:"url"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	Timeout Duration          `yaml:"timeout,omitempty" json:"timeout,omitempty" tstype:"Duration"`
+	// CacheTtl is how long allow/deny decisions are cached per credential, method and network.
+	CacheTtl Duration `yaml:"cacheTtl,omitempty" json:"cacheTtl,omitempty" tstype:"Duration"`
+	// FailOpen allows requests when the service is unreachable or errors (default is fail-closed).
+	FailOpen bool `yaml:"failOpen,omitempty" json:"failOpen,omitempty"`
+	// PayloadTypes limits which credentials are sent to the service, by default all payload types are.
+	PayloadTypes []AuthType `yaml:"payloadTypes,omitempty" json:"payloadTypes,omitempty" tstype:"TsAuthType[]"`
+}
+
+// custom json marshaller to redact the headers which often carry credentials for the service
+func (w *WebhookStrategyConfig) MarshalJSON() ([]byte, error) {
+	headers := make(map[string]string, len(w.Headers))
+	for k := range w.Headers {
+		headers[k] = "REDACTED"
+	}
+	return sonic.Marshal(map[string]interface{}{
+		"url":          w.Url,
+		"headers":      headers,
+		"timeout":      w.Timeout,
+		"cacheTtl":     w.CacheTtl,
+		"failOpen":     w.FailOpen,
+		"payloadTypes": w.PayloadTypes,
+	})
+}
+
+type MtlsStrategyConfig struct {
+	// CAFiles are PEM bundles of the CAs that client certificates must chain to.
+	CAFiles []string `yaml:"caFiles" json:"caFiles"`
+	// Rules are matched against the certificate identity in order, the first matching rule authorizes
+	// the client. When no rules are defined any certificate issued by the CAs is accepted.
+	Rules []*MtlsRuleConfig `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+type MtlsRuleConfig struct {
+	// Subject is a wildcard pattern matched against the certificate subject common name.
+	Subject string `yaml:"subject,omitempty" json:"subject,omitempty"`
+	// San is a wildcard pattern matched against DNS, URI (e.g. spiffe://) and email SANs.
+	San                 string   `yaml:"san,omitempty" json:"san,omitempty"`
+	RateLimitBudget     string   `yaml:"rateLimitBudget,omitempty" json:"rateLimitBudget,omitempty"`
+	AllowedNetworks     []string `yaml:"allowedNetworks,omitempty" json:"allowedNetworks,omitempty"`
+	AllowedMethods      []string `yaml:"allowedMethods,omitempty" json:"allowedMethods,omitempty"`
+	MaxBatchSize        int      `yaml:"maxBatchSize,omitempty" json:"maxBatchSize,omitempty"`
+	MaxBatchConcurrency int      `yaml:"maxBatchConcurrency,omitempty" json:"maxBatchConcurrency,omitempty"`
 }
 
 type SiweStrategyConfig struct {
@@ -1137,6 +1508,47 @@ type MetricsConfig struct {
 	Port             *int      `yaml:"port" json:"port"`
 	ErrorLabelMode   LabelMode `yaml:"errorLabelMode,omitempty" json:"errorLabelMode"`
 	HistogramBuckets string    `yaml:"histogramBuckets,omitempty" json:"histogramBuckets"`
+	// Otlp pushes the same metrics (names and labels) to an OTLP collector, independently of the Prometheus endpoint
+	Otlp *MetricsOtlpConfig `yaml:"otlp,omitempty" json:"otlp"`
+	// ConsumerLabel adds a "consumer" label to network request metrics, limited to the most active consumers
+	ConsumerLabel *MetricsConsumerLabelConfig `yaml:"consumerLabel,omitempty" json:"consumerLabel"`
+}
+
+type ConsumerLabelSource string
+
+const (
+	// ConsumerLabelSourceAuth uses the authenticated identity (secret key owner, JWT subject, SIWE address, etc.)
+	ConsumerLabelSourceAuth ConsumerLabelSource = "auth"
+	// ConsumerLabelSourceHeader uses the value of a request header (e.g. X-Team)
+	ConsumerLabelSourceHeader ConsumerLabelSource = "header"
+	// ConsumerLabelSourceUserAgent uses the User-Agent family (e.g. viem, ethers, curl, browser)
+	ConsumerLabelSourceUserAgent ConsumerLabelSource = "userAgent"
+)
+
+type MetricsConsumerLabelConfig struct {
+	Enabled bool                `yaml:"enabled,omitempty" json:"enabled"`
+	Source  ConsumerLabelSource `yaml:"source,omitempty" json:"source"`
+	Header  string              `yaml:"header,omitempty" json:"header"`
+	// MaxValues is how many distinct consumers get their own label value, others are reported as "other"
+	MaxValues int `yaml:"maxValues,omitempty" json:"maxValues"`
+	// RankingWindow is how often the most active consumers are re-ranked
+	RankingWindow Duration `yaml:"rankingWindow,omitempty" json:"rankingWindow" tstype:"Duration"`
+}
+
+type MetricsTemporality string
+
+const (
+	MetricsTemporalityCumulative MetricsTemporality = "cumulative"
+	MetricsTemporalityDelta      MetricsTemporality = "delta"
+)
+
+type MetricsOtlpConfig struct {
+	Enabled      bool               `yaml:"enabled,omitempty" json:"enabled"`
+	Endpoint     string             `yaml:"endpoint,omitempty" json:"endpoint"`
+	Protocol     TracingProtocol    `yaml:"protocol,omitempty" json:"protocol"`
+	TLS          *TLSConfig         `yaml:"tls,omitempty" json:"tls"`
+	PushInterval Duration           `yaml:"pushInterval,omitempty" json:"pushInterval" tstype:"Duration"`
+	Temporality  MetricsTemporality `yaml:"temporality,omitempty" json:"temporality"`
 }
 
 // GetProjectConfig returns the project configuration by the specified project ID.
@@ -1150,6 +1562,32 @@ func (c *Config) GetProjectConfig(projectId string) *ProjectConfig {
 	return nil
 }
 
+// HasMtlsAuth returns true when any project, admin or healthcheck auth uses the mtls strategy,
+// in which case the http server must request client certificates during the TLS handshake.
+func (c *Config) HasMtlsAuth() bool {
+	auths := []*AuthConfig{}
+	for _, project := range c.Projects {
+		auths = append(auths, project.Auth)
+	}
+	if c.Admin != nil {
+		auths = append(auths, c.Admin.Auth)
+	}
+	if c.HealthCheck != nil {
+		auths = append(auths, c.HealthCheck.Auth)
+	}
+	for _, a := range auths {
+		if a == nil {
+			continue
+		}
+		for _, s := range a.Strategies {
+			if s.Type == AuthTypeMtls {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (c *RateLimitRuleConfig) MarshalZerologObject(e *zerolog.Event) {
 	e.Str("method", c.Method).
 		Uint("maxCount", c.MaxCount).
@@ -38,6 +38,8 @@ type connectorScope string
 const (
 	connectorScopeSharedState connectorScope = "shared-state"
 	connectorScopeCache       connectorScope = "cache"
+	connectorScopeDisputeLog  connectorScope = "dispute-log"
+	connectorScopeSecretKeys  connectorScope = "secret-keys"
 )
 
 // DefaultOptions is used to pass env-provided or args-provided options to the config defaults initializer
@@ -70,6 +72,12 @@ func (c *Config) SetDefaults(opts *DefaultOptions) error {
 		}
 	}
 
+	if c.Logs != nil {
+		if err := c.Logs.SetDefaults(); err != nil {
+			return err
+		}
+	}
+
 	if c.Database != nil {
 		if err := c.Database.SetDefaults(c.ClusterKey); err != nil {
 			return err
@@ -546,10 +554,38 @@ func (c *TracingConfig) SetDefaults() error {
 	if c.SampleRate == 0 {
 		c.SampleRate = 1.0
 	}
+	if c.TailSampling != nil {
+		if c.TailSampling.LatencyThreshold == 0 {
+			c.TailSampling.LatencyThreshold = Duration(2 * time.Second)
+		}
+		if c.TailSampling.MaxSpansPerRequest == 0 {
+			c.TailSampling.MaxSpansPerRequest = 1000
+		}
+	}
+
+	return nil
+}
+
+func (c *LogsConfig) SetDefaults() error {
+	if c.Otlp != nil {
+		if c.Otlp.Protocol == "" {
+			c.Otlp.Protocol = TracingProtocolGrpc
+		}
+		if c.Otlp.Endpoint == "" {
+			c.Otlp.Endpoint = defaultOtlpEndpoint(c.Otlp.Protocol)
+		}
+	}
 
 	return nil
 }
 
+func defaultOtlpEndpoint(protocol TracingProtocol) string {
+	if protocol == TracingProtocolGrpc {
+		return "localhost:4317"
+	}
+	return "http://localhost:4318"
+}
+
 func (s *ServerConfig) SetDefaults() error {
 	if s.ListenV4 == nil {
 		if !util.IsTest() || os.Getenv("FORCE_TEST_LISTEN_V4") == "true" {
@@ -591,6 +627,25 @@ func (s *ServerConfig) SetDefaults() error {
 	if s.IncludeErrorDetails == nil {
 		s.IncludeErrorDetails = util.BoolPtr(true)
 	}
+	if s.Grpc != nil {
+		if err := s.Grpc.SetDefaults(); err != nil {
+			return fmt.Errorf("failed to set defaults for grpc server: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (g *GrpcServerConfig) SetDefaults() error {
+	if g.Enabled == nil {
+		g.Enabled = util.BoolPtr(true)
+	}
+	if g.Host == nil {
+		g.Host = util.StringPtr("0.0.0.0")
+	}
+	if g.Port == nil {
+		g.Port = util.IntPtr(4002)
+	}
 
 	return nil
 }
@@ -622,6 +677,31 @@ func (m *MetricsConfig) SetDefaults() error {
 	if m.ErrorLabelMode == "" {
 		m.ErrorLabelMode = ErrorLabelModeVerbose
 	}
+	if m.Otlp != nil {
+		if m.Otlp.Protocol == "" {
+			m.Otlp.Protocol = TracingProtocolGrpc
+		}
+		if m.Otlp.Endpoint == "" {
+			m.Otlp.Endpoint = defaultOtlpEndpoint(m.Otlp.Protocol)
+		}
+		if m.Otlp.PushInterval == 0 {
+			m.Otlp.PushInterval = Duration(15 * time.Second)
+		}
+		if m.Otlp.Temporality == "" {
+			m.Otlp.Temporality = MetricsTemporalityCumulative
+		}
+	}
+	if m.ConsumerLabel != nil {
+		if m.ConsumerLabel.Source == "" {
+			m.ConsumerLabel.Source = ConsumerLabelSourceAuth
+		}
+		if m.ConsumerLabel.MaxValues == 0 {
+			m.ConsumerLabel.MaxValues = 50
+		}
+		if m.ConsumerLabel.RankingWindow == 0 {
+			m.ConsumerLabel.RankingWindow = Duration(5 * time.Minute)
+		}
+	}
 
 	return nil
 }
@@ -672,6 +752,24 @@ func (c *SharedStateConfig) SetDefaults(defClusterKey string) error {
 	if c.LockTtl == 0 {
 		c.LockTtl = Duration(30 * time.Second)
 	}
+	if c.LeaderElection != nil {
+		if err := c.LeaderElection.SetDefaults(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LeaderElectionConfig) SetDefaults() error {
+	if l.Enabled == nil {
+		l.Enabled = util.BoolPtr(true)
+	}
+	if l.LeaseTtl == 0 {
+		l.LeaseTtl = Duration(15 * time.Second)
+	}
+	if l.RenewInterval == 0 {
+		l.RenewInterval = Duration(l.LeaseTtl.Duration() / 3)
+	}
 	return nil
 }
 
@@ -831,6 +929,10 @@ func (p *PostgreSQLConnectorConfig) SetDefaults(scope connectorScope) error {
 			p.Table = "erpc_shared_state"
 		case connectorScopeCache:
 			p.Table = "erpc_json_rpc_cache"
+		case connectorScopeDisputeLog:
+			p.Table = "erpc_dispute_log"
+		case connectorScopeSecretKeys:
+			p.Table = "erpc_secret_keys"
 		default:
 			return fmt.Errorf("invalid connector scope: %s", scope)
 		}
@@ -861,6 +963,10 @@ func (d *DynamoDBConnectorConfig) SetDefaults(scope connectorScope) error {
 			d.Table = "erpc_shared_state"
 		case connectorScopeCache:
 			d.Table = "erpc_json_rpc_cache"
+		case connectorScopeDisputeLog:
+			d.Table = "erpc_dispute_log"
+		case connectorScopeSecretKeys:
+			d.Table = "erpc_secret_keys"
 		default:
 			return fmt.Errorf("invalid connector scope: %s", scope)
 		}
@@ -989,7 +1095,93 @@ func (p *ProjectConfig) SetDefaults(opts *DefaultOptions) error {
 			p.ScoreMetricsWindowSize = Duration(10 * time.Minute)
 		}
 	}
+	if p.DisputeLog != nil {
+		if err := p.DisputeLog.SetDefaults(); err != nil {
+			return fmt.Errorf("failed to set defaults for dispute log: %w", err)
+		}
+	}
+	if p.Firewall != nil {
+		if err := p.Firewall.SetDefaults(); err != nil {
+			return fmt.Errorf("failed to set defaults for firewall: %w", err)
+		}
+	}
+	if p.Capture != nil {
+		if err := p.Capture.SetDefaults(); err != nil {
+			return fmt.Errorf("failed to set defaults for capture: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (f *FirewallConfig) SetDefaults() error {
+	for i, rule := range f.Rules {
+		if rule == nil {
+			continue
+		}
+		if rule.Id == "" {
+			rule.Id = fmt.Sprintf("rule-%d", i)
+		}
+		if rule.Action == "" {
+			rule.Action = FirewallActionDeny
+		}
+		if rule.Network == "" {
+			rule.Network = "*"
+		}
+		if rule.Method == "" {
+			rule.Method = "*"
+		}
+	}
+	return nil
+}
+
+func (d *DisputeLogConfig) SetDefaults() error {
+	if d.Connector == nil {
+		d.Connector = &ConnectorConfig{
+			Driver: DriverMemory,
+			Memory: &MemoryConnectorConfig{
+				MaxItems:     10_000,
+				MaxTotalSize: "100MB",
+			},
+		}
+	}
+	if d.Connector.Id == "" {
+		d.Connector.Id = "dispute-log"
+	}
+	if err := d.Connector.SetDefaults(connectorScopeDisputeLog); err != nil {
+		return fmt.Errorf("failed to set defaults for connector: %w", err)
+	}
+	if d.Retention == 0 {
+		d.Retention = Duration(7 * 24 * time.Hour)
+	}
+	if d.MaxEntries == 0 {
+		d.MaxEntries = 1000
+	}
+	if d.MaxBodySize == nil {
+		d.MaxBodySize = util.IntPtr(4096)
+	}
+
+	return nil
+}
 
+func (c *CaptureConfig) SetDefaults() error {
+	if c.SampleRate == 0 {
+		c.SampleRate = 0.01
+	}
+	if c.Method == "" {
+		c.Method = "*"
+	}
+	if c.MaxResultSize == 0 {
+		c.MaxResultSize = 1024 * 1024
+	}
+	if c.BufferSize == 0 {
+		c.BufferSize = 1000
+	}
+	for _, rule := range c.Redact {
+		if rule.Method == "" {
+			rule.Method = "*"
+		}
+	}
 	return nil
 }
 
@@ -1019,6 +1211,27 @@ func convertUpstreamToProvider(upstream *UpstreamConfig) (*ProviderConfig, error
 	upsCfg.Endpoint = ""
 	upsCfg.Id = ""
 
+	// dns+http(s):// and srv+http(s):// endpoints are expanded into one upstream per resolved node by the "dns" vendor,
+	// a configured chainId limits the provider to that network instead of detecting it from the nodes.
+	var onlyNetworks []string
+	if strings.HasPrefix(vendorName, "dns+") || strings.HasPrefix(vendorName, "srv+") {
+		vendorName = "dns"
+		// Chain id detection talks to the nodes directly, so it needs the same headers and proxy as the upstreams
+		if upstream.JsonRpc != nil {
+			if len(upstream.JsonRpc.Headers) > 0 {
+				settings["headers"] = upstream.JsonRpc.Headers
+			}
+			if upstream.JsonRpc.ProxyPool != "" {
+				settings["proxyPool"] = upstream.JsonRpc.ProxyPool
+			}
+		}
+		if upstream.Evm != nil && upstream.Evm.ChainId != 0 {
+			onlyNetworks = []string{fmt.Sprintf("evm:%d", upstream.Evm.ChainId)}
+			upsCfg.Evm = upstream.Evm.Copy()
+			upsCfg.Evm.ChainId = 0
+		}
+	}
+
 	id := upstream.Id
 	// Generate a unique provider ID that includes the vendor name and a hash of the endpoint
 	// This ensures that multiple instances of the same provider with different credentials
@@ -1031,6 +1244,7 @@ func convertUpstreamToProvider(upstream *UpstreamConfig) (*ProviderConfig, error
 		Id:                 id,
 		Vendor:             vendorName,
 		Settings:           settings,
+		OnlyNetworks:       onlyNetworks,
 		UpstreamIdTemplate: "<PROVIDER>-<NETWORK>",
 		Overrides: map[string]*UpstreamConfig{
 			"*": upsCfg,
@@ -1154,6 +1368,14 @@ func buildProviderSettings(vendorName string, endpoint *url.URL) (VendorSettings
 			}
 		}
 		return settings, nil
+	case "dns+http", "dns+https", "srv+http", "srv+https":
+		mode, scheme, _ := strings.Cut(vendorName, "+")
+		target := *endpoint
+		target.Scheme = scheme
+		return VendorSettings{
+			"mode":     mode,
+			"endpoint": target.String(),
+		}, nil
 	case "repository", "evm+repository":
 		return VendorSettings{
 			"repositoryUrl": "https://" + endpoint.Host + "/" + strings.TrimPrefix(endpoint.Path, "/") + "?" + endpoint.RawQuery,
@@ -1513,10 +1735,47 @@ func (e *EvmUpstreamConfig) SetDefaults(defaults *EvmUpstreamConfig) error {
 		}
 	}
 
+	if e.HeadSubscription == nil && defaults != nil && defaults.HeadSubscription != nil {
+		hs := *defaults.HeadSubscription
+		e.HeadSubscription = &hs
+	}
+	if e.HeadSubscription != nil {
+		if err := e.HeadSubscription.SetDefaults(e.StatePollerInterval); err != nil {
+			return fmt.Errorf("failed to set defaults for head subscription: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SetDefaults derives timings from the upstream statePollerInterval, which is tuned to the chain block time,
+// so that a stalled subscription is noticed within a few blocks.
+func (h *EvmHeadSubscriptionConfig) SetDefaults(statePollerInterval Duration) error {
+	if h.StaleTimeout == 0 {
+		h.StaleTimeout = Duration(max(5*time.Second, 10*statePollerInterval.Duration()))
+	}
+	if h.MaxReconnectDelay == 0 {
+		h.MaxReconnectDelay = Duration(30 * time.Second)
+	}
+	if h.PollerInterval == 0 {
+		h.PollerInterval = statePollerInterval
+	}
 	return nil
 }
 
+// DefaultStreamingMethods are methods known to return very large results (logs and traces) which are
+// streamed when jsonRpc.streamingThreshold is set.
+var DefaultStreamingMethods = []string{
+	"eth_getLogs",
+	"trace_*",
+	"debug_trace*",
+	"arbtrace_*",
+}
+
 func (j *JsonRpcUpstreamConfig) SetDefaults() error {
+	if j.StreamingThreshold != "" && len(j.StreamingMethods) == 0 {
+		j.StreamingMethods = slices.Clone(DefaultStreamingMethods)
+	}
 	return nil
 }
 
@@ -1525,6 +1784,9 @@ func (n *NetworkConfig) SetDefaults(upstreams []*UpstreamConfig, defaults *Netwo
 		if n.RateLimitBudget == "" {
 			n.RateLimitBudget = defaults.RateLimitBudget
 		}
+		if n.RetryBudget == nil && defaults.RetryBudget != nil {
+			n.RetryBudget = defaults.RetryBudget.Copy()
+		}
 		if len(defaults.Failsafe) > 0 {
 			if len(n.Failsafe) == 0 {
 				n.Failsafe = make([]*FailsafeConfig, len(defaults.Failsafe))
@@ -1580,6 +1842,10 @@ func (n *NetworkConfig) SetDefaults(upstreams []*UpstreamConfig, defaults *Netwo
 				n.Evm.Integrity = &EvmIntegrityConfig{}
 				*n.Evm.Integrity = *defaults.Evm.Integrity
 			}
+			if n.Evm.TransactionBroadcast == nil && defaults.Evm.TransactionBroadcast != nil {
+				n.Evm.TransactionBroadcast = &EvmTransactionBroadcastConfig{}
+				*n.Evm.TransactionBroadcast = *defaults.Evm.TransactionBroadcast
+			}
 			if n.Evm.FallbackStatePollerDebounce == 0 && defaults.Evm.FallbackStatePollerDebounce != 0 {
 				n.Evm.FallbackStatePollerDebounce = defaults.Evm.FallbackStatePollerDebounce
 			}
@@ -1604,6 +1870,12 @@ func (n *NetworkConfig) SetDefaults(upstreams []*UpstreamConfig, defaults *Netwo
 		}
 	}
 
+	if n.RetryBudget != nil {
+		if err := n.RetryBudget.SetDefaults(); err != nil {
+			return fmt.Errorf("failed to set defaults for retry budget: %w", err)
+		}
+	}
+
 	if n.Architecture == "" {
 		if n.Evm != nil {
 			n.Architecture = "evm"
@@ -1764,6 +2036,26 @@ func (t *TimeoutPolicyConfig) SetDefaults(defaults *TimeoutPolicyConfig) error {
 	if defaults != nil && t.Duration == 0 {
 		t.Duration = defaults.Duration
 	}
+	if defaults != nil && t.Quantile == 0 && defaults.Quantile > 0 {
+		t.Quantile = defaults.Quantile
+		if t.Multiplier == 0 {
+			t.Multiplier = defaults.Multiplier
+		}
+		if t.MinDuration == 0 {
+			t.MinDuration = defaults.MinDuration
+		}
+		if t.MaxDuration == 0 {
+			t.MaxDuration = defaults.MaxDuration
+		}
+	}
+	if t.Quantile > 0 {
+		if t.Multiplier == 0 {
+			t.Multiplier = 1
+		}
+		if t.MaxDuration == 0 {
+			t.MaxDuration = t.Duration
+		}
+	}
 
 	return nil
 }
@@ -1812,6 +2104,16 @@ func (r *RetryPolicyConfig) SetDefaults(defaults *RetryPolicyConfig) error {
 	if r.EmptyResultIgnore == nil && defaults != nil && defaults.EmptyResultIgnore != nil {
 		r.EmptyResultIgnore = defaults.EmptyResultIgnore
 	}
+	return nil
+}
+
+func (b *RetryBudgetConfig) SetDefaults() error {
+	if b.Window == 0 {
+		b.Window = Duration(10 * time.Second)
+	}
+	if b.MinRetries == 0 {
+		b.MinRetries = 10
+	}
 
 	return nil
 }
@@ -2098,14 +2400,60 @@ func (s *AuthStrategyConfig) SetDefaults() error {
 		}
 	}
 
+	if s.Type == AuthTypeWebhook && s.Webhook == nil {
+		s.Webhook = &WebhookStrategyConfig{}
+	}
+	if s.Webhook != nil {
+		s.Type = AuthTypeWebhook
+		if err := s.Webhook.SetDefaults(); err != nil {
+			return fmt.Errorf("failed to set defaults for webhook strategy: %w", err)
+		}
+	}
+
+	if s.Type == AuthTypeMtls && s.Mtls == nil {
+		s.Mtls = &MtlsStrategyConfig{}
+	}
+	if s.Mtls != nil {
+		s.Type = AuthTypeMtls
+	}
+
 	return nil
 }
 
 func (s *SecretStrategyConfig) SetDefaults() error {
+	if (s.KeysFile != "" || s.KeysConnector != nil) && s.KeysRefreshInterval == 0 {
+		s.KeysRefreshInterval = Duration(30 * time.Second)
+	}
+	if s.KeysConnector != nil {
+		if s.KeysConnector.Id == "" {
+			s.KeysConnector.Id = "secret-keys"
+		}
+		if err := s.KeysConnector.SetDefaults(connectorScopeSecretKeys); err != nil {
+			return fmt.Errorf("failed to set defaults for keys connector: %w", err)
+		}
+	}
 	return nil
 }
 
 func (j *JwtStrategyConfig) SetDefaults() error {
+	if len(j.JwksUrls) > 0 {
+		if j.JwksRefreshInterval == 0 {
+			j.JwksRefreshInterval = Duration(1 * time.Hour)
+		}
+		if j.JwksMinRefreshInterval == 0 {
+			j.JwksMinRefreshInterval = Duration(1 * time.Minute)
+		}
+	}
+	return nil
+}
+
+func (w *WebhookStrategyConfig) SetDefaults() error {
+	if w.Timeout == 0 {
+		w.Timeout = Duration(2 * time.Second)
+	}
+	if w.CacheTtl == 0 {
+		w.CacheTtl = Duration(10 * time.Second)
+	}
 	return nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/erpc/erpc/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -210,6 +211,72 @@ func TestSetDefaults_UpstreamConfig(t *testing.T) {
 		assert.Nil(t, err, "Validate should pass when only a provider is present")
 	})
 
+	t.Run("DnsExpandedEndpointsShouldConvertToDnsProvider", func(t *testing.T) {
+		cfg := &Config{
+			Projects: []*ProjectConfig{
+				{
+					Id: "test-dns",
+					Upstreams: []*UpstreamConfig{
+						{
+							Id:       "eth-nodes",
+							Endpoint: "dns+http://eth-nodes.internal:8545/rpc",
+							Evm:      &EvmUpstreamConfig{ChainId: 1},
+						},
+						{
+							Endpoint: "srv+https://_rpc._tcp.nodes.internal",
+							JsonRpc:  &JsonRpcUpstreamConfig{Headers: map[string]string{"Authorization": "Bearer xxx"}},
+						},
+					},
+				},
+			},
+		}
+
+		err := cfg.SetDefaults(&DefaultOptions{})
+		assert.Nil(t, err)
+
+		project := cfg.Projects[0]
+		assert.Len(t, project.Upstreams, 0)
+		assert.Len(t, project.Providers, 2)
+
+		assert.Equal(t, "dns", project.Providers[0].Vendor)
+		assert.Equal(t, "eth-nodes", project.Providers[0].Id)
+		assert.Equal(t, VendorSettings{"mode": "dns", "endpoint": "http://eth-nodes.internal:8545/rpc"}, project.Providers[0].Settings)
+		assert.Equal(t, []string{"evm:1"}, project.Providers[0].OnlyNetworks)
+		assert.Equal(t, int64(0), project.Providers[0].Overrides["*"].Evm.ChainId)
+
+		assert.Equal(t, "dns", project.Providers[1].Vendor)
+		assert.Equal(t, VendorSettings{
+			"mode":     "srv",
+			"endpoint": "https://_rpc._tcp.nodes.internal",
+			"headers":  map[string]string{"Authorization": "Bearer xxx"},
+		}, project.Providers[1].Settings)
+		assert.Nil(t, project.Providers[1].OnlyNetworks)
+
+		err = project.Validate(cfg)
+		assert.Nil(t, err)
+	})
+
+	t.Run("DnsExpandedHttpsEndpointsShouldBeRejected", func(t *testing.T) {
+		cfg := &Config{
+			Projects: []*ProjectConfig{
+				{
+					Id: "test-dns-https",
+					Upstreams: []*UpstreamConfig{
+						{
+							Endpoint: "dns+https://eth-nodes.internal",
+						},
+					},
+				},
+			},
+		}
+
+		err := cfg.SetDefaults(&DefaultOptions{})
+		assert.Nil(t, err)
+
+		err = cfg.Projects[0].Validate(cfg)
+		assert.ErrorContains(t, err, "cannot be https in 'dns' mode")
+	})
+
 	t.Run("IndividualAndGlobalUpstreamDefaultsShouldBeAppliedAndValidatedSuccessfully", func(t *testing.T) {
 		cfg := &Config{
 			Projects: []*ProjectConfig{
@@ -289,6 +356,42 @@ func TestSetDefaults_UpstreamConfig(t *testing.T) {
 	})
 }
 
+func TestSetDefaults_EvmHeadSubscriptionConfig(t *testing.T) {
+	t.Run("TimingsFollowStatePollerInterval", func(t *testing.T) {
+		cfg := &EvmHeadSubscriptionConfig{}
+		assert.NoError(t, cfg.SetDefaults(Duration(2*time.Second)))
+		assert.Equal(t, Duration(20*time.Second), cfg.StaleTimeout)
+		assert.Equal(t, Duration(2*time.Second), cfg.PollerInterval)
+	})
+
+	t.Run("StaleTimeoutIsAtLeastFiveSeconds", func(t *testing.T) {
+		cfg := &EvmHeadSubscriptionConfig{}
+		assert.NoError(t, cfg.SetDefaults(Duration(100*time.Millisecond)))
+		assert.Equal(t, Duration(5*time.Second), cfg.StaleTimeout)
+	})
+
+	t.Run("PollerIntervalCannotExceedStatePollerInterval", func(t *testing.T) {
+		evm := &EvmUpstreamConfig{
+			ChainId:             1,
+			StatePollerInterval: Duration(30 * time.Second),
+			HeadSubscription:    &EvmHeadSubscriptionConfig{PollerInterval: Duration(time.Minute)},
+		}
+		assert.NoError(t, evm.SetDefaults(nil))
+		assert.ErrorContains(t, evm.Validate(&UpstreamConfig{Endpoint: "https://rpc.example.com"}), "pollerInterval")
+	})
+}
+
+func TestSetDefaults_DisputeLogConfig(t *testing.T) {
+	cfg := &DisputeLogConfig{}
+	assert.NoError(t, cfg.SetDefaults())
+	assert.Equal(t, 4096, *cfg.MaxBodySize)
+
+	cfg = &DisputeLogConfig{MaxBodySize: util.IntPtr(0)}
+	assert.NoError(t, cfg.SetDefaults())
+	assert.Equal(t, 0, *cfg.MaxBodySize, "an explicit 0 must not be replaced by the default")
+	assert.NoError(t, cfg.Validate())
+}
+
 func TestBuildProviderSettings(t *testing.T) {
 	// Test case for Chainstack with query parameters
 	t.Run("chainstack with filters", func(t *testing.T) {
@@ -397,6 +397,47 @@ func (e *ErrInvalidRequest) ErrorStatusCode() int {
 	return http.StatusBadRequest
 }
 
+type ErrRequestBodyTooLarge struct{ BaseError }
+
+const ErrCodeRequestBodyTooLarge ErrorCode = "ErrRequestBodyTooLarge"
+
+var NewErrRequestBodyTooLarge = func(maxSize int64) error {
+	return &ErrRequestBodyTooLarge{
+		BaseError{
+			Code:    ErrCodeRequestBodyTooLarge,
+			Message: fmt.Sprintf("request body exceeds the maximum allowed size of %d bytes", maxSize),
+			Details: map[string]interface{}{
+				"maxSize": maxSize,
+			},
+		},
+	}
+}
+
+func (e *ErrRequestBodyTooLarge) ErrorStatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+type ErrBatchTooLarge struct{ BaseError }
+
+const ErrCodeBatchTooLarge ErrorCode = "ErrBatchTooLarge"
+
+var NewErrBatchTooLarge = func(size int, maxSize int) error {
+	return &ErrBatchTooLarge{
+		BaseError{
+			Code:    ErrCodeBatchTooLarge,
+			Message: fmt.Sprintf("batch of %d requests exceeds the maximum allowed size of %d", size, maxSize),
+			Details: map[string]interface{}{
+				"size":    size,
+				"maxSize": maxSize,
+			},
+		},
+	}
+}
+
+func (e *ErrBatchTooLarge) ErrorStatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
 type ErrInvalidUrlPath struct{ BaseError }
 
 const ErrCodeInvalidUrlPath ErrorCode = "ErrInvalidUrlPath"
@@ -505,6 +546,26 @@ func (e *ErrAuthRateLimitRuleExceeded) ErrorStatusCode() int {
 	return http.StatusTooManyRequests
 }
 
+type ErrAuthForbidden struct{ BaseError }
+
+const ErrCodeAuthForbidden ErrorCode = "ErrAuthForbidden"
+
+var NewErrAuthForbidden = func(strategy string, message string) error {
+	return &ErrAuthForbidden{
+		BaseError{
+			Code:    ErrCodeAuthForbidden,
+			Message: message,
+			Details: map[string]interface{}{
+				"strategy": strategy,
+			},
+		},
+	}
+}
+
+func (e *ErrAuthForbidden) ErrorStatusCode() int {
+	return http.StatusForbidden
+}
+
 //
 // Projects
 //
@@ -1527,6 +1588,43 @@ func (e *ErrFailsafeRetryExceeded) DeepestMessage() string {
 	return e.Message
 }
 
+type ErrFailsafeRetryBudgetExhausted struct{ BaseError }
+
+const ErrCodeFailsafeRetryBudgetExhausted ErrorCode = "ErrFailsafeRetryBudgetExhausted"
+
+var NewErrFailsafeRetryBudgetExhausted = func(scope Scope, cause error) error {
+	return &ErrFailsafeRetryBudgetExhausted{
+		BaseError{
+			Code:    ErrCodeFailsafeRetryBudgetExhausted,
+			Message: fmt.Sprintf("retry budget exhausted on %s-level, no more retries allowed for now", scope),
+			Cause:   cause,
+			Details: map[string]interface{}{
+				"scope": scope,
+			},
+		},
+	}
+}
+
+func (e *ErrFailsafeRetryBudgetExhausted) ErrorStatusCode() int {
+	if e.Cause != nil {
+		if se, ok := e.Cause.(StandardError); ok {
+			return se.ErrorStatusCode()
+		}
+	}
+	return http.StatusServiceUnavailable
+}
+
+func (e *ErrFailsafeRetryBudgetExhausted) DeepestMessage() string {
+	if e.Cause != nil {
+		if se, ok := e.Cause.(StandardError); ok {
+			return fmt.Sprintf("%s: %s", e.Message, se.DeepestMessage())
+		} else {
+			return fmt.Sprintf("%s: %s", e.Message, e.Cause)
+		}
+	}
+	return e.Message
+}
+
 type ErrFailsafeCircuitBreakerOpen struct{ BaseError }
 
 const ErrCodeFailsafeCircuitBreakerOpen ErrorCode = "ErrFailsafeCircuitBreakerOpen"
@@ -1622,6 +1720,30 @@ func (e *ErrProjectRateLimitRuleExceeded) ErrorStatusCode() int {
 	return http.StatusTooManyRequests
 }
 
+type ErrRequestBlockedByFirewall struct{ BaseError }
+
+const ErrCodeRequestBlockedByFirewall ErrorCode = "ErrRequestBlockedByFirewall"
+
+var NewErrRequestBlockedByFirewall = func(project string, rule string, message string) error {
+	if message == "" {
+		message = "request blocked by firewall rule"
+	}
+	return &ErrRequestBlockedByFirewall{
+		BaseError{
+			Code:    ErrCodeRequestBlockedByFirewall,
+			Message: message,
+			Details: map[string]interface{}{
+				"project": project,
+				"rule":    rule,
+			},
+		},
+	}
+}
+
+func (e *ErrRequestBlockedByFirewall) ErrorStatusCode() int {
+	return http.StatusForbidden
+}
+
 type ErrNetworkRateLimitRuleExceeded struct{ BaseError }
 
 const ErrCodeNetworkRateLimitRuleExceeded ErrorCode = "ErrNetworkRateLimitRuleExceeded"
@@ -2317,6 +2439,7 @@ func IsClientError(err error) bool {
 		ErrCodeUpstreamGetLogsExceededMaxAllowedRange,
 		ErrCodeUpstreamGetLogsExceededMaxAllowedAddresses,
 		ErrCodeUpstreamGetLogsExceededMaxAllowedTopics,
+		ErrCodeRequestBlockedByFirewall,
 	))
 }
 
@@ -1,10 +1,12 @@
 package common
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
@@ -158,9 +160,9 @@ func (r *JsonRpcResponse) ID() interface{} {
 
 func (r *JsonRpcResponse) SetIDBytes(idBytes []byte) error {
 	r.idMu.Lock()
-	defer r.idMu.Unlock()
-
 	r.idBytes = idBytes
+	r.idMu.Unlock()
+
 	return r.parseID()
 }
 
@@ -222,6 +224,69 @@ func (r *JsonRpcResponse) SetResultWriter(w util.ByteWriter) {
 	r.resultWriter = w
 }
 
+// IsStreaming returns true when the result is backed by a one-shot stream (see StreamingResultWriter)
+// which is not read in memory yet.
+func (r *JsonRpcResponse) IsStreaming() bool {
+	if r == nil {
+		return false
+	}
+	r.resultMu.RLock()
+	defer r.resultMu.RUnlock()
+	if len(r.Result) > 0 {
+		return false
+	}
+	sw, ok := r.resultWriter.(StreamingResultWriter)
+	return ok && sw.IsStreaming()
+}
+
+// Materialize reads a streamed result in memory so that it can be inspected or written more than once.
+// It is a no-op for results that are not streamed.
+func (r *JsonRpcResponse) Materialize() error {
+	if !r.IsStreaming() {
+		return nil
+	}
+	r.resultMu.Lock()
+	defer r.resultMu.Unlock()
+	if len(r.Result) > 0 {
+		return nil
+	}
+	sw, ok := r.resultWriter.(StreamingResultWriter)
+	if !ok {
+		return nil
+	}
+	data, err := sw.Materialize()
+	if err != nil {
+		return err
+	}
+	r.Result = data
+	r.cachedNode = nil
+	return nil
+}
+
+// OnResultComplete registers fn to receive a copy of a streamed result once it is fully written, as long as
+// it is not bigger than maxSize. Returns false when the result is not streamed or streaming already started.
+func (r *JsonRpcResponse) OnResultComplete(maxSize int, fn func(result []byte)) bool {
+	r.resultMu.RLock()
+	defer r.resultMu.RUnlock()
+	sw, ok := r.resultWriter.(StreamingResultWriter)
+	if !ok || len(r.Result) > 0 {
+		return false
+	}
+	return sw.OnComplete(maxSize, fn)
+}
+
+// CloseStream releases the upstream body of a streamed result that is not going to be written.
+func (r *JsonRpcResponse) CloseStream() {
+	if r == nil {
+		return
+	}
+	r.resultMu.RLock()
+	defer r.resultMu.RUnlock()
+	if c, ok := r.resultWriter.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
+
 func (r *JsonRpcResponse) ParseError(raw string) error {
 	r.errMu.Lock()
 	defer r.errMu.Unlock()
@@ -293,6 +358,9 @@ func (r *JsonRpcResponse) PeekStringByPath(ctx context.Context, path ...interfac
 	_, span := StartDetailSpan(ctx, "JsonRpcResponse.PeekStringByPath")
 	defer span.End()
 
+	if err := r.Materialize(); err != nil {
+		return "", err
+	}
 	err := r.ensureCachedNode()
 	if err != nil {
 		return "", err
@@ -363,6 +431,9 @@ func (r *JsonRpcResponse) MarshalZerologObject(e *zerolog.Event) {
 			}
 			e.Str("resultHead", util.B2Str(r.Result[:head])).Str("resultTail", util.B2Str(r.Result[tail:]))
 		}
+	} else if sw, ok := r.resultWriter.(StreamingResultWriter); ok && sw.IsStreaming() {
+		// Checking emptiness of a result still in flight could wait for upstreams
+		e.Bool("resultStreaming", true)
 	} else if r.resultWriter != nil {
 		e.Bool("resultWriterEmpty", r.resultWriter.IsResultEmptyish())
 	}
@@ -511,6 +582,11 @@ func (r *JsonRpcResponse) Clone() (*JsonRpcResponse, error) {
 		return nil, nil
 	}
 
+	// A streamed result can only be written once, so clones need it in memory
+	if err := r.Materialize(); err != nil {
+		return nil, err
+	}
+
 	r.idMu.RLock()
 	defer r.idMu.RUnlock()
 	r.errMu.RLock()
@@ -600,6 +676,9 @@ func (r *JsonRpcResponse) CanonicalHash(ctx ...context.Context) (string, error)
 	}
 
 	// Compute hash
+	if err := r.Materialize(); err != nil {
+		return "", err
+	}
 	r.resultMu.RLock()
 	resultCopy := r.Result
 	r.resultMu.RUnlock()
@@ -623,13 +702,97 @@ func (r *JsonRpcResponse) CanonicalHash(ctx ...context.Context) (string, error)
 	return hash, nil
 }
 
+// CanonicalHashFromReader returns the same hash as CanonicalHash for a raw result read from r. Items of
+// a top-level array (e.g. logs or traces) are decoded one at a time, so big results are never held as a
+// whole decoded tree.
+func CanonicalHashFromReader(r io.Reader) (string, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(br)
+	h := sha256.New()
+
+	if first != '[' {
+		var obj interface{}
+		if err := dec.Decode(&obj); err != nil {
+			return "", err
+		}
+		canonical, err := canonicalize(obj)
+		if err != nil {
+			return "", err
+		}
+		h.Write(canonical)
+		return fmt.Sprintf("%x", h.Sum(nil)), nil
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return "", err
+	}
+	written := false
+	for dec.More() {
+		var item interface{}
+		if err := dec.Decode(&item); err != nil {
+			return "", err
+		}
+		if isEmptyishValue(item) {
+			continue
+		}
+		b, err := canonicalize(item)
+		if err != nil {
+			return "", err
+		}
+		if len(b) == 0 {
+			continue
+		}
+		if written {
+			h.Write([]byte{','})
+		} else {
+			h.Write([]byte{'['})
+		}
+		written = true
+		h.Write(b)
+	}
+	if _, err := dec.Token(); err != nil {
+		return "", err
+	}
+	if written {
+		h.Write([]byte{']'})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
+
 // CanonicalHashWithIgnoredFields calculates the canonical hash while ignoring specified field paths
 func (r *JsonRpcResponse) CanonicalHashWithIgnoredFields(ignoreFields []string, ctx ...context.Context) (string, error) {
+	return r.CanonicalHashWithFields(nil, ignoreFields, ctx...)
+}
+
+// CanonicalHashWithFields calculates the canonical hash of only the included field paths (or the whole result
+// when no include paths are given) while ignoring specified field paths
+func (r *JsonRpcResponse) CanonicalHashWithFields(includeFields []string, ignoreFields []string, ctx ...context.Context) (string, error) {
 	if r == nil {
 		return "", nil
 	}
 
-	// Compute hash with ignored fields (no caching for this variant)
+	// Compute hash with selected fields (no caching for this variant)
+	if err := r.Materialize(); err != nil {
+		return "", err
+	}
 	r.resultMu.RLock()
 	resultCopy := r.Result
 	r.resultMu.RUnlock()
@@ -639,7 +802,10 @@ func (r *JsonRpcResponse) CanonicalHashWithIgnoredFields(ignoreFields []string,
 		return "", err
 	}
 
-	// Remove ignored fields before canonicalization
+	// Keep only included fields, then remove ignored fields before canonicalization
+	if len(includeFields) > 0 {
+		obj = keepFieldsByPaths(obj, includeFields)
+	}
 	if len(ignoreFields) > 0 {
 		obj = removeFieldsByPaths(obj, ignoreFields)
 	}
@@ -665,7 +831,11 @@ func removeFieldsByPaths(obj interface{}, paths []string) interface{} {
 		return obj
 	}
 
-	// Parse paths into a tree structure for efficient removal
+	return removeFieldsRecursive(obj, buildFieldPathTree(paths))
+}
+
+// buildFieldPathTree parses dot-separated paths into a tree structure for efficient traversal
+func buildFieldPathTree(paths []string) map[string]interface{} {
 	pathTree := make(map[string]interface{})
 	for _, path := range paths {
 		parts := strings.Split(path, ".")
@@ -683,8 +853,61 @@ func removeFieldsByPaths(obj interface{}, paths []string) interface{} {
 			}
 		}
 	}
+	return pathTree
+}
+
+// keepFieldsByPaths keeps only the fields of an object matching dot-separated paths
+// using the same syntax as removeFieldsByPaths. Arrays without a wildcard apply the
+// paths to each of their items, and primitive values reached by a nested path are kept as-is
+// (e.g. "transactions.*.hash" also matches a block with only transaction hashes).
+func keepFieldsByPaths(obj interface{}, paths []string) interface{} {
+	if obj == nil || len(paths) == 0 {
+		return obj
+	}
+
+	return keepFieldsRecursive(obj, buildFieldPathTree(paths))
+}
+
+// keepFieldsRecursive recursively keeps fields based on the path tree
+func keepFieldsRecursive(obj interface{}, pathTree map[string]interface{}) interface{} {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		for key, value := range v {
+			pathNode, exists := pathTree[key]
+			if !exists {
+				pathNode, exists = pathTree["*"]
+			}
+			if !exists {
+				continue
+			}
+			if pathNode == true {
+				result[key] = value
+			} else if nestedTree, ok := pathNode.(map[string]interface{}); ok {
+				result[key] = keepFieldsRecursive(value, nestedTree)
+			}
+		}
+		return result
+
+	case []interface{}:
+		itemTree := pathTree
+		if wildcardTree, exists := pathTree["*"]; exists {
+			if wildcardTree == true {
+				return v
+			}
+			if nestedTree, ok := wildcardTree.(map[string]interface{}); ok {
+				itemTree = nestedTree
+			}
+		}
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = keepFieldsRecursive(item, itemTree)
+		}
+		return result
 
-	return removeFieldsRecursive(obj, pathTree)
+	default:
+		return v
+	}
 }
 
 // removeFieldsRecursive recursively removes fields based on the path tree
@@ -1252,6 +1475,53 @@ func TranslateToJsonRpcException(err error) error {
 			nil,
 		)
 	}
+	if HasErrorCode(
+		err,
+		ErrCodeAuthForbidden,
+	) {
+		return NewErrJsonRpcExceptionInternal(
+			0,
+			JsonRpcErrorUnauthorized,
+			"forbidden",
+			err,
+			nil,
+		)
+	}
+	rbe := &ErrRequestBodyTooLarge{}
+	if errors.As(err, &rbe) {
+		return NewErrJsonRpcExceptionInternal(
+			0,
+			JsonRpcErrorClientSideException,
+			rbe.Message,
+			err,
+			nil,
+		)
+	}
+	bte := &ErrBatchTooLarge{}
+	if errors.As(err, &bte) {
+		return NewErrJsonRpcExceptionInternal(
+			0,
+			JsonRpcErrorClientSideException,
+			bte.Message,
+			err,
+			map[string]interface{}{
+				"data": map[string]interface{}{"maxSize": bte.Details["maxSize"]},
+			},
+		)
+	}
+	fwe := &ErrRequestBlockedByFirewall{}
+	if errors.As(err, &fwe) {
+		rule, _ := fwe.Details["rule"].(string)
+		return NewErrJsonRpcExceptionInternal(
+			0,
+			JsonRpcErrorClientSideException,
+			fmt.Sprintf("request blocked by firewall rule '%s': %s", rule, fwe.Message),
+			err,
+			map[string]interface{}{
+				"data": map[string]interface{}{"rule": rule},
+			},
+		)
+	}
 	if HasErrorCode(err, ErrCodeUpstreamMethodIgnored) {
 		return NewErrJsonRpcExceptionInternal(
 			0,
@@ -250,3 +250,80 @@ func TestRemoveFieldsByPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestKeepFieldsByPaths(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    interface{}
+		paths    []string
+		expected interface{}
+	}{
+		{
+			name:     "keep simple fields",
+			input:    map[string]interface{}{"hash": "0x1", "stateRoot": "0x2", "size": "0x3"},
+			paths:    []string{"hash", "stateRoot"},
+			expected: map[string]interface{}{"hash": "0x1", "stateRoot": "0x2"},
+		},
+		{
+			name: "keep array wildcard fields",
+			input: map[string]interface{}{
+				"hash": "0x1",
+				"transactions": []interface{}{
+					map[string]interface{}{"hash": "0xa", "gasPrice": "0x1"},
+					map[string]interface{}{"hash": "0xb", "gasPrice": "0x2"},
+				},
+			},
+			paths: []string{"transactions.*.hash"},
+			expected: map[string]interface{}{
+				"transactions": []interface{}{
+					map[string]interface{}{"hash": "0xa"},
+					map[string]interface{}{"hash": "0xb"},
+				},
+			},
+		},
+		{
+			name: "nested path on primitive array items keeps them",
+			input: map[string]interface{}{
+				"transactions": []interface{}{"0xa", "0xb"},
+				"size":         "0x3",
+			},
+			paths: []string{"transactions.*.hash"},
+			expected: map[string]interface{}{
+				"transactions": []interface{}{"0xa", "0xb"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := keepFieldsByPaths(tc.input, tc.paths)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestJsonRpcResponse_CanonicalHashWithFields(t *testing.T) {
+	t.Parallel()
+
+	resp1, err := NewJsonRpcResponseFromBytes([]byte(`1`), []byte(`{"hash":"0x1","stateRoot":"0x2","size":"0x10","logs":[{"a":"0x1","t":"0x1"}]}`), nil)
+	require.NoError(t, err)
+	resp2, err := NewJsonRpcResponseFromBytes([]byte(`1`), []byte(`{"hash":"0x1","stateRoot":"0x2","size":"0x20","logs":[{"a":"0x1","t":"0x2"}]}`), nil)
+	require.NoError(t, err)
+
+	hash1, err := resp1.CanonicalHashWithFields([]string{"hash", "stateRoot", "logs"}, []string{"logs.*.t"})
+	require.NoError(t, err)
+	hash2, err := resp2.CanonicalHashWithFields([]string{"hash", "stateRoot", "logs"}, []string{"logs.*.t"})
+	require.NoError(t, err)
+	assert.Equal(t, hash1, hash2)
+
+	hash1, err = resp1.CanonicalHashWithFields([]string{"size"}, nil)
+	require.NoError(t, err)
+	hash2, err = resp2.CanonicalHashWithFields([]string{"size"}, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash1, hash2)
+}
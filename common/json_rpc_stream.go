@@ -0,0 +1,461 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/erpc/erpc/util"
+)
+
+// ErrResultStreamConsumed is returned when a streamed result is read a second time without being materialized.
+var ErrResultStreamConsumed = errors.New("streamed json-rpc result is already consumed")
+
+const (
+	streamCopyBufferSize = 32 * 1024
+	// streamTailHoldBack is how many trailing bytes are held back while streaming so that the
+	// closing brace of the json-rpc envelope can be removed once the body ends.
+	streamTailHoldBack = 64
+)
+
+// StreamingResultWriter is a result writer backed by a one-shot stream (e.g. an upstream body piped
+// to the client), it can only be written once unless it is materialized in memory first.
+type StreamingResultWriter interface {
+	util.ByteWriter
+	// IsStreaming returns false once the result is materialized in memory.
+	IsStreaming() bool
+	// Materialize reads the whole result in memory, it fails if the stream is already consumed.
+	Materialize() ([]byte, error)
+	// OnComplete registers fn to receive a copy of the result once fully written, as long as it
+	// is not bigger than maxSize (<= 0 means unlimited). Returns false when streaming already started.
+	OnComplete(maxSize int, fn func(result []byte)) bool
+}
+
+// ResultTees copies a result being streamed into bounded buffers, so that it can be stored
+// (e.g. in cache) once fully written without keeping results bigger than maxSize in memory.
+type ResultTees struct {
+	mu      sync.Mutex
+	tees    []*resultTee
+	started bool
+	result  []byte
+}
+
+type resultTee struct {
+	maxSize  int
+	buf      []byte
+	exceeded bool
+	fn       func([]byte)
+}
+
+func (t *ResultTees) Register(maxSize int, fn func(result []byte)) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.result != nil {
+		if maxSize <= 0 || len(t.result) <= maxSize {
+			go fn(t.result)
+		}
+		return true
+	}
+	if t.started {
+		return false
+	}
+	t.tees = append(t.tees, &resultTee{maxSize: maxSize, fn: fn})
+	return true
+}
+
+// Begin marks the start of streaming, tees registered afterwards are rejected.
+func (t *ResultTees) Begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.started = true
+}
+
+func (t *ResultTees) Write(p []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, tee := range t.tees {
+		if tee.exceeded {
+			continue
+		}
+		if tee.maxSize > 0 && len(tee.buf)+len(p) > tee.maxSize {
+			tee.exceeded = true
+			tee.buf = nil
+			continue
+		}
+		tee.buf = append(tee.buf, p...)
+	}
+}
+
+// Finish hands the copied result to every tee that did not exceed its size limit.
+func (t *ResultTees) Finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, tee := range t.tees {
+		if !tee.exceeded {
+			go tee.fn(tee.buf)
+		}
+	}
+	t.tees = nil
+}
+
+// Abort drops copies of a result that could not be fully written.
+func (t *ResultTees) Abort() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tees = nil
+}
+
+// Deliver hands a result that was materialized in memory to current and future tees.
+func (t *ResultTees) Deliver(result []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.result = result
+	for _, tee := range t.tees {
+		if tee.maxSize <= 0 || len(result) <= tee.maxSize {
+			go tee.fn(result)
+		}
+	}
+	t.tees = nil
+}
+
+// StreamedResult is the "result" of a json-rpc response which is still being read from the upstream body.
+type StreamedResult struct {
+	// mu is held while the stream is being read, stateMu only guards the materialized result so that
+	// size and emptiness checks do not wait for an ongoing write.
+	mu           sync.Mutex
+	stateMu      sync.RWMutex
+	head         []byte
+	body         io.ReadCloser
+	expectedSize int
+	emptyish     bool
+	consumed     bool
+	materialized []byte
+	tees         ResultTees
+}
+
+var _ StreamingResultWriter = &StreamedResult{}
+
+// NewStreamedJsonRpcResponse peeks up to peekSize bytes of body. When the body is bigger and the
+// envelope carries a result (preceded only by "jsonrpc" and "id" fields) the result is streamed from
+// body instead of being buffered. Otherwise it returns a nil response and a reader replaying the
+// peeked bytes followed by the rest of body, to be parsed as usual.
+func NewStreamedJsonRpcResponse(body io.ReadCloser, peekSize int, expectedSize int) (*JsonRpcResponse, io.Reader, error) {
+	buf := make([]byte, peekSize)
+	n, err := io.ReadFull(body, buf)
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, bytes.NewReader(buf[:n]), nil
+		}
+		return nil, nil, err
+	}
+
+	idBytes, resultStart, ok := scanJsonRpcEnvelopeHead(buf)
+	if !ok {
+		return nil, io.MultiReader(bytes.NewReader(buf), body), nil
+	}
+
+	head := buf[resultStart:]
+	if expectedSize < peekSize {
+		// Size is only an estimate until the result is fully read (e.g. chunked bodies without content-length)
+		expectedSize = peekSize
+	}
+	jrr := &JsonRpcResponse{}
+	if err := jrr.SetIDBytes(idBytes); err != nil {
+		return nil, nil, err
+	}
+	jrr.resultWriter = &StreamedResult{
+		head:         head,
+		body:         body,
+		expectedSize: expectedSize,
+		emptyish:     isStreamHeadEmptyish(head),
+	}
+	return jrr, nil, nil
+}
+
+func (s *StreamedResult) IsStreaming() bool {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	return s.materialized == nil
+}
+
+func (s *StreamedResult) IsResultEmptyish() bool {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	if s.materialized != nil {
+		return util.IsBytesEmptyish(s.materialized)
+	}
+	return s.emptyish
+}
+
+// Size returns the size of the result when materialized, otherwise the expected size of the upstream body.
+func (s *StreamedResult) Size(ctx ...context.Context) (int, error) {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	if s.materialized != nil {
+		return len(s.materialized), nil
+	}
+	return s.expectedSize, nil
+}
+
+func (s *StreamedResult) OnComplete(maxSize int, fn func(result []byte)) bool {
+	return s.tees.Register(maxSize, fn)
+}
+
+func (s *StreamedResult) WriteTo(w io.Writer, trimSides bool) (n int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.materialized != nil {
+		data := s.materialized
+		if trimSides && len(data) >= 2 {
+			data = data[1 : len(data)-1]
+		}
+		nn, err := w.Write(data)
+		return int64(nn), err
+	}
+	if s.consumed {
+		return 0, ErrResultStreamConsumed
+	}
+	s.consumed = true
+	defer s.closeLocked()
+
+	s.tees.Begin()
+	out := &streamTrimmer{w: w, trimSides: trimSides, tees: &s.tees}
+	_, err = io.CopyBuffer(out, io.MultiReader(bytes.NewReader(s.head), s.body), make([]byte, streamCopyBufferSize))
+	s.head = nil
+	if err == nil {
+		err = out.finish()
+	}
+	if err != nil {
+		s.tees.Abort()
+		return out.written, err
+	}
+	s.tees.Finish()
+	return out.written, nil
+}
+
+func (s *StreamedResult) Materialize() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.materialized != nil {
+		return s.materialized, nil
+	}
+	if s.consumed {
+		return nil, ErrResultStreamConsumed
+	}
+	s.consumed = true
+	defer s.closeLocked()
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(s.head)+streamCopyBufferSize))
+	buf.Write(s.head)
+	s.head = nil
+	if _, err := io.Copy(buf, s.body); err != nil {
+		s.tees.Abort()
+		return nil, err
+	}
+	data, err := trimJsonRpcEnvelopeTail(buf.Bytes())
+	if err != nil {
+		s.tees.Abort()
+		return nil, err
+	}
+	s.stateMu.Lock()
+	s.materialized = data
+	s.stateMu.Unlock()
+	s.tees.Deliver(data)
+	return data, nil
+}
+
+// Close releases the upstream body when the result is not going to be consumed.
+func (s *StreamedResult) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.consumed {
+		return nil
+	}
+	s.consumed = true
+	s.tees.Abort()
+	return s.closeLocked()
+}
+
+func (s *StreamedResult) closeLocked() error {
+	if s.body == nil {
+		return nil
+	}
+	err := s.body.Close()
+	s.body = nil
+	return err
+}
+
+// streamTrimmer passes the streamed body through while holding back the last few bytes,
+// so that the closing brace of the envelope (and optionally the array brackets) are not written.
+type streamTrimmer struct {
+	w         io.Writer
+	trimSides bool
+	tees      *ResultTees
+	pending   []byte
+	started   bool
+	written   int64
+}
+
+func (t *streamTrimmer) Write(p []byte) (int, error) {
+	t.pending = append(t.pending, p...)
+	if len(t.pending) <= streamTailHoldBack {
+		return len(p), nil
+	}
+	emit := len(t.pending) - streamTailHoldBack
+	if err := t.emit(t.pending[:emit]); err != nil {
+		return 0, err
+	}
+	t.pending = append(t.pending[:0], t.pending[emit:]...)
+	return len(p), nil
+}
+
+func (t *streamTrimmer) emit(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	t.tees.Write(b)
+	if t.trimSides && !t.started && b[0] == '[' {
+		b = b[1:]
+	}
+	t.started = true
+	nn, err := t.w.Write(b)
+	t.written += int64(nn)
+	return err
+}
+
+func (t *streamTrimmer) finish() error {
+	tail, err := trimJsonRpcEnvelopeTail(t.pending)
+	if err != nil {
+		return err
+	}
+	if !t.trimSides {
+		return t.emit(tail)
+	}
+	t.tees.Write(tail)
+	if !t.started && len(tail) > 0 && tail[0] == '[' {
+		tail = tail[1:]
+	}
+	t.started = true
+	tail = bytes.TrimRight(tail, " \t\r\n")
+	if len(tail) > 0 && tail[len(tail)-1] == ']' {
+		tail = tail[:len(tail)-1]
+	}
+	nn, err := t.w.Write(tail)
+	t.written += int64(nn)
+	return err
+}
+
+// trimJsonRpcEnvelopeTail removes the closing brace of the json-rpc envelope that follows the result.
+func trimJsonRpcEnvelopeTail(b []byte) ([]byte, error) {
+	b = bytes.TrimRight(b, " \t\r\n")
+	if len(b) == 0 || b[len(b)-1] != '}' {
+		return nil, errors.New("unexpected end of streamed json-rpc response, missing closing brace")
+	}
+	return bytes.TrimRight(b[:len(b)-1], " \t\r\n"), nil
+}
+
+// scanJsonRpcEnvelopeHead finds where the result starts when it is preceded only by "jsonrpc" and
+// "id" fields, which is how most nodes serialize responses.
+func scanJsonRpcEnvelopeHead(b []byte) (idBytes []byte, resultStart int, ok bool) {
+	i := skipJsonWhitespace(b, 0)
+	if i >= len(b) || b[i] != '{' {
+		return nil, 0, false
+	}
+	i++
+	for {
+		i = skipJsonWhitespace(b, i)
+		key, next, ok := scanJsonString(b, i)
+		if !ok {
+			return nil, 0, false
+		}
+		i = skipJsonWhitespace(b, next)
+		if i >= len(b) || b[i] != ':' {
+			return nil, 0, false
+		}
+		i = skipJsonWhitespace(b, i+1)
+
+		switch key {
+		case "result":
+			if idBytes == nil || i >= len(b) {
+				return nil, 0, false
+			}
+			return idBytes, i, true
+		case "jsonrpc", "id":
+			end, ok := scanJsonScalar(b, i)
+			if !ok {
+				return nil, 0, false
+			}
+			if key == "id" {
+				idBytes = b[i:end]
+			}
+			i = skipJsonWhitespace(b, end)
+		default:
+			return nil, 0, false
+		}
+
+		if i >= len(b) || b[i] != ',' {
+			return nil, 0, false
+		}
+		i++
+	}
+}
+
+func skipJsonWhitespace(b []byte, i int) int {
+	for i < len(b) && (b[i] == ' ' || b[i] == '\t' || b[i] == '\n' || b[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+func scanJsonString(b []byte, i int) (string, int, bool) {
+	if i >= len(b) || b[i] != '"' {
+		return "", 0, false
+	}
+	for j := i + 1; j < len(b); j++ {
+		switch b[j] {
+		case '\\':
+			j++
+		case '"':
+			return string(b[i+1 : j]), j + 1, true
+		}
+	}
+	return "", 0, false
+}
+
+// scanJsonScalar returns the end of a string, number, boolean or null value.
+func scanJsonScalar(b []byte, i int) (int, bool) {
+	if i >= len(b) {
+		return 0, false
+	}
+	if b[i] == '"' {
+		_, end, ok := scanJsonString(b, i)
+		return end, ok
+	}
+	j := i
+	for j < len(b) && b[j] != ',' && b[j] != '}' && b[j] != ' ' && b[j] != '\t' && b[j] != '\n' && b[j] != '\r' {
+		if b[j] == '{' || b[j] == '[' {
+			return 0, false
+		}
+		j++
+	}
+	return j, j > i && j < len(b)
+}
+
+func isStreamHeadEmptyish(head []byte) bool {
+	if len(head) == 0 {
+		return true
+	}
+	switch head[0] {
+	case '[':
+		i := skipJsonWhitespace(head, 1)
+		return i < len(head) && head[i] == ']'
+	case 'n':
+		return bytes.HasPrefix(head, []byte("null"))
+	}
+	return false
+}
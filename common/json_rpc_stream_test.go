@@ -0,0 +1,136 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type trackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func newTrackingBody(s string) *trackingBody {
+	return &trackingBody{Reader: strings.NewReader(s)}
+}
+
+func TestStreamedJsonRpcResponse(t *testing.T) {
+	largeResult := `[` + strings.Repeat(`{"logIndex":"0x1","data":"0x00"},`, 200) + `{"logIndex":"0x2","data":"0x00"}]`
+	largeBody := `{"jsonrpc":"2.0","id":42,"result":` + largeResult + "}\n"
+
+	t.Run("StreamsResultBiggerThanPeekSize", func(t *testing.T) {
+		body := newTrackingBody(largeBody)
+		jrr, fallback, err := NewStreamedJsonRpcResponse(body, 256, len(largeBody))
+		require.NoError(t, err)
+		require.Nil(t, fallback)
+		require.NotNil(t, jrr)
+
+		assert.True(t, jrr.IsStreaming())
+		assert.False(t, jrr.IsResultEmptyish())
+		assert.Equal(t, int64(42), jrr.ID())
+
+		buf := bytes.NewBuffer(nil)
+		_, err = jrr.WriteTo(buf)
+		require.NoError(t, err)
+		assert.Equal(t, `{"jsonrpc":"2.0","id":42,"result":`+largeResult+`}`, buf.String())
+		assert.True(t, body.closed)
+
+		_, err = jrr.WriteTo(bytes.NewBuffer(nil))
+		assert.ErrorIs(t, err, ErrResultStreamConsumed)
+	})
+
+	t.Run("TrimsSidesOfStreamedArray", func(t *testing.T) {
+		jrr, _, err := NewStreamedJsonRpcResponse(newTrackingBody(largeBody), 256, 0)
+		require.NoError(t, err)
+		require.NotNil(t, jrr)
+
+		buf := bytes.NewBuffer(nil)
+		_, err = jrr.WriteResultTo(buf, true)
+		require.NoError(t, err)
+		assert.Equal(t, largeResult[1:len(largeResult)-1], buf.String())
+	})
+
+	t.Run("MaterializesBeforeClone", func(t *testing.T) {
+		jrr, _, err := NewStreamedJsonRpcResponse(newTrackingBody(largeBody), 256, 0)
+		require.NoError(t, err)
+
+		clone, err := jrr.Clone()
+		require.NoError(t, err)
+		assert.False(t, jrr.IsStreaming())
+		assert.Equal(t, largeResult, string(clone.Result))
+
+		val, err := jrr.PeekStringByPath(context.Background(), 0, "logIndex")
+		require.NoError(t, err)
+		assert.Equal(t, "0x1", val)
+	})
+
+	t.Run("FallsBackForSmallBodies", func(t *testing.T) {
+		small := `{"jsonrpc":"2.0","id":1,"result":[]}`
+		jrr, fallback, err := NewStreamedJsonRpcResponse(newTrackingBody(small), 256, 0)
+		require.NoError(t, err)
+		assert.Nil(t, jrr)
+		data, err := io.ReadAll(fallback)
+		require.NoError(t, err)
+		assert.Equal(t, small, string(data))
+	})
+
+	t.Run("FallsBackWhenResultIsNotFirst", func(t *testing.T) {
+		errBody := `{"jsonrpc":"2.0","error":{"code":-32000,"message":"` + strings.Repeat("x", 512) + `"},"id":1}`
+		jrr, fallback, err := NewStreamedJsonRpcResponse(newTrackingBody(errBody), 256, 0)
+		require.NoError(t, err)
+		assert.Nil(t, jrr)
+		data, err := io.ReadAll(fallback)
+		require.NoError(t, err)
+		assert.Equal(t, errBody, string(data))
+	})
+
+	t.Run("TeesRespectMaxSize", func(t *testing.T) {
+		jrr, _, err := NewStreamedJsonRpcResponse(newTrackingBody(largeBody), 256, 0)
+		require.NoError(t, err)
+
+		small := make(chan []byte, 1)
+		big := make(chan []byte, 1)
+		require.True(t, jrr.OnResultComplete(100, func(result []byte) { small <- result }))
+		require.True(t, jrr.OnResultComplete(0, func(result []byte) { big <- result }))
+
+		_, err = jrr.WriteResultTo(io.Discard, true)
+		require.NoError(t, err)
+
+		select {
+		case result := <-big:
+			assert.Equal(t, largeResult, string(result))
+		case <-time.After(time.Second):
+			t.Fatal("expected unbounded tee to receive the result")
+		}
+		select {
+		case <-small:
+			t.Fatal("expected tee exceeding max size to be dropped")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		assert.False(t, jrr.OnResultComplete(0, func([]byte) {}))
+	})
+
+	t.Run("CloseReleasesUnconsumedBody", func(t *testing.T) {
+		body := newTrackingBody(largeBody)
+		jrr, _, err := NewStreamedJsonRpcResponse(body, 256, 0)
+		require.NoError(t, err)
+
+		jrr.CloseStream()
+		assert.True(t, body.closed)
+		_, err = jrr.WriteResultTo(io.Discard, false)
+		assert.ErrorIs(t, err, ErrResultStreamConsumed)
+	})
+}
@@ -738,3 +738,22 @@ func TestJsonRpcRequest_CloneDeepCopy(t *testing.T) {
 		assert.Equal(t, "0x2", originalMap["toBlock"])
 	})
 }
+
+func TestCanonicalHashFromReader_MatchesCanonicalHash(t *testing.T) {
+	results := []string{
+		`"0x22"`,
+		`null`,
+		`[]`,
+		`[null, "", {}]`,
+		` [{"address":"0x1","data":"0x","topics":[]}, null, {"topics":["0xa"],"address":"0x2"}, "0x00"] `,
+		`{"b": "2", "a": [1, 0, "0x"], "c": {}}`,
+		`[[1, 2], [], "x"]`,
+	}
+	for _, result := range results {
+		expected, err := (&JsonRpcResponse{Result: []byte(result)}).CanonicalHash()
+		require.NoError(t, err, result)
+		actual, err := CanonicalHashFromReader(bytes.NewReader([]byte(result)))
+		require.NoError(t, err, result)
+		assert.Equal(t, expected, actual, result)
+	}
+}
@@ -0,0 +1,239 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/erpc/erpc/telemetry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	otlpMeterProvider  *sdkmetric.MeterProvider
+	otlpLoggerProvider *sdklog.LoggerProvider
+)
+
+// InitializeOtlpMetrics periodically pushes everything registered with the Prometheus client to an OTLP
+// collector, so metric names and labels are identical to what the /metrics endpoint serves.
+func InitializeOtlpMetrics(ctx context.Context, logger *zerolog.Logger, cfg *MetricsOtlpConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	logger.Info().
+		Str("endpoint", cfg.Endpoint).
+		Str("protocol", string(cfg.Protocol)).
+		Str("pushInterval", cfg.PushInterval.String()).
+		Str("temporality", string(cfg.Temporality)).
+		Msg("initializing OpenTelemetry metrics exporter")
+
+	temporality := metricdata.CumulativeTemporality
+	if cfg.Temporality == MetricsTemporalityDelta {
+		temporality = metricdata.DeltaTemporality
+	}
+
+	var exporter sdkmetric.Exporter
+	var err error
+	switch cfg.Protocol {
+	case TracingProtocolGrpc:
+		exporter, err = createMetricsGRPCExporter(ctx, cfg, temporality)
+	case TracingProtocolHttp:
+		exporter, err = createMetricsHTTPExporter(ctx, cfg, temporality)
+	default:
+		err = fmt.Errorf("unsupported metrics protocol: %s", cfg.Protocol)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create metrics exporter: %w", err)
+	}
+
+	res, err := createOtlpResource(ctx)
+	if err != nil {
+		return err
+	}
+
+	otlpMeterProvider = sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(
+			exporter,
+			sdkmetric.WithInterval(cfg.PushInterval.Duration()),
+			sdkmetric.WithProducer(telemetry.NewPrometheusProducer(prometheus.DefaultGatherer, temporality)),
+		)),
+	)
+
+	logger.Info().Msg("OpenTelemetry metrics exporter initialized successfully")
+	return nil
+}
+
+// InitializeOtlpLogs starts forwarding logs written through telemetry.OtlpLogWriter to an OTLP collector.
+func InitializeOtlpLogs(ctx context.Context, logger *zerolog.Logger, cfg *LogsOtlpConfig) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	logger.Info().
+		Str("endpoint", cfg.Endpoint).
+		Str("protocol", string(cfg.Protocol)).
+		Msg("initializing OpenTelemetry logs exporter")
+
+	var exporter sdklog.Exporter
+	var err error
+	switch cfg.Protocol {
+	case TracingProtocolGrpc:
+		exporter, err = createLogsGRPCExporter(ctx, cfg)
+	case TracingProtocolHttp:
+		exporter, err = createLogsHTTPExporter(ctx, cfg)
+	default:
+		err = fmt.Errorf("unsupported logs protocol: %s", cfg.Protocol)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create logs exporter: %w", err)
+	}
+
+	res, err := createOtlpResource(ctx)
+	if err != nil {
+		return err
+	}
+
+	otlpLoggerProvider = sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+	telemetry.SetOtlpLogger(otlpLoggerProvider.Logger(instrumentationName))
+
+	logger.Info().Msg("OpenTelemetry logs exporter initialized successfully")
+	return nil
+}
+
+// ShutdownOtlp flushes and stops the metrics and logs exporters, if they were initialized.
+func ShutdownOtlp(ctx context.Context) error {
+	var errs []error
+	if otlpMeterProvider != nil {
+		if err := otlpMeterProvider.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if otlpLoggerProvider != nil {
+		telemetry.SetOtlpLogger(nil)
+		if err := otlpLoggerProvider.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func createOtlpResource(ctx context.Context) (*resource.Resource, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String("erpc"),
+			semconv.ServiceVersionKey.String(ErpcVersion),
+			attribute.String("commit.sha", ErpcCommitSha),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+	return res, nil
+}
+
+// isOtlpEndpointURL tells whether the endpoint includes a scheme (e.g. http://localhost:4318) rather than host:port.
+func isOtlpEndpointURL(endpoint string) bool {
+	return strings.Contains(endpoint, "://")
+}
+
+func createMetricsGRPCExporter(ctx context.Context, cfg *MetricsOtlpConfig, temporality metricdata.Temporality) (sdkmetric.Exporter, error) {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithTemporalitySelector(func(sdkmetric.InstrumentKind) metricdata.Temporality { return temporality }),
+	}
+	if isOtlpEndpointURL(cfg.Endpoint) {
+		opts = append(opts, otlpmetricgrpc.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.TLS != nil && cfg.TLS.Enabled {
+		tlsConfig, err := CreateTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+func createMetricsHTTPExporter(ctx context.Context, cfg *MetricsOtlpConfig, temporality metricdata.Temporality) (sdkmetric.Exporter, error) {
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithTemporalitySelector(func(sdkmetric.InstrumentKind) metricdata.Temporality { return temporality }),
+	}
+	if isOtlpEndpointURL(cfg.Endpoint) {
+		opts = append(opts, otlpmetrichttp.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlpmetrichttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.TLS != nil && cfg.TLS.Enabled {
+		tlsConfig, err := CreateTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+	} else {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+
+	return otlpmetrichttp.New(ctx, opts...)
+}
+
+func createLogsGRPCExporter(ctx context.Context, cfg *LogsOtlpConfig) (sdklog.Exporter, error) {
+	var opts []otlploggrpc.Option
+	if isOtlpEndpointURL(cfg.Endpoint) {
+		opts = append(opts, otlploggrpc.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlploggrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.TLS != nil && cfg.TLS.Enabled {
+		tlsConfig, err := CreateTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+
+	return otlploggrpc.New(ctx, opts...)
+}
+
+func createLogsHTTPExporter(ctx context.Context, cfg *LogsOtlpConfig) (sdklog.Exporter, error) {
+	var opts []otlploghttp.Option
+	if isOtlpEndpointURL(cfg.Endpoint) {
+		opts = append(opts, otlploghttp.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlploghttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.TLS != nil && cfg.TLS.Enabled {
+		tlsConfig, err := CreateTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlploghttp.WithTLSClientConfig(tlsConfig))
+	} else {
+		opts = append(opts, otlploghttp.WithInsecure())
+	}
+
+	return otlploghttp.New(ctx, opts...)
+}
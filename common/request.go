@@ -22,6 +22,19 @@ const (
 
 const RequestContextKey ContextKey = "rq"
 const UpstreamsContextKey ContextKey = "ups"
+const RequestLifetimeContextKey ContextKey = "rqLifetime"
+
+// WithRequestLifetime stores the context of the whole client request (cancelled when the client goes away or
+// the response is fully written), so that work outliving a single attempt (e.g. a streamed result) can follow it.
+func WithRequestLifetime(ctx context.Context, lifetime context.Context) context.Context {
+	return context.WithValue(ctx, RequestLifetimeContextKey, lifetime)
+}
+
+// RequestLifetime returns the context stored by WithRequestLifetime if any.
+func RequestLifetime(ctx context.Context) (context.Context, bool) {
+	lifetime, ok := ctx.Value(RequestLifetimeContextKey).(context.Context)
+	return lifetime, ok && lifetime != nil
+}
 
 type RequestDirectives struct {
 	// Instruct the proxy to retry if response from the upstream appears to be empty
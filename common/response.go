@@ -297,6 +297,14 @@ func (r *NormalizedResponse) IsResultEmptyish(ctx ...context.Context) bool {
 	return true
 }
 
+// IsStreaming returns true when the result is still being read from the upstream body (see StreamedResult).
+func (r *NormalizedResponse) IsStreaming() bool {
+	if r == nil {
+		return false
+	}
+	return r.jsonRpcResponse.Load().IsStreaming()
+}
+
 func (r *NormalizedResponse) IsObjectNull(ctx ...context.Context) bool {
 	if r == nil {
 		return true
@@ -364,6 +372,10 @@ func (r *NormalizedResponse) Release() {
 		r.body = nil
 	}
 
+	// Release the upstream body of a streamed result that was never written (no-op once consumed)
+	if jrr := r.jsonRpcResponse.Load(); jrr != nil {
+		jrr.CloseStream()
+	}
 	r.jsonRpcResponse.Store(nil)
 }
 
@@ -32,6 +32,7 @@ var (
 
 	tracerProvider *sdktrace.TracerProvider
 	tracer         trace.Tracer
+	tailSampler    *tailSamplingProcessor
 	initOnce       sync.Once
 )
 
@@ -81,9 +82,19 @@ func InitializeTracing(ctx context.Context, logger *zerolog.Logger, cfg *Tracing
 			return
 		}
 
+		spanProcessor := sdktrace.NewBatchSpanProcessor(exporter)
+		if cfg.TailSampling != nil && cfg.TailSampling.Enabled {
+			tailSampler = newTailSamplingProcessor(ctx, spanProcessor, cfg)
+			spanProcessor = tailSampler
+			logger.Info().
+				Str("latencyThreshold", cfg.TailSampling.LatencyThreshold.String()).
+				Int("maxSpansPerRequest", cfg.TailSampling.MaxSpansPerRequest).
+				Msg("tail sampling enabled, all spans are recorded and only interesting requests are exported")
+		}
+
 		tracerProvider = sdktrace.NewTracerProvider(
 			sdktrace.WithSampler(createTracingSampler(cfg)),
-			sdktrace.WithBatcher(exporter),
+			sdktrace.WithSpanProcessor(spanProcessor),
 			sdktrace.WithResource(res),
 		)
 		otel.SetTracerProvider(tracerProvider)
@@ -179,6 +190,11 @@ func createTracingHTTPExporter(ctx context.Context, cfg *TracingConfig) (*otlptr
 }
 
 func createTracingSampler(cfg *TracingConfig) sdktrace.Sampler {
+	// With tail sampling every span is recorded, the base rate is applied when requests end
+	if cfg.TailSampling != nil && cfg.TailSampling.Enabled {
+		return sdktrace.AlwaysSample()
+	}
+
 	if cfg.SampleRate <= 0 {
 		return sdktrace.NeverSample()
 	}
@@ -0,0 +1,175 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Reasons for keeping the trace of a request when tail sampling is enabled
+const (
+	TraceKeepReasonError            = "error"
+	TraceKeepReasonHedge            = "hedge"
+	TraceKeepReasonConsensusDispute = "consensus_dispute"
+	TraceKeepReasonSlow             = "slow"
+	TraceKeepReasonSampled          = "sampled"
+)
+
+// keptTraceRetention is how long spans ending outside of a request (e.g. the http server span wrapping
+// a batch) are still exported after one of the requests of their trace was kept.
+const keptTraceRetention = time.Minute
+
+type requestSpanBufferContextKey struct{}
+
+// requestSpanBuffer holds the ended spans of a single request until EndRequestSpan decides whether
+// the request is worth exporting. Spans ending after the decision are exported or dropped right away.
+type requestSpanBuffer struct {
+	mu         sync.Mutex
+	startedAt  time.Time
+	decided    bool
+	keep       bool
+	keepReason string
+	spans      []sdktrace.ReadOnlySpan
+}
+
+// tailSamplingProcessor records every span but only hands to the exporter those of requests that ended
+// with an error, hedge, consensus dispute or high latency, plus a base-rate sample of everything else.
+type tailSamplingProcessor struct {
+	next               sdktrace.SpanProcessor
+	baseSampler        sdktrace.Sampler
+	latencyThreshold   time.Duration
+	maxSpansPerRequest int
+
+	buffers    sync.Map // map[trace.SpanID]*requestSpanBuffer
+	keptTraces sync.Map // map[trace.TraceID]time.Time
+}
+
+var _ sdktrace.SpanProcessor = &tailSamplingProcessor{}
+
+func newTailSamplingProcessor(ctx context.Context, next sdktrace.SpanProcessor, cfg *TracingConfig) *tailSamplingProcessor {
+	p := &tailSamplingProcessor{
+		next:               next,
+		baseSampler:        sdktrace.TraceIDRatioBased(cfg.SampleRate),
+		latencyThreshold:   cfg.TailSampling.LatencyThreshold.Duration(),
+		maxSpansPerRequest: cfg.TailSampling.MaxSpansPerRequest,
+	}
+	go p.cleanupKeptTraces(ctx)
+	return p
+}
+
+func (p *tailSamplingProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if buf, ok := parent.Value(requestSpanBufferContextKey{}).(*requestSpanBuffer); ok && buf != nil {
+		p.buffers.Store(s.SpanContext().SpanID(), buf)
+	}
+	p.next.OnStart(parent, s)
+}
+
+func (p *tailSamplingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if v, ok := p.buffers.LoadAndDelete(s.SpanContext().SpanID()); ok {
+		buf := v.(*requestSpanBuffer)
+		buf.mu.Lock()
+		if !buf.decided {
+			if len(buf.spans) < p.maxSpansPerRequest {
+				buf.spans = append(buf.spans, s)
+			}
+			buf.mu.Unlock()
+			return
+		}
+		keep := buf.keep
+		buf.mu.Unlock()
+		if keep {
+			p.next.OnEnd(s)
+		}
+		return
+	}
+
+	// Spans outside of any request (e.g. background jobs, or the http span wrapping the requests)
+	if _, ok := p.keptTraces.Load(s.SpanContext().TraceID()); ok || p.isBaseSampled(s.SpanContext().TraceID()) {
+		p.next.OnEnd(s)
+	}
+}
+
+func (p *tailSamplingProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *tailSamplingProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// isBaseSampled is deterministic for a trace id, so all spans of a trace get the same decision.
+func (p *tailSamplingProcessor) isBaseSampled(traceID trace.TraceID) bool {
+	return p.baseSampler.ShouldSample(sdktrace.SamplingParameters{TraceID: traceID}).Decision == sdktrace.RecordAndSample
+}
+
+// decide determines whether the request is kept, then exports (or discards) the spans buffered so far.
+func (p *tailSamplingProcessor) decide(buf *requestSpanBuffer, traceID trace.TraceID, resp *NormalizedResponse, err interface{}) string {
+	buf.mu.Lock()
+	if buf.decided {
+		buf.mu.Unlock()
+		return buf.keepReason
+	}
+	reason := buf.keepReason
+	if reason == "" {
+		if err != nil {
+			reason = TraceKeepReasonError
+		} else if resp != nil && resp.Hedges() > 0 {
+			reason = TraceKeepReasonHedge
+		} else if p.latencyThreshold > 0 && time.Since(buf.startedAt) >= p.latencyThreshold {
+			reason = TraceKeepReasonSlow
+		} else if p.isBaseSampled(traceID) {
+			reason = TraceKeepReasonSampled
+		}
+	}
+	keep := reason != ""
+	buf.decided = true
+	buf.keep = keep
+	buf.keepReason = reason
+	spans := buf.spans
+	buf.spans = nil
+	buf.mu.Unlock()
+
+	if !keep {
+		return ""
+	}
+	p.keptTraces.Store(traceID, time.Now())
+	for _, s := range spans {
+		p.next.OnEnd(s)
+	}
+	return reason
+}
+
+func (p *tailSamplingProcessor) cleanupKeptTraces(ctx context.Context) {
+	ticker := time.NewTicker(keptTraceRetention)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.keptTraces.Range(func(key, value any) bool {
+				if time.Since(value.(time.Time)) > keptTraceRetention {
+					p.keptTraces.Delete(key)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// KeepRequestTrace makes sure the trace of the current request is exported when tail sampling is enabled,
+// for conditions only known deep in the request lifecycle (e.g. a consensus dispute that still produced a result).
+func KeepRequestTrace(ctx context.Context, reason string) {
+	buf, ok := ctx.Value(requestSpanBufferContextKey{}).(*requestSpanBuffer)
+	if !ok || buf == nil {
+		return
+	}
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+	if !buf.decided && buf.keepReason == "" {
+		buf.keepReason = reason
+	}
+}
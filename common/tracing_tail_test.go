@@ -0,0 +1,90 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTailSamplingTestTracer(t *testing.T, sampleRate float64) (trace.Tracer, *tailSamplingProcessor, *tracetest.SpanRecorder) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	recorder := tracetest.NewSpanRecorder()
+	processor := newTailSamplingProcessor(ctx, recorder, &TracingConfig{
+		SampleRate: sampleRate,
+		TailSampling: &TracingTailSamplingConfig{
+			Enabled:            true,
+			LatencyThreshold:   Duration(time.Second),
+			MaxSpansPerRequest: 100,
+		},
+	})
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(processor),
+	)
+	return provider.Tracer("test"), processor, recorder
+}
+
+// runTailSampledRequest mimics StartRequestSpan/EndRequestSpan with a child span ending before the decision.
+func runTailSampledRequest(tracer trace.Tracer, processor *tailSamplingProcessor, beforeEnd func(ctx context.Context), err error) {
+	buf := &requestSpanBuffer{startedAt: time.Now()}
+	ctx := context.WithValue(context.Background(), requestSpanBufferContextKey{}, buf)
+	ctx, span := tracer.Start(ctx, "Request.Handle")
+	_, child := tracer.Start(ctx, "Upstream.Forward")
+	child.End()
+	if beforeEnd != nil {
+		beforeEnd(ctx)
+	}
+	processor.decide(buf, span.SpanContext().TraceID(), nil, err)
+	span.End()
+}
+
+func TestTailSamplingProcessor(t *testing.T) {
+	t.Run("drops successful fast requests not picked by the base rate", func(t *testing.T) {
+		tracer, processor, recorder := newTailSamplingTestTracer(t, 0)
+		runTailSampledRequest(tracer, processor, nil, nil)
+		assert.Empty(t, recorder.Ended())
+	})
+
+	t.Run("exports all spans of failed requests", func(t *testing.T) {
+		tracer, processor, recorder := newTailSamplingTestTracer(t, 0)
+		runTailSampledRequest(tracer, processor, nil, errors.New("upstream failed"))
+		assert.Len(t, recorder.Ended(), 2)
+	})
+
+	t.Run("exports requests marked during their lifecycle", func(t *testing.T) {
+		tracer, processor, recorder := newTailSamplingTestTracer(t, 0)
+		runTailSampledRequest(tracer, processor, func(ctx context.Context) {
+			KeepRequestTrace(ctx, TraceKeepReasonConsensusDispute)
+		}, nil)
+		assert.Len(t, recorder.Ended(), 2)
+	})
+
+	t.Run("exports spans outside requests when their trace was kept", func(t *testing.T) {
+		tracer, processor, recorder := newTailSamplingTestTracer(t, 0)
+		ctx, root := tracer.Start(context.Background(), "Http.ReceivedRequest")
+
+		buf := &requestSpanBuffer{startedAt: time.Now()}
+		reqCtx, span := tracer.Start(context.WithValue(ctx, requestSpanBufferContextKey{}, buf), "Request.Handle")
+		assert.Equal(t, TraceKeepReasonError, processor.decide(buf, span.SpanContext().TraceID(), nil, errors.New("boom")))
+		span.End()
+		_, late := tracer.Start(reqCtx, "Upstream.Forward")
+		late.End()
+		root.End()
+
+		assert.Len(t, recorder.Ended(), 3)
+	})
+
+	t.Run("follows the base rate for successful requests", func(t *testing.T) {
+		tracer, processor, recorder := newTailSamplingTestTracer(t, 1)
+		runTailSampledRequest(tracer, processor, nil, nil)
+		assert.Len(t, recorder.Ended(), 2)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -109,6 +110,11 @@ func StartRequestSpan(ctx context.Context, req *NormalizedRequest) context.Conte
 		return ctx
 	}
 
+	// Spans of the request are buffered until EndRequestSpan decides whether to export them
+	if tailSampler != nil {
+		ctx = context.WithValue(ctx, requestSpanBufferContextKey{}, &requestSpanBuffer{startedAt: time.Now()})
+	}
+
 	method, _ := req.Method()
 	ctx, span := tracer.Start(ctx, "Request.Handle",
 		trace.WithSpanKind(trace.SpanKindInternal),
@@ -161,6 +167,14 @@ func EndRequestSpan(ctx context.Context, resp *NormalizedResponse, err interface
 		}
 	}
 
+	if tailSampler != nil {
+		if buf, ok := ctx.Value(requestSpanBufferContextKey{}).(*requestSpanBuffer); ok && buf != nil {
+			if reason := tailSampler.decide(buf, span.SpanContext().TraceID(), resp, err); reason != "" {
+				span.SetAttributes(attribute.String("sampling.keep_reason", reason))
+			}
+		}
+	}
+
 	span.End()
 }
 
@@ -129,6 +129,7 @@ func (u *FakeUpstream) EvmAssertBlockAvailability(ctx context.Context, forMethod
 type FakeEvmStatePoller struct {
 	latestBlockNumber    int64
 	finalizedBlockNumber int64
+	latestBlockHead      *EvmBlockHead
 }
 
 func NewFakeEvmStatePoller(latestBlockNumber int64, finalizedBlockNumber int64) EvmStatePoller {
@@ -154,6 +155,8 @@ func (p *FakeEvmStatePoller) IsBlockFinalized(blockNumber int64) (bool, error) {
 	return blockNumber <= p.finalizedBlockNumber, nil
 }
 
+func (p *FakeEvmStatePoller) Shutdown() {}
+
 func (p *FakeEvmStatePoller) IsObjectNull() bool {
 	return false
 }
@@ -162,6 +165,23 @@ func (p *FakeEvmStatePoller) LatestBlock() int64 {
 	return p.latestBlockNumber
 }
 
+func (p *FakeEvmStatePoller) LatestBlockHead() *EvmBlockHead {
+	if p.latestBlockHead != nil {
+		return p.latestBlockHead
+	}
+	return &EvmBlockHead{Number: p.latestBlockNumber}
+}
+
+// SetLatestBlockHead overrides the head (e.g. to simulate upstreams on different forks)
+func (p *FakeEvmStatePoller) SetLatestBlockHead(head *EvmBlockHead) {
+	p.latestBlockHead = head
+	p.latestBlockNumber = head.Number
+}
+
+func (p *FakeEvmStatePoller) SafeBlock() int64 {
+	return p.finalizedBlockNumber
+}
+
 func (p *FakeEvmStatePoller) Poll(ctx context.Context) error {
 	return nil
 }
@@ -170,6 +190,10 @@ func (p *FakeEvmStatePoller) PollFinalizedBlockNumber(ctx context.Context) (int6
 	return p.finalizedBlockNumber, nil
 }
 
+func (p *FakeEvmStatePoller) PollSafeBlockNumber(ctx context.Context) (int64, error) {
+	return p.finalizedBlockNumber, nil
+}
+
 func (p *FakeEvmStatePoller) PollLatestBlockNumber(ctx context.Context) (int64, error) {
 	return p.latestBlockNumber, nil
 }
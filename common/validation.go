@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
@@ -29,6 +30,16 @@ func (c *Config) Validate() error {
 			return err
 		}
 	}
+	if c.Logs != nil {
+		if err := c.Logs.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Tracing != nil {
+		if err := c.Tracing.Validate(); err != nil {
+			return err
+		}
+	}
 	if c.Admin != nil {
 		if err := c.Admin.Validate(); err != nil {
 			return err
@@ -48,6 +59,9 @@ func (c *Config) Validate() error {
 	} else {
 		return fmt.Errorf("projects config is required")
 	}
+	if c.Server.Grpc != nil && c.Server.Grpc.DefaultProjectId != "" && c.GetProjectConfig(c.Server.Grpc.DefaultProjectId) == nil {
+		return fmt.Errorf("server.grpc.defaultProjectId '%s' does not match any project", c.Server.Grpc.DefaultProjectId)
+	}
 	if c.RateLimiters != nil {
 		if err := c.RateLimiters.Validate(); err != nil {
 			return err
@@ -87,6 +101,33 @@ func (s *ServerConfig) Validate() error {
 	if s.MaxTimeout == nil || *s.MaxTimeout == 0 {
 		return fmt.Errorf("server.maxTimeout is required")
 	}
+	if s.Grpc != nil {
+		if err := s.Grpc.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.MaxRequestBodySize != nil {
+		size, err := util.ParseByteSize(*s.MaxRequestBodySize)
+		if err != nil {
+			return fmt.Errorf("server.maxRequestBodySize is invalid: %w", err)
+		}
+		if size <= 0 {
+			return fmt.Errorf("server.maxRequestBodySize must be greater than 0")
+		}
+	}
+	return nil
+}
+
+func (g *GrpcServerConfig) Validate() error {
+	if g.Enabled == nil || !*g.Enabled {
+		return nil
+	}
+	if g.Host == nil || *g.Host == "" {
+		return fmt.Errorf("server.grpc.host is required when grpc server is enabled")
+	}
+	if g.Port == nil || *g.Port <= 0 {
+		return fmt.Errorf("server.grpc.port must be a positive number")
+	}
 	return nil
 }
 
@@ -136,6 +177,72 @@ func (m *MetricsConfig) Validate() error {
 		}
 	}
 
+	if m.Otlp != nil && m.Otlp.Enabled {
+		if err := validateOtlpProtocol("metrics.otlp", m.Otlp.Protocol); err != nil {
+			return err
+		}
+		if m.Otlp.Endpoint == "" {
+			return fmt.Errorf("metrics.otlp.endpoint is required when metrics.otlp.enabled is true")
+		}
+		if m.Otlp.PushInterval <= 0 {
+			return fmt.Errorf("metrics.otlp.pushInterval must be greater than 0")
+		}
+		if m.Otlp.Temporality != MetricsTemporalityCumulative && m.Otlp.Temporality != MetricsTemporalityDelta {
+			return fmt.Errorf("metrics.otlp.temporality must be either 'cumulative' or 'delta'")
+		}
+	}
+
+	if c := m.ConsumerLabel; c != nil && c.Enabled {
+		switch c.Source {
+		case ConsumerLabelSourceAuth, ConsumerLabelSourceUserAgent:
+		case ConsumerLabelSourceHeader:
+			if c.Header == "" {
+				return fmt.Errorf("metrics.consumerLabel.header is required when metrics.consumerLabel.source is 'header'")
+			}
+		default:
+			return fmt.Errorf("metrics.consumerLabel.source must be one of 'auth', 'header' or 'userAgent'")
+		}
+		if c.MaxValues <= 0 {
+			return fmt.Errorf("metrics.consumerLabel.maxValues must be greater than 0")
+		}
+		if c.RankingWindow <= 0 {
+			return fmt.Errorf("metrics.consumerLabel.rankingWindow must be greater than 0")
+		}
+	}
+
+	return nil
+}
+
+func (t *TracingConfig) Validate() error {
+	if t.TailSampling != nil && t.TailSampling.Enabled {
+		if t.TailSampling.LatencyThreshold < 0 {
+			return fmt.Errorf("tracing.tailSampling.latencyThreshold must not be negative")
+		}
+		if t.TailSampling.MaxSpansPerRequest <= 0 {
+			return fmt.Errorf("tracing.tailSampling.maxSpansPerRequest must be greater than 0")
+		}
+	}
+
+	return nil
+}
+
+func (l *LogsConfig) Validate() error {
+	if l.Otlp != nil && l.Otlp.Enabled {
+		if err := validateOtlpProtocol("logs.otlp", l.Otlp.Protocol); err != nil {
+			return err
+		}
+		if l.Otlp.Endpoint == "" {
+			return fmt.Errorf("logs.otlp.endpoint is required when logs.otlp.enabled is true")
+		}
+	}
+
+	return nil
+}
+
+func validateOtlpProtocol(path string, protocol TracingProtocol) error {
+	if protocol != TracingProtocolGrpc && protocol != TracingProtocolHttp {
+		return fmt.Errorf("%s.protocol must be either 'grpc' or 'http'", path)
+	}
 	return nil
 }
 
@@ -216,6 +323,18 @@ func (s *SharedStateConfig) Validate() error {
 	if s.FallbackTimeout == 0 {
 		return fmt.Errorf("sharedState.fallbackTimeout is required")
 	}
+	if s.LeaderElection != nil {
+		if err := s.LeaderElection.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LeaderElectionConfig) Validate() error {
+	if l.RenewInterval >= l.LeaseTtl {
+		return fmt.Errorf("sharedState.leaderElection.renewInterval (%s) must be shorter than leaseTtl (%s) so the lease is renewed before it expires", l.RenewInterval.String(), l.LeaseTtl.String())
+	}
 	return nil
 }
 
@@ -514,6 +633,113 @@ func (p *ProjectConfig) Validate(c *Config) error {
 	if p.ScoreMetricsWindowSize == 0 {
 		return fmt.Errorf("project.*.scoreMetricsWindowSize is required")
 	}
+	if p.DisputeLog != nil {
+		if err := p.DisputeLog.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Firewall != nil {
+		if err := p.Firewall.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Batch != nil {
+		if err := p.Batch.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Capture != nil {
+		if err := p.Capture.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *BatchConfig) Validate() error {
+	if b.MaxSize < 0 {
+		return fmt.Errorf("project.*.batch.maxSize must be greater than or equal to 0")
+	}
+	if b.MaxConcurrency < 0 {
+		return fmt.Errorf("project.*.batch.maxConcurrency must be greater than or equal to 0")
+	}
+	return nil
+}
+
+func (f *FirewallConfig) Validate() error {
+	ids := make(map[string]bool)
+	for i, rule := range f.Rules {
+		if rule == nil {
+			return fmt.Errorf("project.*.firewall.rules[%d] cannot be empty", i)
+		}
+		if ids[rule.Id] {
+			return fmt.Errorf("project.*.firewall.rules.*.id must be unique, '%s' is duplicated", rule.Id)
+		}
+		ids[rule.Id] = true
+		if rule.Action != FirewallActionDeny && rule.Action != FirewallActionAllow {
+			return fmt.Errorf("project.*.firewall.rules.%s.action must be one of: %v", rule.Id, []FirewallAction{FirewallActionDeny, FirewallActionAllow})
+		}
+		if err := ValidatePattern(rule.Network); err != nil {
+			return fmt.Errorf("project.*.firewall.rules.%s.network is invalid: %w", rule.Id, err)
+		}
+		if err := ValidatePattern(rule.Method); err != nil {
+			return fmt.Errorf("project.*.firewall.rules.%s.method is invalid: %w", rule.Id, err)
+		}
+		for _, c := range rule.Contracts {
+			if err := ValidatePattern(c); err != nil {
+				return fmt.Errorf("project.*.firewall.rules.%s.contracts is invalid: %w", rule.Id, err)
+			}
+		}
+		if rule.BlockRangeExceeds < 0 {
+			return fmt.Errorf("project.*.firewall.rules.%s.blockRangeExceeds must be greater than or equal to 0", rule.Id)
+		}
+	}
+	return nil
+}
+
+func (c *CaptureConfig) Validate() error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("project.*.capture.sampleRate must be between 0 and 1")
+	}
+	if c.File == "" {
+		return fmt.Errorf("project.*.capture.file is required")
+	}
+	if c.MaxResultSize < 0 {
+		return fmt.Errorf("project.*.capture.maxResultSize must be greater than or equal to 0")
+	}
+	if c.BufferSize <= 0 {
+		return fmt.Errorf("project.*.capture.bufferSize must be greater than 0")
+	}
+	for i, rule := range c.Redact {
+		if rule == nil || len(rule.Paths) == 0 {
+			return fmt.Errorf("project.*.capture.redact[%d].paths is required", i)
+		}
+		for _, path := range rule.Paths {
+			root := strings.SplitN(path, ".", 2)[0]
+			if root != "params" && root != "result" {
+				return fmt.Errorf("project.*.capture.redact[%d].paths must start with params or result: %s", i, path)
+			}
+		}
+	}
+	return nil
+}
+
+func (d *DisputeLogConfig) Validate() error {
+	if d.Connector == nil {
+		return fmt.Errorf("project.*.disputeLog.connector is required")
+	}
+	if err := d.Connector.Validate(); err != nil {
+		return err
+	}
+	if d.Retention <= 0 {
+		return fmt.Errorf("project.*.disputeLog.retention must be greater than 0")
+	}
+	if d.MaxEntries <= 0 {
+		return fmt.Errorf("project.*.disputeLog.maxEntries must be greater than 0")
+	}
+	if d.MaxBodySize != nil && *d.MaxBodySize < 0 {
+		return fmt.Errorf("project.*.disputeLog.maxBodySize must be greater than or equal to 0")
+	}
 	return nil
 }
 
@@ -562,14 +788,59 @@ func (s *AuthStrategyConfig) Validate() error {
 		if err := s.Siwe.Validate(); err != nil {
 			return err
 		}
+	case AuthTypeWebhook:
+		if s.Webhook == nil {
+			return fmt.Errorf("auth.*.webhook is required for webhook strategy")
+		}
+		if err := s.Webhook.Validate(); err != nil {
+			return err
+		}
+	case AuthTypeMtls:
+		if s.Mtls == nil {
+			return fmt.Errorf("auth.*.mtls is required for mtls strategy")
+		}
+		if err := s.Mtls.Validate(); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("auth.*.type '%s' is invalid must be one of: %v", s.Type, []AuthType{
 			AuthTypeNetwork,
 			AuthTypeSecret,
 			AuthTypeJwt,
 			AuthTypeSiwe,
+			AuthTypeWebhook,
+			AuthTypeMtls,
 		})
 	}
+	if s.Authorization != nil {
+		if s.Type != AuthTypeJwt && s.Type != AuthTypeSiwe {
+			return fmt.Errorf("auth.*.authorization is only supported for jwt and siwe strategies")
+		}
+		if err := s.Authorization.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ClaimsAuthorizationConfig) Validate() error {
+	if len(c.Tiers) > 0 && c.TierClaim == "" {
+		return fmt.Errorf("auth.*.authorization.tierClaim is required when tiers are defined")
+	}
+	if c.TierClaim != "" && len(c.Tiers) == 0 {
+		return fmt.Errorf("auth.*.authorization.tiers must have at least one tier when tierClaim is set")
+	}
+	for name, tier := range c.Tiers {
+		if tier == nil {
+			return fmt.Errorf("auth.*.authorization.tiers.%s cannot be empty", name)
+		}
+		if tier.MaxBatchSize < 0 {
+			return fmt.Errorf("auth.*.authorization.tiers.%s.maxBatchSize must be greater than or equal to 0", name)
+		}
+		if tier.MaxBatchConcurrency < 0 {
+			return fmt.Errorf("auth.*.authorization.tiers.%s.maxBatchConcurrency must be greater than or equal to 0", name)
+		}
+	}
 	return nil
 }
 
@@ -578,15 +849,78 @@ func (s *NetworkStrategyConfig) Validate() error {
 }
 
 func (s *SecretStrategyConfig) Validate() error {
-	if s.Value == "" {
-		return fmt.Errorf("auth.*.secret.value is required")
+	if s.Value == "" && s.KeysFile == "" && s.KeysConnector == nil {
+		return fmt.Errorf("auth.*.secret.value, auth.*.secret.keysFile or auth.*.secret.keysConnector is required")
+	}
+	if s.KeysConnector != nil {
+		if err := s.KeysConnector.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.KeysRefreshInterval < 0 {
+		return fmt.Errorf("auth.*.secret.keysRefreshInterval must be positive")
 	}
 	return nil
 }
 
 func (j *JwtStrategyConfig) Validate() error {
-	if len(j.VerificationKeys) == 0 {
-		return fmt.Errorf("auth.*.jwt.verificationKeys is required, add at least one verification key")
+	if len(j.VerificationKeys) == 0 && len(j.JwksUrls) == 0 {
+		return fmt.Errorf("auth.*.jwt.verificationKeys or auth.*.jwt.jwksUrls is required, add at least one verification key or JWKS url")
+	}
+	for _, u := range j.JwksUrls {
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("auth.*.jwt.jwksUrls contains an invalid url: %s", u)
+		}
+	}
+	if j.JwksRefreshInterval < 0 || j.JwksMinRefreshInterval < 0 {
+		return fmt.Errorf("auth.*.jwt.jwksRefreshInterval and auth.*.jwt.jwksMinRefreshInterval must be positive")
+	}
+	return nil
+}
+
+func (w *WebhookStrategyConfig) Validate() error {
+	if w.Url == "" {
+		return fmt.Errorf("auth.*.webhook.url is required")
+	}
+	parsed, err := url.Parse(w.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("auth.*.webhook.url must be a valid http(s) url: %s", w.Url)
+	}
+	if w.Timeout <= 0 {
+		return fmt.Errorf("auth.*.webhook.timeout must be greater than 0")
+	}
+	if w.CacheTtl < 0 {
+		return fmt.Errorf("auth.*.webhook.cacheTtl must be positive")
+	}
+	for _, t := range w.PayloadTypes {
+		if t != AuthTypeSecret && t != AuthTypeJwt && t != AuthTypeSiwe && t != AuthTypeNetwork {
+			return fmt.Errorf("auth.*.webhook.payloadTypes contains invalid type '%s'", t)
+		}
+	}
+	return nil
+}
+
+func (m *MtlsStrategyConfig) Validate() error {
+	if len(m.CAFiles) == 0 {
+		return fmt.Errorf("auth.*.mtls.caFiles is required, add at least one CA bundle")
+	}
+	for i, rule := range m.Rules {
+		if rule == nil || (rule.Subject == "" && rule.San == "") {
+			return fmt.Errorf("auth.*.mtls.rules[%d] must have a subject or san pattern", i)
+		}
+		if err := ValidatePattern(rule.Subject); err != nil {
+			return fmt.Errorf("auth.*.mtls.rules[%d].subject is invalid: %w", i, err)
+		}
+		if err := ValidatePattern(rule.San); err != nil {
+			return fmt.Errorf("auth.*.mtls.rules[%d].san is invalid: %w", i, err)
+		}
+		if rule.MaxBatchSize < 0 {
+			return fmt.Errorf("auth.*.mtls.rules[%d].maxBatchSize must be greater than or equal to 0", i)
+		}
+		if rule.MaxBatchConcurrency < 0 {
+			return fmt.Errorf("auth.*.mtls.rules[%d].maxBatchConcurrency must be greater than or equal to 0", i)
+		}
 	}
 	return nil
 }
@@ -622,6 +956,14 @@ func (u *ProviderConfig) Validate(c *Config) error {
 	if u.OnlyNetworks != nil && u.IgnoreNetworks != nil {
 		return fmt.Errorf("project.*.providers.*.onlyNetworks and project.*.providers.*.ignoreNetworks are mutually exclusive")
 	}
+	if u.Vendor == "dns" {
+		// Nodes are addressed by IP in dns mode, which node certificates (and SNI) would not match
+		mode, _ := u.Settings["mode"].(string)
+		endpoint, _ := u.Settings["endpoint"].(string)
+		if (mode == "" || mode == "dns") && strings.HasPrefix(endpoint, "https://") {
+			return fmt.Errorf("project.*.providers.*.settings.endpoint cannot be https in 'dns' mode (dns+https://) because nodes are called by IP and TLS verification would fail, use 'srv' mode (srv+https://) instead")
+		}
+	}
 	if u.Overrides != nil {
 		for _, override := range u.Overrides {
 			if err := override.Validate(c, true); err != nil {
@@ -705,10 +1047,39 @@ func (e *EvmUpstreamConfig) Validate(u *UpstreamConfig) error {
 			return fmt.Errorf("upstream.*.evm.nodeType '%s' is invalid must be one of: %v", e.NodeType, allowed)
 		}
 	}
+	if e.HeadSubscription != nil {
+		if err := e.HeadSubscription.Validate(u); err != nil {
+			return err
+		}
+		if e.HeadSubscription.PollerInterval > e.StatePollerInterval {
+			return fmt.Errorf("upstream.*.evm.headSubscription.pollerInterval must not be greater than statePollerInterval (%s)", e.StatePollerInterval.String())
+		}
+	}
 
 	return nil
 }
 
+func (h *EvmHeadSubscriptionConfig) Validate(u *UpstreamConfig) error {
+	endpoint := h.Endpoint
+	if endpoint == "" {
+		if !strings.HasPrefix(u.Endpoint, "http://") && !strings.HasPrefix(u.Endpoint, "https://") {
+			return fmt.Errorf("upstream.*.evm.headSubscription.endpoint is required for non-http upstream %s", util.RedactEndpoint(u.Endpoint))
+		}
+	} else if !strings.HasPrefix(endpoint, "ws://") && !strings.HasPrefix(endpoint, "wss://") {
+		return fmt.Errorf("upstream.*.evm.headSubscription.endpoint must be a ws:// or wss:// url, got %s", util.RedactEndpoint(endpoint))
+	}
+	if h.StaleTimeout <= 0 {
+		return fmt.Errorf("upstream.*.evm.headSubscription.staleTimeout must be greater than 0")
+	}
+	if h.MaxReconnectDelay <= 0 {
+		return fmt.Errorf("upstream.*.evm.headSubscription.maxReconnectDelay must be greater than 0")
+	}
+	if h.PollerInterval <= 0 {
+		return fmt.Errorf("upstream.*.evm.headSubscription.pollerInterval must be greater than 0")
+	}
+	return nil
+}
+
 func (f *FailsafeConfig) Validate() error {
 	// Validate MatchMethod - empty string is not allowed
 	if f.MatchMethod == "" {
@@ -735,6 +1106,25 @@ func (f *FailsafeConfig) Validate() error {
 			return err
 		}
 	}
+	if f.Consensus != nil {
+		if err := f.Consensus.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ConsensusPolicyConfig) Validate() error {
+	for upsId, weight := range c.UpstreamWeights {
+		if weight <= 0 {
+			return fmt.Errorf("failsafe.consensus.upstreamWeights.%s must be greater than 0", upsId)
+		}
+	}
+	for method, profile := range c.ComparisonProfiles {
+		if profile == nil || (len(profile.IncludeFields) == 0 && len(profile.IgnoreFields) == 0) {
+			return fmt.Errorf("failsafe.consensus.comparisonProfiles.%s must have at least one of includeFields or ignoreFields", method)
+		}
+	}
 	return nil
 }
 
@@ -742,6 +1132,20 @@ func (t *TimeoutPolicyConfig) Validate() error {
 	if t.Duration == 0 {
 		return fmt.Errorf("upstream.*.failsafe.timeout.duration is required")
 	}
+	if t.Quantile < 0 || t.Quantile > 1 {
+		return fmt.Errorf("failsafe.timeout.quantile must be between 0 and 1")
+	}
+	if t.Quantile > 0 {
+		if t.Multiplier <= 0 {
+			return fmt.Errorf("failsafe.timeout.multiplier must be greater than 0")
+		}
+		if t.MaxDuration <= 0 {
+			return fmt.Errorf("failsafe.timeout.maxDuration is required when failsafe.timeout.quantile is set")
+		}
+		if t.MinDuration > t.MaxDuration {
+			return fmt.Errorf("failsafe.timeout.minDuration must be less than or equal to failsafe.timeout.maxDuration")
+		}
+	}
 	return nil
 }
 
@@ -755,6 +1159,19 @@ func (r *RetryPolicyConfig) Validate() error {
 	return nil
 }
 
+func (b *RetryBudgetConfig) Validate() error {
+	if b.MaxRatio <= 0 || b.MaxRatio > 1 {
+		return fmt.Errorf("network.*.retryBudget.maxRatio must be greater than 0 and at most 1")
+	}
+	if b.Window <= 0 {
+		return fmt.Errorf("network.*.retryBudget.window must be greater than 0")
+	}
+	if b.MinRetries < 0 {
+		return fmt.Errorf("network.*.retryBudget.minRetries cannot be negative")
+	}
+	return nil
+}
+
 func (h *HedgePolicyConfig) Validate() error {
 	if h.Quantile <= 0 && h.Delay <= 0 {
 		return fmt.Errorf("failsafe.hedge.delay or failsafe.hedge.quantile is required")
@@ -810,6 +1227,15 @@ func (j *JsonRpcUpstreamConfig) Validate(c *Config) error {
 			return fmt.Errorf("jsonRpc.proxyPool '%s' does not exist in configured proxyPools, must be one of: %v", j.ProxyPool, allIds)
 		}
 	}
+	if j.StreamingThreshold != "" {
+		threshold, err := util.ParseByteSize(j.StreamingThreshold)
+		if err != nil {
+			return fmt.Errorf("jsonRpc.streamingThreshold is invalid: %w", err)
+		}
+		if threshold <= 0 {
+			return fmt.Errorf("jsonRpc.streamingThreshold must be greater than 0")
+		}
+	}
 	return nil
 }
 
@@ -871,6 +1297,11 @@ func (n *NetworkConfig) Validate(c *Config) error {
 			}
 		}
 	}
+	if n.RetryBudget != nil {
+		if err := n.RetryBudget.Validate(); err != nil {
+			return err
+		}
+	}
 	if n.SelectionPolicy != nil {
 		if err := n.SelectionPolicy.Validate(); err != nil {
 			return err
@@ -897,6 +1328,9 @@ func (e *EvmNetworkConfig) Validate() error {
 	if e.FallbackStatePollerDebounce == 0 {
 		return fmt.Errorf("network.*.evm.fallbackStatePollerDebounce is required")
 	}
+	if e.TransactionBroadcast != nil && e.TransactionBroadcast.MaxUpstreams < 0 {
+		return fmt.Errorf("network.*.evm.transactionBroadcast.maxUpstreams must be greater than or equal to 0")
+	}
 	return nil
 }
 
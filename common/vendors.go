@@ -14,3 +14,10 @@ type Vendor interface {
 	SupportsNetwork(ctx context.Context, logger *zerolog.Logger, settings VendorSettings, networkId string) (bool, error)
 	GetVendorSpecificErrorIfAny(req *NormalizedRequest, resp *http.Response, bodyObject interface{}, details map[string]interface{}) error
 }
+
+// VendorWithProprietaryMethods is implemented by vendors exposing their own JSON-RPC methods (e.g. alchemy_getAssetTransfers).
+// These methods are only forwarded to upstreams of the owning vendor, unless an upstream explicitly allows them via allowMethods.
+type VendorWithProprietaryMethods interface {
+	// ProprietaryMethods returns wildcard patterns of the methods owned by the vendor (e.g. "alchemy_*")
+	ProprietaryMethods() []string
+}
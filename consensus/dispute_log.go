@@ -0,0 +1,405 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/rs/zerolog"
+)
+
+const (
+	disputeLogIndexRangeKey = "index"
+	disputeLogWriteTimeout  = 5 * time.Second
+	// disputeLogQueueSize bounds the disputes waiting to be stored, more are dropped during a dispute storm
+	disputeLogQueueSize = 1000
+	// disputeLogMaxBatchSize is the max number of queued disputes added to the index in one write
+	disputeLogMaxBatchSize = 100
+)
+
+// DisputeParticipant is the evidence provided by a single upstream during a consensus dispute
+type DisputeParticipant struct {
+	Upstream   string `json:"upstream"`
+	ResultHash string `json:"resultHash,omitempty"`
+	Body       string `json:"body,omitempty"`
+	Truncated  bool   `json:"truncated,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// DisputeRecord is the audit trail of a consensus dispute which can be used to escalate with providers
+type DisputeRecord struct {
+	Id                string                `json:"id"`
+	Timestamp         int64                 `json:"timestamp"` // unix milliseconds
+	ProjectId         string                `json:"projectId"`
+	NetworkId         string                `json:"networkId"`
+	Method            string                `json:"method"`
+	Finality          string                `json:"finality"`
+	Request           string                `json:"request,omitempty"`
+	Participants      []*DisputeParticipant `json:"participants"`
+	DisputeBehavior   string                `json:"disputeBehavior"`
+	WinnerUpstream    string                `json:"winnerUpstream,omitempty"`
+	WinnerHash        string                `json:"winnerHash,omitempty"`
+	Error             string                `json:"error,omitempty"`
+	PunishedUpstreams []string              `json:"punishedUpstreams,omitempty"`
+}
+
+func (r *DisputeRecord) upstreamIds() []string {
+	ids := make([]string, 0, len(r.Participants))
+	for _, p := range r.Participants {
+		ids = append(ids, p.Upstream)
+	}
+	return ids
+}
+
+// DisputeRecorder receives dispute evidence from the consensus executor.
+type DisputeRecorder interface {
+	MaxBodySize() int
+	RecordDispute(ctx context.Context, record *DisputeRecord)
+}
+
+type disputeRecorderContextKey struct{}
+
+// WithDisputeRecorder makes the recorder available to the consensus policy executor of this request
+func WithDisputeRecorder(ctx context.Context, recorder DisputeRecorder) context.Context {
+	return context.WithValue(ctx, disputeRecorderContextKey{}, recorder)
+}
+
+func disputeRecorderFromContext(ctx context.Context) DisputeRecorder {
+	if ctx == nil {
+		return nil
+	}
+	if rec, ok := ctx.Value(disputeRecorderContextKey{}).(DisputeRecorder); ok {
+		return rec
+	}
+	return nil
+}
+
+// DisputeFilter narrows down disputes returned by DisputeLog.List, empty fields match everything
+type DisputeFilter struct {
+	NetworkId  string `json:"networkId,omitempty"`
+	Method     string `json:"method,omitempty"`
+	UpstreamId string `json:"upstreamId,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+}
+
+// disputeIndexEntry keeps enough info to filter disputes without loading every record
+type disputeIndexEntry struct {
+	Id        string   `json:"id"`
+	Timestamp int64    `json:"timestamp"`
+	NetworkId string   `json:"networkId"`
+	Method    string   `json:"method"`
+	Upstreams []string `json:"upstreams"`
+}
+
+func (e *disputeIndexEntry) matches(filter *DisputeFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.NetworkId != "" && filter.NetworkId != e.NetworkId {
+		return false
+	}
+	if filter.Method != "" {
+		if match, err := common.WildcardMatch(filter.Method, e.Method); err != nil || !match {
+			return false
+		}
+	}
+	if filter.UpstreamId != "" {
+		found := false
+		for _, u := range e.Upstreams {
+			if u == filter.UpstreamId {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// DisputeLog persists dispute records of a project into a data connector. Every record is stored
+// with the configured retention as TTL, and an index of recent disputes (capped to maxEntries)
+// is kept under a distributed lock so that multiple instances can share the same log. Records are
+// stored by a single background worker which adds all queued disputes to the index in one write.
+type DisputeLog struct {
+	logger       *zerolog.Logger
+	appCtx       context.Context
+	projectId    string
+	connector    data.Connector
+	partitionKey string
+	retention    time.Duration
+	maxEntries   int
+	maxBodySize  int
+	seq          atomic.Uint64
+	queue        chan *DisputeRecord
+
+	// localIndex is the last index written by this instance, merged with the stored index
+	// because some connectors (e.g. memory) apply writes asynchronously.
+	localIndexMu sync.Mutex
+	localIndex   []*disputeIndexEntry
+}
+
+var _ DisputeRecorder = &DisputeLog{}
+
+func NewDisputeLog(appCtx context.Context, logger *zerolog.Logger, projectId string, cfg *common.DisputeLogConfig) (*DisputeLog, error) {
+	lg := logger.With().Str("component", "disputeLog").Logger()
+	connector, err := data.NewConnector(appCtx, &lg, cfg.Connector)
+	if err != nil {
+		return nil, err
+	}
+	return newDisputeLogWithConnector(appCtx, &lg, projectId, connector, cfg), nil
+}
+
+func newDisputeLogWithConnector(appCtx context.Context, logger *zerolog.Logger, projectId string, connector data.Connector, cfg *common.DisputeLogConfig) *DisputeLog {
+	d := &DisputeLog{
+		logger:       logger,
+		appCtx:       appCtx,
+		projectId:    projectId,
+		connector:    connector,
+		partitionKey: fmt.Sprintf("consensus-disputes/%s", projectId),
+		retention:    cfg.Retention.Duration(),
+		maxEntries:   cfg.MaxEntries,
+		queue:        make(chan *DisputeRecord, disputeLogQueueSize),
+	}
+	if cfg.MaxBodySize != nil {
+		d.maxBodySize = *cfg.MaxBodySize
+	}
+	go d.run()
+	return d
+}
+
+func (d *DisputeLog) MaxBodySize() int {
+	return d.maxBodySize
+}
+
+// RecordDispute queues the record to be stored in the background so that request latency is not
+// affected, the record is dropped when the queue is full.
+func (d *DisputeLog) RecordDispute(_ context.Context, record *DisputeRecord) {
+	if record.Id == "" {
+		record.Id = fmt.Sprintf("%d-%d", time.Now().UnixNano(), d.seq.Add(1))
+	}
+	if record.Timestamp == 0 {
+		record.Timestamp = time.Now().UnixMilli()
+	}
+	select {
+	case d.queue <- record:
+	default:
+		d.logger.Warn().Str("disputeId", record.Id).Msg("dropping consensus dispute evidence as the dispute log cannot keep up")
+	}
+}
+
+func (d *DisputeLog) run() {
+	for {
+		select {
+		case <-d.appCtx.Done():
+			return
+		case record := <-d.queue:
+			batch := []*DisputeRecord{record}
+		drain:
+			for len(batch) < disputeLogMaxBatchSize {
+				select {
+				case record := <-d.queue:
+					batch = append(batch, record)
+				default:
+					break drain
+				}
+			}
+			ctx, cancel := context.WithTimeout(d.appCtx, disputeLogWriteTimeout)
+			if err := d.store(ctx, batch...); err != nil {
+				d.logger.Warn().Err(err).Int("disputes", len(batch)).Msg("failed to persist consensus dispute evidence")
+			}
+			cancel()
+		}
+	}
+}
+
+// store persists the records, then appends them to the index in a single write
+func (d *DisputeLog) store(ctx context.Context, records ...*DisputeRecord) error {
+	entries := make([]*disputeIndexEntry, 0, len(records))
+	for _, record := range records {
+		value, err := common.SonicCfg.Marshal(record)
+		if err == nil {
+			err = d.connector.Set(ctx, d.partitionKey, record.Id, value, &d.retention)
+		}
+		if err != nil {
+			d.logger.Warn().Err(err).Str("disputeId", record.Id).Msg("failed to persist consensus dispute evidence")
+			continue
+		}
+		entries = append(entries, &disputeIndexEntry{
+			Id:        record.Id,
+			Timestamp: record.Timestamp,
+			NetworkId: record.NetworkId,
+			Method:    record.Method,
+			Upstreams: record.upstreamIds(),
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	d.localIndexMu.Lock()
+	defer d.localIndexMu.Unlock()
+
+	lock, err := d.connector.Lock(ctx, d.partitionKey+"/"+disputeLogIndexRangeKey, disputeLogWriteTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to acquire dispute index lock: %w", err)
+	}
+	defer func() {
+		if lock != nil && !lock.IsNil() {
+			if err := lock.Unlock(ctx); err != nil {
+				d.logger.Debug().Err(err).Msg("failed to release dispute index lock")
+			}
+		}
+	}()
+
+	index, err := d.loadIndex(ctx)
+	if err != nil {
+		return err
+	}
+	index = appendDisputeIndex(index, d.localIndex)
+	kept := d.trimIndex(appendDisputeIndex(index, entries))
+
+	indexValue, err := common.SonicCfg.Marshal(kept)
+	if err != nil {
+		return err
+	}
+	if err := d.connector.Set(ctx, d.partitionKey, disputeLogIndexRangeKey, indexValue, &d.retention); err != nil {
+		return err
+	}
+	d.localIndex = kept
+
+	return nil
+}
+
+// trimIndex drops entries older than the retention and keeps at most maxEntries of the most recent ones
+func (d *DisputeLog) trimIndex(index []*disputeIndexEntry) []*disputeIndexEntry {
+	oldest := time.Now().Add(-d.retention).UnixMilli()
+	kept := make([]*disputeIndexEntry, 0, len(index))
+	for _, e := range index {
+		if e.Timestamp >= oldest {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) > d.maxEntries {
+		kept = kept[len(kept)-d.maxEntries:]
+	}
+	return kept
+}
+
+// appendDisputeIndex appends the entries that are not in the index yet (by id), keeping the order of both
+func appendDisputeIndex(index, entries []*disputeIndexEntry) []*disputeIndexEntry {
+	if len(entries) == 0 {
+		return index
+	}
+	seen := make(map[string]struct{}, len(index))
+	for _, e := range index {
+		seen[e.Id] = struct{}{}
+	}
+	for _, e := range entries {
+		if _, ok := seen[e.Id]; ok {
+			continue
+		}
+		seen[e.Id] = struct{}{}
+		index = append(index, e)
+	}
+	return index
+}
+
+// mergeDisputeIndex returns the union of both indexes (by id) sorted by timestamp, oldest first
+func mergeDisputeIndex(a, b []*disputeIndexEntry) []*disputeIndexEntry {
+	if len(b) == 0 {
+		return a
+	}
+	seen := make(map[string]struct{}, len(a)+len(b))
+	merged := make([]*disputeIndexEntry, 0, len(a)+len(b))
+	for _, list := range [][]*disputeIndexEntry{a, b} {
+		for _, e := range list {
+			if _, ok := seen[e.Id]; ok {
+				continue
+			}
+			seen[e.Id] = struct{}{}
+			merged = append(merged, e)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp < merged[j].Timestamp
+	})
+	return merged
+}
+
+func (d *DisputeLog) loadIndex(ctx context.Context) ([]*disputeIndexEntry, error) {
+	raw, err := d.connector.Get(ctx, data.ConnectorMainIndex, d.partitionKey, disputeLogIndexRangeKey)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var index []*disputeIndexEntry
+	if err := common.SonicCfg.Unmarshal(raw, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse dispute index: %w", err)
+	}
+	return index, nil
+}
+
+// List returns the most recent disputes (newest first) matching the filter
+func (d *DisputeLog) List(ctx context.Context, filter *DisputeFilter) ([]*DisputeRecord, error) {
+	index, err := d.loadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.localIndexMu.Lock()
+	index = d.trimIndex(mergeDisputeIndex(index, d.localIndex))
+	d.localIndexMu.Unlock()
+	sort.SliceStable(index, func(i, j int) bool {
+		return index[i].Timestamp > index[j].Timestamp
+	})
+
+	limit := 100
+	if filter != nil && filter.Limit > 0 {
+		limit = filter.Limit
+	}
+
+	records := []*DisputeRecord{}
+	for _, e := range index {
+		if len(records) >= limit {
+			break
+		}
+		if !e.matches(filter) {
+			continue
+		}
+		raw, err := d.connector.Get(ctx, data.ConnectorMainIndex, d.partitionKey, e.Id)
+		if err != nil {
+			if common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+				// Record has expired (or was evicted) before the index entry
+				continue
+			}
+			return nil, err
+		}
+		record := &DisputeRecord{}
+		if err := common.SonicCfg.Unmarshal(raw, record); err != nil {
+			d.logger.Warn().Err(err).Str("disputeId", e.Id).Msg("failed to parse stored consensus dispute")
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// truncateBody returns at most maxSize bytes of the body as string (copied) and whether it was truncated
+func truncateBody(body []byte, maxSize int) (string, bool) {
+	if maxSize <= 0 {
+		return "", len(body) > 0
+	}
+	if len(body) > maxSize {
+		return string(body[:maxSize]), true
+	}
+	return string(body), false
+}
@@ -0,0 +1,247 @@
+package consensus
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/util"
+	failsafeCommon "github.com/failsafe-go/failsafe-go/common"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDisputeLog(t *testing.T, cfg *common.DisputeLogConfig) *DisputeLog {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	logger := zerolog.Nop()
+	connector, err := data.NewMemoryConnector(ctx, &logger, "dispute-log", &common.MemoryConnectorConfig{
+		MaxItems: 1000, MaxTotalSize: "10MB",
+	})
+	require.NoError(t, err)
+	return newDisputeLogWithConnector(ctx, &logger, "test", connector, cfg)
+}
+
+func TestDisputeLog_StoreAndList(t *testing.T) {
+	dl := newTestDisputeLog(t, &common.DisputeLogConfig{
+		Retention:   common.Duration(time.Hour),
+		MaxEntries:  3,
+		MaxBodySize: util.IntPtr(16),
+	})
+	ctx := context.Background()
+
+	records := []*DisputeRecord{
+		{Id: "d1", Timestamp: time.Now().UnixMilli() - 4, NetworkId: "evm:1", Method: "eth_getBalance", Participants: []*DisputeParticipant{{Upstream: "alchemy"}, {Upstream: "infura"}}},
+		{Id: "d2", Timestamp: time.Now().UnixMilli() - 3, NetworkId: "evm:1", Method: "eth_call", Participants: []*DisputeParticipant{{Upstream: "alchemy"}, {Upstream: "public"}}},
+		{Id: "d3", Timestamp: time.Now().UnixMilli() - 2, NetworkId: "evm:10", Method: "eth_getBalance", Participants: []*DisputeParticipant{{Upstream: "public"}}},
+		{Id: "d4", Timestamp: time.Now().UnixMilli() - 1, NetworkId: "evm:1", Method: "eth_getLogs", Participants: []*DisputeParticipant{{Upstream: "infura"}, {Upstream: "public"}}},
+	}
+	for _, r := range records {
+		require.NoError(t, dl.store(ctx, r))
+	}
+	// Memory connector applies writes asynchronously
+	require.Eventually(t, func() bool {
+		list, err := dl.List(ctx, nil)
+		return err == nil && len(list) == 3
+	}, 2*time.Second, 10*time.Millisecond)
+
+	t.Run("NewestFirstAndCappedToMaxEntries", func(t *testing.T) {
+		list, err := dl.List(ctx, nil)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, r := range list {
+			ids = append(ids, r.Id)
+		}
+		assert.Equal(t, []string{"d4", "d3", "d2"}, ids)
+	})
+
+	t.Run("FilterByNetworkMethodAndUpstream", func(t *testing.T) {
+		list, err := dl.List(ctx, &DisputeFilter{NetworkId: "evm:1"})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, "d4", list[0].Id)
+
+		list, err = dl.List(ctx, &DisputeFilter{Method: "eth_get*"})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, "d3", list[1].Id)
+
+		list, err = dl.List(ctx, &DisputeFilter{UpstreamId: "alchemy"})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "d2", list[0].Id)
+
+		list, err = dl.List(ctx, &DisputeFilter{UpstreamId: "public", Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "d4", list[0].Id)
+	})
+
+	t.Run("ExpiredIndexEntriesArePruned", func(t *testing.T) {
+		require.NoError(t, dl.store(ctx, &DisputeRecord{Id: "old", Timestamp: time.Now().Add(-2 * time.Hour).UnixMilli(), NetworkId: "evm:1"}))
+		require.NoError(t, dl.store(ctx, &DisputeRecord{Id: "d5", Timestamp: time.Now().UnixMilli(), NetworkId: "evm:1"}))
+
+		require.Eventually(t, func() bool {
+			list, err := dl.List(ctx, nil)
+			return err == nil && len(list) > 0 && list[0].Id == "d5"
+		}, 2*time.Second, 10*time.Millisecond)
+		for _, e := range dl.localIndex {
+			assert.NotEqual(t, "old", e.Id)
+		}
+	})
+}
+
+func TestDisputeLog_RecordDisputeIsAsynchronous(t *testing.T) {
+	dl := newTestDisputeLog(t, &common.DisputeLogConfig{
+		Retention:   common.Duration(time.Hour),
+		MaxEntries:  100,
+		MaxBodySize: util.IntPtr(16),
+	})
+
+	dl.RecordDispute(context.Background(), &DisputeRecord{NetworkId: "evm:1", Method: "eth_call"})
+	dl.RecordDispute(context.Background(), &DisputeRecord{NetworkId: "evm:1", Method: "eth_call"})
+
+	assert.Eventually(t, func() bool {
+		list, err := dl.List(context.Background(), nil)
+		return err == nil && len(list) == 2
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func TestDisputeLog_StoresQueuedDisputesInOneIndexWrite(t *testing.T) {
+	dl := newTestDisputeLog(t, &common.DisputeLogConfig{
+		Retention:   common.Duration(time.Hour),
+		MaxEntries:  100,
+		MaxBodySize: util.IntPtr(16),
+	})
+	ctx := context.Background()
+
+	now := time.Now().UnixMilli()
+	require.NoError(t, dl.store(ctx,
+		&DisputeRecord{Id: "d1", Timestamp: now - 2, NetworkId: "evm:1"},
+		&DisputeRecord{Id: "d2", Timestamp: now - 1, NetworkId: "evm:1"},
+		&DisputeRecord{Id: "d3", Timestamp: now, NetworkId: "evm:1"},
+	))
+	ids := []string{}
+	for _, e := range dl.localIndex {
+		ids = append(ids, e.Id)
+	}
+	assert.Equal(t, []string{"d1", "d2", "d3"}, ids)
+}
+
+func TestDisputeLog_DropsDisputesWhenQueueIsFull(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	logger := zerolog.Nop()
+	connector, err := data.NewMemoryConnector(context.Background(), &logger, "dispute-log", &common.MemoryConnectorConfig{
+		MaxItems: 1000, MaxTotalSize: "10MB",
+	})
+	require.NoError(t, err)
+	// The worker is stopped along with the app context, so nothing is taken off the queue
+	dl := newDisputeLogWithConnector(ctx, &logger, "test", connector, &common.DisputeLogConfig{
+		Retention:  common.Duration(time.Hour),
+		MaxEntries: 100,
+	})
+
+	for i := 0; i < disputeLogQueueSize+10; i++ {
+		dl.RecordDispute(context.Background(), &DisputeRecord{NetworkId: "evm:1", Method: "eth_call"})
+	}
+	assert.Len(t, dl.queue, disputeLogQueueSize)
+	assert.Equal(t, 0, dl.MaxBodySize())
+}
+
+func TestTruncateBody(t *testing.T) {
+	body, truncated := truncateBody([]byte(`"0x1234"`), 16)
+	assert.Equal(t, `"0x1234"`, body)
+	assert.False(t, truncated)
+
+	body, truncated = truncateBody([]byte(strings.Repeat("a", 20)), 16)
+	assert.Len(t, body, 16)
+	assert.True(t, truncated)
+
+	body, truncated = truncateBody([]byte(`"0x1"`), 0)
+	assert.Empty(t, body)
+	assert.True(t, truncated)
+}
+
+type fakeDisputeRecorder struct {
+	mu      sync.Mutex
+	records []*DisputeRecord
+}
+
+func (f *fakeDisputeRecorder) MaxBodySize() int {
+	return 4
+}
+
+func (f *fakeDisputeRecorder) RecordDispute(_ context.Context, record *DisputeRecord) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records = append(f.records, record)
+}
+
+func TestConsensusExecutor_RecordDispute(t *testing.T) {
+	ups1 := common.NewFakeUpstream("upstream1")
+	ups2 := common.NewFakeUpstream("upstream2")
+	ups3 := common.NewFakeUpstream("upstream3")
+
+	responses := []*common.NormalizedResponse{
+		createResponse("result1", ups1),
+		createResponse("result2", ups2),
+		createResponse("result3", ups3),
+	}
+	mockExec := &mockExecution{responses: responses}
+	results := []*execResult[*common.NormalizedResponse]{
+		{result: responses[0], upstream: ups1, index: 0},
+		{result: responses[1], upstream: ups2, index: 1},
+		{result: responses[2], upstream: ups3, index: 2},
+	}
+
+	lg := log.Logger
+	p := NewConsensusPolicyBuilder[*common.NormalizedResponse]().
+		WithRequiredParticipants(3).
+		WithAgreementThreshold(2).
+		WithDisputeBehavior(common.ConsensusDisputeBehaviorAcceptMostCommonValidResult).
+		WithLowParticipantsBehavior(common.ConsensusLowParticipantsBehaviorReturnError).
+		WithLogger(&lg).
+		Build()
+	e := &executor[*common.NormalizedResponse]{
+		consensusPolicy: p.(*consensusPolicy[*common.NormalizedResponse]),
+	}
+
+	res, disputed := e.evaluateConsensus(mockExec.Context(), &lg, results, mockExec)
+	assert.True(t, disputed)
+
+	t.Run("NoRecorderInContext", func(t *testing.T) {
+		e.recordDispute(mockExec.Context(), nil, results, res, nil, mockExec)
+	})
+
+	t.Run("RecordsEvidence", func(t *testing.T) {
+		recorder := &fakeDisputeRecorder{}
+		ctx := WithDisputeRecorder(mockExec.Context(), recorder)
+		req := ctx.Value(common.RequestContextKey).(*common.NormalizedRequest)
+		e.recordDispute(ctx, req, results, &failsafeCommon.PolicyResult[*common.NormalizedResponse]{Result: responses[0]}, []string{"upstream3"}, mockExec)
+
+		require.Len(t, recorder.records, 1)
+		record := recorder.records[0]
+		assert.Equal(t, "eth_test", record.Method)
+		assert.Equal(t, string(common.ConsensusDisputeBehaviorAcceptMostCommonValidResult), record.DisputeBehavior)
+		assert.Equal(t, "upstream1", record.WinnerUpstream)
+		assert.Equal(t, []string{"upstream3"}, record.PunishedUpstreams)
+		assert.Len(t, record.Request, 4)
+		require.Len(t, record.Participants, 3)
+		for i, participant := range record.Participants {
+			assert.Equal(t, results[i].upstream.Config().Id, participant.Upstream)
+			assert.NotEmpty(t, participant.ResultHash)
+			assert.Equal(t, `"res`, participant.Body)
+			assert.True(t, participant.Truncated)
+		}
+		assert.Equal(t, record.Participants[0].ResultHash, record.WinnerHash)
+		assert.NotEqual(t, record.Participants[0].ResultHash, record.Participants[1].ResultHash)
+	})
+}
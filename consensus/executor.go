@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/erpc/erpc/common"
@@ -33,15 +34,80 @@ type execResult[R any] struct {
 	upstream common.Upstream
 }
 
-// resultToHash converts a result to a string representation for comparison
+// resultToHash converts a result to a string representation for comparison.
+// When a comparison profile is configured for the method only the selected fields are hashed.
 func (e *executor[R]) resultToHash(result R, exec failsafe.Execution[R]) (string, error) {
 	jr := e.resultToJsonRpcResponse(result, exec)
 	if jr == nil {
 		return "", fmt.Errorf("no json-rpc response available on result")
 	}
+	if profile := e.comparisonProfileFor(e.resultMethod(result, exec)); profile != nil {
+		return jr.CanonicalHashWithFields(profile.IncludeFields, profile.IgnoreFields)
+	}
 	return jr.CanonicalHash()
 }
 
+// resultMethod finds the method of the request that produced the result
+func (e *executor[R]) resultMethod(result R, exec failsafe.Execution[R]) string {
+	if resp, ok := any(result).(*common.NormalizedResponse); ok && resp != nil {
+		if req := resp.Request(); req != nil {
+			if m, err := req.Method(); err == nil && m != "" {
+				return m
+			}
+		}
+	}
+	if ctx := exec.Context(); ctx != nil {
+		if req, ok := ctx.Value(common.RequestContextKey).(*common.NormalizedRequest); ok && req != nil {
+			if m, err := req.Method(); err == nil {
+				return m
+			}
+		}
+	}
+	return ""
+}
+
+// comparisonProfileFor returns the comparison profile matching the method (exact name first, then wildcards)
+func (e *executor[R]) comparisonProfileFor(method string) *common.ConsensusComparisonProfileConfig {
+	if len(e.comparisonProfiles) == 0 || method == "" {
+		return nil
+	}
+	if profile, ok := e.comparisonProfiles[method]; ok {
+		return profile
+	}
+	patterns := make([]string, 0, len(e.comparisonProfiles))
+	for pattern := range e.comparisonProfiles {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if match, err := common.WildcardMatch(pattern, method); err == nil && match {
+			return e.comparisonProfiles[pattern]
+		}
+	}
+	return nil
+}
+
+// voteWeight returns how many votes the response counts for based on its upstream
+func (e *executor[R]) voteWeight(r *execResult[R]) int {
+	if len(e.upstreamWeights) > 0 && r.upstream != nil && r.upstream.Config() != nil {
+		if w, ok := e.upstreamWeights[r.upstream.Config().Id]; ok && w > 0 {
+			return w
+		}
+	}
+	return 1
+}
+
+// maxVoteWeight returns the highest vote weight any single participant can have
+func (e *executor[R]) maxVoteWeight() int {
+	maxWeight := 1
+	for _, w := range e.upstreamWeights {
+		if w > maxWeight {
+			maxWeight = w
+		}
+	}
+	return maxWeight
+}
+
 // resultToRawString converts a result to its raw string representation
 func (e *executor[R]) resultToJsonRpcResponse(result R, exec failsafe.Execution[R]) *common.JsonRpcResponse {
 	resp, ok := any(result).(*common.NormalizedResponse)
@@ -185,10 +251,16 @@ func (e *executor[R]) Apply(innerFn func(failsafe.Execution[R]) *failsafeCommon.
 		responses := e.collectResponses(ctx, &lg, originalReq, parentExecution, innerFn)
 
 		// Phase 2: Evaluate consensus
-		result := e.evaluateConsensus(ctx, &lg, responses, exec)
+		result, disputed := e.evaluateConsensus(ctx, &lg, responses, exec)
 
 		// Phase 3: Track misbehaving upstreams (only if we have a clear majority)
-		e.checkAndPunishMisbehavingUpstreams(ctx, &lg, responses, parentExecution)
+		punished := e.checkAndPunishMisbehavingUpstreams(ctx, &lg, responses, parentExecution)
+
+		// Phase 4: Persist dispute evidence (if a dispute log is configured for the project) and keep the trace
+		if disputed {
+			e.recordDispute(ctx, originalReq, responses, result, punished, exec)
+			common.KeepRequestTrace(ctx, common.TraceKeepReasonConsensusDispute)
+		}
 
 		// Set final consensus result attributes
 		consensusSpan.SetAttributes(
@@ -496,7 +568,7 @@ func (e *executor[R]) hasConsensus(responses []*execResult[R], exec policy.Execu
 	resultCounts := make(map[string]int)
 	for _, r := range responses {
 		if resultHash, err := e.resultOrErrorToHash(r.result, r.err, exec); err == nil && resultHash != "" {
-			resultCounts[resultHash]++
+			resultCounts[resultHash] += e.voteWeight(r)
 			if resultCounts[resultHash] >= e.agreementThreshold {
 				return true
 			}
@@ -507,14 +579,14 @@ func (e *executor[R]) hasConsensus(responses []*execResult[R], exec policy.Execu
 
 // checkShortCircuit determines if we can exit early based on current responses
 func (e *executor[R]) checkShortCircuit(lg *zerolog.Logger, responses []*execResult[R], exec policy.ExecutionInternal[R]) bool {
-	// Count results by hash
+	// Count (weighted) results by hash
 	resultCounts := make(map[string]int)
 	for _, r := range responses {
 		resultHash, err := e.resultOrErrorToHash(r.result, r.err, exec)
 		if err != nil || resultHash == "" {
 			continue
 		}
-		resultCounts[resultHash]++
+		resultCounts[resultHash] += e.voteWeight(r)
 	}
 
 	// Find the most common result
@@ -552,7 +624,7 @@ func (e *executor[R]) checkShortCircuit(lg *zerolog.Logger, responses []*execRes
 	// 2. We have enough unique participants (or we know we'll have enough)
 	canReachConsensus := false
 	for _, count := range resultCounts {
-		possibleCount := count + remainingResponses
+		possibleCount := count + remainingResponses*e.maxVoteWeight()
 		if possibleCount >= e.agreementThreshold {
 			canReachConsensus = true
 			break
@@ -590,13 +662,14 @@ func (e *executor[R]) drainResponses(responseChan <-chan *execResult[R], remaini
 	}
 }
 
-// evaluateConsensus performs the final consensus evaluation on collected responses
+// evaluateConsensus performs the final consensus evaluation on collected responses,
+// it also reports whether the responses ended up in a dispute.
 func (e *executor[R]) evaluateConsensus(
 	ctx context.Context,
 	lg *zerolog.Logger,
 	responses []*execResult[R],
 	exec failsafe.Execution[R],
-) *failsafeCommon.PolicyResult[R] {
+) (*failsafeCommon.PolicyResult[R], bool) {
 	// Get request metadata for metrics
 	req := ctx.Value(common.RequestContextKey).(*common.NormalizedRequest)
 	method := "unknown"
@@ -697,7 +770,7 @@ func (e *executor[R]) evaluateConsensus(
 
 			return &failsafeCommon.PolicyResult[R]{
 				Error: consensusError,
-			}
+			}, false
 		}
 
 		// Find the actual result that matches the consensus
@@ -705,7 +778,7 @@ func (e *executor[R]) evaluateConsensus(
 			evalSpan.SetStatus(codes.Ok, "consensus achieved")
 			return &failsafeCommon.PolicyResult[R]{
 				Result: *result,
-			}
+			}, false
 		}
 	}
 
@@ -727,7 +800,7 @@ func (e *executor[R]) evaluateConsensus(
 			common.SetTraceSpanError(evalSpan, result.Error)
 			telemetry.MetricConsensusErrors.WithLabelValues(projectId, networkId, category, "low_participants", finalityStr).Inc()
 		}
-		return result
+		return result, false
 	}
 
 	// Consensus dispute
@@ -760,7 +833,7 @@ func (e *executor[R]) evaluateConsensus(
 				telemetry.MetricConsensusErrors.WithLabelValues(projectId, networkId, category, "agreed_error", finalityStr).Inc()
 				return &failsafeCommon.PolicyResult[R]{
 					Error: r.err,
-				}
+				}, false
 			}
 		}
 	}
@@ -771,7 +844,7 @@ func (e *executor[R]) evaluateConsensus(
 		common.SetTraceSpanError(evalSpan, result.Error)
 		telemetry.MetricConsensusErrors.WithLabelValues(projectId, networkId, category, "dispute", finalityStr).Inc()
 	}
-	return result
+	return result, true
 }
 
 // countUniqueParticipants counts the number of unique participants that provided responses
@@ -792,7 +865,7 @@ func (e *executor[R]) countUniqueParticipants(responses []*execResult[R]) int {
 	return len(uniqueParticipants)
 }
 
-// countResponsesByHash counts responses by their hash and returns the counts, most common hash and count
+// countResponsesByHash counts responses (weighted by upstream vote weight) by their hash and returns the counts, most common hash and count
 func (e *executor[R]) countResponsesByHash(lg *zerolog.Logger, responses []*execResult[R], exec failsafe.Execution[R]) (map[string]int, string, int) {
 	resultCounts := make(map[string]int)
 	errorCount := 0
@@ -802,7 +875,7 @@ func (e *executor[R]) countResponsesByHash(lg *zerolog.Logger, responses []*exec
 			// Also try to hash agreed-upon errors
 			if r.err != nil && e.isAgreedUponError(r.err) {
 				if hash := e.errorToConsensusHash(r.err); hash != "" {
-					resultCounts[hash]++
+					resultCounts[hash] += e.voteWeight(r)
 					continue
 				}
 			}
@@ -817,7 +890,7 @@ func (e *executor[R]) countResponsesByHash(lg *zerolog.Logger, responses []*exec
 					Msg("failed to hash result")
 			}
 		} else {
-			resultCounts[resultHash]++
+			resultCounts[resultHash] += e.voteWeight(r)
 		}
 	}
 
@@ -878,7 +951,7 @@ func (e *executor[R]) logDispute(lg *zerolog.Logger, req *common.NormalizedReque
 		Int("threshold", e.agreementThreshold).
 		Int("totalResponses", len(responses))
 
-	// Count responses by hash for the dispute log
+	// Count weighted votes by hash for the dispute log
 	resultHashCounts := make(map[string]int)
 	hashToUpstreams := make(map[string][]string)
 
@@ -898,7 +971,7 @@ func (e *executor[R]) logDispute(lg *zerolog.Logger, req *common.NormalizedReque
 				hash := e.errorToConsensusHash(resp.err)
 				if hash != "" {
 					evt.Str(fmt.Sprintf("errorHash%d", resp.index), hash)
-					resultHashCounts[hash]++
+					resultHashCounts[hash] += e.voteWeight(resp)
 					hashToUpstreams[hash] = append(hashToUpstreams[hash], upstreamID)
 				}
 			}
@@ -912,7 +985,7 @@ func (e *executor[R]) logDispute(lg *zerolog.Logger, req *common.NormalizedReque
 			// Include the hash for successful responses
 			if hash, err := e.resultToHash(resp.result, exec); err == nil && hash != "" {
 				evt.Str(fmt.Sprintf("hash%d", resp.index), hash)
-				resultHashCounts[hash]++
+				resultHashCounts[hash] += e.voteWeight(resp)
 				hashToUpstreams[hash] = append(hashToUpstreams[hash], upstreamID)
 			}
 		} else {
@@ -1019,16 +1092,17 @@ func (e *executor[R]) handleDispute(
 	}
 }
 
-// checkAndPunishMisbehavingUpstreams tracks misbehaving upstreams if there's a clear majority
+// checkAndPunishMisbehavingUpstreams tracks misbehaving upstreams if there's a clear majority,
+// and returns ids of upstreams that got punished as a result.
 func (e *executor[R]) checkAndPunishMisbehavingUpstreams(
 	ctx context.Context,
 	lg *zerolog.Logger,
 	responses []*execResult[R],
 	parentExecution policy.ExecutionInternal[R],
-) {
+) []string {
 	// Skip if punishment is not configured
 	if e.punishMisbehavior == nil {
-		return
+		return nil
 	}
 
 	// Get request metadata for metrics
@@ -1050,14 +1124,16 @@ func (e *executor[R]) checkAndPunishMisbehavingUpstreams(
 	ctx, punishSpan := common.StartDetailSpan(ctx, "Consensus.CheckMisbehavior")
 	defer punishSpan.End()
 
-	// First, count results to find the most common one
+	// First, count weighted votes per result to find the most common one, the same way the winner is selected
 	resultCounts := make(map[string]int)
+	totalWeight := 0
 	for _, r := range responses {
+		totalWeight += e.voteWeight(r)
 		resultHash, err := e.resultOrErrorToHash(r.result, r.err, parentExecution)
 		if err != nil || resultHash == "" {
 			continue
 		}
-		resultCounts[resultHash]++
+		resultCounts[resultHash] += e.voteWeight(r)
 	}
 
 	// Find the most common result
@@ -1072,14 +1148,17 @@ func (e *executor[R]) checkAndPunishMisbehavingUpstreams(
 
 	punishSpan.SetAttributes(
 		attribute.Int("responses.count", len(responses)),
+		attribute.Int("responses.total_weight", totalWeight),
 		attribute.Int("most_common.count", mostCommonResultCount),
-		attribute.Bool("has_clear_majority", mostCommonResultCount > len(responses)/2),
+		attribute.Bool("has_clear_majority", mostCommonResultCount > totalWeight/2),
 	)
 
-	// Only track misbehaving upstreams if we have a clear majority (>50%)
-	if mostCommonResultCount > len(responses)/2 {
-		e.trackMisbehavingUpstreams(ctx, lg, responses, resultCounts, mostCommonResultHash, parentExecution, projectId, networkId, category, finalityStr)
+	// Only track misbehaving upstreams if we have a clear weighted majority (>50% of total vote weight)
+	if mostCommonResultCount > totalWeight/2 {
+		return e.trackMisbehavingUpstreams(ctx, lg, responses, resultCounts, mostCommonResultHash, parentExecution, projectId, networkId, category, finalityStr)
 	}
+
+	return nil
 }
 
 func (e *executor[R]) extractParticipants(responses []*execResult[R], exec failsafe.Execution[R]) []common.ParticipantInfo {
@@ -1116,6 +1195,75 @@ func (e *executor[R]) extractCauses(responses []*execResult[R]) []error {
 	return causes
 }
 
+// recordDispute hands the evidence of a dispute (each participant's hash and truncated body,
+// the winner and the punished upstreams) to the dispute recorder of the request, if any.
+func (e *executor[R]) recordDispute(
+	ctx context.Context,
+	req *common.NormalizedRequest,
+	responses []*execResult[R],
+	result *failsafeCommon.PolicyResult[R],
+	punished []string,
+	exec failsafe.Execution[R],
+) {
+	recorder := disputeRecorderFromContext(ctx)
+	if recorder == nil {
+		return
+	}
+	maxBodySize := recorder.MaxBodySize()
+
+	record := &DisputeRecord{
+		NetworkId:         req.NetworkId(),
+		Finality:          req.Finality(ctx).String(),
+		DisputeBehavior:   string(e.disputeBehavior),
+		PunishedUpstreams: punished,
+		Participants:      make([]*DisputeParticipant, 0, len(responses)),
+	}
+	if req.Network() != nil {
+		record.ProjectId = req.Network().ProjectId()
+	}
+	if m, err := req.Method(); err == nil {
+		record.Method = m
+	}
+	if body := req.Body(); len(body) > 0 {
+		record.Request, _ = truncateBody(body, maxBodySize)
+	} else if jrq, err := req.JsonRpcRequest(ctx); err == nil && jrq != nil {
+		if raw, err := common.SonicCfg.Marshal(jrq); err == nil {
+			record.Request, _ = truncateBody(raw, maxBodySize)
+		}
+	}
+
+	for _, r := range responses {
+		p := &DisputeParticipant{}
+		if r.upstream != nil && r.upstream.Config() != nil {
+			p.Upstream = r.upstream.Config().Id
+		}
+		if hash, err := e.resultOrErrorToHash(r.result, r.err, exec); err == nil {
+			p.ResultHash = hash
+		}
+		if r.err != nil {
+			p.Error = common.ErrorSummary(r.err)
+		} else if jr := e.resultToJsonRpcResponse(r.result, exec); jr != nil {
+			p.Body, p.Truncated = truncateBody(jr.Result, maxBodySize)
+		}
+		record.Participants = append(record.Participants, p)
+	}
+
+	if result != nil {
+		if result.Error != nil {
+			record.Error = common.ErrorSummary(result.Error)
+		} else if resp, ok := any(result.Result).(*common.NormalizedResponse); ok && resp != nil {
+			if ups := resp.Upstream(); ups != nil && ups.Config() != nil {
+				record.WinnerUpstream = ups.Config().Id
+			}
+			if hash, err := e.resultToHash(result.Result, exec); err == nil {
+				record.WinnerHash = hash
+			}
+		}
+	}
+
+	recorder.RecordDispute(ctx, record)
+}
+
 func (e *executor[R]) createRateLimiter(logger *zerolog.Logger, upstreamId string) ratelimiter.RateLimiter[any] {
 	// Try to get existing limiter
 	if limiter, ok := e.misbehavingUpstreamsLimiter.Load(upstreamId); ok {
@@ -1137,24 +1285,25 @@ func (e *executor[R]) createRateLimiter(logger *zerolog.Logger, upstreamId strin
 	return actual.(ratelimiter.RateLimiter[any])
 }
 
-func (e *executor[R]) trackMisbehavingUpstreams(ctx context.Context, logger *zerolog.Logger, responses []*execResult[R], resultCounts map[string]int, mostCommonResultHash string, execution policy.ExecutionInternal[R], projectId, networkId, category, finalityStr string) {
+func (e *executor[R]) trackMisbehavingUpstreams(ctx context.Context, logger *zerolog.Logger, responses []*execResult[R], resultCounts map[string]int, mostCommonResultHash string, execution policy.ExecutionInternal[R], projectId, networkId, category, finalityStr string) []string {
 	// Start tracking span
 	_, trackingSpan := common.StartDetailSpan(ctx, "Consensus.TrackMisbehavior")
 	defer trackingSpan.End()
 
-	// Count total valid responses
-	totalValidResponses := 0
+	// Total vote weight of valid responses (resultCounts holds weighted votes per hash)
+	totalValidWeight := 0
 	for _, count := range resultCounts {
-		totalValidResponses += count
+		totalValidWeight += count
 	}
 
-	// Only proceed with punishment if we have a clear majority (>50%)
+	// Only proceed with punishment if we have a clear weighted majority (>50%)
 	mostCommonCount := resultCounts[mostCommonResultHash]
-	if mostCommonCount <= totalValidResponses/2 {
+	if mostCommonCount <= totalValidWeight/2 {
 		trackingSpan.SetAttributes(attribute.Bool("punishment.skipped", true))
-		return
+		return nil
 	}
 
+	var punished []string
 	misbehavingCount := 0
 	for _, response := range responses {
 		upstream := response.upstream
@@ -1183,7 +1332,9 @@ func (e *executor[R]) trackMisbehavingUpstreams(ctx context.Context, logger *zer
 
 			limiter := e.createRateLimiter(logger, upstreamId)
 			if !limiter.TryAcquirePermit() {
-				e.handleMisbehavingUpstream(logger, upstream, upstreamId, projectId, networkId)
+				if e.handleMisbehavingUpstream(logger, upstream, upstreamId, projectId, networkId) {
+					punished = append(punished, upstreamId)
+				}
 			}
 		}
 	}
@@ -1192,9 +1343,12 @@ func (e *executor[R]) trackMisbehavingUpstreams(ctx context.Context, logger *zer
 		attribute.Int("misbehaving.count", misbehavingCount),
 		attribute.String("correct.hash", mostCommonResultHash),
 	)
+
+	return punished
 }
 
-func (e *executor[R]) handleMisbehavingUpstream(logger *zerolog.Logger, upstream common.Upstream, upstreamId, projectId, networkId string) {
+// handleMisbehavingUpstream puts the upstream in sitout, returns false if it was already sitting out
+func (e *executor[R]) handleMisbehavingUpstream(logger *zerolog.Logger, upstream common.Upstream, upstreamId, projectId, networkId string) bool {
 	// Create a placeholder value to claim ownership atomically
 	placeholder := &struct{}{}
 
@@ -1203,7 +1357,7 @@ func (e *executor[R]) handleMisbehavingUpstream(logger *zerolog.Logger, upstream
 		logger.Debug().
 			Str("upstream", upstreamId).
 			Msg("upstream already in sitout, skipping")
-		return
+		return false
 	}
 
 	logger.Warn().
@@ -1224,6 +1378,8 @@ func (e *executor[R]) handleMisbehavingUpstream(logger *zerolog.Logger, upstream
 
 	// Replace the placeholder with the actual timer
 	e.misbehavingUpstreamsSitoutTimer.Store(upstreamId, timer)
+
+	return true
 }
 
 func (e *executor[R]) handleReturnError(logger *zerolog.Logger, errFn func() error) *failsafeCommon.PolicyResult[R] {
@@ -0,0 +1,177 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWeightedTestExecutor(t *testing.T, weights map[string]int, profiles map[string]*common.ConsensusComparisonProfileConfig) *executor[*common.NormalizedResponse] {
+	t.Helper()
+	lg := log.Logger
+	p := NewConsensusPolicyBuilder[*common.NormalizedResponse]().
+		WithRequiredParticipants(3).
+		WithAgreementThreshold(2).
+		WithDisputeBehavior(common.ConsensusDisputeBehaviorReturnError).
+		WithLowParticipantsBehavior(common.ConsensusLowParticipantsBehaviorReturnError).
+		WithLogger(&lg).
+		WithUpstreamWeights(weights).
+		WithComparisonProfiles(profiles).
+		Build()
+	return &executor[*common.NormalizedResponse]{
+		consensusPolicy: p.(*consensusPolicy[*common.NormalizedResponse]),
+	}
+}
+
+func createObjectResponse(t *testing.T, result map[string]interface{}, upstream common.Upstream) *common.NormalizedResponse {
+	t.Helper()
+	jrr, err := common.NewJsonRpcResponse(1, result, nil)
+	require.NoError(t, err)
+	return common.NewNormalizedResponse().
+		WithJsonRpcResponse(jrr).
+		SetUpstream(upstream)
+}
+
+func TestConsensusWeightedVoting(t *testing.T) {
+	trusted := common.NewFakeUpstream("trusted")
+	public1 := common.NewFakeUpstream("public1")
+	public2 := common.NewFakeUpstream("public2")
+
+	responses := []*common.NormalizedResponse{
+		createResponse("result1", trusted),
+		createResponse("result2", public1),
+		createResponse("result3", public2),
+	}
+	mockExec := &mockExecution{responses: responses}
+	results := []*execResult[*common.NormalizedResponse]{
+		{result: responses[0], upstream: trusted, index: 0},
+		{result: responses[1], upstream: public1, index: 1},
+		{result: responses[2], upstream: public2, index: 2},
+	}
+
+	t.Run("EqualWeightsResultInDispute", func(t *testing.T) {
+		e := newWeightedTestExecutor(t, nil, nil)
+		lg := log.Logger
+		res, _ := e.evaluateConsensus(mockExec.Context(), &lg, results, mockExec)
+		require.Error(t, res.Error)
+		assert.True(t, common.HasErrorCode(res.Error, common.ErrCodeConsensusDispute))
+	})
+
+	t.Run("TrustedUpstreamWeightReachesThreshold", func(t *testing.T) {
+		e := newWeightedTestExecutor(t, map[string]int{"trusted": 2}, nil)
+		assert.True(t, e.hasConsensus(results, mockExec))
+
+		lg := log.Logger
+		res, _ := e.evaluateConsensus(mockExec.Context(), &lg, results, mockExec)
+		require.NoError(t, res.Error)
+		jrr, err := res.Result.JsonRpcResponse()
+		require.NoError(t, err)
+		assert.Equal(t, `"result1"`, string(jrr.Result))
+	})
+}
+
+func TestConsensusComparisonProfiles(t *testing.T) {
+	ups1 := common.NewFakeUpstream("upstream1")
+	ups2 := common.NewFakeUpstream("upstream2")
+	ups3 := common.NewFakeUpstream("upstream3")
+
+	block := func(stateRoot string, size string) map[string]interface{} {
+		return map[string]interface{}{
+			"hash":      "0xabc",
+			"stateRoot": stateRoot,
+			"size":      size,
+			"transactions": []interface{}{
+				map[string]interface{}{"hash": "0x1", "gasPrice": size},
+			},
+		}
+	}
+	responses := []*common.NormalizedResponse{
+		createObjectResponse(t, block("0x01", "0x10"), ups1),
+		createObjectResponse(t, block("0x01", "0x20"), ups2),
+		createObjectResponse(t, block("0x02", "0x30"), ups3),
+	}
+	mockExec := &mockExecution{responses: responses}
+	results := []*execResult[*common.NormalizedResponse]{
+		{result: responses[0], upstream: ups1, index: 0},
+		{result: responses[1], upstream: ups2, index: 1},
+		{result: responses[2], upstream: ups3, index: 2},
+	}
+
+	t.Run("WholeResponseComparisonDisputes", func(t *testing.T) {
+		e := newWeightedTestExecutor(t, nil, nil)
+		assert.False(t, e.hasConsensus(results, mockExec))
+	})
+
+	t.Run("IncludeFieldsOnlyComparesSelectedFields", func(t *testing.T) {
+		e := newWeightedTestExecutor(t, nil, map[string]*common.ConsensusComparisonProfileConfig{
+			"eth_*": {IncludeFields: []string{"hash", "stateRoot", "transactions.*.hash"}},
+		})
+		assert.True(t, e.hasConsensus(results, mockExec))
+
+		lg := log.Logger
+		res, _ := e.evaluateConsensus(mockExec.Context(), &lg, results, mockExec)
+		require.NoError(t, res.Error)
+	})
+
+	t.Run("IgnoreFieldsSkipsNoisyFields", func(t *testing.T) {
+		e := newWeightedTestExecutor(t, nil, map[string]*common.ConsensusComparisonProfileConfig{
+			"eth_test": {IgnoreFields: []string{"size", "transactions.*.gasPrice"}},
+		})
+		assert.True(t, e.hasConsensus(results, mockExec))
+	})
+
+	t.Run("ProfileOfAnotherMethodIsNotApplied", func(t *testing.T) {
+		e := newWeightedTestExecutor(t, nil, map[string]*common.ConsensusComparisonProfileConfig{
+			"eth_getBlockByNumber": {IncludeFields: []string{"hash"}},
+		})
+		assert.False(t, e.hasConsensus(results, mockExec))
+	})
+}
+
+func TestConsensusWeightedMisbehaviorTracking(t *testing.T) {
+	trusted := common.NewFakeUpstream("trusted")
+	public1 := common.NewFakeUpstream("public1")
+	public2 := common.NewFakeUpstream("public2")
+
+	responses := []*common.NormalizedResponse{
+		createResponse("result1", trusted),
+		createResponse("result2", public1),
+		createResponse("result2", public2),
+	}
+	mockExec := &mockExecution{responses: responses}
+	results := []*execResult[*common.NormalizedResponse]{
+		{result: responses[0], upstream: trusted, index: 0},
+		{result: responses[1], upstream: public1, index: 1},
+		{result: responses[2], upstream: public2, index: 2},
+	}
+
+	lg := log.Logger
+	p := NewConsensusPolicyBuilder[*common.NormalizedResponse]().
+		WithRequiredParticipants(3).
+		WithAgreementThreshold(2).
+		WithLogger(&lg).
+		WithUpstreamWeights(map[string]int{"trusted": 3}).
+		WithPunishMisbehavior(&common.PunishMisbehaviorConfig{
+			DisputeThreshold: 1,
+			DisputeWindow:    common.Duration(10 * time.Second),
+			SitOutPenalty:    common.Duration(time.Minute),
+		}).
+		Build()
+	e := &executor[*common.NormalizedResponse]{
+		consensusPolicy: p.(*consensusPolicy[*common.NormalizedResponse]),
+	}
+
+	// The first dispute consumes the permit of the rate limiter, the second one punishes
+	var punished []string
+	for i := 0; i < 2; i++ {
+		punished = append(punished, e.checkAndPunishMisbehavingUpstreams(mockExec.Context(), &lg, results, mockExec)...)
+	}
+
+	// The trusted upstream wins the weighted vote (3 of 5) so the public upstreams misbehaved, not the trusted one
+	assert.ElementsMatch(t, []string{"public1", "public2"}, punished)
+	assert.NotContains(t, punished, "trusted")
+}
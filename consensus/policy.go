@@ -30,6 +30,8 @@ type ConsensusPolicyBuilder[R any] interface {
 	OnDispute(listener func(failsafe.ExecutionEvent[R])) ConsensusPolicyBuilder[R]
 	OnLowParticipants(listener func(failsafe.ExecutionEvent[R])) ConsensusPolicyBuilder[R]
 	WithDisputeLogLevel(level zerolog.Level) ConsensusPolicyBuilder[R]
+	WithUpstreamWeights(weights map[string]int) ConsensusPolicyBuilder[R]
+	WithComparisonProfiles(profiles map[string]*common.ConsensusComparisonProfileConfig) ConsensusPolicyBuilder[R]
 
 	// Build returns a new ConsensusPolicy using the builder's configuration.
 	Build() ConsensusPolicy[R]
@@ -46,6 +48,8 @@ type config[R any] struct {
 	timeout                 time.Duration
 	logger                  *zerolog.Logger
 	disputeLogLevel         zerolog.Level
+	upstreamWeights         map[string]int
+	comparisonProfiles      map[string]*common.ConsensusComparisonProfileConfig
 
 	onAgreement       func(event failsafe.ExecutionEvent[R])
 	onDispute         func(event failsafe.ExecutionEvent[R])
@@ -120,6 +124,16 @@ func (c *config[R]) WithDisputeLogLevel(level zerolog.Level) ConsensusPolicyBuil
 	return c
 }
 
+func (c *config[R]) WithUpstreamWeights(weights map[string]int) ConsensusPolicyBuilder[R] {
+	c.upstreamWeights = weights
+	return c
+}
+
+func (c *config[R]) WithComparisonProfiles(profiles map[string]*common.ConsensusComparisonProfileConfig) ConsensusPolicyBuilder[R] {
+	c.comparisonProfiles = profiles
+	return c
+}
+
 func (c *config[R]) Build() ConsensusPolicy[R] {
 	hCopy := *c
 	if !c.BaseAbortablePolicy.IsConfigured() {
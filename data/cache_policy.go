@@ -151,16 +151,30 @@ func (p *CachePolicy) MatchesSizeLimits(size int) bool {
 	return true
 }
 
+// MaxSize returns the maximum item size allowed by this policy, or 0 when there is no limit.
+func (p *CachePolicy) MaxSize() int {
+	if p.maxSize == nil {
+		return 0
+	}
+	return *p.maxSize
+}
+
 func (p *CachePolicy) EmptyState() common.CacheEmptyBehavior {
 	return p.config.Empty
 }
 
 func (p *CachePolicy) matchParams(params []interface{}) (bool, error) {
-	if len(p.config.Params) == 0 {
+	return MatchParams(p.config.Params, params)
+}
+
+// MatchParams matches request params positionally against patterns, where strings are wildcard
+// patterns, objects match the listed fields and arrays match element by element.
+func MatchParams(patterns []interface{}, params []interface{}) (bool, error) {
+	if len(patterns) == 0 {
 		return true, nil
 	}
 
-	for i, pattern := range p.config.Params {
+	for i, pattern := range patterns {
 		var v interface{}
 		if i < len(params) {
 			v = params[i]
@@ -16,6 +16,8 @@ const (
 
 type DistributedLock interface {
 	Unlock(ctx context.Context) error
+	// Extend renews the lock for another ttl, failing if the lock is no longer held by this instance.
+	Extend(ctx context.Context, ttl time.Duration) error
 	IsNil() bool
 }
 
@@ -28,6 +30,10 @@ type Connector interface {
 	Lock(ctx context.Context, key string, ttl time.Duration) (DistributedLock, error)
 	WatchCounterInt64(ctx context.Context, key string) (<-chan int64, func(), error)
 	PublishCounterInt64(ctx context.Context, key string, value int64) error
+	// WatchValue emits the raw stored bytes of a shared value (at range key "value") whenever it changes.
+	WatchValue(ctx context.Context, key string) (<-chan []byte, func(), error)
+	// PublishValue notifies watchers of a value that has already been persisted via Set.
+	PublishValue(ctx context.Context, key string, value []byte) error
 }
 
 func NewConnector(
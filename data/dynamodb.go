@@ -1,11 +1,13 @@
 package data
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -51,6 +53,8 @@ var _ DistributedLock = &dynamoLock{}
 type dynamoLock struct {
 	connector *DynamoDBConnector
 	lockKey   string
+	// Expiry last written by this instance, used as the ownership check when extending
+	expiry int64
 }
 
 func (l *dynamoLock) IsNil() bool {
@@ -628,6 +632,7 @@ func (d *DynamoDBConnector) Lock(ctx context.Context, key string, ttl time.Durat
 			return &dynamoLock{
 				connector: d,
 				lockKey:   lockKey,
+				expiry:    lockItemExpiryTime,
 			}, nil
 		}
 
@@ -698,6 +703,43 @@ func (l *dynamoLock) Unlock(ctx context.Context) error {
 	return err
 }
 
+// Extend pushes the lock expiry forward, only if the item still carries the expiry this instance wrote
+// (i.e. the lock has not expired and been taken over by another instance in the meantime).
+func (l *dynamoLock) Extend(ctx context.Context, ttl time.Duration) error {
+	ctx, span := common.StartSpan(ctx, "DynamoDBConnector.ExtendLock",
+		trace.WithAttributes(
+			attribute.String("lock_key", l.lockKey),
+		),
+	)
+	defer span.End()
+
+	newExpiry := time.Now().Add(ttl).Unix()
+	_, err := l.connector.writeClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(l.connector.table),
+		Key: map[string]*dynamodb.AttributeValue{
+			l.connector.partitionKeyName: {S: aws.String(l.lockKey)},
+			l.connector.rangeKeyName:     {S: aws.String("lock")},
+		},
+		UpdateExpression:    aws.String("SET #expiry = :newExpiry"),
+		ConditionExpression: aws.String("#expiry = :expiry"),
+		ExpressionAttributeNames: map[string]*string{
+			"#expiry": aws.String("expiry"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":expiry":    {N: aws.String(fmt.Sprintf("%d", l.expiry))},
+			":newExpiry": {N: aws.String(fmt.Sprintf("%d", newExpiry))},
+		},
+	})
+	if err != nil {
+		common.SetTraceSpanError(span, err)
+		return fmt.Errorf("failed to extend distributed lock: %w", err)
+	}
+
+	l.expiry = newExpiry
+	l.connector.logger.Trace().Str("lockKey", l.lockKey).Msg("distributed lock extended")
+	return nil
+}
+
 func (d *DynamoDBConnector) WatchCounterInt64(ctx context.Context, key string) (<-chan int64, func(), error) {
 	if d.readClient == nil {
 		return nil, nil, fmt.Errorf("DynamoDB client not initialized yet")
@@ -748,6 +790,66 @@ func (d *DynamoDBConnector) WatchCounterInt64(ctx context.Context, key string) (
 	return updates, cleanup, nil
 }
 
+// WatchValue polls the shared value every statePollInterval since DynamoDB has no native pub/sub,
+// emitting only when the stored bytes change.
+func (d *DynamoDBConnector) WatchValue(ctx context.Context, key string) (<-chan []byte, func(), error) {
+	if d.readClient == nil {
+		return nil, nil, fmt.Errorf("DynamoDB client not initialized yet")
+	}
+
+	updates := make(chan []byte, 1)
+	ticker := time.NewTicker(d.statePollInterval)
+	done := make(chan struct{})
+
+	// Get initial value
+	var lastValue []byte
+	if val, err := d.Get(ctx, ConnectorMainIndex, key, "value"); err == nil {
+		lastValue = val
+		updates <- val
+	}
+
+	go func() {
+		defer close(updates)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				value, err := d.Get(ctx, ConnectorMainIndex, key, "value")
+				if err != nil {
+					if !common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+						d.logger.Warn().Err(err).Str("key", key).Msg("failed to poll shared value")
+					}
+					continue
+				}
+				if !bytes.Equal(value, lastValue) {
+					lastValue = value
+					select {
+					case updates <- value:
+					default:
+					}
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	cleanup := func() {
+		once.Do(func() { close(done) })
+	}
+
+	return updates, cleanup, nil
+}
+
+// PublishValue is a no-op for DynamoDB because the value is already persisted via Set
+// and watchers pick it up on their next poll.
+func (d *DynamoDBConnector) PublishValue(ctx context.Context, key string, value []byte) error {
+	return nil
+}
+
 func (d *DynamoDBConnector) getSimpleValue(ctx context.Context, key string) (int64, error) {
 	ctx, span := common.StartDetailSpan(ctx, "DynamoDBConnector.getSimpleValue",
 		trace.WithAttributes(
@@ -0,0 +1,170 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
+)
+
+// LeaderElection tells whether this instance is the one that should run a singleton background job.
+// Followers are expected to consume the leader's results through shared state instead of running the job.
+type LeaderElection interface {
+	IsLeader() bool
+	OnLeadershipChange(callback func(isLeader bool))
+	// Release tells the election this holder no longer needs it (e.g. its upstream is shut down). Once every
+	// holder of the same key released it, the instance stops competing and gives up the lease if it holds it.
+	Release()
+}
+
+// alwaysLeader is used when leader election is disabled so every instance keeps running its own jobs.
+type alwaysLeader struct{}
+
+func (alwaysLeader) IsLeader() bool                { return true }
+func (alwaysLeader) OnLeadershipChange(func(bool)) {}
+func (alwaysLeader) Release()                      {}
+
+// leaderElection holds a lease on top of Connector.Lock and keeps renewing it while leading.
+// If renewal fails (lost connection, expired lease) it steps down and competes again on the next round.
+type leaderElection struct {
+	registry      *sharedStateRegistry
+	key           string
+	leaseTtl      time.Duration
+	renewInterval time.Duration
+	leader        atomic.Bool
+	lock          DistributedLock
+	callbacks     []func(bool)
+	callbackMu    sync.RWMutex
+	refs          int // guarded by registry.electionsMu
+	stop          chan struct{}
+}
+
+func (l *leaderElection) IsLeader() bool {
+	return l.leader.Load()
+}
+
+func (l *leaderElection) OnLeadershipChange(cb func(bool)) {
+	l.callbackMu.Lock()
+	defer l.callbackMu.Unlock()
+	l.callbacks = append(l.callbacks, cb)
+}
+
+func (l *leaderElection) Release() {
+	l.registry.releaseLeaderElection(l)
+}
+
+// run keeps competing until the app or the election is stopped, restarting after an unexpected panic
+// so the instance does not silently drop out of the election.
+func (l *leaderElection) run() {
+	for !l.compete() {
+		select {
+		case <-l.registry.appCtx.Done():
+			return
+		case <-l.stop:
+			return
+		case <-time.After(l.renewInterval):
+		}
+	}
+}
+
+// compete runs election rounds and returns true once stopped, or false if a round panicked.
+func (l *leaderElection) compete() (stopped bool) {
+	defer func() {
+		if rc := recover(); rc != nil {
+			telemetry.MetricUnexpectedPanicTotal.WithLabelValues(
+				"shared-state-leader-election",
+				fmt.Sprintf("connector:%s cluster:%s", l.registry.connector.Id(), l.registry.clusterKey),
+				common.ErrorFingerprint(rc),
+			).Inc()
+			l.registry.logger.Error().
+				Interface("panic", rc).
+				Str("stack", string(debug.Stack())).
+				Str("key", l.key).
+				Msg("unexpected panic in leader election, will compete again")
+			// The lease (if any) is left to expire, leadership is acquired again through a fresh lock
+			l.lock = nil
+			l.setLeader(false)
+			stopped = false
+		}
+	}()
+
+	ticker := time.NewTicker(l.renewInterval)
+	defer ticker.Stop()
+	for {
+		l.tick()
+		select {
+		case <-l.registry.appCtx.Done():
+			l.resign()
+			return true
+		case <-l.stop:
+			l.resign()
+			telemetry.MetricSharedStateLeader.DeleteLabelValues(l.key)
+			return true
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *leaderElection) tick() {
+	ctx, cancel := context.WithTimeout(l.registry.appCtx, l.renewInterval)
+	defer cancel()
+
+	if l.lock != nil {
+		if err := l.lock.Extend(ctx, l.leaseTtl); err != nil {
+			l.registry.logger.Warn().Err(err).Str("key", l.key).Msg("failed to renew leadership lease, stepping down")
+			l.lock = nil
+			l.setLeader(false)
+		}
+		return
+	}
+
+	lock, err := l.registry.connector.Lock(ctx, l.key, l.leaseTtl)
+	if err != nil || lock == nil || lock.IsNil() {
+		l.registry.logger.Trace().Err(err).Str("key", l.key).Msg("leadership is held by another instance")
+		return
+	}
+	l.lock = lock
+	l.setLeader(true)
+}
+
+// resign releases the lease on shutdown so another instance can take over without waiting for expiry.
+func (l *leaderElection) resign() {
+	if l.lock == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), l.renewInterval)
+	defer cancel()
+	if err := l.lock.Unlock(ctx); err != nil {
+		l.registry.logger.Warn().Err(err).Str("key", l.key).Msg("failed to release leadership lease, it will expire after ttl")
+	}
+	l.lock = nil
+	l.setLeader(false)
+}
+
+func (l *leaderElection) setLeader(isLeader bool) {
+	if l.leader.Swap(isLeader) == isLeader {
+		return
+	}
+	if isLeader {
+		l.registry.logger.Info().Str("key", l.key).Msg("acquired leadership")
+		telemetry.MetricSharedStateLeader.WithLabelValues(l.key).Set(1)
+	} else {
+		l.registry.logger.Info().Str("key", l.key).Msg("lost leadership")
+		telemetry.MetricSharedStateLeader.WithLabelValues(l.key).Set(0)
+	}
+
+	l.callbackMu.RLock()
+	callbacks := make([]func(bool), len(l.callbacks))
+	copy(callbacks, l.callbacks)
+	l.callbackMu.RUnlock()
+	for _, cb := range callbacks {
+		if cb != nil {
+			cb(isLeader)
+		}
+	}
+}
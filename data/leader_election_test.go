@@ -0,0 +1,128 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newLeaderElectionTestRegistry(ctx context.Context, connector Connector) *sharedStateRegistry {
+	return &sharedStateRegistry{
+		appCtx:          ctx,
+		clusterKey:      "test",
+		logger:          &log.Logger,
+		connector:       connector,
+		fallbackTimeout: time.Second,
+		lockTtl:         time.Second,
+		leaderElection: &common.LeaderElectionConfig{
+			Enabled:       util.BoolPtr(true),
+			LeaseTtl:      common.Duration(300 * time.Millisecond),
+			RenewInterval: common.Duration(50 * time.Millisecond),
+		},
+		initializer: util.NewInitializer(ctx, &log.Logger, nil),
+	}
+}
+
+func TestLeaderElection(t *testing.T) {
+	t.Run("every instance leads when disabled", func(t *testing.T) {
+		registry, _, _ := setupTest("test")
+		election := registry.GetLeaderElection("job")
+		assert.True(t, election.IsLeader())
+	})
+
+	t.Run("only one instance leads and another takes over after it resigns", func(t *testing.T) {
+		connector, err := NewMemoryConnector(context.Background(), &log.Logger, "test", &common.MemoryConnectorConfig{MaxItems: 1000, MaxTotalSize: "10MB"})
+		require.NoError(t, err)
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		defer cancel1()
+		first := newLeaderElectionTestRegistry(ctx1, connector).GetLeaderElection("job")
+		require.Eventually(t, first.IsLeader, time.Second, 10*time.Millisecond)
+
+		ctx2, cancel2 := context.WithCancel(context.Background())
+		defer cancel2()
+		second := newLeaderElectionTestRegistry(ctx2, connector).GetLeaderElection("job")
+		time.Sleep(200 * time.Millisecond)
+		assert.False(t, second.IsLeader())
+
+		cancel1()
+		require.Eventually(t, second.IsLeader, time.Second, 10*time.Millisecond)
+		assert.False(t, first.IsLeader())
+	})
+
+	t.Run("steps down when the lease cannot be renewed", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		lock := &MockLock{}
+		lock.On("Extend", mock.Anything, mock.Anything).Return(errors.New("lease lost"))
+		connector := NewMockConnector("test")
+		connector.On("Lock", mock.Anything, "test/leader/job", 300*time.Millisecond).Return(lock, nil).Once()
+		connector.On("Lock", mock.Anything, "test/leader/job", 300*time.Millisecond).Return(nil, errors.New("already locked"))
+
+		var lost atomic.Bool
+		election := newLeaderElectionTestRegistry(ctx, connector).GetLeaderElection("job")
+		election.OnLeadershipChange(func(isLeader bool) {
+			if !isLeader {
+				lost.Store(true)
+			}
+		})
+
+		require.Eventually(t, lost.Load, time.Second, 10*time.Millisecond)
+		assert.False(t, election.IsLeader())
+		lock.AssertCalled(t, "Extend", mock.Anything, 300*time.Millisecond)
+	})
+
+	t.Run("competes again after a panic", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		lock := &MockLock{}
+		lock.On("Extend", mock.Anything, mock.Anything).Return(nil)
+		lock.On("Unlock", mock.Anything).Return(nil)
+		connector := NewMockConnector("test")
+		connector.On("Lock", mock.Anything, "test/leader/job", 300*time.Millisecond).Run(func(mock.Arguments) {
+			panic("unexpected")
+		}).Once()
+		connector.On("Lock", mock.Anything, "test/leader/job", 300*time.Millisecond).Return(lock, nil)
+
+		election := newLeaderElectionTestRegistry(ctx, connector).GetLeaderElection("job")
+		require.Eventually(t, election.IsLeader, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("stops competing and gives up the lease once every holder released it", func(t *testing.T) {
+		connector, err := NewMemoryConnector(context.Background(), &log.Logger, "test", &common.MemoryConnectorConfig{MaxItems: 1000, MaxTotalSize: "10MB"})
+		require.NoError(t, err)
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		defer cancel1()
+		registry := newLeaderElectionTestRegistry(ctx1, connector)
+		first := registry.GetLeaderElection("job")
+		again := registry.GetLeaderElection("job")
+		require.Eventually(t, first.IsLeader, time.Second, 10*time.Millisecond)
+
+		ctx2, cancel2 := context.WithCancel(context.Background())
+		defer cancel2()
+		other := newLeaderElectionTestRegistry(ctx2, connector).GetLeaderElection("job")
+
+		first.Release()
+		time.Sleep(200 * time.Millisecond)
+		assert.True(t, again.IsLeader(), "election is kept while another holder still uses it")
+		assert.False(t, other.IsLeader())
+
+		again.Release()
+		require.Eventually(t, other.IsLeader, time.Second, 10*time.Millisecond)
+		assert.False(t, first.IsLeader())
+		_, stillTracked := registry.elections.Load("test/leader/job")
+		assert.False(t, stillTracked)
+	})
+}
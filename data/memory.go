@@ -29,6 +29,9 @@ type MemoryConnector struct {
 	locks       sync.Map // map[string]*sync.Mutex
 	emitMetrics bool
 
+	valueWatchers   map[string][]chan []byte
+	valueWatchersMu sync.Mutex
+
 	// Previous metric values for calculating deltas
 	prevMetrics struct {
 		setsDropped  uint64
@@ -179,6 +182,11 @@ func (l *memoryLock) Unlock(ctx context.Context) error {
 	return nil
 }
 
+// Extend is a no-op because in-memory locks never expire.
+func (l *memoryLock) Extend(ctx context.Context, ttl time.Duration) error {
+	return nil
+}
+
 // WatchCounterInt64 is a no-op for memory connector since distributed pub/sub
 // is unnecessary when all operations are in-memory within the same process.
 // Any updates to counters are immediately visible to all code accessing the
@@ -194,6 +202,62 @@ func (m *MemoryConnector) PublishCounterInt64(ctx context.Context, key string, v
 	return nil
 }
 
+// WatchValue subscribes to values published on this connector instance. Unlike counters, shared values
+// are fanned out in-process so that multiple registries sharing one memory connector stay consistent.
+func (m *MemoryConnector) WatchValue(ctx context.Context, key string) (<-chan []byte, func(), error) {
+	updates := make(chan []byte, 1)
+
+	m.valueWatchersMu.Lock()
+	if m.valueWatchers == nil {
+		m.valueWatchers = make(map[string][]chan []byte)
+	}
+	m.valueWatchers[key] = append(m.valueWatchers[key], updates)
+	m.valueWatchersMu.Unlock()
+
+	var once sync.Once
+	cleanup := func() {
+		once.Do(func() {
+			m.valueWatchersMu.Lock()
+			defer m.valueWatchersMu.Unlock()
+			watchers := m.valueWatchers[key]
+			for i, ch := range watchers {
+				if ch == updates {
+					m.valueWatchers[key] = append(watchers[:i], watchers[i+1:]...)
+					break
+				}
+			}
+			if len(m.valueWatchers[key]) == 0 {
+				delete(m.valueWatchers, key)
+			}
+			close(updates)
+		})
+	}
+
+	return updates, cleanup, nil
+}
+
+// PublishValue delivers the value to in-process watchers of the key. A watcher that has not consumed
+// the previous update gets it replaced by this one, so slow watchers always end up with the latest value.
+func (m *MemoryConnector) PublishValue(ctx context.Context, key string, value []byte) error {
+	m.valueWatchersMu.Lock()
+	defer m.valueWatchersMu.Unlock()
+	for _, ch := range m.valueWatchers[key] {
+		select {
+		case ch <- value:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- value:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
 // metricsCollectionLoop runs in a background goroutine to periodically collect
 // and emit Ristretto cache metrics to Prometheus.
 func (m *MemoryConnector) metricsCollectionLoop(ctx context.Context) {
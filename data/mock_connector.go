@@ -64,6 +64,22 @@ func (m *MockConnector) PublishCounterInt64(ctx context.Context, key string, val
 	return args.Error(0)
 }
 
+// WatchValue mocks the WatchValue method of the Connector interface
+func (m *MockConnector) WatchValue(ctx context.Context, key string) (<-chan []byte, func(), error) {
+	args := m.Called(ctx, key)
+	var a0 <-chan []byte = nil
+	a0, _ = args.Get(0).(chan []byte)
+	a1, _ := args.Get(1).(func())
+	a2 := args.Error(2)
+	return a0, a1, a2
+}
+
+// PublishValue mocks the PublishValue method of the Connector interface
+func (m *MockConnector) PublishValue(ctx context.Context, key string, value []byte) error {
+	args := m.Called(ctx, key, value)
+	return args.Error(0)
+}
+
 // NewMockConnector creates a new instance of MockConnector
 func NewMockConnector(id string) *MockConnector {
 	return &MockConnector{id: id}
@@ -167,20 +167,17 @@ func TestPostgreSQLDistributedLocking(t *testing.T) {
 		defer cleanup()
 
 		lockKey := "pg-lock-immediate"
-		// TTL is not strictly used by pg_advisory_xact_lock, but pass a value for API compliance
 		lock, err := connector.Lock(ctx, lockKey, 5*time.Second)
 		require.NoError(t, err, "should acquire lock without issues")
 		require.NotNil(t, lock, "lock should not be nil")
 		require.False(t, lock.IsNil(), "lock.IsNil should be false")
 
-		// Check if underlying transaction is present
 		pgLock, ok := lock.(*postgresLock)
 		require.True(t, ok, "lock should be of type *postgresLock")
-		require.NotNil(t, pgLock.tx, "transaction should be present in acquired lock")
+		require.NotEmpty(t, pgLock.token, "lease token should be set")
 
-		err = lock.Unlock(ctx) // This commits the transaction
+		err = lock.Unlock(ctx)
 		require.NoError(t, err, "unlock should succeed")
-		require.Nil(t, pgLock.tx, "transaction should be nil after unlock")
 	})
 
 	t.Run("LockFailsIfAlreadyHeld", func(t *testing.T) {
@@ -224,58 +221,45 @@ func TestPostgreSQLDistributedLocking(t *testing.T) {
 		require.NoError(t, err, "lock2: unlock should succeed")
 	})
 
-	t.Run("LockIsReleasedOnCommit", func(t *testing.T) {
+	t.Run("ExpiredLeaseCanBeTakenOver", func(t *testing.T) {
 		ctx, connector, cleanup := setupConnector(t)
 		defer cleanup()
-		lockKey := "pg-lock-commit-release"
+		lockKey := "pg-lock-expired-takeover"
 
-		// Acquire lock (this starts a transaction)
-		lock1, err := connector.Lock(ctx, lockKey, 5*time.Second)
+		lock1, err := connector.Lock(ctx, lockKey, 200*time.Millisecond)
 		require.NoError(t, err)
-		pgLock1, ok := lock1.(*postgresLock)
-		require.True(t, ok)
-
-		// Try to acquire again (should fail as lock1's transaction holds it)
-		_, err = connector.Lock(ctx, lockKey, 1*time.Second)
-		require.Error(t, err, "second lock attempt should fail while first is active")
 
-		// Commit the transaction for lock1
-		err = pgLock1.Unlock(ctx) // Unlock is a commit
-		require.NoError(t, err)
+		_, err = connector.Lock(ctx, lockKey, 5*time.Second)
+		require.Error(t, err, "second lock attempt should fail while the lease is active")
 
-		// Now, acquiring the lock should succeed
+		time.Sleep(300 * time.Millisecond)
 		lock2, err := connector.Lock(ctx, lockKey, 5*time.Second)
-		require.NoError(t, err, "should acquire lock after first transaction committed")
-		require.NotNil(t, lock2)
-		err = lock2.Unlock(ctx)
-		require.NoError(t, err)
+		require.NoError(t, err, "should take over the expired lease")
+
+		// The previous owner can neither renew nor release the new owner's lease
+		require.Error(t, lock1.Extend(ctx, 5*time.Second))
+		require.NoError(t, lock1.Unlock(ctx))
+		_, err = connector.Lock(ctx, lockKey, 5*time.Second)
+		require.Error(t, err, "lease of the new owner must still be held")
+
+		require.NoError(t, lock2.Unlock(ctx))
 	})
 
-	t.Run("LockIsReleasedOnRollback", func(t *testing.T) {
+	t.Run("ExtendKeepsLeaseAlive", func(t *testing.T) {
 		ctx, connector, cleanup := setupConnector(t)
 		defer cleanup()
-		lockKey := "pg-lock-rollback-release"
+		lockKey := "pg-lock-extend"
 
-		// Acquire lock (starts a transaction)
-		lock1, err := connector.Lock(ctx, lockKey, 5*time.Second)
+		lock1, err := connector.Lock(ctx, lockKey, 300*time.Millisecond)
 		require.NoError(t, err)
-		pgLock1, ok := lock1.(*postgresLock)
-		require.True(t, ok)
-
-		// Manually rollback the transaction instead of calling Unlock()
-		// Note: This is for testing the rollback scenario. Normally, Unlock() handles commit.
-		if pgLock1.tx != nil {
-			err = pgLock1.tx.Rollback(ctx)
-			require.NoError(t, err, "manual rollback should succeed")
-			pgLock1.tx = nil // Simulate that unlock would do this too on rollback path (though current Unlock always commits)
+		for i := 0; i < 3; i++ {
+			time.Sleep(150 * time.Millisecond)
+			require.NoError(t, lock1.Extend(ctx, 300*time.Millisecond))
 		}
 
-		// Now, acquiring the lock should succeed
-		lock2, err := connector.Lock(ctx, lockKey, 5*time.Second)
-		require.NoError(t, err, "should acquire lock after first transaction rolled back")
-		require.NotNil(t, lock2)
-		err = lock2.Unlock(ctx)
-		require.NoError(t, err)
+		_, err = connector.Lock(ctx, lockKey, 5*time.Second)
+		require.Error(t, err, "extended lease must still be held")
+		require.NoError(t, lock1.Unlock(ctx))
 	})
 
 }
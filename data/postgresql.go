@@ -2,8 +2,10 @@ package data
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"hash/fnv"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,20 +27,21 @@ const (
 var _ Connector = (*PostgreSQLConnector)(nil)
 
 type PostgreSQLConnector struct {
-	id            string
-	logger        *zerolog.Logger
-	conn          *pgxpool.Pool
-	connMu        sync.RWMutex
-	initializer   *util.Initializer
-	minConns      int32
-	maxConns      int32
-	table         string
-	cleanupTicker *time.Ticker
-	initTimeout   time.Duration
-	getTimeout    time.Duration
-	setTimeout    time.Duration
-	listeners     sync.Map      // map[string]*pgxListener
-	listenerPool  *pgxpool.Pool // Separate pool for LISTEN connections
+	id             string
+	logger         *zerolog.Logger
+	conn           *pgxpool.Pool
+	connMu         sync.RWMutex
+	initializer    *util.Initializer
+	minConns       int32
+	maxConns       int32
+	table          string
+	cleanupTicker  *time.Ticker
+	initTimeout    time.Duration
+	getTimeout     time.Duration
+	setTimeout     time.Duration
+	listeners      sync.Map      // map[string]*pgxListener
+	valueListeners sync.Map      // map[string]*pgxValueListener
+	listenerPool   *pgxpool.Pool // Separate pool for LISTEN connections
 }
 
 type pgxListener struct {
@@ -47,17 +50,23 @@ type pgxListener struct {
 	watchers []chan int64
 }
 
+// pgxValueListener fans out shared value changes. Notifications carry no payload (NOTIFY is limited
+// to 8000 bytes) so the listener re-reads the stored value before broadcasting it.
+type pgxValueListener struct {
+	mu       sync.Mutex
+	watchers []chan []byte
+}
+
 var _ DistributedLock = &postgresLock{}
 
 type postgresLock struct {
-	conn   *pgxpool.Pool
-	lockID int64
-	logger *zerolog.Logger
-	tx     pgx.Tx
+	connector *PostgreSQLConnector
+	key       string
+	token     string
 }
 
 func (l *postgresLock) IsNil() bool {
-	return l == nil || l.conn == nil
+	return l == nil || l.connector == nil
 }
 
 func NewPostgreSQLConnector(
@@ -377,6 +386,8 @@ func (p *PostgreSQLConnector) Get(ctx context.Context, index, partitionKey, rang
 	return value, err
 }
 
+// Lock takes a lease row (range key "lock") that expires after ttl, so no connection or transaction is held
+// while the lock is owned. The row carries a random token so only the owner can extend or release it.
 func (p *PostgreSQLConnector) Lock(ctx context.Context, key string, ttl time.Duration) (DistributedLock, error) {
 	ctx, span := common.StartSpan(ctx, "PostgreSQLConnector.Lock")
 	defer span.End()
@@ -397,82 +408,107 @@ func (p *PostgreSQLConnector) Lock(ctx context.Context, key string, ttl time.Dur
 		return nil, err
 	}
 
-	// Generate consistent hash for the key as advisory lock ID
-	h := fnv.New64a()
-	_, err := h.Write([]byte(key))
-	if err != nil {
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
 		common.SetTraceSpanError(span, err)
-		return nil, fmt.Errorf("failed to generate advisory lock ID: %w", err)
+		return nil, fmt.Errorf("failed to generate lock token: %w", err)
 	}
-	lockID := int64(h.Sum64()) // #nosec
+	token := hex.EncodeToString(tokenBytes)
 
-	// Start a transaction
-	tx, err := p.conn.Begin(ctx)
-	if err != nil {
-		p.handleConnectionFailure(err)
+	ctx, cancel := context.WithTimeout(ctx, p.setTimeout)
+	defer cancel()
+
+	// Insert the lease, or take it over only if the current one has expired
+	var owner []byte
+	err := p.conn.QueryRow(ctx, fmt.Sprintf(`
+		INSERT INTO %[1]s (partition_key, range_key, value, expires_at)
+		VALUES ($1, 'lock', $2, NOW() + $3 * INTERVAL '1 millisecond')
+		ON CONFLICT (partition_key, range_key) DO UPDATE
+		SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+		WHERE %[1]s.expires_at IS NULL OR %[1]s.expires_at <= NOW()
+		RETURNING value
+	`, p.table), key, []byte(token), ttl.Milliseconds()).Scan(&owner)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		err := fmt.Errorf("failed to acquire lock: already locked")
 		common.SetTraceSpanError(span, err)
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, err
 	}
-
-	// Try to acquire transaction-level advisory lock
-	var acquired bool
-	err = tx.QueryRow(ctx, `
-        SELECT pg_try_advisory_xact_lock($1)
-    `, lockID).Scan(&acquired)
-
 	if err != nil {
 		p.handleConnectionFailure(err)
-		go tx.Rollback(context.Background())
 		common.SetTraceSpanError(span, err)
-		return nil, fmt.Errorf("failed to acquire advisory lock: %w", err)
-	}
-
-	if !acquired {
-		go tx.Rollback(context.Background())
-		err := fmt.Errorf("failed to acquire lock: already locked")
-		common.SetTraceSpanError(span, err)
-		return nil, err
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
 	}
 
-	p.logger.Trace().Str("key", key).Int64("lockID", lockID).Msg("distributed lock acquired")
+	p.logger.Trace().Str("key", key).Msg("distributed lock acquired")
 
 	return &postgresLock{
-		conn:   p.conn,
-		lockID: lockID,
-		logger: p.logger,
-		tx:     tx,
+		connector: p,
+		key:       key,
+		token:     token,
 	}, nil
 }
 
 func (l *postgresLock) Unlock(ctx context.Context) error {
 	ctx, span := common.StartSpan(ctx, "PostgreSQLConnector.Unlock",
 		trace.WithAttributes(
-			attribute.Int64("lockID", l.lockID),
+			attribute.String("lock_key", l.key),
 		),
 	)
 	defer span.End()
 
-	if l.tx == nil {
-		err := fmt.Errorf("no active transaction")
+	l.connector.connMu.RLock()
+	defer l.connector.connMu.RUnlock()
+	if l.connector.conn == nil {
+		err := fmt.Errorf("PostgreSQLConnector not connected yet")
 		common.SetTraceSpanError(span, err)
 		return err
 	}
 
-	// Commit or rollback the transaction will automatically release the advisory lock
-	err := l.tx.Commit(ctx)
+	_, err := l.connector.conn.Exec(ctx, fmt.Sprintf(`
+		DELETE FROM %s WHERE partition_key = $1 AND range_key = 'lock' AND value = $2
+	`, l.connector.table), l.key, []byte(l.token))
 	if err != nil {
-		// Try to rollback if commit fails
-		rollbackErr := l.tx.Rollback(ctx)
-		if rollbackErr != nil {
-			l.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction after commit failure")
-		}
+		l.connector.handleConnectionFailure(err)
 		common.SetTraceSpanError(span, err)
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return fmt.Errorf("failed to release lock: %w", err)
 	}
 
-	l.logger.Trace().Int64("lockID", l.lockID).Msg("distributed lock released")
+	l.connector.logger.Trace().Str("key", l.key).Msg("distributed lock released")
+	return nil
+}
+
+// Extend pushes the lease expiry forward, only if the lease is still owned by this lock (not expired and taken over).
+func (l *postgresLock) Extend(ctx context.Context, ttl time.Duration) error {
+	ctx, span := common.StartSpan(ctx, "PostgreSQLConnector.ExtendLock",
+		trace.WithAttributes(
+			attribute.String("lock_key", l.key),
+		),
+	)
+	defer span.End()
 
-	l.tx = nil
+	l.connector.connMu.RLock()
+	defer l.connector.connMu.RUnlock()
+	if l.connector.conn == nil {
+		err := fmt.Errorf("PostgreSQLConnector not connected yet")
+		common.SetTraceSpanError(span, err)
+		return err
+	}
+
+	tag, err := l.connector.conn.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s SET expires_at = NOW() + $3 * INTERVAL '1 millisecond'
+		WHERE partition_key = $1 AND range_key = 'lock' AND value = $2 AND expires_at > NOW()
+	`, l.connector.table), l.key, []byte(l.token), ttl.Milliseconds())
+	if err != nil {
+		l.connector.handleConnectionFailure(err)
+		common.SetTraceSpanError(span, err)
+		return fmt.Errorf("failed to extend lock: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		err := fmt.Errorf("lock is no longer held")
+		common.SetTraceSpanError(span, err)
+		return err
+	}
 	return nil
 }
 
@@ -573,6 +609,123 @@ func (p *PostgreSQLConnector) PublishCounterInt64(ctx context.Context, key strin
 	return err
 }
 
+func (p *PostgreSQLConnector) WatchValue(ctx context.Context, key string) (<-chan []byte, func(), error) {
+	updates := make(chan []byte, 1)
+
+	listener, err := p.getOrCreateValueListener(ctx, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create listener: %w", err)
+	}
+
+	listener.mu.Lock()
+	listener.watchers = append(listener.watchers, updates)
+	listener.mu.Unlock()
+
+	p.logger.Debug().Str("key", key).Msg("starting shared value watcher for key")
+
+	// Start fallback polling, stopped by the context or by cleanup
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				p.logger.Debug().Str("key", key).Msg("stopping shared value watcher for key due to context termination")
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				if val, err := p.Get(ctx, ConnectorMainIndex, key, "value"); err == nil {
+					listener.broadcast(val)
+				} else if !common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+					p.logger.Warn().Err(err).Str("key", key).Msg("failed to proactively get current shared value from postgres")
+				}
+			}
+		}
+	}()
+
+	// Send initial value, unless a notification already queued a fresher one
+	if val, err := p.Get(ctx, ConnectorMainIndex, key, "value"); err == nil {
+		listener.mu.Lock()
+		select {
+		case updates <- val:
+		default:
+		}
+		listener.mu.Unlock()
+	}
+
+	var once sync.Once
+	cleanup := func() {
+		once.Do(func() {
+			close(done)
+
+			listener.mu.Lock()
+			defer listener.mu.Unlock()
+
+			for i, ch := range listener.watchers {
+				if ch == updates {
+					listener.watchers = append(listener.watchers[:i], listener.watchers[i+1:]...)
+					break
+				}
+			}
+
+			close(updates)
+		})
+	}
+
+	return updates, cleanup, nil
+}
+
+func (p *PostgreSQLConnector) PublishValue(ctx context.Context, key string, value []byte) error {
+	ctx, span := common.StartSpan(ctx, "PostgreSQLConnector.PublishValue",
+		trace.WithAttributes(
+			attribute.String("key", key),
+		),
+	)
+	defer span.End()
+
+	p.connMu.RLock()
+	defer p.connMu.RUnlock()
+
+	if p.conn == nil {
+		err := fmt.Errorf("postgres not connected yet")
+		common.SetTraceSpanError(span, err)
+		return err
+	}
+
+	p.logger.Debug().Str("key", key).Int("size", len(value)).Msg("publishing shared value update to postgres")
+
+	channel := sanitizeChannelName(fmt.Sprintf("value_%s", key))
+	_, err := p.conn.Exec(ctx, fmt.Sprintf("NOTIFY %s", channel))
+
+	if err != nil {
+		common.SetTraceSpanError(span, err)
+	}
+
+	return err
+}
+
+func (l *pgxValueListener) broadcast(val []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, ch := range l.watchers {
+		// Replace any unconsumed update so slow watchers end up with the latest value
+		select {
+		case ch <- val:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- val:
+			default:
+			}
+		}
+	}
+}
+
 func (p *PostgreSQLConnector) taskId() string {
 	return fmt.Sprintf("postgres-connect/%s", p.id)
 }
@@ -645,6 +798,51 @@ func (p *PostgreSQLConnector) getOrCreateListener(ctx context.Context, key strin
 	return listener, nil
 }
 
+func (p *PostgreSQLConnector) getOrCreateValueListener(ctx context.Context, key string) (*pgxValueListener, error) {
+	if l, ok := p.valueListeners.Load(key); ok {
+		return l.(*pgxValueListener), nil
+	}
+
+	listener := &pgxValueListener{}
+	channel := sanitizeChannelName(fmt.Sprintf("value_%s", key))
+
+	conn, err := p.connectListener(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for {
+			if err := ctx.Err(); err != nil {
+				p.logger.Debug().Err(err).Str("key", key).Msg("stopping postgres value listener due to context termination")
+				return
+			}
+
+			_, err := conn.Conn().WaitForNotification(ctx)
+			if err != nil {
+				p.logger.Warn().Err(err).Str("key", key).Msg("lost postgres connection, attempting reconnect")
+				if newConn, err := p.connectListener(ctx, channel); err == nil {
+					p.logger.Debug().Str("key", key).Msg("successfully reconnected to postgres channel")
+					conn = newConn
+					continue
+				}
+				return
+			}
+
+			val, err := p.Get(ctx, ConnectorMainIndex, key, "value")
+			if err != nil {
+				p.logger.Warn().Err(err).Str("key", key).Msg("failed to read shared value after postgres notification")
+				continue
+			}
+			listener.broadcast(val)
+		}
+	}()
+
+	p.logger.Debug().Str("key", key).Msg("successfully created postgres value listener for key")
+	actual, _ := p.valueListeners.LoadOrStore(key, listener)
+	return actual.(*pgxValueListener), nil
+}
+
 func (p *PostgreSQLConnector) connectListener(ctx context.Context, channel string) (*pgxpool.Conn, error) {
 	for {
 		if err := ctx.Err(); err != nil {
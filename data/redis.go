@@ -7,6 +7,7 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/erpc/erpc/common"
@@ -520,6 +521,133 @@ func (r *RedisConnector) PublishCounterInt64(ctx context.Context, key string, va
 	return err
 }
 
+// WatchValue watches a shared value in Redis via pub/sub with a periodic fallback poll.
+// Callers of this method are responsible to re-try the operation if "values" channel is closed.
+func (r *RedisConnector) WatchValue(ctx context.Context, key string) (<-chan []byte, func(), error) {
+	r.logger.Debug().Str("key", key).Msg("trying to watch shared value in Redis")
+	if err := r.checkReady(); err != nil {
+		return nil, nil, err
+	}
+	updates := make(chan []byte, 1)
+	pubsub := r.client.Subscribe(ctx, "value:"+key)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(updates)
+		defer func() {
+			if rc := recover(); rc != nil {
+				telemetry.MetricUnexpectedPanicTotal.WithLabelValues(
+					"redis-watch-value",
+					fmt.Sprintf("connector:%s", r.id),
+					common.ErrorFingerprint(rc),
+				).Inc()
+				r.logger.Error().
+					Interface("panic", rc).
+					Str("stack", string(debug.Stack())).
+					Msg("unexpected panic in redis WatchValue")
+			}
+		}()
+		defer func() {
+			if err := pubsub.Close(); err != nil {
+				r.logger.Warn().Err(err).Str("key", key).Msg("failed to close pubsub")
+				r.markConnectionAsLostIfNecessary(err)
+			}
+		}()
+
+		// Send the initial value so watchers do not depend on a publish to catch up
+		if val, err := r.Get(ctx, ConnectorMainIndex, key, "value"); err == nil {
+			select {
+			case updates <- val:
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+		} else if !common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+			r.logger.Warn().Err(err).Str("key", key).Msg("failed to get initial shared value")
+		}
+
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		ch := pubsub.Channel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-done:
+				return
+
+			case msg, ok := <-ch:
+				if !ok || msg == nil {
+					r.logger.Warn().Str("key", key).Interface("msg", msg).Msg("pubsub channel closed")
+					return
+				}
+				select {
+				case updates <- []byte(msg.Payload):
+				default:
+				}
+
+			case <-ticker.C:
+				r.logger.Debug().Str("key", key).Msg("polling current shared value")
+				val, err := r.Get(ctx, ConnectorMainIndex, key, "value")
+				if err != nil {
+					if common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+						continue
+					}
+					r.markConnectionAsLostIfNecessary(err)
+					return
+				}
+				select {
+				case updates <- val:
+				default:
+				}
+			}
+		}
+	}()
+
+	// The watcher goroutine closes pubsub and updates on its way out
+	var once sync.Once
+	cleanup := func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+
+	r.logger.Info().Str("key", key).Msg("started watching shared value in Redis")
+
+	return updates, cleanup, nil
+}
+
+// PublishValue publishes an already persisted shared value to Redis watchers.
+func (r *RedisConnector) PublishValue(ctx context.Context, key string, value []byte) error {
+	ctx, span := common.StartSpan(ctx, "RedisConnector.PublishValue",
+		trace.WithAttributes(
+			attribute.String("key", key),
+		),
+	)
+	defer span.End()
+
+	if common.IsTracingDetailed {
+		span.SetAttributes(
+			attribute.Int("value_size", len(value)),
+		)
+	}
+
+	if err := r.checkReady(); err != nil {
+		common.SetTraceSpanError(span, err)
+		return err
+	}
+	r.logger.Debug().Str("key", key).Int("size", len(value)).Msg("publishing shared value update to Redis")
+
+	err := r.client.Publish(ctx, "value:"+key, value).Err()
+	if err != nil {
+		common.SetTraceSpanError(span, err)
+	}
+	return err
+}
+
 func (r *RedisConnector) getCurrentValue(ctx context.Context, key string) (int64, error) {
 	ctx, span := common.StartDetailSpan(ctx, "RedisConnector.getCurrentValue",
 		trace.WithAttributes(
@@ -582,3 +710,29 @@ func (l *redisLock) Unlock(ctx context.Context) error {
 	l.connector.logger.Trace().Str("key", l.key).Msg("distributed lock released")
 	return nil
 }
+
+// Extend renews the lock expiry. Redsync always extends by the expiry the lock was created with,
+// so ttl is expected to match the one passed to Lock.
+func (l *redisLock) Extend(ctx context.Context, ttl time.Duration) error {
+	ctx, span := common.StartSpan(ctx, "RedisConnector.ExtendLock",
+		trace.WithAttributes(
+			attribute.String("lock_key", l.key),
+		),
+	)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, l.connector.setTimeout)
+	defer cancel()
+	ok, err := l.mutex.ExtendContext(ctx)
+	if err != nil {
+		common.SetTraceSpanError(span, err)
+		return fmt.Errorf("error extending lock: %w", err)
+	}
+	if !ok {
+		err := errors.New("failed to extend lock")
+		common.SetTraceSpanError(span, err)
+		return err
+	}
+	l.connector.logger.Trace().Str("key", l.key).Msg("distributed lock extended")
+	return nil
+}
@@ -15,6 +15,8 @@ import (
 
 type SharedStateRegistry interface {
 	GetCounterInt64(key string, ignoreRollbackOf int64) CounterInt64SharedVariable
+	GetJsonValue(key string) JsonSharedVariable
+	GetLeaderElection(key string) LeaderElection
 }
 
 type sharedStateRegistry struct {
@@ -23,8 +25,13 @@ type sharedStateRegistry struct {
 	clusterKey      string
 	connector       Connector
 	variables       sync.Map // map[string]*counterInt64
+	values          sync.Map // map[string]*jsonValue
+	variablesMu     sync.Mutex
+	elections       sync.Map // map[string]*leaderElection
+	electionsMu     sync.Mutex
 	fallbackTimeout time.Duration
 	lockTtl         time.Duration
+	leaderElection  *common.LeaderElectionConfig
 	initializer     *util.Initializer
 }
 
@@ -46,36 +53,156 @@ func NewSharedStateRegistry(
 		connector:       connector,
 		fallbackTimeout: cfg.FallbackTimeout.Duration(),
 		lockTtl:         cfg.LockTtl.Duration(),
+		leaderElection:  cfg.LeaderElection,
 		initializer:     util.NewInitializer(appCtx, &lg, nil),
 	}, nil
 }
 
 func (r *sharedStateRegistry) GetCounterInt64(key string, ignoreRollbackOf int64) CounterInt64SharedVariable {
 	fkey := fmt.Sprintf("%s/%s", r.clusterKey, key)
-	value, alreadySetup := r.variables.LoadOrStore(fkey, &counterInt64{
+
+	r.variablesMu.Lock()
+	defer r.variablesMu.Unlock()
+	if existing, ok := r.variables.Load(fkey); ok {
+		counter := existing.(*counterInt64)
+		counter.refs++
+		return counter
+	}
+	counter := &counterInt64{
 		registry:         r,
 		key:              fkey,
 		ignoreRollbackOf: ignoreRollbackOf,
-	})
-	counter := value.(*counterInt64)
+		refs:             1,
+		stop:             make(chan struct{}),
+	}
+	r.variables.Store(fkey, counter)
 
 	// Setup sync only once per counter
-	if !alreadySetup {
-		go func() {
-			err := r.initializer.ExecuteTasks(
-				r.appCtx,
-				r.buildCounterSyncTask(counter),
-				r.buildInitialValueTask(counter),
-			)
-			if err != nil {
-				r.logger.Error().Err(err).Str("key", fkey).Msg("failed to setup shared counter on initial attempt (will retry in background)")
-			}
-		}()
-	}
+	go func() {
+		err := r.initializer.ExecuteTasks(
+			r.appCtx,
+			r.buildCounterSyncTask(counter),
+			r.buildInitialValueTask(counter),
+		)
+		if err != nil {
+			r.logger.Error().Err(err).Str("key", fkey).Msg("failed to setup shared counter on initial attempt (will retry in background)")
+		}
+	}()
 
 	return counter
 }
 
+func (r *sharedStateRegistry) GetJsonValue(key string) JsonSharedVariable {
+	fkey := fmt.Sprintf("%s/%s", r.clusterKey, key)
+
+	r.variablesMu.Lock()
+	defer r.variablesMu.Unlock()
+	if existing, ok := r.values.Load(fkey); ok {
+		value := existing.(*jsonValue)
+		value.refs++
+		return value
+	}
+	value := &jsonValue{
+		registry: r,
+		key:      fkey,
+		refs:     1,
+		stop:     make(chan struct{}),
+	}
+	r.values.Store(fkey, value)
+
+	// Setup sync only once per value
+	go func() {
+		err := r.initializer.ExecuteTasks(
+			r.appCtx,
+			r.buildValueSyncTask(value),
+			r.buildInitialJsonValueTask(value),
+		)
+		if err != nil {
+			r.logger.Error().Err(err).Str("key", fkey).Msg("failed to setup shared value on initial attempt (will retry in background)")
+		}
+	}()
+
+	return value
+}
+
+// releaseCounter stops syncing a counter once its last holder released it.
+func (r *sharedStateRegistry) releaseCounter(counter *counterInt64) {
+	r.variablesMu.Lock()
+	defer r.variablesMu.Unlock()
+	if counter.refs <= 0 {
+		return
+	}
+	counter.refs--
+	if counter.refs > 0 {
+		return
+	}
+	r.variables.CompareAndDelete(counter.key, counter)
+	close(counter.stop)
+	r.initializer.RemoveTask(r.getCounterSyncTaskName(counter))
+	r.initializer.RemoveTask(r.getInitialValueTaskName(counter))
+}
+
+// releaseValue stops syncing a value once its last holder released it.
+func (r *sharedStateRegistry) releaseValue(value *jsonValue) {
+	r.variablesMu.Lock()
+	defer r.variablesMu.Unlock()
+	if value.refs <= 0 {
+		return
+	}
+	value.refs--
+	if value.refs > 0 {
+		return
+	}
+	r.values.CompareAndDelete(value.key, value)
+	close(value.stop)
+	r.initializer.RemoveTask(r.getValueSyncTaskName(value))
+	r.initializer.RemoveTask(r.getInitialJsonValueTaskName(value))
+}
+
+// GetLeaderElection returns the election for a singleton job identified by key. When leader election
+// is not enabled every instance is considered the leader, which preserves per-instance behavior.
+func (r *sharedStateRegistry) GetLeaderElection(key string) LeaderElection {
+	if r.leaderElection == nil || r.leaderElection.Enabled == nil || !*r.leaderElection.Enabled {
+		return alwaysLeader{}
+	}
+	fkey := fmt.Sprintf("%s/leader/%s", r.clusterKey, key)
+
+	r.electionsMu.Lock()
+	defer r.electionsMu.Unlock()
+	if existing, ok := r.elections.Load(fkey); ok {
+		election := existing.(*leaderElection)
+		election.refs++
+		return election
+	}
+	election := &leaderElection{
+		registry:      r,
+		key:           fkey,
+		leaseTtl:      r.leaderElection.LeaseTtl.Duration(),
+		renewInterval: r.leaderElection.RenewInterval.Duration(),
+		refs:          1,
+		stop:          make(chan struct{}),
+	}
+	r.elections.Store(fkey, election)
+	go election.run()
+	return election
+}
+
+// releaseLeaderElection stops the election once its last holder released it, so a later
+// GetLeaderElection for the same key starts a new one.
+func (r *sharedStateRegistry) releaseLeaderElection(election *leaderElection) {
+	r.electionsMu.Lock()
+	defer r.electionsMu.Unlock()
+	if election.refs <= 0 {
+		return
+	}
+	election.refs--
+	if election.refs > 0 {
+		return
+	}
+	r.elections.CompareAndDelete(election.key, election)
+	close(election.stop)
+}
+
 func (r *sharedStateRegistry) buildCounterSyncTask(counter *counterInt64) *util.BootstrapTask {
 	return util.NewBootstrapTask(
 		r.getCounterSyncTaskName(counter),
@@ -126,6 +253,12 @@ func (r *sharedStateRegistry) initCounterSync(counter *counterInt64) error {
 		}
 	}()
 
+	select {
+	case <-counter.stop:
+		return nil
+	default:
+	}
+
 	// Initial setup using the provided context
 	updates, cleanup, err := r.connector.WatchCounterInt64(r.appCtx, counter.key)
 	if err != nil {
@@ -142,6 +275,8 @@ func (r *sharedStateRegistry) initCounterSync(counter *counterInt64) error {
 			select {
 			case <-r.appCtx.Done():
 				return
+			case <-counter.stop:
+				return
 
 			case newValue, ok := <-updates:
 				if !ok {
@@ -162,6 +297,113 @@ func (r *sharedStateRegistry) initCounterSync(counter *counterInt64) error {
 	return nil
 }
 
+func (r *sharedStateRegistry) buildValueSyncTask(value *jsonValue) *util.BootstrapTask {
+	return util.NewBootstrapTask(
+		r.getValueSyncTaskName(value),
+		func(ctx context.Context) error {
+			return r.initValueSync(value)
+		},
+	)
+}
+
+func (r *sharedStateRegistry) buildInitialJsonValueTask(value *jsonValue) *util.BootstrapTask {
+	return util.NewBootstrapTask(
+		r.getInitialJsonValueTaskName(value),
+		func(ctx context.Context) error {
+			raw, err := r.connector.Get(ctx, ConnectorMainIndex, value.key, "value")
+			if err != nil {
+				if common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+					r.logger.Debug().Str("key", value.key).Msg("no initial value found for shared value")
+					return nil
+				}
+				r.logger.Error().Err(err).Str("key", value.key).Msg("failed to fetch initial value for shared value")
+				return err
+			}
+			env, err := decodeSharedValueEnvelope(raw)
+			if err != nil {
+				r.logger.Warn().Err(err).Str("key", value.key).Msg("ignoring unparsable initial shared value")
+				return nil
+			}
+			r.logger.Debug().Str("key", value.key).Int64("version", env.Version).Msg("fetched initial value for shared value")
+			value.processNewValue(env)
+			return nil
+		},
+	)
+}
+
+func (r *sharedStateRegistry) initValueSync(value *jsonValue) error {
+	defer func() {
+		if rc := recover(); rc != nil {
+			telemetry.MetricUnexpectedPanicTotal.WithLabelValues(
+				"shared-state-value-sync",
+				fmt.Sprintf("connector:%s cluster:%s", r.connector.Id(), r.clusterKey),
+				common.ErrorFingerprint(rc),
+			).Inc()
+			r.logger.Error().
+				Interface("panic", rc).
+				Str("stack", string(debug.Stack())).
+				Str("key", value.key).
+				Msg("unexpected panic in shared state value sync")
+			err := fmt.Errorf("unexpected panic in shared state value sync: %v stack: %s", rc, string(debug.Stack()))
+			r.initializer.MarkTaskAsFailed(r.getValueSyncTaskName(value), err)
+		}
+	}()
+
+	select {
+	case <-value.stop:
+		return nil
+	default:
+	}
+
+	updates, cleanup, err := r.connector.WatchValue(r.appCtx, value.key)
+	if err != nil {
+		r.logger.Error().Err(err).Str("key", value.key).Msg("failed to setup shared value sync")
+		return err
+	}
+
+	go func() {
+		if cleanup != nil {
+			defer cleanup()
+		}
+		for {
+			select {
+			case <-r.appCtx.Done():
+				return
+			case <-value.stop:
+				return
+
+			case raw, ok := <-updates:
+				if !ok {
+					err := fmt.Errorf("shared value sync channel closed unexpectedly")
+					r.initializer.MarkTaskAsFailed(r.getValueSyncTaskName(value), err)
+					return
+				}
+
+				env, err := decodeSharedValueEnvelope(raw)
+				if err != nil {
+					r.logger.Warn().Err(err).Str("key", value.key).Msg("ignoring unparsable shared value update")
+					continue
+				}
+				r.logger.Debug().
+					Str("key", value.key).
+					Int64("version", env.Version).
+					Msg("received new shared value from shared state")
+				value.processNewValue(env)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (r *sharedStateRegistry) getValueSyncTaskName(value *jsonValue) string {
+	return fmt.Sprintf("valueSync/%s", value.key)
+}
+
+func (r *sharedStateRegistry) getInitialJsonValueTaskName(value *jsonValue) string {
+	return fmt.Sprintf("initialJsonValue/%s", value.key)
+}
+
 func (r *sharedStateRegistry) getCounterSyncTaskName(counter *counterInt64) string {
 	return fmt.Sprintf("counterSync/%s", counter.key)
 }
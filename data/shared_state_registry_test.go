@@ -26,6 +26,11 @@ func (m *MockLock) Unlock(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockLock) Extend(ctx context.Context, ttl time.Duration) error {
+	args := m.Called(ctx, ttl)
+	return args.Error(0)
+}
+
 func setupTest(clusterKey string) (*sharedStateRegistry, *MockConnector, context.Context) {
 	connector := &MockConnector{}
 	registry := &sharedStateRegistry{
@@ -259,3 +264,35 @@ func TestSharedStateRegistry_GetCounterInt64_WatchFailure(t *testing.T) {
 	counter.TryUpdate(context.Background(), 42)
 	assert.Equal(t, int64(42), counter.GetValue()) // Should still work with local value
 }
+
+func TestSharedStateRegistry_GetCounterInt64_Release(t *testing.T) {
+	registry, connector, _ := setupTest("my-dev")
+
+	updates := make(chan int64, 1)
+	watchStopped := make(chan struct{})
+	connector.On("WatchCounterInt64", mock.Anything, "my-dev/test").Return(updates, func() { close(watchStopped) }, nil).Once()
+	connector.On("Get", mock.Anything, ConnectorMainIndex, "my-dev/test", "value").Return([]byte("5"), nil)
+
+	first := registry.GetCounterInt64("test", 1024)
+	second := registry.GetCounterInt64("test", 1024)
+	assert.Same(t, first, second)
+	time.Sleep(100 * time.Millisecond)
+
+	// Still held by the second holder
+	first.Release()
+	_, ok := registry.variables.Load("my-dev/test")
+	assert.True(t, ok)
+
+	second.Release()
+	_, ok = registry.variables.Load("my-dev/test")
+	assert.False(t, ok)
+	select {
+	case <-watchStopped:
+	case <-time.After(time.Second):
+		t.Fatal("watch was not stopped once the counter was released")
+	}
+
+	// A later lookup starts over
+	connector.On("WatchCounterInt64", mock.Anything, "my-dev/test").Return(make(chan int64), func() {}, nil).Once()
+	assert.NotSame(t, first, registry.GetCounterInt64("test", 1024))
+}
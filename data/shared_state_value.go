@@ -0,0 +1,432 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// JsonSharedVariable is a replicated JSON document guarded by a monotonically increasing version.
+// Writes go through CompareAndSwap so concurrent writers (local or on other replicas) cannot
+// silently overwrite each other: a swap that cannot hold the remote lock or persist the value fails.
+type JsonSharedVariable interface {
+	SharedVariable
+	GetValue() (json.RawMessage, int64)
+	CompareAndSwap(ctx context.Context, expectedVersion int64, newValue json.RawMessage) (int64, bool, error)
+	Publish(ctx context.Context, newValue json.RawMessage) int64
+	OnValue(callback func(value json.RawMessage, version int64))
+}
+
+// sharedValueEnvelope is what gets stored in the connector; replicas only accept a received
+// envelope when its version is higher than the one they hold.
+type sharedValueEnvelope struct {
+	Version int64           `json:"version"`
+	Value   json.RawMessage `json:"value,omitempty"`
+}
+
+type jsonValue struct {
+	baseSharedVariable
+	registry       *sharedStateRegistry
+	key            string
+	value          json.RawMessage
+	version        int64
+	valueMu        sync.RWMutex
+	updateMu       sync.Mutex
+	valueCallbacks []func(json.RawMessage, int64)
+	callbackMu     sync.RWMutex
+
+	// Guarded by registry.variablesMu
+	refs int
+	stop chan struct{}
+}
+
+func (v *jsonValue) Release() {
+	v.registry.releaseValue(v)
+}
+
+func (v *jsonValue) GetValue() (json.RawMessage, int64) {
+	v.valueMu.RLock()
+	defer v.valueMu.RUnlock()
+	return v.value, v.version
+}
+
+func (v *jsonValue) processNewValue(env *sharedValueEnvelope) bool {
+	v.valueMu.Lock()
+	currentVersion := v.version
+	updated := env.Version > currentVersion
+	if updated {
+		v.value = env.Value
+		v.version = env.Version
+	}
+	v.valueMu.Unlock()
+
+	if updated {
+		v.triggerValueCallback(env.Value, env.Version)
+	}
+
+	v.lastProcessedMu.Lock()
+	v.lastProcessed = time.Now()
+	v.lastProcessedMu.Unlock()
+
+	v.registry.logger.Trace().Str("key", v.key).Int64("currentVersion", currentVersion).Int64("newVersion", env.Version).Bool("updated", updated).Msg("processed new shared value")
+	return updated
+}
+
+func (v *jsonValue) triggerValueCallback(value json.RawMessage, version int64) {
+	v.callbackMu.RLock()
+	callbacks := make([]func(json.RawMessage, int64), len(v.valueCallbacks))
+	copy(callbacks, v.valueCallbacks)
+	v.callbackMu.RUnlock()
+
+	for _, cb := range callbacks {
+		if cb != nil {
+			cb(value, version)
+		}
+	}
+}
+
+// CompareAndSwap replaces the value only if the current version equals expectedVersion, returning the
+// resulting version and whether the swap happened. The swap is done while holding the remote lock and only
+// applied locally once persisted, otherwise two replicas could both reach the same version with different values.
+// An error means the shared state backend was not available and nothing was changed.
+func (v *jsonValue) CompareAndSwap(ctx context.Context, expectedVersion int64, newValue json.RawMessage) (int64, bool, error) {
+	ctx, span := common.StartSpan(ctx, "JsonValue.CompareAndSwap",
+		trace.WithAttributes(
+			attribute.String("key", v.key),
+			attribute.Int64("expected_version", expectedVersion),
+		),
+	)
+	defer span.End()
+
+	v.updateMu.Lock()
+	defer v.updateMu.Unlock()
+
+	rctx, cancel := context.WithTimeout(ctx, v.registry.fallbackTimeout)
+	defer cancel()
+
+	unlock, err := v.acquireLock(rctx)
+	if err != nil {
+		common.SetTraceSpanError(span, err)
+		_, currentVersion := v.GetValue()
+		return currentVersion, false, err
+	}
+	defer unlock()
+
+	// Catch up with writes from other replicas that we have not been notified about yet
+	if remote := v.tryGetRemoteValue(rctx); remote != nil {
+		v.processNewValue(remote)
+	}
+
+	current, currentVersion := v.GetValue()
+	if currentVersion != expectedVersion {
+		return currentVersion, false, nil
+	}
+	if currentVersion > 0 && bytes.Equal(current, newValue) {
+		return currentVersion, true, nil
+	}
+
+	env := &sharedValueEnvelope{Version: currentVersion + 1, Value: newValue}
+
+	// Using a fresh timeout so a slow lock/get does not eat into it
+	pctx, cancel := context.WithTimeout(ctx, v.registry.fallbackTimeout)
+	defer cancel()
+	if err := v.updateRemoteValue(pctx, env); err != nil {
+		common.SetTraceSpanError(span, err)
+		return currentVersion, false, err
+	}
+	v.processNewValue(env)
+
+	return env.Version, true, nil
+}
+
+// Publish overwrites the value under the next version with a plain set and publish, without the remote lock.
+// It is meant for values with a single writer (e.g. owned by an elected leader) that change often;
+// values with concurrent writers must go through CompareAndSwap.
+func (v *jsonValue) Publish(ctx context.Context, newValue json.RawMessage) int64 {
+	v.updateMu.Lock()
+	defer v.updateMu.Unlock()
+
+	current, currentVersion := v.GetValue()
+	if currentVersion > 0 && bytes.Equal(current, newValue) {
+		return currentVersion
+	}
+
+	env := &sharedValueEnvelope{Version: currentVersion + 1, Value: newValue}
+	v.processNewValue(env)
+
+	pctx, cancel := context.WithTimeout(ctx, v.registry.fallbackTimeout)
+	defer cancel()
+	if err := v.updateRemoteValue(pctx, env); err != nil {
+		v.registry.logger.Warn().Err(err).Str("key", v.key).Int64("version", env.Version).Msg("failed to publish shared value, it is only updated locally")
+	}
+
+	return env.Version
+}
+
+func (v *jsonValue) OnValue(cb func(json.RawMessage, int64)) {
+	v.callbackMu.Lock()
+	defer v.callbackMu.Unlock()
+	v.valueCallbacks = append(v.valueCallbacks, cb)
+}
+
+func (v *jsonValue) acquireLock(ctx context.Context) (func(), error) {
+	lock, err := v.registry.connector.Lock(ctx, v.key, v.registry.lockTtl)
+	if err == nil && (lock == nil || lock.IsNil()) {
+		err = fmt.Errorf("no lock returned by connector")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to remotely lock shared value %s: %w", v.key, err)
+	}
+	return func() {
+		unlockCtx, cancel := context.WithTimeout(v.registry.appCtx, v.registry.lockTtl)
+		defer cancel()
+		if err := lock.Unlock(unlockCtx); err != nil {
+			v.registry.logger.Warn().Err(err).Str("key", v.key).Int64("lock_ttl_ms", v.registry.lockTtl.Milliseconds()).Msg("failed to unlock shared value, so it will be expired after ttl")
+		}
+	}, nil
+}
+
+func (v *jsonValue) tryGetRemoteValue(ctx context.Context) *sharedValueEnvelope {
+	remoteVal, err := v.registry.connector.Get(ctx, ConnectorMainIndex, v.key, "value")
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeRecordNotFound) {
+			v.registry.logger.Debug().Err(err).Str("key", v.key).Msg("remote shared value not found, will initialize it")
+		} else {
+			v.registry.logger.Warn().Err(err).Str("key", v.key).Msg("failed to get remote shared value")
+		}
+		return nil
+	}
+	env, err := decodeSharedValueEnvelope(remoteVal)
+	if err != nil {
+		v.registry.logger.Warn().Err(err).Str("key", v.key).Msg("failed to parse remote shared value")
+		return nil
+	}
+	return env
+}
+
+// updateRemoteValue persists the envelope and notifies other replicas. Only a failed write is returned,
+// a failed notification is logged as replicas still catch up from the stored value.
+func (v *jsonValue) updateRemoteValue(ctx context.Context, env *sharedValueEnvelope) error {
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	if err := v.registry.connector.Set(ctx, v.key, "value", payload, nil); err != nil {
+		return fmt.Errorf("failed to store shared value %s: %w", v.key, err)
+	}
+	if err := v.registry.connector.PublishValue(ctx, v.key, payload); err != nil {
+		v.registry.logger.Warn().Err(err).
+			Str("key", v.key).
+			Int64("version", env.Version).
+			Msg("failed to notify replicas of shared value update")
+	}
+	return nil
+}
+
+func decodeSharedValueEnvelope(raw []byte) (*sharedValueEnvelope, error) {
+	env := &sharedValueEnvelope{}
+	if err := json.Unmarshal(raw, env); err != nil {
+		return nil, err
+	}
+	// Copy so the envelope does not alias connector-owned memory (see Connector.Set)
+	env.Value = append(json.RawMessage(nil), env.Value...)
+	return env, nil
+}
+
+// SharedValue is a typed view over a JsonSharedVariable.
+type SharedValue[T any] struct {
+	variable JsonSharedVariable
+}
+
+func NewSharedValue[T any](registry SharedStateRegistry, key string) *SharedValue[T] {
+	return &SharedValue[T]{variable: registry.GetJsonValue(key)}
+}
+
+func (s *SharedValue[T]) IsStale(staleness time.Duration) bool {
+	return s.variable.IsStale(staleness)
+}
+
+func (s *SharedValue[T]) Release() {
+	s.variable.Release()
+}
+
+// Get returns the decoded value and its version. A version of 0 means nothing was ever written.
+func (s *SharedValue[T]) Get() (T, int64, error) {
+	raw, version := s.variable.GetValue()
+	var value T
+	if len(raw) == 0 {
+		return value, version, nil
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return value, version, fmt.Errorf("failed to decode shared value: %w", err)
+	}
+	return value, version, nil
+}
+
+func (s *SharedValue[T]) CompareAndSwap(ctx context.Context, expectedVersion int64, value T) (bool, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode shared value: %w", err)
+	}
+	_, swapped, err := s.variable.CompareAndSwap(ctx, expectedVersion, raw)
+	return swapped, err
+}
+
+// Publish writes the value without the remote lock, see JsonSharedVariable.Publish for when that is safe.
+func (s *SharedValue[T]) Publish(ctx context.Context, value T) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode shared value: %w", err)
+	}
+	s.variable.Publish(ctx, raw)
+	return nil
+}
+
+// Update applies fn to the latest value and retries on version conflicts until it succeeds,
+// fn returns an error, or ctx is done.
+func (s *SharedValue[T]) Update(ctx context.Context, fn func(current T) (T, error)) (T, error) {
+	for {
+		current, version, err := s.Get()
+		if err != nil {
+			return current, err
+		}
+		next, err := fn(current)
+		if err != nil {
+			return current, err
+		}
+		swapped, err := s.CompareAndSwap(ctx, version, next)
+		if err != nil {
+			return current, err
+		}
+		if swapped {
+			return next, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return current, err
+		}
+	}
+}
+
+// OnValue registers a callback for every newer version, whether written locally or by another replica.
+// Values that fail to decode are skipped.
+func (s *SharedValue[T]) OnValue(cb func(T)) {
+	s.variable.OnValue(func(raw json.RawMessage, _ int64) {
+		var value T
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return
+			}
+		}
+		cb(value)
+	})
+}
+
+type sharedMapEntry struct {
+	Value     json.RawMessage `json:"value"`
+	ExpiresAt int64           `json:"expiresAt,omitempty"` // unix milliseconds, 0 means no expiry
+}
+
+func (e *sharedMapEntry) expired(now time.Time) bool {
+	return e.ExpiresAt > 0 && now.UnixMilli() >= e.ExpiresAt
+}
+
+// SharedMap is a replicated map whose entries can expire individually. The whole map is stored as a single
+// shared value so every mutation is a compare-and-swap; expired entries are hidden on read and pruned on write.
+type SharedMap[T any] struct {
+	value *SharedValue[map[string]sharedMapEntry]
+}
+
+func NewSharedMap[T any](registry SharedStateRegistry, key string) *SharedMap[T] {
+	return &SharedMap[T]{value: NewSharedValue[map[string]sharedMapEntry](registry, key)}
+}
+
+func (m *SharedMap[T]) Get(key string) (T, bool) {
+	var value T
+	entries, _, err := m.value.Get()
+	if err != nil {
+		return value, false
+	}
+	entry, ok := entries[key]
+	if !ok || entry.expired(time.Now()) {
+		return value, false
+	}
+	if err := json.Unmarshal(entry.Value, &value); err != nil {
+		return value, false
+	}
+	return value, true
+}
+
+// Entries returns a snapshot of all non-expired entries.
+func (m *SharedMap[T]) Entries() map[string]T {
+	entries, _, err := m.value.Get()
+	if err != nil {
+		return map[string]T{}
+	}
+	return decodeSharedMapEntries[T](entries, time.Now())
+}
+
+// Set stores value under key; a ttl of 0 keeps the entry until it is deleted.
+func (m *SharedMap[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode shared map entry: %w", err)
+	}
+	_, err = m.value.Update(ctx, func(current map[string]sharedMapEntry) (map[string]sharedMapEntry, error) {
+		now := time.Now()
+		next := pruneSharedMapEntries(current, now)
+		entry := sharedMapEntry{Value: raw}
+		if ttl > 0 {
+			entry.ExpiresAt = now.Add(ttl).UnixMilli()
+		}
+		next[key] = entry
+		return next, nil
+	})
+	return err
+}
+
+func (m *SharedMap[T]) Delete(ctx context.Context, key string) error {
+	_, err := m.value.Update(ctx, func(current map[string]sharedMapEntry) (map[string]sharedMapEntry, error) {
+		next := pruneSharedMapEntries(current, time.Now())
+		delete(next, key)
+		return next, nil
+	})
+	return err
+}
+
+// OnChange registers a callback receiving the non-expired entries whenever the map changes.
+func (m *SharedMap[T]) OnChange(cb func(map[string]T)) {
+	m.value.OnValue(func(entries map[string]sharedMapEntry) {
+		cb(decodeSharedMapEntries[T](entries, time.Now()))
+	})
+}
+
+func pruneSharedMapEntries(entries map[string]sharedMapEntry, now time.Time) map[string]sharedMapEntry {
+	next := make(map[string]sharedMapEntry, len(entries)+1)
+	for k, e := range entries {
+		if !e.expired(now) {
+			next[k] = e
+		}
+	}
+	return next
+}
+
+func decodeSharedMapEntries[T any](entries map[string]sharedMapEntry, now time.Time) map[string]T {
+	result := make(map[string]T, len(entries))
+	for k, e := range entries {
+		if e.expired(now) {
+			continue
+		}
+		var value T
+		if err := json.Unmarshal(e.Value, &value); err == nil {
+			result[k] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,233 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSharedMemoryRegistries(t *testing.T, ctx context.Context, count int) []*sharedStateRegistry {
+	t.Helper()
+	connector, err := NewMemoryConnector(ctx, &log.Logger, "test", &common.MemoryConnectorConfig{MaxItems: 1000, MaxTotalSize: "10MB"})
+	require.NoError(t, err)
+	registries := make([]*sharedStateRegistry, count)
+	for i := range registries {
+		registries[i] = &sharedStateRegistry{
+			appCtx:          ctx,
+			clusterKey:      "test",
+			logger:          &log.Logger,
+			connector:       connector,
+			fallbackTimeout: time.Second,
+			lockTtl:         time.Second,
+			initializer:     util.NewInitializer(ctx, &log.Logger, nil),
+		}
+	}
+	return registries
+}
+
+func TestJsonValue_CompareAndSwap(t *testing.T) {
+	t.Run("catches up with remote version before comparing", func(t *testing.T) {
+		registry, connector, ctx := setupTest("test")
+		value := &jsonValue{registry: registry, key: "test/cordons"}
+
+		lock := &MockLock{}
+		lock.On("Unlock", mock.Anything).Return(nil)
+		connector.On("Lock", mock.Anything, "test/cordons", mock.Anything).Return(lock, nil)
+		connector.On("Get", mock.Anything, ConnectorMainIndex, "test/cordons", "value").
+			Return([]byte(`{"version":3,"value":["rpc1"]}`), nil)
+
+		version, swapped, err := value.CompareAndSwap(ctx, 0, json.RawMessage(`["rpc2"]`))
+		require.NoError(t, err)
+		assert.False(t, swapped)
+		assert.Equal(t, int64(3), version)
+
+		raw, _ := value.GetValue()
+		assert.JSONEq(t, `["rpc1"]`, string(raw))
+		connector.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("writes and publishes new version while holding the lock", func(t *testing.T) {
+		registry, connector, ctx := setupTest("test")
+		value := &jsonValue{registry: registry, key: "test/cordons"}
+
+		lock := &MockLock{}
+		lock.On("Unlock", mock.Anything).Return(nil)
+		connector.On("Lock", mock.Anything, "test/cordons", mock.Anything).Return(lock, nil)
+		connector.On("Get", mock.Anything, ConnectorMainIndex, "test/cordons", "value").
+			Return(nil, common.NewErrRecordNotFound("test/cordons", "value", "mock"))
+		connector.On("Set", mock.Anything, "test/cordons", "value", []byte(`{"version":1,"value":["rpc2"]}`), (*time.Duration)(nil)).
+			Return(nil)
+		connector.On("PublishValue", mock.Anything, "test/cordons", []byte(`{"version":1,"value":["rpc2"]}`)).
+			Return(nil)
+
+		version, swapped, err := value.CompareAndSwap(ctx, 0, json.RawMessage(`["rpc2"]`))
+		require.NoError(t, err)
+		assert.True(t, swapped)
+		assert.Equal(t, int64(1), version)
+		connector.AssertExpectations(t)
+		lock.AssertExpectations(t)
+	})
+
+	t.Run("fails without swapping when remote lock is unavailable", func(t *testing.T) {
+		registry, connector, ctx := setupTest("test")
+		value := &jsonValue{registry: registry, key: "test/cordons"}
+
+		connector.On("Lock", mock.Anything, "test/cordons", mock.Anything).Return(nil, errors.New("lock failed"))
+
+		version, swapped, err := value.CompareAndSwap(ctx, 0, json.RawMessage(`{"a":1}`))
+		assert.Error(t, err)
+		assert.False(t, swapped)
+		assert.Equal(t, int64(0), version)
+		_, localVersion := value.GetValue()
+		assert.Equal(t, int64(0), localVersion)
+		connector.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not swap locally when the remote write fails", func(t *testing.T) {
+		registry, connector, ctx := setupTest("test")
+		value := &jsonValue{registry: registry, key: "test/cordons"}
+
+		lock := &MockLock{}
+		lock.On("Unlock", mock.Anything).Return(nil)
+		connector.On("Lock", mock.Anything, "test/cordons", mock.Anything).Return(lock, nil)
+		connector.On("Get", mock.Anything, ConnectorMainIndex, "test/cordons", "value").
+			Return(nil, common.NewErrRecordNotFound("test/cordons", "value", "mock"))
+		connector.On("Set", mock.Anything, "test/cordons", "value", mock.Anything, (*time.Duration)(nil)).
+			Return(errors.New("write failed"))
+
+		_, swapped, err := value.CompareAndSwap(ctx, 0, json.RawMessage(`{"a":1}`))
+		assert.Error(t, err)
+		assert.False(t, swapped)
+		_, localVersion := value.GetValue()
+		assert.Equal(t, int64(0), localVersion)
+		connector.AssertNotCalled(t, "PublishValue", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestJsonValue_Publish(t *testing.T) {
+	t.Run("writes and publishes the next version without locking", func(t *testing.T) {
+		registry, connector, ctx := setupTest("test")
+		value := &jsonValue{registry: registry, key: "test/head"}
+		value.processNewValue(&sharedValueEnvelope{Version: 4, Value: json.RawMessage(`{"number":1}`)})
+
+		connector.On("Set", mock.Anything, "test/head", "value", []byte(`{"version":5,"value":{"number":2}}`), (*time.Duration)(nil)).
+			Return(nil)
+		connector.On("PublishValue", mock.Anything, "test/head", []byte(`{"version":5,"value":{"number":2}}`)).
+			Return(nil)
+
+		assert.Equal(t, int64(5), value.Publish(ctx, json.RawMessage(`{"number":2}`)))
+		connector.AssertExpectations(t)
+		connector.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything, mock.Anything)
+		connector.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("skips unchanged values", func(t *testing.T) {
+		registry, connector, ctx := setupTest("test")
+		value := &jsonValue{registry: registry, key: "test/head"}
+		value.processNewValue(&sharedValueEnvelope{Version: 4, Value: json.RawMessage(`{"number":1}`)})
+
+		assert.Equal(t, int64(4), value.Publish(ctx, json.RawMessage(`{"number":1}`)))
+		connector.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSharedValue_ReplicatesAcrossRegistries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type quota struct {
+		Limit int `json:"limit"`
+	}
+
+	registries := newSharedMemoryRegistries(t, ctx, 2)
+	writer := NewSharedValue[quota](registries[0], "quota")
+	reader := NewSharedValue[quota](registries[1], "quota")
+
+	observed := make(chan quota, 1)
+	reader.OnValue(func(q quota) { observed <- q })
+
+	// Give the reader's watch time to register before publishing
+	require.Eventually(t, func() bool {
+		return registries[1].initializer.State() == util.StateReady
+	}, time.Second, 10*time.Millisecond)
+
+	swapped, err := writer.CompareAndSwap(ctx, 0, quota{Limit: 10})
+	require.NoError(t, err)
+	require.True(t, swapped)
+
+	select {
+	case q := <-observed:
+		assert.Equal(t, 10, q.Limit)
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected reader to observe the new value")
+	}
+	got, version, err := reader.Get()
+	require.NoError(t, err)
+	assert.Equal(t, quota{Limit: 10}, got)
+	assert.Equal(t, int64(1), version)
+
+	// A writer holding an outdated version must not overwrite newer state
+	swapped, err = writer.CompareAndSwap(ctx, 0, quota{Limit: 20})
+	require.NoError(t, err)
+	assert.False(t, swapped)
+}
+
+func TestSharedValue_UpdateRetriesOnConflict(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registries := newSharedMemoryRegistries(t, ctx, 1)
+	counter := NewSharedValue[int](registries[0], "counter")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := counter.Update(ctx, func(current int) (int, error) { return current + 1, nil })
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	value, _, err := counter.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 20, value)
+}
+
+func TestSharedMap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registries := newSharedMemoryRegistries(t, ctx, 1)
+	disputes := NewSharedMap[string](registries[0], "disputes")
+
+	require.NoError(t, disputes.Set(ctx, "rpc1", "stale-block", 50*time.Millisecond))
+	require.NoError(t, disputes.Set(ctx, "rpc2", "bad-hash", 0))
+
+	v, ok := disputes.Get("rpc1")
+	assert.True(t, ok)
+	assert.Equal(t, "stale-block", v)
+	assert.Equal(t, map[string]string{"rpc1": "stale-block", "rpc2": "bad-hash"}, disputes.Entries())
+
+	time.Sleep(60 * time.Millisecond)
+	_, ok = disputes.Get("rpc1")
+	assert.False(t, ok, "entry must expire after its ttl")
+	assert.Equal(t, map[string]string{"rpc2": "bad-hash"}, disputes.Entries())
+
+	require.NoError(t, disputes.Delete(ctx, "rpc2"))
+	assert.Empty(t, disputes.Entries())
+
+	raw, _ := registries[0].GetJsonValue("disputes").GetValue()
+	assert.JSONEq(t, `{}`, string(raw), "expired entries must be pruned on write")
+}
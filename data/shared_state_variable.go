@@ -15,6 +15,9 @@ import (
 
 type SharedVariable interface {
 	IsStale(staleness time.Duration) bool
+	// Release stops syncing the variable once every holder released it (e.g. its upstream was removed),
+	// a later lookup of the same key starts over.
+	Release()
 }
 
 type CounterInt64SharedVariable interface {
@@ -50,6 +53,14 @@ type counterInt64 struct {
 	ignoreRollbackOf       int64
 	largeRollbackCallbacks []func(localVal, newVal int64)
 	callbackMu             sync.RWMutex
+
+	// Guarded by registry.variablesMu
+	refs int
+	stop chan struct{}
+}
+
+func (c *counterInt64) Release() {
+	c.registry.releaseCounter(c)
 }
 
 func (c *counterInt64) GetValue() int64 {
@@ -14,4 +14,10 @@ module.exports = {
 	cors: {
 		title: "CORS",
 	},
+	firewall: {
+		title: "Firewall",
+	},
+	batch: {
+		title: "Batch requests",
+	},
 };
@@ -8,9 +8,18 @@ module.exports = {
 	"batch": {
 		title: "Batching",
 	},
+	"grpc": {
+		title: "gRPC",
+	},
+	"streaming": {
+		title: "Streaming",
+	},
 	"directives": {
 		title: "Directives",
 	},
+	"capture": {
+		title: "Capture & replay",
+	},
 	"production": {
 		title: "Production",
 	},
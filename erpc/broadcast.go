@@ -0,0 +1,264 @@
+package erpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/architecture/evm"
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	broadcastOutcomeAccepted     = "accepted"
+	broadcastOutcomeAlreadyKnown = "already_known"
+	broadcastOutcomeAlreadyMined = "already_mined"
+	broadcastOutcomeRejected     = "rejected"
+)
+
+type broadcastResult struct {
+	upstream common.Upstream
+	resp     *common.NormalizedResponse
+	outcome  string
+	err      error
+}
+
+func (n *Network) shouldBroadcastTransaction(method string) bool {
+	if method != "eth_sendRawTransaction" || n.cfg == nil || n.cfg.Evm == nil {
+		return false
+	}
+	bc := n.cfg.Evm.TransactionBroadcast
+	return bc != nil && bc.Enabled
+}
+
+// selectBroadcastUpstreams picks top N (score-sorted) upstreams that pass the selection policy,
+// plus any upstream explicitly listed in the broadcast config (e.g. private or MEV-protected endpoints).
+func (n *Network) selectBroadcastUpstreams(ctx context.Context, lg *zerolog.Logger, req *common.NormalizedRequest, upsList []common.Upstream) []common.Upstream {
+	cfg := n.cfg.Evm.TransactionBroadcast
+	var useUpstream string
+	if dr := req.Directives(); dr != nil {
+		useUpstream = dr.UseUpstream
+	}
+
+	selected := make([]common.Upstream, 0, len(upsList))
+	regular := 0
+	for _, u := range upsList {
+		if useUpstream != "" {
+			if match, err := common.WildcardMatch(useUpstream, u.Id()); err != nil || !match {
+				continue
+			}
+		}
+		pinned := false
+		for _, pattern := range cfg.Upstreams {
+			if match, err := common.WildcardMatch(pattern, u.Id()); err == nil && match {
+				pinned = true
+				break
+			}
+		}
+		if !pinned {
+			if cfg.MaxUpstreams > 0 && regular >= cfg.MaxUpstreams {
+				continue
+			}
+			if err := n.acquireSelectionPolicyPermit(ctx, lg, u, req); err != nil {
+				lg.Debug().Err(err).Str("upstreamId", u.Id()).Msg("skipping upstream for transaction broadcast due to selection policy")
+				continue
+			}
+			regular++
+		}
+		selected = append(selected, u)
+	}
+
+	return selected
+}
+
+// broadcastTransaction submits the signed transaction to multiple upstreams in parallel and returns the first
+// successful submission. Remaining submissions are not cancelled so that the transaction still propagates
+// to all selected upstreams (e.g. private mempools) even after the client received the response.
+func (n *Network) broadcastTransaction(ctx context.Context, lg *zerolog.Logger, req *common.NormalizedRequest, upsList []common.Upstream, startTime time.Time) (*common.NormalizedResponse, error) {
+	ctx, span := common.StartSpan(ctx, "Network.BroadcastTransaction")
+	defer span.End()
+
+	selected := n.selectBroadcastUpstreams(ctx, lg, req, upsList)
+	span.SetAttributes(attribute.Int("upstreams.count", len(selected)))
+	if len(selected) == 0 {
+		err := common.NewErrUpstreamsExhausted(req, &req.ErrorsByUpstream, n.projectId, n.networkId, "eth_sendRawTransaction", time.Since(startTime), 0, 0, 0, len(upsList))
+		common.SetTraceSpanError(span, err)
+		return nil, err
+	}
+
+	var txHash string
+	if jrq, err := req.JsonRpcRequest(ctx); err == nil {
+		jrq.RLock()
+		if len(jrq.Params) > 0 {
+			if rawTx, ok := jrq.Params[0].(string); ok {
+				txHash, err = evm.TransactionHashFromRawTransaction(rawTx)
+				if err != nil {
+					lg.Debug().Err(err).Msg("could not compute hash of raw transaction for broadcast")
+				}
+			}
+		}
+		jrq.RUnlock()
+	}
+
+	// Detach from client cancellation but keep the deadline (e.g. network timeout or server maxTimeout)
+	deadline, hasDeadline := ctx.Deadline()
+	if fe := n.getFailsafeExecutor(req); fe != nil && fe.timeout != nil {
+		if d := startTime.Add(*fe.timeout); !hasDeadline || d.Before(deadline) {
+			deadline, hasDeadline = d, true
+		}
+	}
+	bctx := context.WithoutCancel(ctx)
+	cancel := func() {}
+	if hasDeadline {
+		bctx, cancel = context.WithDeadline(bctx, deadline)
+	}
+
+	results := make(chan *broadcastResult, len(selected))
+	wg := sync.WaitGroup{}
+	for _, u := range selected {
+		wg.Add(1)
+		go func(u common.Upstream) {
+			defer wg.Done()
+			defer func() {
+				if rec := recover(); rec != nil {
+					telemetry.MetricUnexpectedPanicTotal.WithLabelValues(
+						"transaction-broadcast",
+						fmt.Sprintf("network:%s upstream:%s", n.networkId, u.Id()),
+						common.ErrorFingerprint(rec),
+					).Inc()
+					results <- &broadcastResult{upstream: u, outcome: broadcastOutcomeRejected, err: fmt.Errorf("panic in transaction broadcast: %v", rec)}
+				}
+			}()
+			results <- n.submitTransaction(bctx, lg, u, req, txHash)
+		}(u)
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+	}()
+
+	for i := 0; i < len(selected); i++ {
+		r := <-results
+		if r.err != nil {
+			req.ErrorsByUpstream.Store(r.upstream, r.err)
+			continue
+		}
+		req.SetLastUpstream(r.upstream)
+		span.SetAttributes(
+			attribute.String("upstream.id", r.upstream.Id()),
+			attribute.String("broadcast.outcome", r.outcome),
+		)
+		// Submissions completing after the first success are not returned, release their responses
+		go func(remaining int) {
+			for j := 0; j < remaining; j++ {
+				if late := <-results; late.resp != nil {
+					late.resp.Release()
+				}
+			}
+		}(len(selected) - i - 1)
+		return r.resp, nil
+	}
+
+	err := common.NewErrUpstreamsExhausted(req, &req.ErrorsByUpstream, n.projectId, n.networkId, "eth_sendRawTransaction", time.Since(startTime), len(selected), 0, 0, len(upsList))
+	common.SetTraceSpanError(span, err)
+	return nil, err
+}
+
+// submitTransaction sends the transaction to a single upstream and classifies the outcome,
+// "already known" and "nonce too low" (when the same transaction hash exists) are considered accepted.
+func (n *Network) submitTransaction(ctx context.Context, lg *zerolog.Logger, u common.Upstream, req *common.NormalizedRequest, txHash string) *broadcastResult {
+	ctx, span := common.StartDetailSpan(ctx, "Network.BroadcastTransaction.Submit")
+	defer span.End()
+	span.SetAttributes(attribute.String("upstream.id", u.Id()))
+
+	ulg := lg.With().Str("upstreamId", u.Id()).Str("txHash", txHash).Logger()
+
+	// Each upstream receives its own copy of the request so that concurrent submissions do not race on shared state
+	var ureq *common.NormalizedRequest
+	if body := req.Body(); len(body) > 0 {
+		ureq = common.NewNormalizedRequest(append([]byte(nil), body...))
+	} else {
+		jrq, err := req.JsonRpcRequest(ctx)
+		if err != nil {
+			return &broadcastResult{upstream: u, outcome: broadcastOutcomeRejected, err: err}
+		}
+		jrq.RLock()
+		body, err := common.SonicCfg.Marshal(jrq)
+		jrq.RUnlock()
+		if err != nil {
+			return &broadcastResult{upstream: u, outcome: broadcastOutcomeRejected, err: err}
+		}
+		ureq = common.NewNormalizedRequest(body)
+	}
+	if dr := req.Directives(); dr != nil {
+		ureq.SetDirectives(dr.Clone())
+	}
+	ureq.SetNetwork(n)
+
+	result := &broadcastResult{upstream: u}
+	resp, err := n.doForward(ctx, u, ureq, false)
+	switch {
+	case err == nil && resp != nil:
+		if e := n.normalizeResponse(ctx, req, resp); e != nil {
+			resp.Release()
+			result.outcome, result.err = broadcastOutcomeRejected, e
+			break
+		}
+		resp.WithRequest(req).SetUpstream(u)
+		result.outcome, result.resp = broadcastOutcomeAccepted, resp
+	case evm.IsTransactionAlreadyKnownError(err):
+		result.outcome = broadcastOutcomeAlreadyKnown
+	case evm.IsNonceTooLowError(err) && txHash != "" && n.isTransactionKnownByUpstream(ctx, u, txHash):
+		result.outcome = broadcastOutcomeAlreadyMined
+	default:
+		if err == nil {
+			err = common.NewErrEndpointMissingData(fmt.Errorf("upstream returned no response for eth_sendRawTransaction"), u)
+		}
+		result.outcome, result.err = broadcastOutcomeRejected, err
+	}
+
+	if result.err == nil && result.resp == nil {
+		if txHash == "" {
+			result.outcome, result.err = broadcastOutcomeRejected, err
+		} else {
+			jrr, e := common.NewJsonRpcResponse(req.ID(), txHash, nil)
+			if e != nil {
+				result.outcome, result.err = broadcastOutcomeRejected, e
+			} else {
+				result.resp = common.NewNormalizedResponse().WithRequest(req).WithJsonRpcResponse(jrr).SetUpstream(u)
+			}
+		}
+	}
+
+	telemetry.MetricNetworkTransactionBroadcastTotal.WithLabelValues(n.projectId, n.networkId, u.Id(), result.outcome).Inc()
+	span.SetAttributes(attribute.String("broadcast.outcome", result.outcome))
+	if result.err != nil {
+		common.SetTraceSpanError(span, result.err)
+		ulg.Debug().Err(result.err).Str("outcome", result.outcome).Msg("upstream rejected broadcasted transaction")
+	} else {
+		ulg.Debug().Err(err).Str("outcome", result.outcome).Msg("upstream accepted broadcasted transaction")
+	}
+
+	return result
+}
+
+// isTransactionKnownByUpstream checks if the upstream already has the transaction (in mempool or mined),
+// used to distinguish "nonce too low" due to the same transaction vs. a different one using the same nonce.
+func (n *Network) isTransactionKnownByUpstream(ctx context.Context, u common.Upstream, txHash string) bool {
+	jrq, err := evm.BuildGetTransactionByHashRequest(txHash)
+	if err != nil {
+		return false
+	}
+	nq := common.NewNormalizedRequestFromJsonRpcRequest(jrq)
+	nq.SetNetwork(n)
+	resp, err := u.Forward(ctx, nq, false)
+	if err != nil || resp == nil {
+		return false
+	}
+	defer resp.Release()
+	return !resp.IsResultEmptyish(ctx)
+}
@@ -0,0 +1,344 @@
+package erpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog"
+)
+
+// CaptureRecord is a sampled request with its outcome, stored as one JSON line so that it can be
+// replayed against another config (see Replay).
+type CaptureRecord struct {
+	Id         string          `json:"id"`
+	Timestamp  int64           `json:"timestamp"` // unix milliseconds
+	ProjectId  string          `json:"projectId"`
+	NetworkId  string          `json:"networkId"`
+	Method     string          `json:"method"`
+	Finality   string          `json:"finality"`
+	UpstreamId string          `json:"upstreamId,omitempty"`
+	LatencyMs  float64         `json:"latencyMs"`
+	Request    json.RawMessage `json:"request"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	// ResultHash is the canonical hash of the original result (before redaction), used to compare replays
+	ResultHash string        `json:"resultHash,omitempty"`
+	ResultSize int           `json:"resultSize,omitempty"`
+	Error      *CaptureError `json:"error,omitempty"`
+	// Redacted is set when request params were redacted, replaying such requests is not meaningful
+	Redacted bool `json:"redacted,omitempty"`
+
+	// reserved is the size of the copied result accounted in the pending bytes of the capture
+	reserved int64
+}
+
+// CaptureError is the json-rpc error returned to the client
+type CaptureError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newCaptureError(err error) *CaptureError {
+	err = translateForwardError(err)
+	jre := &common.ErrJsonRpcExceptionInternal{}
+	if errors.As(err, &jre) {
+		return &CaptureError{Code: int(jre.NormalizedCode()), Message: jre.Message}
+	}
+	return &CaptureError{Code: int(common.JsonRpcErrorServerSideException), Message: err.Error()}
+}
+
+// Capture samples forwarded requests of a project and writes them in the background, so that
+// request latency is not affected. Records are dropped when the writer can not keep up, and results
+// are only copied while the ones waiting to be written fit in bufferSize x maxResultSize bytes.
+type Capture struct {
+	logger          *zerolog.Logger
+	projectId       string
+	cfg             *common.CaptureConfig
+	sink            *fileCaptureSink
+	records         chan *CaptureRecord
+	seq             atomic.Uint64
+	pendingBytes    atomic.Int64
+	maxPendingBytes int64
+}
+
+func NewCapture(appCtx context.Context, logger *zerolog.Logger, projectId string, cfg *common.CaptureConfig) (*Capture, error) {
+	lg := logger.With().Str("component", "capture").Logger()
+
+	sink, err := newFileCaptureSink(cfg.File)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Capture{
+		logger:          &lg,
+		projectId:       projectId,
+		cfg:             cfg,
+		sink:            sink,
+		records:         make(chan *CaptureRecord, cfg.BufferSize),
+		maxPendingBytes: int64(cfg.BufferSize) * int64(cfg.MaxResultSize),
+	}
+	go c.run(appCtx)
+
+	return c, nil
+}
+
+// ShouldSample decides whether a request of this method is captured, it is safe on a nil capture.
+func (c *Capture) ShouldSample(method string) bool {
+	if c == nil || c.cfg.SampleRate <= 0 {
+		return false
+	}
+	if c.cfg.SampleRate < 1 && rand.Float64() >= c.cfg.SampleRate { // #nosec G404
+		return false
+	}
+	match, err := common.WildcardMatch(c.cfg.Method, method)
+	return err == nil && match
+}
+
+// Record copies the request and its outcome synchronously (the response is released once it is
+// written to the client) and hands it off to the background writer, which hashes, redacts and
+// writes it.
+func (c *Capture) Record(
+	ctx context.Context,
+	networkId string,
+	method string,
+	nq *common.NormalizedRequest,
+	resp *common.NormalizedResponse,
+	err error,
+	latency time.Duration,
+	finality common.DataFinalityState,
+) {
+	// Skip copying anything when the record would be dropped anyway
+	if len(c.records) >= cap(c.records) {
+		telemetry.MetricCaptureRecordsTotal.WithLabelValues(c.projectId, "dropped").Inc()
+		return
+	}
+
+	record := &CaptureRecord{
+		Id:        fmt.Sprintf("%d-%d", time.Now().UnixNano(), c.seq.Add(1)),
+		Timestamp: time.Now().UnixMilli(),
+		ProjectId: c.projectId,
+		NetworkId: networkId,
+		Method:    method,
+		Finality:  finality.String(),
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+		Request:   bytes.Clone(nq.Body()),
+	}
+
+	if err != nil {
+		record.Error = newCaptureError(err)
+	} else if resp != nil {
+		if resp.FromCache() {
+			record.UpstreamId = "<cache>"
+		} else {
+			record.UpstreamId = resp.UpstreamId()
+		}
+		jrr, jerr := resp.JsonRpcResponse(ctx)
+		if jerr != nil {
+			record.Error = newCaptureError(jerr)
+		} else if jrr != nil && jrr.Error != nil {
+			record.Error = &CaptureError{Code: jrr.Error.Code, Message: jrr.Error.Message}
+		} else if jrr != nil && !jrr.IsStreaming() {
+			// Streamed results are never buffered for capture, only their metadata is recorded
+			if size, serr := jrr.Size(ctx); serr == nil {
+				record.ResultSize = size
+				// Results bigger than maxResultSize are copied too so that their hash is computed in the
+				// background, unless the pending results already use up the budget.
+				if c.reserve(int64(size)) {
+					record.reserved = int64(size)
+					buf := bytes.NewBuffer(make([]byte, 0, size))
+					if _, werr := jrr.WriteResultTo(buf, false); werr == nil {
+						record.Result = buf.Bytes()
+					}
+				}
+			}
+		}
+	}
+
+	select {
+	case c.records <- record:
+	default:
+		c.pendingBytes.Add(-record.reserved)
+		telemetry.MetricCaptureRecordsTotal.WithLabelValues(c.projectId, "dropped").Inc()
+	}
+}
+
+func (c *Capture) reserve(size int64) bool {
+	if c.pendingBytes.Add(size) > c.maxPendingBytes {
+		c.pendingBytes.Add(-size)
+		return false
+	}
+	return true
+}
+
+func (c *Capture) run(appCtx context.Context) {
+	for {
+		select {
+		case <-appCtx.Done():
+			if err := c.sink.close(); err != nil {
+				c.logger.Warn().Err(err).Msg("failed to close capture sink")
+			}
+			return
+		case record := <-c.records:
+			err := c.write(record)
+			c.pendingBytes.Add(-record.reserved)
+			if err != nil {
+				telemetry.MetricCaptureRecordsTotal.WithLabelValues(c.projectId, "failed").Inc()
+				c.logger.Warn().Err(err).Str("captureId", record.Id).Msg("failed to write capture record")
+			} else {
+				telemetry.MetricCaptureRecordsTotal.WithLabelValues(c.projectId, "written").Inc()
+			}
+		}
+	}
+}
+
+func (c *Capture) write(record *CaptureRecord) error {
+	if len(record.Result) > 0 {
+		hash, err := common.CanonicalHashFromReader(bytes.NewReader(record.Result))
+		if err == nil {
+			record.ResultHash = hash
+		}
+		if len(record.Result) > c.cfg.MaxResultSize {
+			record.Result = nil
+		}
+	}
+	if err := c.redact(record); err != nil {
+		return err
+	}
+	line, err := common.SonicCfg.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return c.sink.write(line)
+}
+
+func (c *Capture) redact(record *CaptureRecord) error {
+	var request, result interface{}
+	requestChanged, resultChanged := false, false
+	for _, rule := range c.cfg.Redact {
+		if match, err := common.WildcardMatch(rule.Method, record.Method); err != nil || !match {
+			continue
+		}
+		for _, path := range rule.Paths {
+			parts := strings.Split(path, ".")
+			switch parts[0] {
+			case "params":
+				if request == nil {
+					if err := common.SonicCfg.Unmarshal(record.Request, &request); err != nil {
+						return fmt.Errorf("failed to parse captured request for redaction: %w", err)
+					}
+				}
+				if redactPath(request, parts) {
+					requestChanged = true
+				}
+			case "result":
+				if len(record.Result) == 0 {
+					continue
+				}
+				if result == nil {
+					if err := common.SonicCfg.Unmarshal(record.Result, &result); err != nil {
+						return fmt.Errorf("failed to parse captured result for redaction: %w", err)
+					}
+				}
+				if len(parts) == 1 {
+					result = util.RedactValue(string(record.Result))
+					resultChanged = true
+				} else if redactPath(result, parts[1:]) {
+					resultChanged = true
+				}
+			}
+		}
+	}
+	if requestChanged {
+		b, err := common.SonicCfg.Marshal(request)
+		if err != nil {
+			return err
+		}
+		record.Request = b
+		record.Redacted = true
+	}
+	if resultChanged {
+		b, err := common.SonicCfg.Marshal(result)
+		if err != nil {
+			return err
+		}
+		record.Result = b
+	}
+	return nil
+}
+
+// redactPath replaces values under the path (object keys, array indexes or "*" for every item)
+// with their redacted hash, and returns true when anything was replaced.
+func redactPath(node interface{}, parts []string) bool {
+	if len(parts) == 0 {
+		return false
+	}
+	key, rest := parts[0], parts[1:]
+	replaced := false
+	apply := func(get func() interface{}, set func(interface{})) {
+		if len(rest) == 0 {
+			raw, err := common.SonicCfg.Marshal(get())
+			if err != nil {
+				return
+			}
+			set(util.RedactValue(string(raw)))
+			replaced = true
+		} else if redactPath(get(), rest) {
+			replaced = true
+		}
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k := range n {
+			if key == "*" || key == k {
+				k := k
+				apply(func() interface{} { return n[k] }, func(v interface{}) { n[k] = v })
+			}
+		}
+	case []interface{}:
+		for i := range n {
+			if key == "*" || key == strconv.Itoa(i) {
+				i := i
+				apply(func() interface{} { return n[i] }, func(v interface{}) { n[i] = v })
+			}
+		}
+	}
+	return replaced
+}
+
+type fileCaptureSink struct {
+	file *os.File
+}
+
+func newFileCaptureSink(path string) (*fileCaptureSink, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create capture directory: %w", err)
+		}
+	}
+	f, err := os.OpenFile(filepath.Clean(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %w", err)
+	}
+	return &fileCaptureSink{file: f}, nil
+}
+
+func (s *fileCaptureSink) write(line []byte) error {
+	_, err := s.file.Write(append(line, '\n'))
+	return err
+}
+
+func (s *fileCaptureSink) close() error {
+	return s.file.Close()
+}
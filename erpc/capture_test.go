@@ -0,0 +1,231 @@
+package erpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/h2non/gock"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapture_RedactPath(t *testing.T) {
+	var node interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"method":"eth_call","params":[{"from":"0xabc","to":"0xdef"},"latest"]}`), &node))
+
+	assert.True(t, redactPath(node, []string{"params", "0", "from"}))
+	assert.False(t, redactPath(node, []string{"params", "5"}))
+
+	params := node.(map[string]interface{})["params"].([]interface{})
+	call := params[0].(map[string]interface{})
+	assert.Equal(t, util.RedactValue(`"0xabc"`), call["from"])
+	assert.Equal(t, "0xdef", call["to"])
+	assert.Equal(t, "latest", params[1])
+
+	var logs interface{}
+	require.NoError(t, json.Unmarshal([]byte(`[{"data":"0x01"},{"data":"0x02"}]`), &logs))
+	assert.True(t, redactPath(logs, []string{"*", "data"}))
+	for _, l := range logs.([]interface{}) {
+		assert.True(t, strings.HasPrefix(l.(map[string]interface{})["data"].(string), "redacted="))
+	}
+}
+
+func newCaptureTestConfig(captureFile string) *common.Config {
+	return &common.Config{
+		Server: &common.ServerConfig{
+			MaxTimeout: common.Duration(5 * time.Second).Ptr(),
+		},
+		Projects: []*common.ProjectConfig{
+			{
+				Id: "test_project",
+				Networks: []*common.NetworkConfig{
+					{
+						Architecture: common.ArchitectureEvm,
+						Evm: &common.EvmNetworkConfig{
+							ChainId: 1,
+						},
+					},
+				},
+				Upstreams: []*common.UpstreamConfig{
+					{
+						Id:       "rpc1",
+						Type:     common.UpstreamTypeEvm,
+						Endpoint: "https://rpc1.localhost",
+						Evm: &common.EvmUpstreamConfig{
+							ChainId: 1,
+						},
+					},
+				},
+				Capture: &common.CaptureConfig{
+					SampleRate: 1,
+					Method:     "eth_getBalance",
+					File:       captureFile,
+					Redact: []*common.CaptureRedactRuleConfig{
+						{
+							Method: "eth_getBalance",
+							Paths:  []string{"params.1"},
+						},
+					},
+				},
+			},
+		},
+		RateLimiters: &common.RateLimiterConfig{},
+	}
+}
+
+func readCaptureRecords(t *testing.T, path string, expected int) []*CaptureRecord {
+	t.Helper()
+	var records []*CaptureRecord
+	require.Eventually(t, func() bool {
+		f, err := os.Open(path)
+		if err != nil {
+			return false
+		}
+		defer f.Close()
+		records = nil
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			record := &CaptureRecord{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), record))
+			records = append(records, record)
+		}
+		return len(records) >= expected
+	}, 2*time.Second, 20*time.Millisecond)
+	return records
+}
+
+func TestCapture_WritesSampledRequestsToFile(t *testing.T) {
+	util.ResetGock()
+	defer util.ResetGock()
+	util.SetupMocksForEvmStatePoller()
+	defer util.AssertNoPendingMocks(t, 0)
+
+	gock.New("https://rpc1.localhost").
+		Post("/").
+		Filter(func(request *http.Request) bool {
+			return strings.Contains(util.SafeReadBody(request), "eth_getBalance")
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  "0x22",
+		})
+
+	captureFile := filepath.Join(t.TempDir(), "capture.jsonl")
+	cfg := newCaptureTestConfig(captureFile)
+	require.NoError(t, cfg.Projects[0].Capture.SetDefaults())
+
+	sendRequest, _, _, shutdown, _ := createServerTestFixtures(cfg, t)
+	defer shutdown()
+
+	statusCode, body := sendRequest(`{"jsonrpc":"2.0","method":"eth_getBalance","params":["0x1111111111111111111111111111111111111111","0x1"],"id":1}`, nil, nil)
+	require.Equal(t, http.StatusOK, statusCode, body)
+
+	records := readCaptureRecords(t, captureFile, 1)
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, "test_project", record.ProjectId)
+	assert.Equal(t, "evm:1", record.NetworkId)
+	assert.Equal(t, "eth_getBalance", record.Method)
+	assert.Equal(t, "rpc1", record.UpstreamId)
+	assert.Nil(t, record.Error)
+	assert.JSONEq(t, `"0x22"`, string(record.Result))
+	assert.NotEmpty(t, record.ResultHash)
+	assert.Greater(t, record.LatencyMs, float64(0))
+	assert.True(t, record.Redacted)
+	assert.Contains(t, string(record.Request), "0x1111111111111111111111111111111111111111")
+	assert.NotContains(t, string(record.Request), `"0x1"`)
+}
+
+func TestCapture_HashesResultsBiggerThanMaxResultSize(t *testing.T) {
+	captureFile := filepath.Join(t.TempDir(), "capture.jsonl")
+	cfg := &common.CaptureConfig{File: captureFile, MaxResultSize: 16}
+	require.NoError(t, cfg.SetDefaults())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, err := NewCapture(ctx, &log.Logger, "test_project", cfg)
+	require.NoError(t, err)
+
+	result := `[{"address":"0x1111111111111111111111111111111111111111","data":"0x22"}]`
+	expectedHash, err := (&common.JsonRpcResponse{Result: []byte(result)}).CanonicalHash()
+	require.NoError(t, err)
+
+	c.pendingBytes.Add(int64(len(result)))
+	c.records <- &CaptureRecord{Id: "1", Method: "eth_getLogs", Request: []byte(`{}`), Result: []byte(result), reserved: int64(len(result))}
+
+	records := readCaptureRecords(t, captureFile, 1)
+	assert.Empty(t, records[0].Result)
+	assert.Equal(t, expectedHash, records[0].ResultHash)
+	assert.Eventually(t, func() bool { return c.pendingBytes.Load() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestReplay_ComparesCapturedResults(t *testing.T) {
+	util.ResetGock()
+	defer util.ResetGock()
+	util.SetupMocksForEvmStatePoller()
+
+	gock.New("https://rpc1.localhost").
+		Post("/").
+		Persist().
+		Filter(func(request *http.Request) bool {
+			return strings.Contains(util.SafeReadBody(request), "eth_getBalance")
+		}).
+		Reply(200).
+		JSON(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  "0x22",
+		})
+
+	hash, err := (&common.JsonRpcResponse{Result: []byte(`"0x22"`)}).CanonicalHash()
+	require.NoError(t, err)
+
+	records := []*CaptureRecord{
+		{Id: "1", ProjectId: "test_project", NetworkId: "evm:1", Method: "eth_getBalance", LatencyMs: 10, ResultHash: hash,
+			Request: json.RawMessage(`{"jsonrpc":"2.0","method":"eth_getBalance","params":["0x1111111111111111111111111111111111111111","0x1"],"id":1}`)},
+		{Id: "2", ProjectId: "test_project", NetworkId: "evm:1", Method: "eth_getBalance", LatencyMs: 20, ResultHash: "stale",
+			Request: json.RawMessage(`{"jsonrpc":"2.0","method":"eth_getBalance","params":["0x2222222222222222222222222222222222222222","0x1"],"id":2}`)},
+		{Id: "3", ProjectId: "test_project", NetworkId: "evm:1", Method: "eth_getBalance", LatencyMs: 30,
+			Request: json.RawMessage(`{"jsonrpc":"2.0","method":"eth_getBalance","params":["0x3333333333333333333333333333333333333333","0x1"],"id":3}`)},
+		{Id: "4", ProjectId: "test_project", NetworkId: "evm:1", Method: "eth_getBalance", Redacted: true,
+			Request: json.RawMessage(`{"jsonrpc":"2.0","method":"eth_getBalance","params":["0x4444444444444444444444444444444444444444","redacted=abcde"],"id":4}`)},
+	}
+	captureFile := filepath.Join(t.TempDir(), "capture.jsonl")
+	f, err := os.Create(captureFile)
+	require.NoError(t, err)
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		require.NoError(t, err)
+		_, err = f.Write(append(line, '\n'))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := newCaptureTestConfig(filepath.Join(t.TempDir(), "unused.jsonl"))
+	report, err := Replay(ctx, cfg, log.Logger, &ReplayOptions{File: captureFile, Concurrency: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 1, report.Matched)
+	assert.Equal(t, 1, report.Mismatched)
+	assert.Equal(t, 1, report.Unverified)
+	assert.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Mismatches, 1)
+	assert.Equal(t, "2", report.Mismatches[0].Id)
+	assert.Equal(t, "result stale", report.Mismatches[0].Captured)
+	assert.Equal(t, float64(30), report.CapturedLatency.Max)
+	assert.Nil(t, cfg.Projects[0].Capture, "capture must be disabled while replaying")
+}
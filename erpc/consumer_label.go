@@ -0,0 +1,96 @@
+package erpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/erpc/erpc/auth"
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
+)
+
+const (
+	consumerLabelContextKey common.ContextKey = "consumerLabel"
+	maxConsumerLabelLength                    = 64
+)
+
+// consumerLabeler resolves the "consumer" label of network request metrics from the auth identity,
+// a header or the User-Agent family, bounded to the most active consumers. A nil labeler leaves the label empty.
+type consumerLabeler struct {
+	source  common.ConsumerLabelSource
+	header  string
+	limiter *telemetry.ConsumerLabelLimiter
+}
+
+func newConsumerLabeler(cfg *common.MetricsConfig) *consumerLabeler {
+	if cfg == nil || cfg.ConsumerLabel == nil || !cfg.ConsumerLabel.Enabled {
+		return nil
+	}
+	limiter := telemetry.NewConsumerLabelLimiter(cfg.ConsumerLabel.MaxValues, cfg.ConsumerLabel.RankingWindow.Duration())
+	limiter.OnEvict(telemetry.DeleteConsumerSeries)
+	return &consumerLabeler{
+		source:  cfg.ConsumerLabel.Source,
+		header:  cfg.ConsumerLabel.Header,
+		limiter: limiter,
+	}
+}
+
+// withConsumerLabel attaches the consumer label of the request to the context, read when recording metrics.
+func (c *consumerLabeler) withConsumerLabel(ctx context.Context, grant *auth.Grant, headers http.Header) context.Context {
+	if c == nil {
+		return ctx
+	}
+	var consumer string
+	switch c.source {
+	case common.ConsumerLabelSourceAuth:
+		if grant != nil {
+			consumer = grant.Owner
+		}
+	case common.ConsumerLabelSourceHeader:
+		consumer = strings.TrimSpace(headers.Get(c.header))
+	case common.ConsumerLabelSourceUserAgent:
+		consumer = userAgentFamily(headers.Get("User-Agent"))
+	}
+	if len(consumer) > maxConsumerLabelLength {
+		consumer = consumer[:maxConsumerLabelLength]
+	}
+	return context.WithValue(ctx, consumerLabelContextKey, &consumerLabel{
+		value:   c.limiter.Label(consumer),
+		limiter: c.limiter,
+	})
+}
+
+type consumerLabel struct {
+	value   string
+	limiter *telemetry.ConsumerLabelLimiter
+}
+
+// observeConsumerMetric calls write with the consumer label of the request, which is empty when the label is
+// disabled. The label is checked again at write time so that consumers evicted while the request was in flight
+// are reported as "other" instead of recreating their deleted series.
+func observeConsumerMetric(ctx context.Context, write func(consumer string)) {
+	v, ok := ctx.Value(consumerLabelContextKey).(*consumerLabel)
+	if !ok {
+		write("")
+		return
+	}
+	v.limiter.Observe(v.value, write)
+}
+
+// userAgentFamily reduces a User-Agent to its first product name without version (e.g. "viem/2.21.0 ..." -> "viem"),
+// so the label does not grow with every client release. All browsers are reported as "browser".
+func userAgentFamily(ua string) string {
+	ua = strings.TrimSpace(ua)
+	if ua == "" {
+		return ""
+	}
+	if strings.HasPrefix(ua, "Mozilla/") {
+		return "browser"
+	}
+	product := ua
+	if i := strings.IndexAny(product, " /("); i >= 0 {
+		product = product[:i]
+	}
+	return strings.ToLower(product)
+}
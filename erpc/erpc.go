@@ -3,12 +3,15 @@ package erpc
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/erpc/erpc/architecture/evm"
 	"github.com/erpc/erpc/auth"
 	"github.com/erpc/erpc/clients"
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/consensus"
 	"github.com/erpc/erpc/data"
 	"github.com/erpc/erpc/thirdparty"
 	"github.com/erpc/erpc/upstream"
@@ -19,6 +22,7 @@ type ERPC struct {
 	cfg               *common.Config
 	projectsRegistry  *ProjectsRegistry
 	adminAuthRegistry *auth.AuthRegistry
+	consumerLabels    *consumerLabeler
 	logger            *zerolog.Logger
 }
 
@@ -65,6 +69,8 @@ func NewERPC(
 		}
 	}
 	vendorsRegistry := thirdparty.NewVendorsRegistry()
+	vendorsRegistry.SetSharedState(sharedState)
+	vendorsRegistry.SetProxyPools(proxyPoolRegistry)
 	projectRegistry, err := NewProjectsRegistry(
 		appCtx,
 		logger,
@@ -81,7 +87,7 @@ func NewERPC(
 
 	var adminAuthRegistry *auth.AuthRegistry
 	if cfg.Admin != nil && cfg.Admin.Auth != nil {
-		adminAuthRegistry, err = auth.NewAuthRegistry(logger, "admin", cfg.Admin.Auth, rateLimitersRegistry)
+		adminAuthRegistry, err = auth.NewAuthRegistry(appCtx, logger, "admin", cfg.Admin.Auth, rateLimitersRegistry)
 		if err != nil {
 			return nil, err
 		}
@@ -101,6 +107,7 @@ func NewERPC(
 		cfg:               cfg,
 		projectsRegistry:  projectRegistry,
 		adminAuthRegistry: adminAuthRegistry,
+		consumerLabels:    newConsumerLabeler(cfg.Metrics),
 		logger:            logger,
 	}, nil
 }
@@ -116,7 +123,7 @@ func (e *ERPC) Bootstrap(ctx context.Context) error {
 
 func (e *ERPC) AdminAuthenticate(ctx context.Context, method string, ap *auth.AuthPayload) error {
 	if e.adminAuthRegistry != nil {
-		err := e.adminAuthRegistry.Authenticate(ctx, method, ap)
+		_, err := e.adminAuthRegistry.Authenticate(ctx, method, ap)
 		if err != nil {
 			return err
 		}
@@ -140,8 +147,9 @@ func (e *ERPC) AdminHandleRequest(ctx context.Context, nq *common.NormalizedRequ
 			Id string `json:"id"`
 		}
 		type taxonomyNetwork struct {
-			Id        string              `json:"id"`
-			Upstreams []*taxonomyUpstream `json:"upstreams"`
+			Id            string              `json:"id"`
+			Upstreams     []*taxonomyUpstream `json:"upstreams"`
+			VendorMethods []string            `json:"vendorMethods"`
 		}
 		type taxonomyProject struct {
 			Id       string             `json:"id"`
@@ -156,13 +164,22 @@ func (e *ERPC) AdminHandleRequest(ctx context.Context, nq *common.NormalizedRequ
 			networks := []*taxonomyNetwork{}
 			for _, n := range p.GetNetworks() {
 				ntw := &taxonomyNetwork{
-					Id:        n.Id(),
-					Upstreams: []*taxonomyUpstream{},
+					Id:            n.Id(),
+					Upstreams:     []*taxonomyUpstream{},
+					VendorMethods: []string{},
 				}
 				upstreams := n.upstreamsRegistry.GetNetworkUpstreams(ctx, n.Id())
 				for _, u := range upstreams {
 					ntw.Upstreams = append(ntw.Upstreams, &taxonomyUpstream{Id: u.Id()})
+					if v, ok := u.Vendor().(common.VendorWithProprietaryMethods); ok {
+						for _, m := range v.ProprietaryMethods() {
+							if !slices.Contains(ntw.VendorMethods, m) {
+								ntw.VendorMethods = append(ntw.VendorMethods, m)
+							}
+						}
+					}
 				}
+				sort.Strings(ntw.VendorMethods)
 				networks = append(networks, ntw)
 			}
 			result.Projects = append(result.Projects, &taxonomyProject{
@@ -233,6 +250,48 @@ func (e *ERPC) AdminHandleRequest(ctx context.Context, nq *common.NormalizedRequ
 			return nil, err
 		}
 		return common.NewNormalizedResponse().WithJsonRpcResponse(jrrs), nil
+	case "erpc_consensusDisputes":
+		jrr, err := nq.JsonRpcRequest()
+		if err != nil {
+			return nil, err
+		}
+		if len(jrr.Params) == 0 {
+			return nil, common.NewErrInvalidRequest(fmt.Errorf("project id (params[0]) is required"))
+		}
+		pid, ok := jrr.Params[0].(string)
+		if !ok {
+			return nil, common.NewErrInvalidRequest(fmt.Errorf("project id (params[0]) must be a string"))
+		}
+		p, err := e.GetProject(pid)
+		if err != nil {
+			return nil, err
+		}
+		if p.disputeLog == nil {
+			return nil, common.NewErrInvalidRequest(fmt.Errorf("dispute log is not configured for project %s", pid))
+		}
+		filter := &consensus.DisputeFilter{}
+		if len(jrr.Params) > 1 && jrr.Params[1] != nil {
+			raw, err := common.SonicCfg.Marshal(jrr.Params[1])
+			if err != nil {
+				return nil, common.NewErrInvalidRequest(fmt.Errorf("filter (params[1]) is invalid: %w", err))
+			}
+			if err := common.SonicCfg.Unmarshal(raw, filter); err != nil {
+				return nil, common.NewErrInvalidRequest(fmt.Errorf("filter (params[1]) must be an object of networkId, method, upstreamId and limit: %w", err))
+			}
+		}
+		disputes, err := p.disputeLog.List(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		jrrs, err := common.NewJsonRpcResponse(
+			jrr.ID,
+			disputes,
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return common.NewNormalizedResponse().WithJsonRpcResponse(jrrs), nil
 	default:
 		return nil, common.NewErrEndpointUnsupported(
 			fmt.Errorf("admin method %s is not supported", method),
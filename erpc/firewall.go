@@ -0,0 +1,192 @@
+package erpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/telemetry"
+	"github.com/rs/zerolog"
+)
+
+// Firewall evaluates declarative allow/deny rules of a project against incoming requests, so that
+// abusive patterns (e.g. eth_getLogs over all contracts) are rejected before reaching upstreams.
+type Firewall struct {
+	projectId string
+	logger    *zerolog.Logger
+	rules     []*common.FirewallRuleConfig
+}
+
+func NewFirewall(logger *zerolog.Logger, projectId string, cfg *common.FirewallConfig) *Firewall {
+	lg := logger.With().Str("component", "firewall").Logger()
+	return &Firewall{
+		projectId: projectId,
+		logger:    &lg,
+		rules:     cfg.Rules,
+	}
+}
+
+// Evaluate returns an error when the first matching rule denies the request.
+func (f *Firewall) Evaluate(ctx context.Context, network common.Network, nq *common.NormalizedRequest) error {
+	if f == nil || len(f.rules) == 0 {
+		return nil
+	}
+
+	jrq, err := nq.JsonRpcRequest(ctx)
+	if err != nil {
+		return err
+	}
+	jrq.RLock()
+	method := jrq.Method
+	params := jrq.Params
+	jrq.RUnlock()
+
+	for _, rule := range f.rules {
+		match, err := f.matchRule(ctx, rule, network, method, params)
+		if err != nil {
+			f.logger.Warn().Err(err).Str("rule", rule.Id).Str("method", method).Msg("failed to evaluate firewall rule, skipping it")
+			continue
+		}
+		if !match {
+			continue
+		}
+
+		telemetry.MetricFirewallRuleHitsTotal.WithLabelValues(f.projectId, network.Id(), method, rule.Id, string(rule.Action)).Inc()
+		if rule.Action == common.FirewallActionAllow {
+			return nil
+		}
+		f.logger.Debug().Str("rule", rule.Id).Str("networkId", network.Id()).Str("method", method).Msg("request blocked by firewall rule")
+		return common.NewErrRequestBlockedByFirewall(f.projectId, rule.Id, rule.Message)
+	}
+
+	return nil
+}
+
+func (f *Firewall) matchRule(ctx context.Context, rule *common.FirewallRuleConfig, network common.Network, method string, params []interface{}) (bool, error) {
+	if match, err := common.WildcardMatch(rule.Network, network.Id()); err != nil || !match {
+		return false, err
+	}
+	if match, err := common.WildcardMatch(rule.Method, method); err != nil || !match {
+		return false, err
+	}
+	if match, err := data.MatchParams(rule.Params, params); err != nil || !match {
+		return false, err
+	}
+
+	// Conditions below inspect the first param as a call object or log filter
+	var obj map[string]interface{}
+	if len(params) > 0 {
+		obj, _ = params[0].(map[string]interface{})
+	}
+
+	if len(rule.Contracts) > 0 {
+		match, err := matchContracts(rule.Contracts, obj)
+		if err != nil || !match {
+			return false, err
+		}
+	}
+	if rule.MissingAddress {
+		if obj == nil || len(filterAddresses(obj["address"])) > 0 {
+			return false, nil
+		}
+	}
+	if rule.BlockRangeExceeds > 0 {
+		blockRange, ok := filterBlockRange(ctx, network, obj)
+		if !ok || blockRange <= rule.BlockRangeExceeds {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func matchContracts(patterns []string, obj map[string]interface{}) (bool, error) {
+	if obj == nil {
+		return false, nil
+	}
+	var addresses []string
+	if to, ok := obj["to"].(string); ok && to != "" {
+		addresses = append(addresses, to)
+	} else {
+		addresses = filterAddresses(obj["address"])
+	}
+	for _, address := range addresses {
+		address = strings.ToLower(address)
+		for _, pattern := range patterns {
+			match, err := common.WildcardMatch(strings.ToLower(pattern), address)
+			if err != nil {
+				return false, err
+			}
+			if match {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// filterAddresses normalizes the "address" field of a log filter which can be a single address or a list.
+func filterAddresses(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		if t == "" {
+			return nil
+		}
+		return []string{t}
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// filterBlockRange returns the number of blocks a log filter spans, filters by blockHash span a single block.
+func filterBlockRange(ctx context.Context, network common.Network, filter map[string]interface{}) (int64, bool) {
+	if filter == nil {
+		return 0, false
+	}
+	if bh, ok := filter["blockHash"].(string); ok && bh != "" {
+		return 1, true
+	}
+	from, ok := resolveFilterBlock(ctx, network, filter["fromBlock"])
+	if !ok {
+		return 0, false
+	}
+	to, ok := resolveFilterBlock(ctx, network, filter["toBlock"])
+	if !ok {
+		return 0, false
+	}
+	if to < from {
+		return 0, true
+	}
+	return to - from + 1, true
+}
+
+func resolveFilterBlock(ctx context.Context, network common.Network, v interface{}) (int64, bool) {
+	tag, _ := v.(string)
+	switch tag {
+	case "", "latest", "pending", "safe", "finalized":
+		// Omitted fromBlock/toBlock default to latest, and safe/finalized are approximated
+		// as latest since they are only a few blocks behind it.
+		latest := network.EvmHighestLatestBlockNumber(ctx)
+		return latest, latest > 0
+	case "earliest":
+		return 0, true
+	}
+	if !strings.HasPrefix(tag, "0x") {
+		return 0, false
+	}
+	bn, err := strconv.ParseInt(tag, 0, 64)
+	if err != nil {
+		return 0, false
+	}
+	return bn, true
+}
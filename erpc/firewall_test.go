@@ -0,0 +1,132 @@
+package erpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type firewallTestNetwork struct {
+	common.Network
+	id     string
+	latest int64
+}
+
+func (n *firewallTestNetwork) Id() string {
+	return n.id
+}
+
+func (n *firewallTestNetwork) EvmHighestLatestBlockNumber(ctx context.Context) int64 {
+	return n.latest
+}
+
+func newTestFirewall(t *testing.T, rules ...*common.FirewallRuleConfig) *Firewall {
+	t.Helper()
+	cfg := &common.FirewallConfig{Rules: rules}
+	require.NoError(t, cfg.SetDefaults())
+	require.NoError(t, cfg.Validate())
+	logger := zerolog.Nop()
+	return NewFirewall(&logger, "test", cfg)
+}
+
+func evaluateFirewall(t *testing.T, fw *Firewall, networkId string, body string) error {
+	t.Helper()
+	ntw := &firewallTestNetwork{id: networkId, latest: 0x1000000}
+	return fw.Evaluate(context.Background(), ntw, common.NewNormalizedRequest([]byte(body)))
+}
+
+func TestFirewall(t *testing.T) {
+	fw := newTestFirewall(t,
+		&common.FirewallRuleConfig{
+			Id:      "allow-partner-contract",
+			Action:  common.FirewallActionAllow,
+			Method:  "eth_call",
+			Network: "evm:1",
+			Contracts: []string{
+				"0xDEAD00000000000000000000000000000000BEEF",
+			},
+		},
+		&common.FirewallRuleConfig{
+			Id:        "deny-blocked-contracts",
+			Method:    "eth_call|eth_estimateGas",
+			Contracts: []string{"0xdead*"},
+			Message:   "calls to this contract are not allowed",
+		},
+		&common.FirewallRuleConfig{
+			Id:             "deny-logs-without-address",
+			Method:         "eth_getLogs",
+			MissingAddress: true,
+		},
+		&common.FirewallRuleConfig{
+			Id:                "deny-large-logs-range",
+			Method:            "eth_getLogs",
+			BlockRangeExceeds: 10_000,
+		},
+		&common.FirewallRuleConfig{
+			Id:     "deny-custom-tracers",
+			Method: "debug_traceCall",
+			Params: []interface{}{"*", "*", map[string]interface{}{"tracer": "!callTracer & !prestateTracer"}},
+		},
+	)
+
+	t.Run("DeniesCallsToBlockedContracts", func(t *testing.T) {
+		err := evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"to":"0xDEAD000000000000000000000000000000000001","data":"0x"},"latest"]}`)
+		require.True(t, common.HasErrorCode(err, common.ErrCodeRequestBlockedByFirewall), err)
+		assert.Contains(t, err.Error(), "calls to this contract are not allowed")
+		assert.Equal(t, "deny-blocked-contracts", err.(*common.ErrRequestBlockedByFirewall).Details["rule"])
+
+		jerr, ok := common.TranslateToJsonRpcException(err).(*common.ErrJsonRpcExceptionInternal)
+		require.True(t, ok)
+		assert.Equal(t, common.JsonRpcErrorClientSideException, jerr.NormalizedCode())
+		assert.Equal(t, "request blocked by firewall rule 'deny-blocked-contracts': calls to this contract are not allowed", jerr.Message)
+
+		assert.NoError(t, evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"to":"0x1111000000000000000000000000000000000001","data":"0x"},"latest"]}`))
+	})
+
+	t.Run("AllowRuleShortCircuits", func(t *testing.T) {
+		fw := newTestFirewall(t,
+			&common.FirewallRuleConfig{Id: "allow", Action: common.FirewallActionAllow, Method: "eth_getLogs", Network: "evm:10"},
+			&common.FirewallRuleConfig{Id: "deny", Method: "eth_getLogs", MissingAddress: true},
+		)
+		body := `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x2"}]}`
+		assert.NoError(t, evaluateFirewall(t, fw, "evm:10", body))
+		assert.Error(t, evaluateFirewall(t, fw, "evm:1", body))
+	})
+
+	t.Run("DeniesLogsWithoutAddress", func(t *testing.T) {
+		err := evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x2","address":[]}]}`)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeRequestBlockedByFirewall), err)
+
+		assert.NoError(t, evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0x1","toBlock":"0x2","address":"0x1111000000000000000000000000000000000001"}]}`))
+	})
+
+	t.Run("DeniesLargeLogsRange", func(t *testing.T) {
+		addr := `"address":"0x1111000000000000000000000000000000000001"`
+		err := evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0x0","toBlock":"0x2710",`+addr+`}]}`)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeRequestBlockedByFirewall), err)
+
+		// Tags are resolved to the latest block of the network
+		err = evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0xff0000",`+addr+`}]}`)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeRequestBlockedByFirewall), err)
+
+		assert.NoError(t, evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"fromBlock":"0xfff000","toBlock":"latest",`+addr+`}]}`))
+		assert.NoError(t, evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"eth_getLogs","params":[{"blockHash":"0xabc",`+addr+`}]}`))
+	})
+
+	t.Run("DeniesCustomTracers", func(t *testing.T) {
+		err := evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"debug_traceCall","params":[{"to":"0x1111000000000000000000000000000000000001"},"latest",{"tracer":"{data: [], step: function() {}}"}]}`)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeRequestBlockedByFirewall), err)
+
+		assert.NoError(t, evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"debug_traceCall","params":[{"to":"0x1111000000000000000000000000000000000001"},"latest",{"tracer":"callTracer"}]}`))
+		assert.NoError(t, evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"debug_traceCall","params":[{"to":"0x1111000000000000000000000000000000000001"},"latest"]}`))
+	})
+
+	t.Run("AllowedContractBypassesLaterRules", func(t *testing.T) {
+		assert.NoError(t, evaluateFirewall(t, fw, "evm:1", `{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"to":"0xdead00000000000000000000000000000000beef"},"latest"]}`))
+		assert.Error(t, evaluateFirewall(t, fw, "evm:10", `{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{"to":"0xdead00000000000000000000000000000000beef"},"latest"]}`))
+	})
+}
@@ -0,0 +1,423 @@
+package erpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+
+	"github.com/blockchain-data-standards/manifesto/evm"
+	"github.com/erpc/erpc/auth"
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// grpcProjectMetadataKey selects the project when server.grpc.defaultProjectId is not set (or to override it)
+	grpcProjectMetadataKey = "x-erpc-project"
+	// grpcNetworkMetadataKey selects the network (e.g. evm:1) for requests without a chainId
+	grpcNetworkMetadataKey = "x-erpc-network"
+)
+
+// GrpcServer exposes the blockchain-data-standards RPCQueryService. Each call is translated to its
+// JSON-RPC equivalent and forwarded through the project so that auth, firewall, rate limits, cache,
+// failover and consensus apply exactly like for http requests.
+type GrpcServer struct {
+	evm.UnimplementedRPCQueryServiceServer
+
+	appCtx    context.Context
+	logger    *zerolog.Logger
+	serverCfg *common.ServerConfig
+	cfg       *common.GrpcServerConfig
+	erpc      *ERPC
+	server    *grpc.Server
+}
+
+func NewGrpcServer(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	serverCfg *common.ServerConfig,
+	erpc *ERPC,
+) (*GrpcServer, error) {
+	lg := logger.With().Str("component", "grpcServer").Logger()
+	s := &GrpcServer{
+		appCtx:    ctx,
+		logger:    &lg,
+		serverCfg: serverCfg,
+		cfg:       serverCfg.Grpc,
+		erpc:      erpc,
+	}
+
+	opts := []grpc.ServerOption{}
+	if serverCfg.TLS != nil && serverCfg.TLS.Enabled {
+		requestClientCerts := erpc != nil && erpc.cfg != nil && erpc.cfg.HasMtlsAuth()
+		tlsConfig, err := newServerTLSConfig(serverCfg.TLS, requestClientCerts)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s.server = grpc.NewServer(opts...)
+	evm.RegisterRPCQueryServiceServer(s.server, s)
+
+	go func() {
+		<-ctx.Done()
+		lg.Info().Msg("shutting down grpc server...")
+		s.server.GracefulStop()
+	}()
+
+	return s, nil
+}
+
+func (s *GrpcServer) Start(logger *zerolog.Logger) error {
+	addr := fmt.Sprintf("%s:%d", *s.cfg.Host, *s.cfg.Port)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", addr, err)
+	}
+	logger.Info().Msgf("starting grpc server on %s", addr)
+	return s.Serve(ln)
+}
+
+func (s *GrpcServer) Serve(ln net.Listener) error {
+	return s.server.Serve(ln)
+}
+
+func (s *GrpcServer) ChainId(ctx context.Context, req *evm.ChainIdRequest) (*evm.ChainIdResponse, error) {
+	result, err := s.forward(ctx, nil, "eth_chainId", []interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	var hex string
+	if err := common.SonicCfg.Unmarshal(result, &hex); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse eth_chainId result: %v", err)
+	}
+	chainId, err := evm.NumberishToUint64(hex)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse eth_chainId result: %v", err)
+	}
+	return &evm.ChainIdResponse{ChainId: chainId}, nil
+}
+
+func (s *GrpcServer) GetBlockByNumber(ctx context.Context, req *evm.GetBlockByNumberRequest) (*evm.GetBlockResponse, error) {
+	result, err := s.forward(ctx, req.ChainId, "eth_getBlockByNumber", []interface{}{req.BlockNumber, req.IncludeTransactions})
+	if err != nil {
+		return nil, err
+	}
+	return blockResponseFromJsonRpc(result, req.ChainId)
+}
+
+func (s *GrpcServer) GetBlockByHash(ctx context.Context, req *evm.GetBlockByHashRequest) (*evm.GetBlockResponse, error) {
+	result, err := s.forward(ctx, req.ChainId, "eth_getBlockByHash", []interface{}{evm.BytesToHex(req.BlockHash), req.IncludeTransactions})
+	if err != nil {
+		return nil, err
+	}
+	return blockResponseFromJsonRpc(result, req.ChainId)
+}
+
+func (s *GrpcServer) GetLogs(ctx context.Context, req *evm.GetLogsRequest) (*evm.GetLogsResponse, error) {
+	filter := map[string]interface{}{}
+	if req.BlockHash != nil {
+		filter["blockHash"] = evm.BytesToHex(req.BlockHash)
+	} else {
+		if req.FromBlock != nil {
+			filter["fromBlock"] = fmt.Sprintf("0x%x", *req.FromBlock)
+		}
+		if req.ToBlock != nil {
+			filter["toBlock"] = fmt.Sprintf("0x%x", *req.ToBlock)
+		}
+	}
+	if len(req.Addresses) > 0 {
+		addresses := make([]interface{}, len(req.Addresses))
+		for i, addr := range req.Addresses {
+			addresses[i] = evm.BytesToHex(addr)
+		}
+		filter["address"] = addresses
+	}
+	if len(req.Topics) > 0 {
+		topics := make([]interface{}, len(req.Topics))
+		for i, tf := range req.Topics {
+			// An empty topic filter matches any value at this position
+			if tf == nil || len(tf.Values) == 0 {
+				continue
+			}
+			values := make([]interface{}, len(tf.Values))
+			for j, v := range tf.Values {
+				values[j] = evm.BytesToHex(v)
+			}
+			topics[i] = values
+		}
+		filter["topics"] = topics
+	}
+
+	result, err := s.forward(ctx, req.ChainId, "eth_getLogs", []interface{}{filter})
+	if err != nil {
+		return nil, err
+	}
+	var logs []*evm.JsonRpcLog
+	if err := common.SonicCfg.Unmarshal(result, &logs); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse eth_getLogs result: %v", err)
+	}
+	resp := &evm.GetLogsResponse{Logs: make([]*evm.Log, 0, len(logs))}
+	for _, l := range logs {
+		pl, err := l.ToProto()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert log: %v", err)
+		}
+		resp.Logs = append(resp.Logs, pl)
+	}
+	return resp, nil
+}
+
+func (s *GrpcServer) GetTransactionByHash(ctx context.Context, req *evm.GetTransactionByHashRequest) (*evm.GetTransactionByHashResponse, error) {
+	result, err := s.forward(ctx, req.ChainId, "eth_getTransactionByHash", []interface{}{evm.BytesToHex(req.TransactionHash)})
+	if err != nil {
+		return nil, err
+	}
+	var tx map[string]interface{}
+	if err := common.SonicCfg.Unmarshal(result, &tx); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse eth_getTransactionByHash result: %v", err)
+	}
+	if tx == nil {
+		return &evm.GetTransactionByHashResponse{}, nil
+	}
+	ptx, err := evm.ParseJsonRpcTransaction(tx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert transaction: %v", err)
+	}
+	return &evm.GetTransactionByHashResponse{Transaction: ptx}, nil
+}
+
+func (s *GrpcServer) GetTransactionReceipt(ctx context.Context, req *evm.GetTransactionReceiptRequest) (*evm.GetTransactionReceiptResponse, error) {
+	result, err := s.forward(ctx, req.ChainId, "eth_getTransactionReceipt", []interface{}{evm.BytesToHex(req.TransactionHash)})
+	if err != nil {
+		return nil, err
+	}
+	var receipt *evm.JsonRpcReceipt
+	if err := common.SonicCfg.Unmarshal(result, &receipt); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse eth_getTransactionReceipt result: %v", err)
+	}
+	if receipt == nil {
+		return &evm.GetTransactionReceiptResponse{}, nil
+	}
+	pr, err := receipt.ToProto()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert receipt: %v", err)
+	}
+	return &evm.GetTransactionReceiptResponse{Receipt: pr}, nil
+}
+
+func (s *GrpcServer) GetBlockReceipts(ctx context.Context, req *evm.GetBlockReceiptsRequest) (*evm.GetBlockReceiptsResponse, error) {
+	result, err := s.forward(ctx, req.ChainId, "eth_getBlockReceipts", []interface{}{req.BlockNumber})
+	if err != nil {
+		return nil, err
+	}
+	var receipts []*evm.JsonRpcReceipt
+	if err := common.SonicCfg.Unmarshal(result, &receipts); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse eth_getBlockReceipts result: %v", err)
+	}
+	resp := &evm.GetBlockReceiptsResponse{Receipts: make([]*evm.Receipt, 0, len(receipts))}
+	for _, r := range receipts {
+		pr, err := r.ToProto()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert receipt: %v", err)
+		}
+		resp.Receipts = append(resp.Receipts, pr)
+	}
+	return resp, nil
+}
+
+func blockResponseFromJsonRpc(result []byte, chainId *uint64) (*evm.GetBlockResponse, error) {
+	var block *evm.JsonRpcBlock
+	if err := common.SonicCfg.Unmarshal(result, &block); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse block: %v", err)
+	}
+	if block == nil {
+		return &evm.GetBlockResponse{ChainId: chainId}, nil
+	}
+	pb, err := block.ToProto()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert block: %v", err)
+	}
+	return &evm.GetBlockResponse{
+		Block:            pb.Header,
+		Transactions:     pb.TransactionHashes,
+		FullTransactions: pb.FullTransactions,
+		Withdrawals:      pb.Withdrawals,
+		ChainId:          chainId,
+	}, nil
+}
+
+// forward runs the JSON-RPC equivalent of a gRPC call through the project and returns the raw result.
+func (s *GrpcServer) forward(ctx context.Context, chainId *uint64, method string, params []interface{}) (result []byte, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			telemetry.MetricUnexpectedPanicTotal.WithLabelValues(
+				"grpc-request-handler",
+				fmt.Sprintf("method:%s", method),
+				common.ErrorFingerprint(rec),
+			).Inc()
+			s.logger.Error().
+				Interface("panic", rec).
+				Str("stack", string(debug.Stack())).
+				Msgf("unexpected server panic on grpc request handler")
+			result, err = nil, status.Errorf(codes.Internal, "unexpected server panic: %v", rec)
+		}
+	}()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	headers := http.Header{}
+	for k, vs := range md {
+		for _, v := range vs {
+			headers.Add(k, v)
+		}
+	}
+
+	projectId := headers.Get(grpcProjectMetadataKey)
+	if projectId == "" {
+		projectId = s.cfg.DefaultProjectId
+	}
+	if projectId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "project must be provided via '%s' metadata or server.grpc.defaultProjectId", grpcProjectMetadataKey)
+	}
+	project, err := s.erpc.GetProject(projectId)
+	if err != nil {
+		return nil, grpcStatusFromError(err)
+	}
+
+	var networkId string
+	if chainId != nil {
+		networkId = fmt.Sprintf("evm:%d", *chainId)
+	} else {
+		networkId = headers.Get(grpcNetworkMetadataKey)
+	}
+	if networkId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "network must be provided via chainId field or '%s' metadata (for example evm:1)", grpcNetworkMetadataKey)
+	}
+
+	if s.serverCfg.MaxTimeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.serverCfg.MaxTimeout.Duration())
+		defer cancel()
+	}
+
+	jrq := common.NewJsonRpcRequest(method, params)
+	jrq.ID = util.RandomID()
+	nq := common.NewNormalizedRequestFromJsonRpcRequest(jrq)
+	requestCtx := common.StartRequestSpan(ctx, nq)
+	lg := s.logger.With().Str("projectId", projectId).Str("networkId", networkId).Str("method", method).Logger()
+
+	remoteAddr := ""
+	var tlsState *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			remoteAddr = p.Addr.String()
+		}
+		if ti, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			tlsState = &ti.State
+		}
+	}
+	ap, err := auth.NewPayloadFromHttp(method, remoteAddr, headers, url.Values{}, tlsState)
+	if err != nil {
+		common.EndRequestSpan(requestCtx, nil, err)
+		return nil, status.Errorf(codes.Unauthenticated, "%v", err)
+	}
+	ap.BatchSize = 1
+	ap.NetworkId = networkId
+
+	grant, err := project.AuthenticateConsumer(requestCtx, method, ap)
+	if err != nil {
+		common.EndRequestSpan(requestCtx, nil, err)
+		return nil, grpcStatusFromError(err)
+	}
+	requestCtx = auth.WithGrant(requestCtx, grant)
+	requestCtx = s.erpc.consumerLabels.withConsumerLabel(requestCtx, grant, headers)
+	if grant != nil && grant.Owner != "" {
+		lg = lg.With().Str("authOwner", grant.Owner).Logger()
+	}
+
+	nw, err := project.GetNetwork(networkId)
+	if err != nil {
+		common.EndRequestSpan(requestCtx, nil, err)
+		return nil, grpcStatusFromError(err)
+	}
+	nq.SetNetwork(nw)
+	nq.ApplyDirectiveDefaults(nw.Config().DirectiveDefaults)
+	nq.ApplyDirectivesFromHttp(headers, url.Values{})
+
+	resp, err := project.Forward(requestCtx, networkId, nq)
+	if err != nil {
+		lg.Debug().Err(err).Msg("failed to forward grpc request")
+		common.EndRequestSpan(requestCtx, nil, err)
+		return nil, grpcStatusFromError(err)
+	}
+	defer resp.Release()
+
+	jrr, err := resp.JsonRpcResponse(requestCtx)
+	if err != nil {
+		common.EndRequestSpan(requestCtx, nil, err)
+		return nil, grpcStatusFromError(err)
+	}
+	if jrr.Error != nil {
+		common.EndRequestSpan(requestCtx, nil, jrr.Error)
+		return nil, grpcStatusFromError(jrr.Error)
+	}
+	buf := bytes.NewBuffer(nil)
+	if _, err := jrr.WriteResultTo(buf, false); err != nil {
+		common.EndRequestSpan(requestCtx, nil, err)
+		return nil, status.Errorf(codes.Internal, "failed to read result: %v", err)
+	}
+	common.EndRequestSpan(requestCtx, resp, nil)
+
+	return buf.Bytes(), nil
+}
+
+// grpcStatusFromError maps eRPC errors to grpc status codes based on the http status code
+// the same error would have been returned with.
+func grpcStatusFromError(err error) error {
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return err
+	}
+	msg := err.Error()
+	if jerr, ok := common.TranslateToJsonRpcException(err).(*common.ErrJsonRpcExceptionInternal); ok && jerr.Message != "" {
+		msg = jerr.Message
+	}
+
+	var code codes.Code
+	switch decideErrorStatusCode(err) {
+	case http.StatusOK:
+		code = codes.Unknown
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		code = codes.DeadlineExceeded
+	case http.StatusNotImplemented:
+		code = codes.Unimplemented
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		code = codes.Unavailable
+	default:
+		code = codes.Internal
+	}
+
+	return status.Error(code, msg)
+}
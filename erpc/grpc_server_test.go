@@ -0,0 +1,209 @@
+package erpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blockchain-data-standards/manifesto/evm"
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/erpc/erpc/util"
+	"github.com/h2non/gock"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func createGrpcServerTestFixtures(t *testing.T, cfg *common.Config) evm.RPCQueryServiceClient {
+	t.Helper()
+	logger := log.Logger
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	ssr, err := data.NewSharedStateRegistry(ctx, &logger, &common.SharedStateConfig{
+		Connector: &common.ConnectorConfig{
+			Driver: "memory",
+			Memory: &common.MemoryConnectorConfig{
+				MaxItems: 100_000, MaxTotalSize: "1GB",
+			},
+		},
+	})
+	require.NoError(t, err)
+	erpcInstance, err := NewERPC(ctx, &logger, ssr, nil, cfg)
+	require.NoError(t, err)
+	require.NoError(t, erpcInstance.Bootstrap(ctx))
+
+	grpcServer, err := NewGrpcServer(ctx, &logger, cfg.Server, erpcInstance)
+	require.NoError(t, err)
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return evm.NewRPCQueryServiceClient(conn)
+}
+
+func TestGrpcServer(t *testing.T) {
+	util.ResetGock()
+	defer util.ResetGock()
+	util.SetupMocksForEvmStatePoller()
+	defer util.AssertNoPendingMocks(t, 0)
+
+	chainId := uint64(123)
+	cfg := &common.Config{
+		Server: &common.ServerConfig{
+			MaxTimeout: common.Duration(5 * time.Second).Ptr(),
+			Grpc: &common.GrpcServerConfig{
+				DefaultProjectId: "test_project",
+			},
+		},
+		Projects: []*common.ProjectConfig{
+			{
+				Id: "test_project",
+				Networks: []*common.NetworkConfig{
+					{
+						Architecture: common.ArchitectureEvm,
+						Evm: &common.EvmNetworkConfig{
+							ChainId: 123,
+						},
+					},
+				},
+				Upstreams: []*common.UpstreamConfig{
+					{
+						Type:     common.UpstreamTypeEvm,
+						Endpoint: "http://rpc1.localhost",
+						Evm: &common.EvmUpstreamConfig{
+							ChainId: 123,
+						},
+					},
+				},
+			},
+			{
+				Id: "private_project",
+				Auth: &common.AuthConfig{
+					Strategies: []*common.AuthStrategyConfig{
+						{Type: common.AuthTypeSecret, Secret: &common.SecretStrategyConfig{Value: "test-secret"}},
+					},
+				},
+				Networks: []*common.NetworkConfig{
+					{
+						Architecture: common.ArchitectureEvm,
+						Evm: &common.EvmNetworkConfig{
+							ChainId: 123,
+						},
+					},
+				},
+				Upstreams: []*common.UpstreamConfig{
+					{
+						Type:     common.UpstreamTypeEvm,
+						Endpoint: "http://rpc1.localhost",
+						Evm: &common.EvmUpstreamConfig{
+							ChainId: 123,
+						},
+					},
+				},
+			},
+		},
+		RateLimiters: &common.RateLimiterConfig{},
+	}
+	require.NoError(t, cfg.Server.Grpc.SetDefaults())
+
+	client := createGrpcServerTestFixtures(t, cfg)
+	ctx := context.Background()
+
+	t.Run("GetLogsTranslatesFilterAndResult", func(t *testing.T) {
+		gock.New("http://rpc1.localhost").
+			Post("").
+			Filter(func(request *http.Request) bool {
+				body := util.SafeReadBody(request)
+				return strings.Contains(body, "eth_getLogs") &&
+					strings.Contains(body, `"fromBlock":"0x10"`) &&
+					strings.Contains(body, `"toBlock":"0x11"`) &&
+					strings.Contains(body, `"address":["0x00000000000000000000000000000000000000aa"]`)
+			}).
+			Reply(200).
+			JSON([]byte(`{"jsonrpc":"2.0","id":1,"result":[{
+				"address":"0x00000000000000000000000000000000000000aa",
+				"topics":["0x0000000000000000000000000000000000000000000000000000000000000001"],
+				"data":"0x",
+				"blockNumber":"0x10",
+				"blockHash":"0x0000000000000000000000000000000000000000000000000000000000000abc",
+				"transactionHash":"0x0000000000000000000000000000000000000000000000000000000000000def",
+				"transactionIndex":"0x0",
+				"logIndex":"0x2",
+				"removed":false
+			}]}`))
+
+		from, to := uint64(0x10), uint64(0x11)
+		resp, err := client.GetLogs(ctx, &evm.GetLogsRequest{
+			ChainId:   &chainId,
+			FromBlock: &from,
+			ToBlock:   &to,
+			Addresses: [][]byte{evm.MustHexToBytes("0x00000000000000000000000000000000000000aa")},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Logs, 1)
+		assert.Equal(t, uint64(0x10), resp.Logs[0].BlockNumber)
+		assert.Equal(t, uint32(2), resp.Logs[0].LogIndex)
+	})
+
+	t.Run("GetTransactionReceiptReturnsEmptyWhenNotFound", func(t *testing.T) {
+		gock.New("http://rpc1.localhost").
+			Post("").
+			Filter(func(request *http.Request) bool {
+				return strings.Contains(util.SafeReadBody(request), "eth_getTransactionReceipt")
+			}).
+			Reply(200).
+			JSON([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`))
+
+		resp, err := client.GetTransactionReceipt(ctx, &evm.GetTransactionReceiptRequest{
+			ChainId:         &chainId,
+			TransactionHash: evm.MustHexToBytes("0x0000000000000000000000000000000000000000000000000000000000000def"),
+		})
+		require.NoError(t, err)
+		assert.Nil(t, resp.Receipt)
+	})
+
+	t.Run("ResolvesNetworkFromMetadata", func(t *testing.T) {
+		mctx := metadata.AppendToOutgoingContext(ctx, "x-erpc-network", "evm:123")
+		resp, err := client.ChainId(mctx, &evm.ChainIdRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(123), resp.ChainId)
+	})
+
+	t.Run("RequiresNetwork", func(t *testing.T) {
+		_, err := client.ChainId(ctx, &evm.ChainIdRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	})
+
+	t.Run("AuthenticatesUsingMetadata", func(t *testing.T) {
+		mctx := metadata.AppendToOutgoingContext(ctx, "x-erpc-project", "private_project", "x-erpc-network", "evm:123")
+		_, err := client.ChainId(mctx, &evm.ChainIdRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), err)
+
+		mctx = metadata.AppendToOutgoingContext(mctx, "x-erpc-secret-token", "test-secret")
+		resp, err := client.ChainId(mctx, &evm.ChainIdRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(123), resp.ChainId)
+	})
+}
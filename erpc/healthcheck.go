@@ -43,13 +43,13 @@ func (s *HttpServer) handleHealthCheck(
 		headers := r.Header
 		queryArgs := r.URL.Query()
 
-		ap, err := auth.NewPayloadFromHttp("healthcheck", r.RemoteAddr, headers, queryArgs)
+		ap, err := auth.NewPayloadFromHttp("healthcheck", r.RemoteAddr, headers, queryArgs, r.TLS)
 		if err != nil {
 			handleErrorResponse(ctx, &logger, startedAt, nil, err, w, encoder, writeFatalError, &common.TRUE)
 			return
 		}
 		if s.healthCheckAuthRegistry != nil {
-			if err := s.healthCheckAuthRegistry.Authenticate(ctx, "healthcheck", ap); err != nil {
+			if _, err := s.healthCheckAuthRegistry.Authenticate(ctx, "healthcheck", ap); err != nil {
 				handleErrorResponse(ctx, &logger, startedAt, nil, err, w, encoder, writeFatalError, &common.TRUE)
 				return
 			}
@@ -0,0 +1,130 @@
+package erpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/erpc/erpc/auth"
+	"github.com/erpc/erpc/util"
+)
+
+// maxRequestBodySize returns the limit for (decompressed) request bodies, 0 means unlimited.
+func (s *HttpServer) maxRequestBodySize() int64 {
+	if s.serverCfg == nil || s.serverCfg.MaxRequestBodySize == nil {
+		return 0
+	}
+	v, err := util.ParseByteSize(*s.serverCfg.MaxRequestBodySize)
+	if err != nil || v <= 0 {
+		return 0
+	}
+	return int64(v)
+}
+
+// batchLimits returns the max number of items and max in-flight items of a batch for the project,
+// 0 means unlimited.
+func batchLimits(project *PreparedProject) (maxSize int, maxConcurrency int) {
+	if project == nil || project.Config == nil || project.Config.Batch == nil {
+		return 0, 0
+	}
+	return project.Config.Batch.MaxSize, project.Config.Batch.MaxConcurrency
+}
+
+// batchLimiter bounds how many items of a single batch are processed at the same time (project
+// batch.maxConcurrency), a nil slots channel means unlimited.
+type batchLimiter struct {
+	slots chan struct{}
+}
+
+func newBatchLimiter(maxConcurrency int) *batchLimiter {
+	b := &batchLimiter{}
+	if maxConcurrency > 0 {
+		b.slots = make(chan struct{}, maxConcurrency)
+	}
+	return b
+}
+
+// acquire blocks until an item slot is free or the context is done.
+func (b *batchLimiter) acquire(ctx context.Context) error {
+	if b.slots == nil {
+		return ctx.Err()
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *batchLimiter) release() {
+	if b.slots != nil {
+		<-b.slots
+	}
+}
+
+// acquireConsumer takes a slot of the consumer of the grant before the item's project slot: the item
+// gives its project slot back while it waits for its consumer, so that items queued behind one consumer
+// never starve the rest of the batch. The returned func releases the consumer slot. On error the item
+// holds no project slot anymore, which is reported by holdsSlot.
+func (b *batchLimiter) acquireConsumer(ctx context.Context, consumers *consumerBatchSlots, projectId string, grant *auth.Grant) (release func(), holdsSlot bool, err error) {
+	if grant == nil || grant.MaxBatchConcurrency <= 0 {
+		return func() {}, true, nil
+	}
+	slots, done := consumers.get(projectId, grant)
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots; done() }, true, nil
+	default:
+	}
+
+	b.release()
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		done()
+		return nil, false, ctx.Err()
+	}
+	if err := b.acquire(ctx); err != nil {
+		<-slots
+		done()
+		return nil, false, err
+	}
+	return func() { <-slots; done() }, true, nil
+}
+
+// consumerBatchSlots bounds the batch items each consumer (grant maxBatchConcurrency) has in flight
+// across all of its requests to a project. Entries are dropped once no item of the consumer uses them.
+type consumerBatchSlots struct {
+	mu      sync.Mutex
+	entries map[string]*consumerBatchSlotsEntry
+}
+
+type consumerBatchSlotsEntry struct {
+	slots chan struct{}
+	refs  int
+}
+
+// get returns the slots of the consumer of the grant, the returned func must be called once the caller
+// does not use them anymore.
+func (c *consumerBatchSlots) get(projectId string, grant *auth.Grant) (chan struct{}, func()) {
+	key := projectId + "/" + string(grant.Strategy) + "/" + grant.Owner
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*consumerBatchSlotsEntry)
+	}
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &consumerBatchSlotsEntry{slots: make(chan struct{}, grant.MaxBatchConcurrency)}
+		c.entries[key] = entry
+	}
+	entry.refs++
+	return entry.slots, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		entry.refs--
+		if entry.refs == 0 && c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package erpc
 import (
 	"fmt"
 	"io"
+	"net/http"
 
 	"github.com/erpc/erpc/common"
 )
@@ -10,11 +11,14 @@ import (
 // BatchResponseWriter efficiently writes multiple responses without buffering
 type BatchResponseWriter struct {
 	responses []interface{}
+	// ready[i] (when set) is closed once responses[i] is set, so items are written in order as each one completes
+	ready []chan struct{}
 }
 
-func NewBatchResponseWriter(responses []interface{}) *BatchResponseWriter {
+func NewBatchResponseWriter(responses []interface{}, ready []chan struct{}) *BatchResponseWriter {
 	return &BatchResponseWriter{
 		responses: responses,
+		ready:     ready,
 	}
 }
 
@@ -26,7 +30,12 @@ func (b *BatchResponseWriter) WriteTo(w io.Writer) (n int64, err error) {
 	}
 	n += int64(nn)
 
-	for i, resp := range b.responses {
+	flusher, _ := w.(http.Flusher)
+	for i := range b.responses {
+		if b.ready != nil {
+			<-b.ready[i]
+		}
+		resp := b.responses[i]
 		if i > 0 {
 			// Write comma separator
 			nn, err = w.Write([]byte{','})
@@ -71,6 +80,10 @@ func (b *BatchResponseWriter) WriteTo(w io.Writer) (n int64, err error) {
 			return n, fmt.Errorf("no bytes written for response %d error: %w", i, err)
 		}
 		n += written
+		if flusher != nil {
+			// Send each item as soon as it is written (streamed items are flushed as they are piped)
+			flusher.Flush()
+		}
 	}
 
 	// Write closing bracket
@@ -0,0 +1,273 @@
+package erpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/auth"
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchLimiter(t *testing.T) {
+	runItems := func(t *testing.T, limiters []*batchLimiter, consumers *consumerBatchSlots, grant *auth.Grant, items int) int32 {
+		t.Helper()
+		var inFlight, maxInFlight atomic.Int32
+		var wg sync.WaitGroup
+		for _, limiter := range limiters {
+			wg.Add(1)
+			go func(limiter *batchLimiter) {
+				defer wg.Done()
+				var itemsWg sync.WaitGroup
+				for i := 0; i < items; i++ {
+					require.NoError(t, limiter.acquire(context.Background()))
+					itemsWg.Add(1)
+					go func() {
+						defer itemsWg.Done()
+						release, holdsSlot, err := limiter.acquireConsumer(context.Background(), consumers, "test_project", grant)
+						if holdsSlot {
+							defer limiter.release()
+						}
+						if !assert.NoError(t, err) {
+							return
+						}
+						defer release()
+						cur := inFlight.Add(1)
+						for {
+							prev := maxInFlight.Load()
+							if cur <= prev || maxInFlight.CompareAndSwap(prev, cur) {
+								break
+							}
+						}
+						time.Sleep(10 * time.Millisecond)
+						inFlight.Add(-1)
+					}()
+				}
+				itemsWg.Wait()
+			}(limiter)
+		}
+		wg.Wait()
+		return maxInFlight.Load()
+	}
+
+	t.Run("BoundsItemsByProjectConcurrency", func(t *testing.T) {
+		assert.LessOrEqual(t, runItems(t, []*batchLimiter{newBatchLimiter(3)}, &consumerBatchSlots{}, nil, 20), int32(3))
+	})
+
+	t.Run("BoundsItemsByConsumerConcurrencyAcrossBatches", func(t *testing.T) {
+		grant := &auth.Grant{Strategy: common.AuthTypeSecret, Owner: "customer-a", MaxBatchConcurrency: 2}
+		consumers := &consumerBatchSlots{}
+		limiters := []*batchLimiter{newBatchLimiter(10), newBatchLimiter(10), newBatchLimiter(0)}
+		assert.LessOrEqual(t, runItems(t, limiters, consumers, grant, 20), int32(2))
+		assert.Empty(t, consumers.entries, "unused consumer slots must be dropped")
+	})
+
+	t.Run("UnlimitedWhenMaxConcurrencyIsZero", func(t *testing.T) {
+		limiter := newBatchLimiter(0)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, limiter.acquire(context.Background()))
+		}
+	})
+
+	t.Run("WaitingForConsumerDoesNotHoldProjectSlot", func(t *testing.T) {
+		grant := &auth.Grant{Strategy: common.AuthTypeSecret, Owner: "customer-a", MaxBatchConcurrency: 1}
+		consumers := &consumerBatchSlots{}
+		// Another batch of the same consumer holds its only slot
+		busy, _, err := newBatchLimiter(1).acquireConsumer(context.Background(), consumers, "test_project", grant)
+		require.NoError(t, err)
+
+		limiter := newBatchLimiter(1)
+		require.NoError(t, limiter.acquire(context.Background()))
+		acquired := make(chan struct{})
+		go func() {
+			release, holdsSlot, err := limiter.acquireConsumer(context.Background(), consumers, "test_project", grant)
+			assert.NoError(t, err)
+			assert.True(t, holdsSlot)
+			release()
+			limiter.release()
+			close(acquired)
+		}()
+
+		// Items of other consumers still get the project slot meanwhile
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, limiter.acquire(ctx))
+		limiter.release()
+
+		busy()
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("item did not get its consumer and project slots back")
+		}
+	})
+
+	t.Run("StopsWaitingWhenContextIsDone", func(t *testing.T) {
+		limiter := newBatchLimiter(1)
+		require.NoError(t, limiter.acquire(context.Background()))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, limiter.acquire(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestBatchResponseWriter_WritesItemsAsTheyComplete(t *testing.T) {
+	newResponse := func(id int, result string) *common.NormalizedResponse {
+		jrr, err := common.NewJsonRpcResponse(id, result, nil)
+		require.NoError(t, err)
+		return common.NewNormalizedResponse().WithJsonRpcResponse(jrr)
+	}
+
+	responses := make([]interface{}, 2)
+	ready := []chan struct{}{make(chan struct{}), make(chan struct{})}
+	responses[0] = newResponse(1, "0x1")
+	close(ready[0])
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := NewBatchResponseWriter(responses, ready).WriteTo(pw)
+		pw.CloseWithError(err)
+	}()
+
+	// The first item is received while the second one is still in flight
+	first := `[{"jsonrpc":"2.0","id":1,"result":"0x1"}`
+	head := make([]byte, len(first))
+	_, err := io.ReadFull(pr, head)
+	require.NoError(t, err)
+	assert.Equal(t, first, string(head))
+
+	responses[1] = newResponse(2, "0x2")
+	close(ready[1])
+	rest, err := io.ReadAll(pr)
+	require.NoError(t, err)
+	assert.Equal(t, `,{"jsonrpc":"2.0","id":2,"result":"0x2"}]`, string(rest))
+}
+
+func TestHttpServer_BatchLimits(t *testing.T) {
+	newConfig := func() *common.Config {
+		return &common.Config{
+			Server: &common.ServerConfig{
+				MaxTimeout:         common.Duration(5 * time.Second).Ptr(),
+				MaxRequestBodySize: util.StringPtr("1kb"),
+			},
+			Projects: []*common.ProjectConfig{
+				{
+					Id:              "test_project",
+					RateLimitBudget: "batch-budget",
+					Batch: &common.BatchConfig{
+						MaxSize:        3,
+						MaxConcurrency: 2,
+					},
+					Networks: []*common.NetworkConfig{
+						{
+							Architecture: common.ArchitectureEvm,
+							Evm: &common.EvmNetworkConfig{
+								ChainId: 1,
+							},
+						},
+					},
+					Upstreams: []*common.UpstreamConfig{
+						{
+							Id:       "rpc1",
+							Type:     common.UpstreamTypeEvm,
+							Endpoint: "https://rpc1.localhost",
+							Evm: &common.EvmUpstreamConfig{
+								ChainId: 1,
+							},
+						},
+					},
+				},
+			},
+			RateLimiters: &common.RateLimiterConfig{
+				Budgets: []*common.RateLimitBudgetConfig{
+					{
+						Id: "batch-budget",
+						Rules: []*common.RateLimitRuleConfig{
+							{
+								Method:   "*",
+								MaxCount: 2,
+								Period:   common.Duration(60 * time.Second),
+								WaitTime: common.Duration(0),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	batchOf := func(n int) string {
+		items := make([]string, n)
+		for i := range items {
+			items[i] = fmt.Sprintf(`{"jsonrpc":"2.0","method":"eth_getBalance","params":["0x%040x","latest"],"id":%d}`, i, i+1)
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	}
+
+	t.Run("RejectsOversizedBatchWithSingleError", func(t *testing.T) {
+		util.ResetGock()
+		defer util.ResetGock()
+		util.SetupMocksForEvmStatePoller()
+		defer util.AssertNoPendingMocks(t, 0)
+
+		sendRequest, _, _, shutdown, _ := createServerTestFixtures(newConfig(), t)
+		defer shutdown()
+
+		statusCode, body := sendRequest(batchOf(4), nil, nil)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, statusCode)
+		assert.True(t, strings.HasPrefix(strings.TrimSpace(body), "{"), "expected a single error object, got: %s", body)
+		assert.Contains(t, body, "-32600")
+		assert.Contains(t, body, "exceeds the maximum allowed size of 3")
+	})
+
+	t.Run("RejectsTooLargeRequestBody", func(t *testing.T) {
+		util.ResetGock()
+		defer util.ResetGock()
+		util.SetupMocksForEvmStatePoller()
+		defer util.AssertNoPendingMocks(t, 0)
+
+		sendRequest, _, _, shutdown, _ := createServerTestFixtures(newConfig(), t)
+		defer shutdown()
+
+		statusCode, body := sendRequest(`{"jsonrpc":"2.0","method":"eth_call","params":[{"data":"0x`+strings.Repeat("00", 1024)+`"},"latest"],"id":1}`, nil, nil)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, statusCode)
+		assert.Contains(t, body, "request body exceeds the maximum allowed size")
+	})
+
+	t.Run("ChargesRateLimitPerBatchItem", func(t *testing.T) {
+		util.ResetGock()
+		defer util.ResetGock()
+		util.SetupMocksForEvmStatePoller()
+		defer util.AssertNoPendingMocks(t, 0)
+
+		gock.New("https://rpc1.localhost").
+			Post("/").
+			Times(2).
+			Filter(func(request *http.Request) bool {
+				return strings.Contains(util.SafeReadBody(request), "eth_getBalance")
+			}).
+			Reply(200).
+			JSON(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"result":  "0x1",
+			})
+
+		sendRequest, _, _, shutdown, _ := createServerTestFixtures(newConfig(), t)
+		defer shutdown()
+
+		_, body := sendRequest(batchOf(3), nil, nil)
+		assert.True(t, strings.HasPrefix(strings.TrimSpace(body), "["), "expected a batch response, got: %s", body)
+		assert.Equal(t, 2, strings.Count(body, `"result":"0x1"`))
+		assert.Equal(t, 1, strings.Count(body, "rate-limit exceeded"))
+	})
+}
@@ -40,6 +40,7 @@ type HttpServer struct {
 	logger                  *zerolog.Logger
 	healthCheckAuthRegistry *auth.AuthRegistry
 	draining                *atomic.Bool
+	consumerBatchSlots      consumerBatchSlots
 }
 
 func NewHttpServer(
@@ -88,6 +89,7 @@ func NewHttpServer(
 		Handler: TimeoutHandler(
 			h,
 			reqMaxTimeout,
+			writeTimeout,
 		),
 		ReadTimeout:  readTimeout,
 		WriteTimeout: writeTimeout,
@@ -95,7 +97,7 @@ func NewHttpServer(
 
 	if healthCheckCfg != nil && healthCheckCfg.Auth != nil {
 		var err error
-		srv.healthCheckAuthRegistry, err = auth.NewAuthRegistry(logger, "healthcheck", healthCheckCfg.Auth, nil)
+		srv.healthCheckAuthRegistry, err = auth.NewAuthRegistry(ctx, logger, "healthcheck", healthCheckCfg.Auth, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create healthcheck auth registry: %w", err)
 		}
@@ -250,10 +252,18 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 			bodyReader = gzReader
 		}
 
-		// Replace the existing body read with our potentially decompressed reader
+		// Replace the existing body read with our potentially decompressed reader,
+		// reading one byte past the limit to detect oversized bodies
+		maxBodySize := s.maxRequestBodySize()
+		if maxBodySize > 0 {
+			bodyReader = io.LimitReader(bodyReader, maxBodySize+1)
+		}
 		_, readBodySpan := common.StartDetailSpan(httpCtx, "Http.ReadBody")
 		body, err := util.ReadAll(bodyReader, 1024*1024, 512)
 		readBodySpan.End()
+		if err == nil && maxBodySize > 0 && int64(len(body)) > maxBodySize {
+			err = common.NewErrRequestBodyTooLarge(maxBodySize)
+		}
 		if err != nil {
 			common.SetTraceSpanError(readBodySpan, err)
 			handleErrorResponse(
@@ -297,7 +307,33 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 			}
 		}
 
+		// Oversized batches are rejected as a whole before any item is authenticated or charged
+		maxBatchSize, maxBatchConcurrency := batchLimits(project)
+		if isBatch && maxBatchSize > 0 && len(requests) > maxBatchSize {
+			err = common.NewErrBatchTooLarge(len(requests), maxBatchSize)
+			handleErrorResponse(
+				httpCtx,
+				&lg,
+				&startedAt,
+				nil,
+				err,
+				w,
+				encoder,
+				writeFatalError,
+				&common.TRUE,
+			)
+			common.SetTraceSpanError(parseRequestsSpan, err)
+			parseRequestsSpan.End()
+			return
+		}
+		limiter := newBatchLimiter(maxBatchConcurrency)
+
 		responses := make([]interface{}, len(requests))
+		// ready[i] is closed once responses[i] is set, so batch items are written as soon as they complete
+		ready := make([]chan struct{}, len(requests))
+		for i := range ready {
+			ready[i] = make(chan struct{})
+		}
 		var wg sync.WaitGroup
 
 		headers := r.Header
@@ -306,10 +342,25 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 		parseRequestsSpan.End()
 
 		for i, reqBody := range requests {
+			if err := limiter.acquire(httpCtx); err != nil {
+				// Client is gone or request timed out, remaining items are not processed
+				for j := i; j < len(requests); j++ {
+					responses[j] = processErrorBody(&lg, &startedAt, nil, err, s.serverCfg.IncludeErrorDetails)
+					close(ready[j])
+				}
+				break
+			}
 			wg.Add(1)
-			go func(index int, rawReq json.RawMessage, headers http.Header, queryArgs map[string][]string) {
+			go func(itemsCtx context.Context, index int, rawReq json.RawMessage, headers http.Header, queryArgs map[string][]string) {
+				holdsSlot := true
 				defer func() {
 					defer wg.Done()
+					defer func() {
+						if holdsSlot {
+							limiter.release()
+						}
+					}()
+					defer close(ready[index])
 					if rec := recover(); rec != nil {
 						telemetry.MetricUnexpectedPanicTotal.WithLabelValues(
 							"request-handler",
@@ -326,7 +377,7 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 				}()
 
 				nq := common.NewNormalizedRequest(rawReq)
-				requestCtx := common.StartRequestSpan(httpCtx, nq)
+				requestCtx := common.StartRequestSpan(itemsCtx, nq)
 
 				// Validate the raw JSON-RPC payload early
 				if err := nq.Validate(); err != nil {
@@ -342,15 +393,19 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 				var err error
 
 				if project != nil {
-					ap, err = auth.NewPayloadFromHttp(method, r.RemoteAddr, headers, queryArgs)
+					ap, err = auth.NewPayloadFromHttp(method, r.RemoteAddr, headers, queryArgs, r.TLS)
 				} else if isAdmin {
-					ap, err = auth.NewPayloadFromHttp(method, r.RemoteAddr, headers, queryArgs)
+					ap, err = auth.NewPayloadFromHttp(method, r.RemoteAddr, headers, queryArgs, r.TLS)
 				}
 				if err != nil {
 					responses[index] = processErrorBody(&rlg, &startedAt, nq, err, &common.TRUE)
 					common.EndRequestSpan(requestCtx, nil, err)
 					return
 				}
+				ap.BatchSize = len(requests)
+				if architecture != "" && chainId != "" {
+					ap.NetworkId = fmt.Sprintf("%s:%s", architecture, chainId)
+				}
 
 				if isAdmin {
 					if err := s.erpc.AdminAuthenticate(requestCtx, method, ap); err != nil {
@@ -359,11 +414,37 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 						return
 					}
 				} else {
-					if err := project.AuthenticateConsumer(requestCtx, method, ap); err != nil {
+					grant, err := project.AuthenticateConsumer(requestCtx, method, ap)
+					if err != nil {
 						responses[index] = processErrorBody(&rlg, &startedAt, nq, err, &common.TRUE)
 						common.EndRequestSpan(requestCtx, nil, err)
 						return
 					}
+					requestCtx = auth.WithGrant(requestCtx, grant)
+					requestCtx = s.erpc.consumerLabels.withConsumerLabel(requestCtx, grant, headers)
+					if isBatch {
+						var releaseConsumer func()
+						releaseConsumer, holdsSlot, err = limiter.acquireConsumer(requestCtx, &s.consumerBatchSlots, project.Config.Id, grant)
+						if err != nil {
+							responses[index] = processErrorBody(&rlg, &startedAt, nq, err, s.serverCfg.IncludeErrorDetails)
+							common.EndRequestSpan(requestCtx, nil, err)
+							return
+						}
+						defer releaseConsumer()
+					}
+					if grant != nil && grant.Owner != "" {
+						rlg = rlg.With().Str("authOwner", grant.Owner).Logger()
+						common.SpanFromContext(requestCtx).SetAttributes(attribute.String("auth.owner", grant.Owner))
+					}
+					if grant != nil && len(grant.Metadata) > 0 {
+						md := zerolog.Dict()
+						span := common.SpanFromContext(requestCtx)
+						for k, v := range grant.Metadata {
+							md.Str(k, v)
+							span.SetAttributes(attribute.String("auth.metadata."+k, v))
+						}
+						rlg = rlg.With().Dict("authMetadata", md).Logger()
+					}
 				}
 
 				if isAdmin {
@@ -443,10 +524,18 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 
 				responses[index] = resp
 				common.EndRequestSpan(requestCtx, resp, nil)
-			}(i, reqBody, headers, queryArgs)
+			}(httpCtx, i, reqBody, headers, queryArgs)
 		}
 
-		wg.Wait()
+		if isBatch && len(requests) > 0 {
+			// Batch items are written in order as each one completes, only the first one is awaited before responding
+			select {
+			case <-ready[0]:
+			case <-httpCtx.Done():
+			}
+		} else {
+			wg.Wait()
+		}
 
 		httpCtx, writeResponseSpan := common.StartDetailSpan(httpCtx, "HttpServer.WriteResponse")
 		defer writeResponseSpan.End()
@@ -466,32 +555,26 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 		common.InjectHTTPResponseTraceContext(httpCtx, w)
 
 		if isBatch {
-			statusSet := false
-			for _, resp := range responses {
-				statusCode := determineResponseStatusCode(resp)
-				if statusCode != http.StatusOK {
-					if !statusSet {
-						w.WriteHeader(statusCode)
-						statusSet = true
-					}
-				}
-			}
-			if !statusSet {
-				w.WriteHeader(http.StatusOK)
+			// Items are written as each one completes so the status cannot depend on them: batches always
+			// respond with 200 and each item carries its own json-rpc error.
+			w.WriteHeader(http.StatusOK)
+			// Commit the headers so each item (including streamed results) is piped to the client as it is written
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
 			}
 
-			bw := NewBatchResponseWriter(responses)
+			bw := NewBatchResponseWriter(responses, ready)
 			_, err = bw.WriteTo(w)
+			wg.Wait()
 			for _, resp := range responses {
 				if r, ok := resp.(*common.NormalizedResponse); ok {
 					go r.Release()
 				}
 			}
 
-			if err != nil && !errors.Is(err, http.ErrHandlerTimeout) {
+			if err != nil && !errors.Is(err, http.ErrHandlerTimeout) && !errors.Is(err, ErrHandlerTimeout) {
 				s.logger.Error().Err(err).Msg("failed to write batch response")
-				writeFatalError(httpCtx, http.StatusInternalServerError, err)
-				return
+				abortCommittedResponse(httpCtx, http.StatusOK, err)
 			}
 
 			common.EnrichHTTPServerSpan(httpCtx, http.StatusOK, nil)
@@ -501,8 +584,16 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 			statusCode := determineResponseStatusCode(res)
 			w.WriteHeader(statusCode)
 
+			committed := false
 			switch v := res.(type) {
 			case *common.NormalizedResponse:
+				if v.IsStreaming() {
+					// Commit the headers so the result is piped to the client as it is read from upstream
+					if flusher, ok := w.(http.Flusher); ok {
+						flusher.Flush()
+						committed = true
+					}
+				}
 				_, err = v.WriteTo(w)
 				go v.Release()
 			case *HttpJsonRpcErrorResponse:
@@ -512,6 +603,10 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 			}
 
 			if err != nil {
+				if committed && !errors.Is(err, ErrHandlerTimeout) {
+					s.logger.Warn().Err(err).Msg("failed to write streamed response")
+					abortCommittedResponse(httpCtx, statusCode, err)
+				}
 				writeFatalError(httpCtx, statusCode, err)
 				return
 			} else {
@@ -555,7 +650,9 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 		}
 
 		defer func() {
-			if rec := recover(); rec != nil {
+			if rec := recover(); rec == http.ErrAbortHandler {
+				panic(rec)
+			} else if rec != nil {
 				telemetry.MetricUnexpectedPanicTotal.WithLabelValues(
 					"top-level-handler",
 					"",
@@ -577,6 +674,14 @@ func (s *HttpServer) createRequestHandler() http.Handler {
 	})
 }
 
+// abortCommittedResponse aborts the connection when writing fails after the status and part of the body were
+// sent (e.g. a streamed result). Appending an error there would leave a malformed (or truncated but complete
+// looking) body, whereas an aborted connection is seen by clients as a failed response.
+func abortCommittedResponse(ctx context.Context, statusCode int, err error) {
+	common.EnrichHTTPServerSpan(ctx, statusCode, err)
+	panic(http.ErrAbortHandler)
+}
+
 func (s *HttpServer) parseUrlPath(
 	r *http.Request,
 	preSelectedProjectId,
@@ -895,6 +1000,26 @@ type HttpJsonRpcErrorResponse struct {
 	Cause   error       `json:"-"`
 }
 
+// translateForwardError picks the most relevant error of a failed forward and translates it to
+// the json-rpc exception that is returned to clients.
+func translateForwardError(err error) error {
+	// This is a special attempt to extract execution errors first (e.g. execution reverted):
+	exe := &common.ErrEndpointExecutionException{}
+	if errors.As(err, &exe) {
+		err = exe
+	}
+
+	// To simplify client's life if there's only one upstream error, we can use that as the error
+	// instead of Exhausted error which obfuscates underlying errors.
+	if ex, ok := err.(*common.ErrUpstreamsExhausted); ok {
+		if len(ex.Errors()) == 1 {
+			err = ex.Errors()[0]
+		}
+	}
+
+	return common.TranslateToJsonRpcException(err)
+}
+
 func processErrorBody(logger *zerolog.Logger, startedAt *time.Time, nq *common.NormalizedRequest, origErr error, includeErrorDetails *bool) interface{} {
 	err := origErr
 	if !common.IsNull(err) {
@@ -910,6 +1035,7 @@ func processErrorBody(logger *zerolog.Logger, startedAt *time.Time, nq *common.N
 			common.ErrCodeAuthUnauthorized,
 			common.ErrCodeJsonRpcRequestUnmarshal,
 			common.ErrCodeProjectNotFound,
+			common.ErrCodeRequestBlockedByFirewall,
 		) {
 			logger.Debug().Err(err).Object("request", nq).Msgf("forward request errored with client-side exception")
 		} else if errors.Is(err, context.Canceled) {
@@ -928,21 +1054,7 @@ func processErrorBody(logger *zerolog.Logger, startedAt *time.Time, nq *common.N
 		}
 	}
 
-	// This is a special attempt to extract execution errors first (e.g. execution reverted):
-	exe := &common.ErrEndpointExecutionException{}
-	if errors.As(err, &exe) {
-		err = exe
-	}
-
-	// To simplify client's life if there's only one upstream error, we can use that as the error
-	// instead of Exhausted error which obfuscates underlying errors.
-	if ex, ok := err.(*common.ErrUpstreamsExhausted); ok {
-		if len(ex.Errors()) == 1 {
-			err = ex.Errors()[0]
-		}
-	}
-
-	err = common.TranslateToJsonRpcException(err)
+	err = translateForwardError(err)
 	var jsonrpcVersion string = "2.0"
 	var reqId interface{} = nil
 	var method string = ""
@@ -1158,33 +1270,12 @@ func (s *HttpServer) Start(logger *zerolog.Logger) error {
 
 	// Handle TLS configuration if enabled
 	if s.serverCfg.TLS != nil && s.serverCfg.TLS.Enabled {
-		tlsConfig := &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		}
-
-		// Load certificate and key
-		cert, err := tls.LoadX509KeyPair(s.serverCfg.TLS.CertFile, s.serverCfg.TLS.KeyFile)
+		requestClientCerts := s.erpc != nil && s.erpc.cfg != nil && s.erpc.cfg.HasMtlsAuth()
+		tlsConfig, err := newServerTLSConfig(s.serverCfg.TLS, requestClientCerts)
 		if err != nil {
-			return fmt.Errorf("failed to load TLS certificate and key: %w", err)
-		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
-
-		// Load CA if specified
-		if s.serverCfg.TLS.CAFile != "" {
-			caCert, err := os.ReadFile(s.serverCfg.TLS.CAFile)
-			if err != nil {
-				return fmt.Errorf("failed to read CA file: %w", err)
-			}
-			caCertPool := x509.NewCertPool()
-			if !caCertPool.AppendCertsFromPEM(caCert) {
-				return fmt.Errorf("failed to parse CA certificate")
-			}
-			tlsConfig.ClientCAs = caCertPool
-			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+			return err
 		}
 
-		tlsConfig.InsecureSkipVerify = s.serverCfg.TLS.InsecureSkipVerify
-
 		// Wrap the listener with TLS
 		ln = tls.NewListener(ln, tlsConfig)
 		logger.Info().Msg("TLS enabled for HTTP server")
@@ -1193,6 +1284,42 @@ func (s *HttpServer) Start(logger *zerolog.Logger) error {
 	return s.server.Serve(ln)
 }
 
+// newServerTLSConfig builds the tls config shared by http and grpc listeners.
+func newServerTLSConfig(cfg *common.TLSConfig, requestClientCerts bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	// Load certificate and key
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate and key: %w", err)
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+
+	// Load CA if specified
+	if cfg.CAFile != "" {
+		caCert, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate")
+		}
+		tlsConfig.ClientCAs = caCertPool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	} else if requestClientCerts {
+		// Client certificates are only requested (not required) so that clients of other auth
+		// strategies can still connect, chains are verified by the mtls strategy of each project.
+		tlsConfig.ClientAuth = tls.RequestClientCert
+	}
+
+	tlsConfig.InsecureSkipVerify = cfg.InsecureSkipVerify
+
+	return tlsConfig, nil
+}
+
 func (s *HttpServer) Shutdown(logger *zerolog.Logger) error {
 	logger.Info().Msg("stopping http server...")
 	return s.server.Shutdown(context.Background())
@@ -5021,7 +5021,7 @@ func TestHttpServer_HandleHealthCheck(t *testing.T) {
 				_ = pp.upstreamsRegistry.Bootstrap(ctx)
 				pp.networksRegistry = NewNetworksRegistry(pp, ctx, pp.upstreamsRegistry, nil, nil, nil, logger)
 
-				authReg, _ := auth.NewAuthRegistry(logger, "test", &common.AuthConfig{Strategies: []*common.AuthStrategyConfig{
+				authReg, _ := auth.NewAuthRegistry(ctx, logger, "test", &common.AuthConfig{Strategies: []*common.AuthStrategyConfig{
 					{Type: common.AuthTypeSecret, Secret: &common.SecretStrategyConfig{Value: "test-secret"}},
 				}}, nil)
 
@@ -5058,7 +5058,7 @@ func TestHttpServer_HandleHealthCheck(t *testing.T) {
 				_ = pp.upstreamsRegistry.Bootstrap(ctx)
 				pp.networksRegistry = NewNetworksRegistry(pp, ctx, pp.upstreamsRegistry, nil, nil, nil, logger)
 
-				authReg, _ := auth.NewAuthRegistry(logger, "test", &common.AuthConfig{Strategies: []*common.AuthStrategyConfig{
+				authReg, _ := auth.NewAuthRegistry(ctx, logger, "test", &common.AuthConfig{Strategies: []*common.AuthStrategyConfig{
 					{Type: common.AuthTypeSecret, Secret: &common.SecretStrategyConfig{Value: "test-secret"}},
 				}}, nil)
 
@@ -8446,3 +8446,53 @@ func createServerTestFixtures(cfg *common.Config, t *testing.T) (
 		time.Sleep(500 * time.Millisecond)
 	}, erpcInstance
 }
+
+func TestTimeoutHandler_StreamedResponses(t *testing.T) {
+	t.Run("AbortedResponseIsSeenAsFailedByClient", func(t *testing.T) {
+		srv := httptest.NewServer(TimeoutHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":["log1"`)
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}), time.Second, time.Second))
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		assert.Error(t, err)
+		assert.Equal(t, `{"jsonrpc":"2.0","id":1,"result":["log1"`, string(body))
+	})
+
+	t.Run("StreamedBodyIsBoundedByStreamTimeout", func(t *testing.T) {
+		cause := make(chan error, 1)
+		srv := httptest.NewServer(TimeoutHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":[`)
+			w.(http.Flusher).Flush()
+
+			// Handler timeout no longer applies, the request lifetime is cancelled by the stream timeout
+			lifetime, ok := common.RequestLifetime(r.Context())
+			require.True(t, ok)
+			select {
+			case <-lifetime.Done():
+				cause <- context.Cause(lifetime)
+			case <-time.After(5 * time.Second):
+				cause <- nil
+			}
+			panic(http.ErrAbortHandler)
+		}), 50*time.Millisecond, 300*time.Millisecond))
+		defer srv.Close()
+
+		startedAt := time.Now()
+		resp, err := http.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		_, err = io.ReadAll(resp.Body)
+		assert.Error(t, err)
+		assert.ErrorIs(t, <-cause, ErrStreamTimeout)
+		assert.GreaterOrEqual(t, time.Since(startedAt), 300*time.Millisecond)
+	})
+}
@@ -15,36 +15,51 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func TimeoutHandler(h http.Handler, dt time.Duration) http.Handler {
+// TimeoutHandler bounds handling of each request to dt, and the writing of streamed bodies (committed to the
+// client before completion) to streamDt after they are committed.
+func TimeoutHandler(h http.Handler, dt time.Duration, streamDt time.Duration) http.Handler {
 	return &timeoutHandler{
-		handler: h,
-		dt:      dt,
+		handler:  h,
+		dt:       dt,
+		streamDt: streamDt,
 	}
 }
 
 var ErrHandlerTimeout = errors.New("http request handling timeout")
+var ErrStreamTimeout = errors.New("http streamed response writing timeout")
 
 type timeoutHandler struct {
-	handler http.Handler
-	dt      time.Duration
+	handler  http.Handler
+	dt       time.Duration
+	streamDt time.Duration
 }
 
 func (h *timeoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ctx, cancelCtx := context.WithTimeoutCause(r.Context(), h.dt, ErrHandlerTimeout)
+	// Streamed bodies keep being written after the timeout fires, the lifetime context tells
+	// whether the client is still there and the streamed body is within its own deadline.
+	lifetime, cancelLifetime := context.WithCancelCause(r.Context())
+	defer cancelLifetime(nil)
+	ctx, cancelCtx := context.WithTimeoutCause(common.WithRequestLifetime(r.Context(), lifetime), h.dt, ErrHandlerTimeout)
 	defer func() {
 		cancelCtx()
 	}()
 	r = r.WithContext(ctx)
 	done := make(chan struct{})
 	tw := &timeoutWriter{
-		w:   w,
-		h:   make(http.Header),
-		req: r,
+		w:              w,
+		h:              make(http.Header),
+		req:            r,
+		streamDt:       h.streamDt,
+		cancelLifetime: cancelLifetime,
 	}
+	defer tw.stopStreamTimer()
 	panicChan := make(chan any, 1)
 	go func() {
 		defer func() {
-			if p := recover(); p != nil {
+			if p := recover(); p == http.ErrAbortHandler {
+				// Response is cut short on purpose (e.g. a streamed body failed after being committed)
+				panicChan <- p
+			} else if p != nil {
 				telemetry.MetricUnexpectedPanicTotal.WithLabelValues(
 					"timeout-handler",
 					"",
@@ -66,6 +81,9 @@ func (h *timeoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case <-done:
 		tw.mu.Lock()
 		defer tw.mu.Unlock()
+		if tw.passthrough {
+			return
+		}
 		dst := w.Header()
 		for k, vv := range tw.h {
 			dst[k] = vv
@@ -80,6 +98,16 @@ func (h *timeoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	case <-ctx.Done():
 		tw.mu.Lock()
+		if tw.passthrough {
+			// Response is already committed to the client (e.g. a streamed result), so let the handler finish writing
+			tw.mu.Unlock()
+			select {
+			case p := <-panicChan:
+				panic(p)
+			case <-done:
+			}
+			return
+		}
 		defer tw.mu.Unlock()
 		err := context.Cause(ctx)
 		if err == nil {
@@ -107,10 +135,15 @@ type timeoutWriter struct {
 	wbuf bytes.Buffer
 	req  *http.Request
 
+	streamDt       time.Duration
+	streamTimer    *time.Timer
+	cancelLifetime context.CancelCauseFunc
+
 	mu          sync.Mutex
 	err         error
 	wroteHeader bool
 	code        int
+	passthrough bool
 }
 
 var _ http.Pusher = (*timeoutWriter)(nil)
@@ -127,6 +160,10 @@ func (tw *timeoutWriter) Header() http.Header { return tw.h }
 
 func (tw *timeoutWriter) Write(p []byte) (int, error) {
 	tw.mu.Lock()
+	if tw.passthrough {
+		tw.mu.Unlock()
+		return tw.w.Write(p)
+	}
 	defer tw.mu.Unlock()
 	if tw.err != nil {
 		return 0, tw.err
@@ -156,3 +193,53 @@ func (tw *timeoutWriter) WriteHeader(code int) {
 	defer tw.mu.Unlock()
 	tw.writeHeaderLocked(code)
 }
+
+// Flush commits the response and switches to writing directly to the client, so that streamed results are not
+// buffered. The handler timeout and server write timeout no longer apply once the response is committed, the rest
+// of the body must be written within streamDt instead: past it writes fail and the request lifetime is cancelled
+// so that upstream reads feeding the body are aborted too.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	if tw.err != nil {
+		tw.mu.Unlock()
+		return
+	}
+	if !tw.passthrough {
+		if !tw.wroteHeader {
+			tw.writeHeaderLocked(http.StatusOK)
+		}
+		dst := tw.w.Header()
+		for k, vv := range tw.h {
+			dst[k] = vv
+		}
+		var deadline time.Time
+		if tw.streamDt > 0 {
+			deadline = time.Now().Add(tw.streamDt)
+			tw.streamTimer = time.AfterFunc(tw.streamDt, func() {
+				tw.cancelLifetime(ErrStreamTimeout)
+			})
+		}
+		_ = http.NewResponseController(tw.w).SetWriteDeadline(deadline)
+		tw.w.WriteHeader(tw.code)
+		if tw.wbuf.Len() > 0 {
+			if _, err := tw.w.Write(tw.wbuf.Bytes()); err != nil {
+				log.Warn().Err(err).Msg("failed to write buffered response")
+			}
+			tw.wbuf.Reset()
+		}
+		tw.passthrough = true
+	}
+	tw.mu.Unlock()
+
+	if flusher, ok := tw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (tw *timeoutWriter) stopStreamTimer() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.streamTimer != nil {
+		tw.streamTimer.Stop()
+	}
+}
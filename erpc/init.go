@@ -14,6 +14,7 @@ import (
 	"github.com/erpc/erpc/util"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
 )
 
 func Init(
@@ -41,6 +42,12 @@ func Init(
 		}
 	}
 
+	if cfg.Logs != nil {
+		if err := common.InitializeOtlpLogs(appCtx, &logger, cfg.Logs.Otlp); err != nil {
+			logger.Error().Err(err).Msg("failed to initialize otlp logs exporter")
+		}
+	}
+
 	//
 	// 2) Set the right histogram buckets
 	//
@@ -94,7 +101,35 @@ func Init(
 				}
 			}
 		}()
+		if cfg.Server.Grpc != nil && cfg.Server.Grpc.Enabled != nil && *cfg.Server.Grpc.Enabled {
+			grpcServer, err := NewGrpcServer(appCtx, &logger, cfg.Server, erpcInstance)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := grpcServer.Start(&logger); err != nil && err != grpc.ErrServerStopped {
+					logger.Error().Msgf("failed to start grpc server: %v", err)
+					util.OsExit(util.ExitCodeHttpServerFailed)
+				}
+			}()
+		}
 	}
+	if cfg.Metrics != nil && cfg.Metrics.Otlp != nil && cfg.Metrics.Otlp.Enabled {
+		if cfg.Metrics.ErrorLabelMode != "" {
+			common.SetErrorLabelMode(cfg.Metrics.ErrorLabelMode)
+		}
+		if err := common.InitializeOtlpMetrics(appCtx, &logger, cfg.Metrics.Otlp); err != nil {
+			logger.Error().Err(err).Msg("failed to initialize otlp metrics exporter")
+		}
+	}
+	go func() {
+		<-appCtx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := common.ShutdownOtlp(shutdownCtx); err != nil {
+			logger.Error().Err(err).Msg("failed to shutdown otlp metrics and logs exporters")
+		}
+	}()
 	if cfg.Metrics != nil && cfg.Metrics.Enabled != nil && *cfg.Metrics.Enabled {
 		if cfg.Metrics.ErrorLabelMode != "" {
 			common.SetErrorLabelMode(cfg.Metrics.ErrorLabelMode)
@@ -15,6 +15,9 @@ type Multiplexer struct {
 	done chan struct{}
 	mu   *sync.RWMutex
 	once sync.Once
+
+	// followers counts requests already waiting on this multiplexer, guarded by mu
+	followers int
 }
 
 func NewMultiplexer(hash string) *Multiplexer {
@@ -36,7 +39,12 @@ func (m *Multiplexer) Close(ctx context.Context, resp *common.NormalizedResponse
 
 		// Process the response if provided
 		if resp != nil {
-			if jrr, parseErr := resp.JsonRpcResponse(ctx); parseErr != nil {
+			jrr, parseErr := resp.JsonRpcResponse(ctx)
+			if parseErr == nil && m.followers > 0 {
+				// A streamed result can be written only once, so keep it in memory when others are waiting for it
+				parseErr = jrr.Materialize()
+			}
+			if parseErr != nil {
 				log.Warn().Err(parseErr).Str("multiplexer_hash", m.hash).Object("response", resp).Msg("failed to parse response before storing in multiplexer")
 				// If parsing fails, propagate this error instead of storing a response that can't be copied
 				if err == nil {
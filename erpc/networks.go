@@ -11,6 +11,7 @@ import (
 
 	"github.com/erpc/erpc/architecture/evm"
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/consensus"
 	"github.com/erpc/erpc/health"
 	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/upstream"
@@ -27,6 +28,9 @@ type FailsafeExecutor struct {
 	finalities []common.DataFinalityState
 	executor   failsafe.Executor[*common.NormalizedResponse]
 	timeout    *time.Duration
+
+	// dynamicTimeout is set when timeout is derived from method response-time quantiles
+	dynamicTimeout *common.TimeoutPolicyConfig
 }
 
 type Network struct {
@@ -40,6 +44,7 @@ type Network struct {
 	failsafeExecutors        []*FailsafeExecutor
 	rateLimitersRegistry     *upstream.RateLimitersRegistry
 	cacheDal                 common.CacheDAL
+	disputeRecorder          consensus.DisputeRecorder
 	metricsTracker           *health.Tracker
 	upstreamsRegistry        *upstream.UpstreamsRegistry
 	selectionPolicyEvaluator *PolicyEvaluator
@@ -162,6 +167,43 @@ func (n *Network) EvmHighestFinalizedBlockNumber(ctx context.Context) int64 {
 	return maxBlock
 }
 
+func (n *Network) EvmHighestSafeBlockNumber(ctx context.Context) int64 {
+	ctx, span := common.StartDetailSpan(ctx, "Network.EvmHighestSafeBlockNumber", trace.WithAttributes(
+		attribute.String("network.id", n.networkId),
+	))
+	defer span.End()
+
+	upstreams := n.upstreamsRegistry.GetNetworkUpstreams(ctx, n.networkId)
+	var maxBlock int64 = 0
+	for _, u := range upstreams {
+		statePoller := u.EvmStatePoller()
+		if statePoller == nil {
+			continue
+		}
+
+		// Check if the node is syncing - skip syncing nodes as their block numbers may be unreliable
+		if u.EvmSyncingState() == common.EvmSyncingStateSyncing {
+			n.logger.Debug().Str("upstreamId", u.Id()).Msg("skipping syncing upstream for highest safe block calculation")
+			continue
+		}
+
+		// Check if upstream is excluded by selection policy
+		if n.selectionPolicyEvaluator != nil {
+			if err := n.selectionPolicyEvaluator.AcquirePermit(n.logger, u, "eth_getBlockByNumber"); err != nil {
+				n.logger.Debug().Str("upstreamId", u.Id()).Err(err).Msg("skipping upstream excluded by selection policy for highest safe block calculation")
+				continue
+			}
+		}
+
+		upBlock := statePoller.SafeBlock()
+		if upBlock > maxBlock {
+			maxBlock = upBlock
+		}
+	}
+	span.SetAttributes(attribute.Int64("highest_safe_block", maxBlock))
+	return maxBlock
+}
+
 func (n *Network) EvmLowestFinalizedBlockNumber(ctx context.Context) int64 {
 	ctx, span := common.StartDetailSpan(ctx, "Network.EvmLowestFinalizedBlockNumber", trace.WithAttributes(
 		attribute.String("network.id", n.networkId),
@@ -369,6 +411,18 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 		return nil, err
 	}
 
+	// 5) Broadcast signed transactions to multiple upstreams (when enabled) instead of single-winner forwarding
+	if n.shouldBroadcastTransaction(method) {
+		resp, err := n.broadcastTransaction(ctx, &lg, req, upsList, startTime)
+		if err != nil {
+			common.SetTraceSpanError(forwardSpan, err)
+		}
+		if mlx != nil {
+			mlx.Close(ctx, resp, err)
+		}
+		return resp, err
+	}
+
 	// 5) Iterate over upstreams and forward the request until success or fatal failure
 	tryForward := func(
 		u common.Upstream,
@@ -406,12 +460,22 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 	}
 	// This is the only way to pass additional values to failsafe policy executors context
 	ectx := context.WithValue(ctx, common.RequestContextKey, req)
+	if n.disputeRecorder != nil {
+		ectx = consensus.WithDisputeRecorder(ectx, n.disputeRecorder)
+	}
 
 	failsafeExecutor := n.getFailsafeExecutor(req)
 	if failsafeExecutor == nil {
 		return nil, errors.New("no failsafe executor found for this request")
 	}
 
+	timeoutDuration := failsafeExecutor.timeout
+	if failsafeExecutor.dynamicTimeout != nil {
+		d := upstream.ResolveTimeout(ectx, failsafeExecutor.dynamicTimeout, n.GetMethodMetrics(method))
+		ectx = upstream.WithResolvedTimeout(ectx, common.ScopeNetwork, d)
+		timeoutDuration = &d
+	}
+
 	resp, execErr := failsafeExecutor.executor.
 		WithContext(ectx).
 		GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (*common.NormalizedResponse, error) {
@@ -458,7 +522,7 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 					return nil, ctxErr
 				}
 			}
-			if failsafeExecutor.timeout != nil {
+			if timeoutDuration != nil {
 				var cancelFn context.CancelFunc
 				execSpanCtx, cancelFn = context.WithTimeout(
 					execSpanCtx,
@@ -466,7 +530,7 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 					//      Is there a way to do this cleanly? e.g. if failsafe lib works via context rather than Ticker?
 					//      5ms is a workaround to ensure context carries the timeout deadline (used when calling upstreams),
 					//      but allow the failsafe execution to fail with timeout first for proper error handling.
-					*failsafeExecutor.timeout+5*time.Millisecond,
+					*timeoutDuration+5*time.Millisecond,
 				)
 
 				defer cancelFn()
@@ -633,7 +697,7 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 		if n.cacheDal != nil {
 			resp.RLockWithTrace(ctx)
 
-			go (func(resp *common.NormalizedResponse, forwardSpan trace.Span) {
+			setCache := func(resp *common.NormalizedResponse, forwardSpan trace.Span) {
 				defer (func() {
 					if rec := recover(); rec != nil {
 						telemetry.MetricUnexpectedPanicTotal.WithLabelValues(
@@ -656,7 +720,14 @@ func (n *Network) Forward(ctx context.Context, req *common.NormalizedRequest) (*
 				if err != nil {
 					lg.Warn().Err(err).Msgf("could not store response in cache")
 				}
-			})(resp, forwardSpan)
+			}
+			if resp.IsStreaming() {
+				// Streamed results are stored by a tee which must be in place before the result is written
+				// to the client, registering it does not block on the cache driver.
+				setCache(resp, forwardSpan)
+			} else {
+				go setCache(resp, forwardSpan)
+			}
 		}
 
 		// Use the counters embedded earlier in the response
@@ -908,25 +979,18 @@ func (n *Network) waitForMultiplexResult(ctx context.Context, mlx *Multiplexer,
 	defer span.End()
 
 	// Check if result is already available
-	mlx.mu.RLock()
+	mlx.mu.Lock()
 	if mlx.resp != nil || mlx.err != nil {
-		mlx.mu.RUnlock()
-		resp, err := common.CopyResponseForRequest(ctx, mlx.resp, req)
-		if err != nil {
-			return nil, err
-		}
-		return resp, mlx.err
+		mlx.mu.Unlock()
+		return copyMultiplexedResponse(ctx, mlx, req)
 	}
-	mlx.mu.RUnlock()
+	mlx.followers++
+	mlx.mu.Unlock()
 
 	// Wait for result
 	select {
 	case <-mlx.done:
-		resp, err := common.CopyResponseForRequest(ctx, mlx.resp, req)
-		if err != nil {
-			return nil, err
-		}
-		return resp, mlx.err
+		return copyMultiplexedResponse(ctx, mlx, req)
 	case <-ctx.Done():
 		n.cleanupMultiplexer(mlx)
 		err := ctx.Err()
@@ -937,10 +1001,23 @@ func (n *Network) waitForMultiplexResult(ctx context.Context, mlx *Multiplexer,
 	}
 }
 
+func copyMultiplexedResponse(ctx context.Context, mlx *Multiplexer, req *common.NormalizedRequest) (*common.NormalizedResponse, error) {
+	resp, err := common.CopyResponseForRequest(ctx, mlx.resp, req)
+	if err != nil {
+		if errors.Is(err, common.ErrResultStreamConsumed) {
+			// Arrived after the leader already streamed its result to the client, so forward on our own
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp, mlx.err
+}
+
 func (n *Network) cleanupMultiplexer(mlx *Multiplexer) {
 	mlx.mu.Lock()
 	defer mlx.mu.Unlock()
-	n.inFlightRequests.Delete(mlx.hash)
+	// Only remove our own entry, a late follower might have already replaced it with a new multiplexer
+	n.inFlightRequests.CompareAndDelete(mlx.hash, mlx)
 }
 
 func (n *Network) shouldHandleMethod(method string, upsList []common.Upstream) error {
@@ -956,6 +1033,18 @@ func (n *Network) shouldHandleMethod(method string, upsList []common.Upstream) e
 		return common.NewErrNotImplemented("eth_accounts and eth_sign are not supported")
 	}
 
+	// Vendor-proprietary methods (e.g. alchemy_*) fail early when no upstream of this network can serve them
+	if owner := n.upstreamsRegistry.GetVendorsRegistry().LookupByMethod(method); owner != nil {
+		for _, ups := range upsList {
+			if u, ok := ups.(*upstream.Upstream); ok {
+				if allowed, err := u.ShouldHandleMethod(method); err == nil && allowed {
+					return nil
+				}
+			}
+		}
+		return common.NewErrNotImplemented(fmt.Sprintf("%s is a %s-specific method and no %s upstream is configured for network %s", method, owner.Name(), owner.Name(), n.networkId))
+	}
+
 	return nil
 }
 
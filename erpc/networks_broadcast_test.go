@@ -0,0 +1,234 @@
+package erpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/architecture/evm"
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRawTransaction = "0x02f86f0180843b9aca00850c92a69c0082520894d8da6bf26964af9d7eed9e03e53415d37aa9604588016345785d8a000080c001a0b1d7d5fcd4e0dfc1cb1e01b1e7a3df9e7d4e4d5bd43d69b8e5b7d1d1b5c2e3f4a06a0d9a8f5e1c7b3d2c4e6f8a1b3c5d7e9f1a3b5c7d9e1f3a5b7c9d1e3f5a7b9c"
+
+func setupTestNetworkWithTransactionBroadcast(t *testing.T, ctx context.Context, broadcastConfig *common.EvmTransactionBroadcastConfig) *Network {
+	t.Helper()
+
+	upstreamConfigs := []*common.UpstreamConfig{
+		{
+			Type:     common.UpstreamTypeEvm,
+			Id:       "rpc1",
+			Endpoint: "http://rpc1.localhost",
+			Evm: &common.EvmUpstreamConfig{
+				ChainId: 123,
+			},
+		},
+		{
+			Type:     common.UpstreamTypeEvm,
+			Id:       "rpc2",
+			Endpoint: "http://rpc2.localhost",
+			Evm: &common.EvmUpstreamConfig{
+				ChainId: 123,
+			},
+		},
+	}
+
+	networkConfig := &common.NetworkConfig{
+		Architecture: common.ArchitectureEvm,
+		Evm: &common.EvmNetworkConfig{
+			ChainId:              123,
+			TransactionBroadcast: broadcastConfig,
+		},
+		Failsafe: []*common.FailsafeConfig{{
+			Timeout: &common.TimeoutPolicyConfig{
+				Duration: common.Duration(2 * time.Second),
+			},
+		}},
+	}
+
+	return setupTestNetwork(t, ctx, upstreamConfigs, networkConfig)
+}
+
+func mockSendRawTransaction(host string, counter *atomic.Int32, delay time.Duration, body map[string]interface{}) {
+	gock.New(host).
+		Post("").
+		Persist().
+		Filter(func(r *http.Request) bool {
+			// Filters are evaluated against requests of every host
+			if "http://"+r.URL.Host == host && strings.Contains(util.SafeReadBody(r), "eth_sendRawTransaction") {
+				counter.Add(1)
+				return true
+			}
+			return false
+		}).
+		Reply(200).
+		Delay(delay).
+		JSON(body)
+}
+
+func TestNetwork_TransactionBroadcast(t *testing.T) {
+	txHash, err := evm.TransactionHashFromRawTransaction(testRawTransaction)
+	require.NoError(t, err)
+	requestBytes := []byte(`{"jsonrpc":"2.0","id":7,"method":"eth_sendRawTransaction","params":["` + testRawTransaction + `"]}`)
+
+	t.Run("SubmitsToAllUpstreamsAndTreatsAlreadyKnownAsSuccess", func(t *testing.T) {
+		util.ResetGock()
+		defer util.ResetGock()
+		util.SetupMocksForEvmStatePoller()
+
+		var rpc1Calls, rpc2Calls atomic.Int32
+		mockSendRawTransaction("http://rpc1.localhost", &rpc1Calls, 0, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      7,
+			"error":   map[string]interface{}{"code": -32000, "message": "already known"},
+		})
+		mockSendRawTransaction("http://rpc2.localhost", &rpc2Calls, 300*time.Millisecond, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      7,
+			"result":  txHash,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		network := setupTestNetworkWithTransactionBroadcast(t, ctx, &common.EvmTransactionBroadcastConfig{Enabled: true})
+
+		start := time.Now()
+		resp, err := network.Forward(ctx, common.NewNormalizedRequest(requestBytes))
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 300*time.Millisecond, "first success must be returned without waiting for other upstreams")
+
+		jrr, err := resp.JsonRpcResponse()
+		require.NoError(t, err)
+		assert.Equal(t, `"`+txHash+`"`, string(jrr.Result))
+		assert.Equal(t, "rpc1", resp.Upstream().Id())
+		assert.EqualValues(t, 7, jrr.ID())
+
+		// Slower upstream still receives the transaction
+		assert.Eventually(t, func() bool {
+			return rpc1Calls.Load() == 1 && rpc2Calls.Load() == 1
+		}, time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("ReturnsErrorWhenAllUpstreamsReject", func(t *testing.T) {
+		util.ResetGock()
+		defer util.ResetGock()
+		util.SetupMocksForEvmStatePoller()
+
+		var rpc1Calls, rpc2Calls atomic.Int32
+		rejected := map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      7,
+			"error":   map[string]interface{}{"code": -32000, "message": "insufficient funds for gas * price + value"},
+		}
+		mockSendRawTransaction("http://rpc1.localhost", &rpc1Calls, 0, rejected)
+		mockSendRawTransaction("http://rpc2.localhost", &rpc2Calls, 0, rejected)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		network := setupTestNetworkWithTransactionBroadcast(t, ctx, &common.EvmTransactionBroadcastConfig{Enabled: true})
+
+		resp, err := network.Forward(ctx, common.NewNormalizedRequest(requestBytes))
+		assert.Nil(t, resp)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "insufficient funds")
+		assert.Equal(t, int32(1), rpc1Calls.Load())
+		assert.Equal(t, int32(1), rpc2Calls.Load())
+	})
+
+	t.Run("NonceTooLowForSameTransactionIsSuccess", func(t *testing.T) {
+		util.ResetGock()
+		defer util.ResetGock()
+		util.SetupMocksForEvmStatePoller()
+
+		var rpc1Calls, rpc2Calls atomic.Int32
+		nonceTooLow := map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      7,
+			"error":   map[string]interface{}{"code": -32000, "message": "nonce too low: next nonce 5, tx nonce 4"},
+		}
+		mockSendRawTransaction("http://rpc1.localhost", &rpc1Calls, 0, nonceTooLow)
+		mockSendRawTransaction("http://rpc2.localhost", &rpc2Calls, 0, nonceTooLow)
+		gock.New("http://rpc1.localhost").
+			Post("").
+			Persist().
+			Filter(func(r *http.Request) bool {
+				return strings.Contains(util.SafeReadBody(r), "eth_getTransactionByHash")
+			}).
+			Reply(200).
+			JSON(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"result":  map[string]interface{}{"hash": txHash, "blockNumber": "0x10"},
+			})
+		gock.New("http://rpc2.localhost").
+			Post("").
+			Persist().
+			Filter(func(r *http.Request) bool {
+				return strings.Contains(util.SafeReadBody(r), "eth_getTransactionByHash")
+			}).
+			Reply(200).
+			JSON(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"result":  nil,
+			})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		network := setupTestNetworkWithTransactionBroadcast(t, ctx, &common.EvmTransactionBroadcastConfig{Enabled: true})
+
+		resp, err := network.Forward(ctx, common.NewNormalizedRequest(requestBytes))
+		require.NoError(t, err)
+		jrr, err := resp.JsonRpcResponse()
+		require.NoError(t, err)
+		assert.Equal(t, `"`+txHash+`"`, string(jrr.Result))
+		assert.Equal(t, "rpc1", resp.Upstream().Id())
+	})
+
+	t.Run("MaxUpstreamsWithPinnedUpstream", func(t *testing.T) {
+		util.ResetGock()
+		defer util.ResetGock()
+		util.SetupMocksForEvmStatePoller()
+
+		var rpc1Calls, rpc2Calls atomic.Int32
+		accepted := map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      7,
+			"result":  txHash,
+		}
+		mockSendRawTransaction("http://rpc1.localhost", &rpc1Calls, 0, accepted)
+		mockSendRawTransaction("http://rpc2.localhost", &rpc2Calls, 0, accepted)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		network := setupTestNetworkWithTransactionBroadcast(t, ctx, &common.EvmTransactionBroadcastConfig{
+			Enabled:      true,
+			MaxUpstreams: 1,
+		})
+		_, err := network.Forward(ctx, common.NewNormalizedRequest(requestBytes))
+		require.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, int32(1), rpc1Calls.Load()+rpc2Calls.Load())
+
+		rpc1Calls.Store(0)
+		rpc2Calls.Store(0)
+		network = setupTestNetworkWithTransactionBroadcast(t, ctx, &common.EvmTransactionBroadcastConfig{
+			Enabled:      true,
+			MaxUpstreams: 1,
+			Upstreams:    []string{"rpc*"},
+		})
+		_, err = network.Forward(ctx, common.NewNormalizedRequest(requestBytes))
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return rpc1Calls.Load() == 1 && rpc2Calls.Load() == 1
+		}, time.Second, 20*time.Millisecond)
+	})
+}
@@ -74,19 +74,29 @@ func NewNetwork(
 
 	key := fmt.Sprintf("%s/%s", projectId, nwCfg.NetworkId())
 
+	// A single retry budget covers retries and hedges of all failsafe entries of the network
+	var retryBudget *upstream.RetryBudget
+	if nwCfg.RetryBudget != nil {
+		retryBudget = upstream.NewRetryBudget(nwCfg.RetryBudget)
+	}
+
 	// Create failsafe executors from configs
 	var failsafeExecutors []*FailsafeExecutor
 	if len(nwCfg.Failsafe) > 0 {
 		for _, fsCfg := range nwCfg.Failsafe {
-			pls, err := upstream.CreateFailSafePolicies(&lg, common.ScopeNetwork, key, fsCfg)
+			pls, err := upstream.CreateFailSafePolicies(&lg, common.ScopeNetwork, key, fsCfg, retryBudget)
 			if err != nil {
 				return nil, err
 			}
-			policyArray := upstream.ToPolicyArray(pls, "timeout", "consensus", "retry", "hedge")
+			policyArray := upstream.ToPolicyArray(pls, "timeout", "consensus", "retry", "retryBudget", "hedge")
 
 			var timeoutDuration *time.Duration
+			var dynamicTimeout *common.TimeoutPolicyConfig
 			if fsCfg.Timeout != nil {
 				timeoutDuration = fsCfg.Timeout.Duration.DurationPtr()
+				if fsCfg.Timeout.Quantile > 0 {
+					dynamicTimeout = fsCfg.Timeout
+				}
 			}
 
 			method := fsCfg.MatchMethod
@@ -98,15 +108,21 @@ func NewNetwork(
 				finalities: fsCfg.MatchFinality,
 				executor:   failsafe.NewExecutor(policyArray...),
 				timeout:    timeoutDuration,
+
+				dynamicTimeout: dynamicTimeout,
 			})
 		}
 	}
 
 	// Create a default executor if no failsafe config is provided or matched
+	defaultPls, err := upstream.CreateFailSafePolicies(&lg, common.ScopeNetwork, key, nil, retryBudget)
+	if err != nil {
+		return nil, err
+	}
 	failsafeExecutors = append(failsafeExecutors, &FailsafeExecutor{
 		method:     "*", // "*" means match any method
 		finalities: nil, // nil means match any finality
-		executor:   failsafe.NewExecutor[*common.NormalizedResponse](),
+		executor:   failsafe.NewExecutor(upstream.ToPolicyArray(defaultPls, "retryBudget")...),
 		timeout:    nil,
 	})
 
@@ -266,6 +282,10 @@ func (nr *NetworksRegistry) prepareNetwork(nwCfg *common.NetworkConfig) (*Networ
 		return nil, errors.New("unknown network architecture")
 	}
 
+	if nr.project != nil && nr.project.disputeLog != nil {
+		network.disputeRecorder = nr.project.disputeLog
+	}
+
 	return network, nil
 }
 
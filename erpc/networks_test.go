@@ -9472,6 +9472,18 @@ func TestNetwork_ThunderingHerdProtection(t *testing.T) {
 			Reply(200).
 			JSON([]byte(`{"result":null}`))
 
+		// eth_getBlockByNumber("safe")
+		gock.New("http://rpc1.localhost").
+			Post("").
+			Persist().
+			Filter(func(r *http.Request) bool {
+				body := util.SafeReadBody(r)
+				return strings.Contains(body, `"eth_getBlockByNumber"`) &&
+					strings.Contains(body, `"safe"`)
+			}).
+			Reply(200).
+			JSON([]byte(`{"result":null}`))
+
 		// eth_syncing – upstream is fully synced
 		gock.New("http://rpc1.localhost").
 			Post("").
@@ -9588,8 +9600,8 @@ func TestNetwork_ThunderingHerdProtection(t *testing.T) {
 
 		// No pending or unmatched mocks remain
 		assert.False(t, gock.HasUnmatchedRequest(), "Unexpected gock requests")
-		// finalized & syncing mocks are persistent, so they remain pending
-		require.Equal(t, 2, len(gock.Pending()), "expected only the 2 persistent mocks to remain")
+		// finalized, safe & syncing mocks are persistent, so they remain pending
+		require.Equal(t, 3, len(gock.Pending()), "expected only the 3 persistent mocks to remain")
 	})
 
 	t.Run("ForwardThunderingHerdGetLatestBlockWithoutErrors", func(t *testing.T) {
@@ -9638,6 +9650,18 @@ func TestNetwork_ThunderingHerdProtection(t *testing.T) {
 			Reply(200).
 			JSON([]byte(`{"result":null}`))
 
+		// eth_getBlockByNumber("safe")
+		gock.New("http://rpc1.localhost").
+			Post("").
+			Persist().
+			Filter(func(r *http.Request) bool {
+				body := util.SafeReadBody(r)
+				return strings.Contains(body, `"eth_getBlockByNumber"`) &&
+					strings.Contains(body, `"safe"`)
+			}).
+			Reply(200).
+			JSON([]byte(`{"result":null}`))
+
 		// eth_syncing
 		gock.New("http://rpc1.localhost").
 			Post("").
@@ -9783,6 +9807,18 @@ func TestNetwork_ThunderingHerdProtection(t *testing.T) {
 			Reply(200).
 			JSON([]byte(`{"result":null}`))
 
+		// eth_getBlockByNumber("safe")
+		gock.New("http://rpc1.localhost").
+			Post("").
+			Persist().
+			Filter(func(r *http.Request) bool {
+				body := util.SafeReadBody(r)
+				return strings.Contains(body, `"eth_getBlockByNumber"`) &&
+					strings.Contains(body, `"safe"`)
+			}).
+			Reply(200).
+			JSON([]byte(`{"result":null}`))
+
 		// Counter for eth_syncing calls
 		const workers = 25
 		var syncCalls int32
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/erpc/erpc/architecture/evm"
 	"github.com/erpc/erpc/auth"
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/consensus"
 	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/upstream"
 	"github.com/erpc/erpc/util"
@@ -24,6 +26,9 @@ type PreparedProject struct {
 	consumerAuthRegistry *auth.AuthRegistry
 	rateLimitersRegistry *upstream.RateLimitersRegistry
 	upstreamsRegistry    *upstream.UpstreamsRegistry
+	disputeLog           *consensus.DisputeLog
+	firewall             *Firewall
+	capture              *Capture
 	cfgMu                sync.RWMutex
 }
 
@@ -102,26 +107,37 @@ func (p *PreparedProject) GatherHealthInfo() (*ProjectHealthInfo, error) {
 	}, nil
 }
 
-func (p *PreparedProject) AuthenticateConsumer(ctx context.Context, method string, ap *auth.AuthPayload) error {
+// AuthenticateConsumer returns the grant of the authenticated consumer which must be attached to the
+// request context (via auth.WithGrant) so that network restrictions are enforced on the forward path
+func (p *PreparedProject) AuthenticateConsumer(ctx context.Context, method string, ap *auth.AuthPayload) (*auth.Grant, error) {
 	if p.consumerAuthRegistry != nil {
-		err := p.consumerAuthRegistry.Authenticate(ctx, method, ap)
+		grant, err := p.consumerAuthRegistry.Authenticate(ctx, method, ap)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		return grant, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
 func (p *PreparedProject) Forward(ctx context.Context, networkId string, nq *common.NormalizedRequest) (*common.NormalizedResponse, error) {
 	ctx, span := common.StartDetailSpan(ctx, "Project.Forward")
 	defer span.End()
 
+	if err := auth.GrantFromContext(ctx).CheckNetwork(networkId); err != nil {
+		common.SetTraceSpanError(span, err)
+		return nil, err
+	}
 	network, err := p.networksRegistry.GetNetwork(networkId)
 	if err != nil {
 		common.SetTraceSpanError(span, err)
 		return nil, err
 	}
+	if err := p.firewall.Evaluate(ctx, network, nq); err != nil {
+		common.SetTraceSpanError(span, err)
+		return nil, err
+	}
 	if err := p.acquireRateLimitPermit(nq); err != nil {
 		common.SetTraceSpanError(span, err)
 		return nil, err
@@ -133,16 +149,21 @@ func (p *PreparedProject) Forward(ctx context.Context, networkId string, nq *com
 	reqFinality := nq.Finality(ctx)
 
 	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
-		telemetry.MetricNetworkRequestDuration.WithLabelValues(
-			p.Config.Id,
-			network.networkId,
-			method,
-			reqFinality.String(),
-		).Observe(v)
+		observeConsumerMetric(ctx, func(consumer string) {
+			telemetry.MetricNetworkRequestDuration.WithLabelValues(
+				p.Config.Id,
+				network.networkId,
+				method,
+				reqFinality.String(),
+				consumer,
+			).Observe(v)
+		})
 	}))
 	defer timer.ObserveDuration()
 
-	telemetry.MetricNetworkRequestsReceived.WithLabelValues(p.Config.Id, network.networkId, method, reqFinality.String()).Inc()
+	observeConsumerMetric(ctx, func(consumer string) {
+		telemetry.MetricNetworkRequestsReceived.WithLabelValues(p.Config.Id, network.networkId, method, reqFinality.String(), consumer).Inc()
+	})
 	lg := p.Logger.With().
 		Str("component", "proxy").
 		Str("projectId", p.Config.Id).
@@ -152,6 +173,7 @@ func (p *PreparedProject) Forward(ctx context.Context, networkId string, nq *com
 		Str("ptr", fmt.Sprintf("%p", nq)).
 		Logger()
 
+	startedAt := time.Now()
 	resp, err := p.doForward(ctx, network, nq)
 
 	shadowUpstreams := network.ShadowUpstreams()
@@ -174,6 +196,10 @@ func (p *PreparedProject) Forward(ctx context.Context, networkId string, nq *com
 		finality = resp.Finality(ctx)
 	}
 
+	if p.capture.ShouldSample(method) {
+		p.capture.Record(ctx, network.networkId, method, nq, resp, err, time.Since(startedAt), finality)
+	}
+
 	if err == nil && resp != nil {
 		upstream := resp.Upstream()
 		vendor := "n/a"
@@ -185,16 +211,20 @@ func (p *PreparedProject) Forward(ctx context.Context, networkId string, nq *com
 			vendor = upstream.VendorName()
 			upstreamId = upstream.Id()
 		}
-		telemetry.MetricNetworkSuccessfulRequests.WithLabelValues(
-			p.Config.Id,
-			network.networkId,
-			vendor,
-			upstreamId,
-			method,
-			strconv.FormatInt(int64(resp.Attempts()), 10),
-			finality.String(),
-			strconv.FormatBool(resp.IsResultEmptyish(ctx)),
-		).Inc()
+		emptyish := strconv.FormatBool(resp.IsResultEmptyish(ctx))
+		observeConsumerMetric(ctx, func(consumer string) {
+			telemetry.MetricNetworkSuccessfulRequests.WithLabelValues(
+				p.Config.Id,
+				network.networkId,
+				vendor,
+				upstreamId,
+				method,
+				strconv.FormatInt(int64(resp.Attempts()), 10),
+				finality.String(),
+				emptyish,
+				consumer,
+			).Inc()
+		})
 		if lg.GetLevel() == zerolog.TraceLevel {
 			lg.Info().Object("response", resp).Msgf("successfully forwarded request for network")
 		} else {
@@ -211,15 +241,18 @@ func (p *PreparedProject) Forward(ctx context.Context, networkId string, nq *com
 				lg.Info().Err(err).Msgf("failed to forward request for network")
 			}
 		}
-		telemetry.MetricNetworkFailedRequests.WithLabelValues(
-			network.projectId,
-			network.networkId,
-			method,
-			strconv.FormatInt(int64(resp.Attempts()), 10),
-			common.ErrorFingerprint(err),
-			string(common.ClassifySeverity(err)),
-			finality.String(),
-		).Inc()
+		observeConsumerMetric(ctx, func(consumer string) {
+			telemetry.MetricNetworkFailedRequests.WithLabelValues(
+				network.projectId,
+				network.networkId,
+				method,
+				strconv.FormatInt(int64(resp.Attempts()), 10),
+				common.ErrorFingerprint(err),
+				string(common.ClassifySeverity(err)),
+				finality.String(),
+				consumer,
+			).Inc()
+		})
 	}
 
 	return nil, err
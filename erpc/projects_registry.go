@@ -11,6 +11,7 @@ import (
 	"github.com/erpc/erpc/auth"
 	"github.com/erpc/erpc/clients"
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/consensus"
 	"github.com/erpc/erpc/data"
 	"github.com/erpc/erpc/health"
 	"github.com/erpc/erpc/thirdparty"
@@ -128,7 +129,28 @@ func (r *ProjectsRegistry) RegisterProject(prjCfg *common.ProjectConfig) (*Prepa
 
 	var consumerAuthRegistry *auth.AuthRegistry
 	if prjCfg.Auth != nil {
-		consumerAuthRegistry, err = auth.NewAuthRegistry(&lg, prjCfg.Id, prjCfg.Auth, r.rateLimitersRegistry)
+		consumerAuthRegistry, err = auth.NewAuthRegistry(r.appCtx, &lg, prjCfg.Id, prjCfg.Auth, r.rateLimitersRegistry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var disputeLog *consensus.DisputeLog
+	if prjCfg.DisputeLog != nil {
+		disputeLog, err = consensus.NewDisputeLog(r.appCtx, &lg, prjCfg.Id, prjCfg.DisputeLog)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var firewall *Firewall
+	if prjCfg.Firewall != nil {
+		firewall = NewFirewall(&lg, prjCfg.Id, prjCfg.Firewall)
+	}
+
+	var capture *Capture
+	if prjCfg.Capture != nil {
+		capture, err = NewCapture(r.appCtx, &lg, prjCfg.Id, prjCfg.Capture)
 		if err != nil {
 			return nil, err
 		}
@@ -140,6 +162,9 @@ func (r *ProjectsRegistry) RegisterProject(prjCfg *common.ProjectConfig) (*Prepa
 		upstreamsRegistry:    upstreamsRegistry,
 		consumerAuthRegistry: consumerAuthRegistry,
 		rateLimitersRegistry: r.rateLimitersRegistry,
+		disputeLog:           disputeLog,
+		firewall:             firewall,
+		capture:              capture,
 		cfgMu:                sync.RWMutex{},
 	}
 	pp.networksRegistry = NewNetworksRegistry(
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/erpc/erpc/auth"
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/data"
 	"github.com/erpc/erpc/thirdparty"
@@ -121,6 +122,86 @@ func TestProject_Forward(t *testing.T) {
 
 		log.Logger.Info().Msgf("Last Resp: %+v", lastResp)
 	})
+
+	t.Run("ForwardRejectsNetworkNotAllowedByConsumerGrant", func(t *testing.T) {
+		util.ResetGock()
+		defer util.ResetGock()
+		util.SetupMocksForEvmStatePoller()
+
+		rateLimitersRegistry, err := upstream.NewRateLimitersRegistry(&common.RateLimiterConfig{}, &log.Logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ssr, err := data.NewSharedStateRegistry(ctx, &log.Logger, &common.SharedStateConfig{
+			Connector: &common.ConnectorConfig{
+				Driver: "memory",
+				Memory: &common.MemoryConnectorConfig{
+					MaxItems: 100_000, MaxTotalSize: "1GB",
+				},
+			},
+		})
+		if err != nil {
+			panic(err)
+		}
+		prjReg, err := NewProjectsRegistry(
+			ctx,
+			&log.Logger,
+			[]*common.ProjectConfig{
+				{
+					Id: "prjA",
+					Networks: []*common.NetworkConfig{
+						{
+							Architecture: common.ArchitectureEvm,
+							Evm: &common.EvmNetworkConfig{
+								ChainId: 123,
+							},
+						},
+					},
+					Upstreams: []*common.UpstreamConfig{
+						{
+							Id:       "rpc1",
+							Endpoint: "http://rpc1.localhost",
+							Type:     common.UpstreamTypeEvm,
+							Evm: &common.EvmUpstreamConfig{
+								ChainId: 123,
+							},
+						},
+					},
+				},
+			},
+			ssr,
+			nil,
+			rateLimitersRegistry,
+			thirdparty.NewVendorsRegistry(),
+			nil, // ProxyPoolRegistry
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = prjReg.Bootstrap(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		prj, err := prjReg.GetProject("prjA")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		grantCtx := auth.WithGrant(ctx, &auth.Grant{
+			Strategy:        common.AuthTypeJwt,
+			AllowedNetworks: []string{"1", "evm:10"},
+		})
+		fakeReq := common.NewNormalizedRequest([]byte(`{"method": "eth_chainId","params":[]}`))
+		_, err = prj.Forward(grantCtx, "evm:123", fakeReq)
+		if !common.HasErrorCode(err, common.ErrCodeAuthForbidden) {
+			t.Errorf("Expected %v, got %v", common.ErrCodeAuthForbidden, err)
+		}
+	})
 }
 func TestProject_TimeoutScenarios(t *testing.T) {
 	t.Run("UpstreamTimeout", func(t *testing.T) {
@@ -0,0 +1,304 @@
+package erpc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/architecture/evm"
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/rs/zerolog"
+)
+
+const (
+	replayMaxLineSize     = 64 * 1024 * 1024
+	replayMaxMismatches   = 50
+	replayDefaultParallel = 10
+)
+
+// ReplayOptions controls how a capture file is replayed against a config
+type ReplayOptions struct {
+	File string
+	// ProjectId replays every record against this project instead of the captured one
+	ProjectId   string
+	Concurrency int
+	// IncludeRedacted also replays records whose request params were redacted
+	IncludeRedacted bool
+	// Limit stops after this many records, 0 means all records
+	Limit int
+}
+
+// ReplayLatency percentiles are in milliseconds
+type ReplayLatency struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+type ReplayMismatch struct {
+	Id        string `json:"id"`
+	NetworkId string `json:"networkId"`
+	Method    string `json:"method"`
+	Captured  string `json:"captured"`
+	Replayed  string `json:"replayed"`
+}
+
+// ReplayReport compares the correctness and latency of replayed requests with the captured ones.
+// Unverified records have no captured result hash (e.g. results that did not fit in the capture budget).
+type ReplayReport struct {
+	Total           int               `json:"total"`
+	Matched         int               `json:"matched"`
+	Mismatched      int               `json:"mismatched"`
+	Unverified      int               `json:"unverified"`
+	Skipped         int               `json:"skipped"`
+	CapturedLatency ReplayLatency     `json:"capturedLatency"`
+	ReplayedLatency ReplayLatency     `json:"replayedLatency"`
+	Mismatches      []*ReplayMismatch `json:"mismatches,omitempty"`
+}
+
+// Replay boots the projects of the config (without http/grpc servers and capture) and forwards
+// every captured request through them.
+func Replay(ctx context.Context, cfg *common.Config, logger zerolog.Logger, opts *ReplayOptions) (*ReplayReport, error) {
+	f, err := os.Open(filepath.Clean(opts.File))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %w", err)
+	}
+	defer f.Close()
+
+	for _, prjCfg := range cfg.Projects {
+		// Replayed traffic must not be captured again
+		prjCfg.Capture = nil
+	}
+
+	var evmJsonRpcCache *evm.EvmJsonRpcCache
+	var sharedState data.SharedStateRegistry
+	if cfg.Database != nil {
+		if cfg.Database.EvmJsonRpcCache != nil {
+			evmJsonRpcCache, err = evm.NewEvmJsonRpcCache(ctx, &logger, cfg.Database.EvmJsonRpcCache)
+			if err != nil {
+				logger.Warn().Msgf("failed to initialize evm json rpc cache: %v", err)
+			}
+		}
+		if cfg.Database.SharedState != nil {
+			sharedState, err = data.NewSharedStateRegistry(ctx, &logger, cfg.Database.SharedState)
+			if err != nil {
+				logger.Warn().Msgf("failed to initialize shared state registry: %v", err)
+			}
+		}
+	}
+	erpcInstance, err := NewERPC(ctx, &logger, sharedState, evmJsonRpcCache, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := erpcInstance.Bootstrap(ctx); err != nil {
+		return nil, err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = replayDefaultParallel
+	}
+
+	report := &ReplayReport{}
+	var mu sync.Mutex
+	var capturedLatencies, replayedLatencies []float64
+
+	records := make(chan *CaptureRecord)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for record := range records {
+				outcome, mismatch, latency := replayRecord(ctx, erpcInstance, opts, record)
+				mu.Lock()
+				switch outcome {
+				case replayOutcomeMatched:
+					report.Matched++
+				case replayOutcomeMismatched:
+					report.Mismatched++
+					if len(report.Mismatches) < replayMaxMismatches {
+						report.Mismatches = append(report.Mismatches, mismatch)
+					}
+				case replayOutcomeUnverified:
+					report.Unverified++
+				case replayOutcomeSkipped:
+					report.Skipped++
+				}
+				if outcome != replayOutcomeSkipped {
+					capturedLatencies = append(capturedLatencies, record.LatencyMs)
+					replayedLatencies = append(replayedLatencies, float64(latency.Microseconds())/1000)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 1024*1024), replayMaxLineSize)
+	var scanErr error
+	for scanner.Scan() {
+		if opts.Limit > 0 && report.Total >= opts.Limit {
+			break
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		record := &CaptureRecord{}
+		if err := common.SonicCfg.Unmarshal(line, record); err != nil {
+			scanErr = fmt.Errorf("failed to parse capture record #%d: %w", report.Total+1, err)
+			break
+		}
+		report.Total++
+		select {
+		case records <- record:
+		case <-ctx.Done():
+			scanErr = ctx.Err()
+		}
+		if scanErr != nil {
+			break
+		}
+	}
+	close(records)
+	wg.Wait()
+
+	if scanErr == nil {
+		scanErr = scanner.Err()
+	}
+	if scanErr != nil {
+		return report, scanErr
+	}
+
+	report.CapturedLatency = latencyPercentiles(capturedLatencies)
+	report.ReplayedLatency = latencyPercentiles(replayedLatencies)
+
+	return report, nil
+}
+
+type replayOutcome int
+
+const (
+	replayOutcomeMatched replayOutcome = iota
+	replayOutcomeMismatched
+	replayOutcomeUnverified
+	replayOutcomeSkipped
+)
+
+func replayRecord(ctx context.Context, e *ERPC, opts *ReplayOptions, record *CaptureRecord) (replayOutcome, *ReplayMismatch, time.Duration) {
+	lg := e.logger.With().Str("captureId", record.Id).Logger()
+	if record.Redacted && !opts.IncludeRedacted {
+		return replayOutcomeSkipped, nil, 0
+	}
+	projectId := record.ProjectId
+	if opts.ProjectId != "" {
+		projectId = opts.ProjectId
+	}
+	project, err := e.GetProject(projectId)
+	if err != nil {
+		lg.Warn().Err(err).Msg("skipping captured request of unknown project")
+		return replayOutcomeSkipped, nil, 0
+	}
+	nw, err := project.GetNetwork(record.NetworkId)
+	if err != nil {
+		lg.Warn().Err(err).Msg("skipping captured request of unknown network")
+		return replayOutcomeSkipped, nil, 0
+	}
+
+	nq := common.NewNormalizedRequest(record.Request)
+	nq.SetNetwork(nw)
+	nq.ApplyDirectiveDefaults(nw.Config().DirectiveDefaults)
+
+	startedAt := time.Now()
+	resp, err := project.Forward(ctx, record.NetworkId, nq)
+	latency := time.Since(startedAt)
+	if resp != nil {
+		defer resp.Release()
+	}
+
+	replayed := describeReplayOutcome(ctx, resp, err)
+	captured := ""
+	if record.Error != nil {
+		captured = fmt.Sprintf("error %d: %s", record.Error.Code, record.Error.Message)
+	} else if record.ResultHash != "" {
+		captured = "result " + record.ResultHash
+	}
+
+	mismatch := &ReplayMismatch{
+		Id:        record.Id,
+		NetworkId: record.NetworkId,
+		Method:    record.Method,
+		Captured:  captured,
+		Replayed:  replayed.summary,
+	}
+	switch {
+	case record.Error != nil && replayed.err != nil:
+		if record.Error.Code == replayed.err.Code {
+			return replayOutcomeMatched, nil, latency
+		}
+		return replayOutcomeMismatched, mismatch, latency
+	case record.Error != nil || replayed.err != nil:
+		return replayOutcomeMismatched, mismatch, latency
+	case record.ResultHash == "" || replayed.hash == "":
+		return replayOutcomeUnverified, nil, latency
+	case record.ResultHash == replayed.hash:
+		return replayOutcomeMatched, nil, latency
+	default:
+		return replayOutcomeMismatched, mismatch, latency
+	}
+}
+
+type replayedResult struct {
+	hash    string
+	err     *CaptureError
+	summary string
+}
+
+func describeReplayOutcome(ctx context.Context, resp *common.NormalizedResponse, err error) *replayedResult {
+	r := &replayedResult{}
+	if err == nil && resp != nil {
+		jrr, jerr := resp.JsonRpcResponse(ctx)
+		switch {
+		case jerr != nil:
+			err = jerr
+		case jrr == nil:
+		case jrr.Error != nil:
+			r.err = &CaptureError{Code: jrr.Error.Code, Message: jrr.Error.Message}
+		default:
+			r.hash, _ = jrr.CanonicalHash(ctx)
+		}
+	}
+	if err != nil {
+		r.err = newCaptureError(err)
+	}
+	if r.err != nil {
+		r.summary = fmt.Sprintf("error %d: %s", r.err.Code, r.err.Message)
+	} else if r.hash != "" {
+		r.summary = "result " + r.hash
+	}
+	return r
+}
+
+func latencyPercentiles(values []float64) ReplayLatency {
+	if len(values) == 0 {
+		return ReplayLatency{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	at := func(p float64) float64 {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+	return ReplayLatency{
+		P50: at(0.50),
+		P90: at(0.90),
+		P99: at(0.99),
+		Max: sorted[len(sorted)-1],
+	}
+}
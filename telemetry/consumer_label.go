@@ -0,0 +1,153 @@
+package telemetry
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// ConsumerLabelOther is reported for consumers outside the current top N
+	ConsumerLabelOther = "other"
+	// ConsumerLabelUnknown is reported when the consumer cannot be identified (e.g. no auth or missing header)
+	ConsumerLabelUnknown = "unknown"
+
+	// consumerCandidatesFactor bounds how many distinct consumers are counted per window, relative to maxValues
+	consumerCandidatesFactor = 4
+)
+
+// ConsumerLabelLimiter bounds the cardinality of the consumer metric label to the N most active consumers.
+// Consumers are counted per window (with bounded memory, evicting the least active candidate when full) and at
+// the end of each window the top N become the admitted label values for the next one. Until N values are
+// admitted, new consumers are admitted on first sight so the label is useful right after startup.
+type ConsumerLabelLimiter struct {
+	// mu is held for reading while metrics are written (see Observe) and for writing on rotation, so
+	// series of evicted consumers are never recreated by in-flight requests after they are deleted.
+	mu            sync.RWMutex
+	maxValues     int
+	maxCandidates int
+	window        time.Duration
+	windowStart   time.Time
+	admitted      map[string]struct{}
+	counts        map[string]int64
+	onEvict       func(consumer string)
+}
+
+func NewConsumerLabelLimiter(maxValues int, window time.Duration) *ConsumerLabelLimiter {
+	return &ConsumerLabelLimiter{
+		maxValues:     maxValues,
+		maxCandidates: maxValues * consumerCandidatesFactor,
+		window:        window,
+		windowStart:   time.Now(),
+		admitted:      make(map[string]struct{}, maxValues),
+		counts:        make(map[string]int64, maxValues*consumerCandidatesFactor),
+	}
+}
+
+// OnEvict registers a callback invoked with each consumer that drops out of the admitted values on rotation,
+// so its metric series can be removed instead of lingering in the exporter. It runs under the limiter lock,
+// so no metric is written through Observe meanwhile.
+func (l *ConsumerLabelLimiter) OnEvict(cb func(consumer string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onEvict = cb
+}
+
+// Label counts a request of the consumer and returns the label value to report for it.
+func (l *ConsumerLabelLimiter) Label(consumer string) string {
+	if consumer == "" {
+		return ConsumerLabelUnknown
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if time.Since(l.windowStart) >= l.window {
+		l.rotate()
+	}
+	l.count(consumer)
+
+	if _, ok := l.admitted[consumer]; ok {
+		return consumer
+	}
+	if len(l.admitted) < l.maxValues {
+		l.admitted[consumer] = struct{}{}
+		return consumer
+	}
+	return ConsumerLabelOther
+}
+
+// Observe calls write with the label to report for a label returned earlier by Label. Consumers evicted
+// since then are reported as "other", and their series cannot be deleted while write runs.
+func (l *ConsumerLabelLimiter) Observe(label string, write func(label string)) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if label != ConsumerLabelOther && label != ConsumerLabelUnknown {
+		if _, ok := l.admitted[label]; !ok {
+			label = ConsumerLabelOther
+		}
+	}
+	write(label)
+}
+
+// count increments the consumer, replacing the least active candidate when the table is full
+// (space-saving), so a newly active heavy consumer can still make it into the top N.
+func (l *ConsumerLabelLimiter) count(consumer string) {
+	if _, ok := l.counts[consumer]; ok || len(l.counts) < l.maxCandidates {
+		l.counts[consumer]++
+		return
+	}
+	var minKey string
+	var minCount int64 = -1
+	for k, c := range l.counts {
+		if minCount < 0 || c < minCount {
+			minKey, minCount = k, c
+		}
+	}
+	delete(l.counts, minKey)
+	l.counts[consumer] = minCount + 1
+}
+
+func (l *ConsumerLabelLimiter) rotate() {
+	ranked := make([]string, 0, len(l.counts))
+	for k := range l.counts {
+		ranked = append(ranked, k)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if l.counts[ranked[i]] != l.counts[ranked[j]] {
+			return l.counts[ranked[i]] > l.counts[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	if len(ranked) > l.maxValues {
+		ranked = ranked[:l.maxValues]
+	}
+
+	previous := l.admitted
+	l.admitted = make(map[string]struct{}, l.maxValues)
+	for _, k := range ranked {
+		l.admitted[k] = struct{}{}
+	}
+	if l.onEvict != nil {
+		for k := range previous {
+			if _, ok := l.admitted[k]; !ok {
+				l.onEvict(k)
+			}
+		}
+	}
+	l.counts = make(map[string]int64, l.maxCandidates)
+	l.windowStart = time.Now()
+}
+
+// DeleteConsumerSeries removes all network request series labeled with the consumer.
+func DeleteConsumerSeries(consumer string) {
+	labels := prometheus.Labels{"consumer": consumer}
+	MetricNetworkRequestsReceived.DeletePartialMatch(labels)
+	MetricNetworkFailedRequests.DeletePartialMatch(labels)
+	MetricNetworkSuccessfulRequests.DeletePartialMatch(labels)
+	if MetricNetworkRequestDuration != nil {
+		MetricNetworkRequestDuration.DeletePartialMatch(labels)
+	}
+}
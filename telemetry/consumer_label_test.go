@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerLabelLimiter(t *testing.T) {
+	t.Run("reports unknown for unidentified consumers", func(t *testing.T) {
+		l := NewConsumerLabelLimiter(2, time.Minute)
+		assert.Equal(t, ConsumerLabelUnknown, l.Label(""))
+	})
+
+	t.Run("admits first consumers until the limit then reports other", func(t *testing.T) {
+		l := NewConsumerLabelLimiter(2, time.Minute)
+		assert.Equal(t, "alice", l.Label("alice"))
+		assert.Equal(t, "bob", l.Label("bob"))
+		assert.Equal(t, ConsumerLabelOther, l.Label("carol"))
+		assert.Equal(t, "alice", l.Label("alice"))
+	})
+
+	t.Run("admits the most active consumers after each window", func(t *testing.T) {
+		l := NewConsumerLabelLimiter(2, time.Minute)
+		l.Label("alice")
+		l.Label("bob")
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, ConsumerLabelOther, l.Label("carol"))
+		}
+		l.Label("alice")
+
+		l.windowStart = time.Now().Add(-2 * time.Minute)
+		assert.Equal(t, "carol", l.Label("carol"))
+		assert.Equal(t, "alice", l.Label("alice"))
+		assert.Equal(t, ConsumerLabelOther, l.Label("bob"))
+	})
+
+	t.Run("keeps a bounded number of candidates", func(t *testing.T) {
+		l := NewConsumerLabelLimiter(1, time.Minute)
+		for _, c := range []string{"a", "b", "c", "d", "e", "f"} {
+			l.Label(c)
+		}
+		assert.LessOrEqual(t, len(l.counts), consumerCandidatesFactor)
+	})
+
+	t.Run("deletes series of evicted consumers", func(t *testing.T) {
+		if MetricNetworkRequestDuration == nil {
+			require.NoError(t, SetHistogramBuckets("0.05,0.5,5,30"))
+		}
+		l := NewConsumerLabelLimiter(1, time.Minute)
+		l.OnEvict(DeleteConsumerSeries)
+		project := "consumer-label-eviction-test"
+		record := func(consumer string) {
+			MetricNetworkRequestsReceived.WithLabelValues(project, "evm:1", "eth_call", "unknown", consumer).Inc()
+			MetricNetworkFailedRequests.WithLabelValues(project, "evm:1", "eth_call", "1", "ErrTest", "critical", "unknown", consumer).Inc()
+			MetricNetworkSuccessfulRequests.WithLabelValues(project, "evm:1", "vendor", "rpc1", "eth_call", "1", "unknown", "false", consumer).Inc()
+			MetricNetworkRequestDuration.WithLabelValues(project, "evm:1", "eth_call", "unknown", consumer).Observe(0.1)
+		}
+		record(l.Label("alice"))
+		assert.Equal(t, 1.0, testutil.ToFloat64(MetricNetworkRequestsReceived.WithLabelValues(project, "evm:1", "eth_call", "unknown", "alice")))
+
+		for i := 0; i < 5; i++ {
+			l.Label("bob")
+		}
+		l.windowStart = time.Now().Add(-2 * time.Minute)
+		assert.Equal(t, "bob", l.Label("bob"))
+
+		// DeleteLabelValues reports false when the series no longer exists
+		assert.False(t, MetricNetworkRequestsReceived.DeleteLabelValues(project, "evm:1", "eth_call", "unknown", "alice"))
+		assert.False(t, MetricNetworkFailedRequests.DeleteLabelValues(project, "evm:1", "eth_call", "1", "ErrTest", "critical", "unknown", "alice"))
+		assert.False(t, MetricNetworkSuccessfulRequests.DeleteLabelValues(project, "evm:1", "vendor", "rpc1", "eth_call", "1", "unknown", "false", "alice"))
+		assert.False(t, MetricNetworkRequestDuration.DeleteLabelValues(project, "evm:1", "eth_call", "unknown", "alice"))
+	})
+
+	t.Run("reports evicted consumers of in-flight requests as other", func(t *testing.T) {
+		l := NewConsumerLabelLimiter(1, time.Minute)
+		label := l.Label("alice")
+		for i := 0; i < 5; i++ {
+			l.Label("bob")
+		}
+		l.windowStart = time.Now().Add(-2 * time.Minute)
+		l.Label("bob")
+
+		var observed string
+		l.Observe(label, func(consumer string) { observed = consumer })
+		assert.Equal(t, ConsumerLabelOther, observed)
+		l.Observe("bob", func(consumer string) { observed = consumer })
+		assert.Equal(t, "bob", observed)
+		l.Observe(ConsumerLabelUnknown, func(consumer string) { observed = consumer })
+		assert.Equal(t, ConsumerLabelUnknown, observed)
+	})
+}
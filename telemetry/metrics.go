@@ -64,6 +64,12 @@ var (
 		Help:      "Total number of blocks (head) behind the most up-to-date upstream.",
 	}, []string{"project", "vendor", "network", "upstream"})
 
+	MetricUpstreamHeadSubscriptionEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "upstream_head_subscription_events_total",
+		Help:      "Total number of newHeads subscription events of an upstream (connected, disconnected or head).",
+	}, []string{"project", "vendor", "network", "upstream", "event"})
+
 	MetricUpstreamFinalizationLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "erpc",
 		Name:      "upstream_finalization_lag",
@@ -154,6 +160,18 @@ var (
 		Help:      "Total number of times the finalized block was pro-actively polled from an upstream.",
 	}, []string{"project", "vendor", "network", "upstream"})
 
+	MetricUpstreamHeadForked = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "erpc",
+		Name:      "upstream_head_forked",
+		Help:      "Whether latest block hash of upstream disagrees with the majority of upstreams at the same height (1) or not (0).",
+	}, []string{"project", "vendor", "network", "upstream"})
+
+	MetricSharedStateLeader = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "erpc",
+		Name:      "shared_state_leader",
+		Help:      "Whether this instance currently holds leadership for a singleton background job (1) or not (0).",
+	}, []string{"key"})
+
 	MetricUpstreamBlockHeadLargeRollback = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "erpc",
 		Name:      "upstream_block_head_large_rollback",
@@ -176,7 +194,7 @@ var (
 		Namespace: "erpc",
 		Name:      "network_request_received_total",
 		Help:      "Total number of requests received for a network.",
-	}, []string{"project", "network", "category", "finality"})
+	}, []string{"project", "network", "category", "finality", "consumer"})
 
 	MetricNetworkMultiplexedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "erpc",
@@ -196,17 +214,29 @@ var (
 		Help:      "Total number of hedged requests discarded towards a network (i.e. attempt > 1 means wasted requests).",
 	}, []string{"project", "network", "upstream", "category", "attempt", "hedge", "finality"})
 
+	MetricNetworkTransactionBroadcastTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "network_transaction_broadcast_total",
+		Help:      "Total number of raw transactions broadcasted to upstreams by outcome (accepted, already_known, already_mined, rejected).",
+	}, []string{"project", "network", "upstream", "outcome"})
+
+	MetricRetryBudgetExhaustedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "retry_budget_exhausted_total",
+		Help:      "Total number of retries or hedges denied because the failsafe retry budget was exhausted.",
+	}, []string{"scope", "entity", "category", "kind"}) // kind: retry, hedge
+
 	MetricNetworkFailedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "erpc",
 		Name:      "network_failed_request_total",
 		Help:      "Total number of failed requests for a network.",
-	}, []string{"project", "network", "category", "attempt", "error", "severity", "finality"})
+	}, []string{"project", "network", "category", "attempt", "error", "severity", "finality", "consumer"})
 
 	MetricNetworkSuccessfulRequests = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "erpc",
 		Name:      "network_successful_request_total",
 		Help:      "Total number of successful requests for a network.",
-	}, []string{"project", "network", "vendor", "upstream", "category", "attempt", "finality", "emptyish"})
+	}, []string{"project", "network", "vendor", "upstream", "category", "attempt", "finality", "emptyish", "consumer"})
 
 	MetricProjectRequestSelfRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "erpc",
@@ -226,6 +256,30 @@ var (
 		Help:      "Total number of self-imposed (locally) rate limited requests due to auth config for a project.",
 	}, []string{"project", "strategy", "category"})
 
+	MetricAuthSecretKeyRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "auth_secret_key_requests_total",
+		Help:      "Total number of requests authenticated with a hashed secret key, by key owner and outcome (accepted, revoked or expired).",
+	}, []string{"project", "owner", "outcome"})
+
+	MetricAuthWebhookDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "auth_webhook_decisions_total",
+		Help:      "Total number of authorization webhook decisions by outcome (allowed, denied, failed_open or failed_closed) and source (service or cache).",
+	}, []string{"project", "outcome", "source"})
+
+	MetricFirewallRuleHitsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "firewall_rule_hits_total",
+		Help:      "Total number of requests matched by a firewall rule, by rule id and action (allow or deny).",
+	}, []string{"project", "network", "category", "rule", "action"})
+
+	MetricCaptureRecordsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "erpc",
+		Name:      "capture_records_total",
+		Help:      "Total number of sampled request/response records by outcome (written, dropped or failed).",
+	}, []string{"project", "outcome"})
+
 	MetricCacheSetSuccessTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "erpc",
 		Name:      "cache_set_success_total",
@@ -459,7 +513,7 @@ func SetHistogramBuckets(bucketsStr string) error {
 		Name:      "network_request_duration_seconds",
 		Help:      "Duration of requests for a network.",
 		Buckets:   buckets,
-	}, []string{"project", "network", "category", "finality"})
+	}, []string{"project", "network", "category", "finality", "consumer"})
 
 	MetricCacheSetSuccessDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "erpc",
@@ -525,3 +579,53 @@ func ParseHistogramBuckets(bucketsStr string) ([]float64, error) {
 	sort.Float64s(buckets)
 	return buckets, nil
 }
+
+// DeleteUpstreamSeries removes all series labeled with an upstream of a project, e.g. once it is deregistered.
+func DeleteUpstreamSeries(project, upstream string) {
+	labels := prometheus.Labels{"project": project, "upstream": upstream}
+	for _, vec := range []interface {
+		DeletePartialMatch(labels prometheus.Labels) int
+	}{
+		MetricUpstreamRequestTotal,
+		MetricUpstreamErrorTotal,
+		MetricUpstreamSelfRateLimitedTotal,
+		MetricUpstreamRemoteRateLimitedTotal,
+		MetricUpstreamSkippedTotal,
+		MetricUpstreamMissingDataErrorTotal,
+		MetricUpstreamEmptyResponseTotal,
+		MetricUpstreamBlockHeadLag,
+		MetricUpstreamHeadSubscriptionEvents,
+		MetricUpstreamFinalizationLag,
+		MetricUpstreamScoreOverall,
+		MetricUpstreamLatestBlockNumber,
+		MetricUpstreamFinalizedBlockNumber,
+		MetricUpstreamCordoned,
+		MetricUpstreamStaleLatestBlock,
+		MetricUpstreamStaleFinalizedBlock,
+		MetricUpstreamStaleUpperBound,
+		MetricUpstreamStaleLowerBound,
+		MetricUpstreamEvmGetLogsRangeExceededAutoSplittingThreshold,
+		MetricUpstreamEvmGetLogsSplitSuccess,
+		MetricUpstreamEvmGetLogsSplitFailure,
+		MetricUpstreamEvmGetLogsForcedSplits,
+		MetricUpstreamLatestBlockPolled,
+		MetricUpstreamFinalizedBlockPolled,
+		MetricUpstreamHeadForked,
+		MetricUpstreamBlockHeadLargeRollback,
+		MetricUpstreamWrongEmptyResponseTotal,
+		MetricNetworkHedgedRequestTotal,
+		MetricNetworkHedgeDiscardsTotal,
+		MetricNetworkTransactionBroadcastTotal,
+		MetricNetworkSuccessfulRequests,
+		MetricShadowResponseIdenticalTotal,
+		MetricShadowResponseMismatchTotal,
+		MetricShadowResponseErrorTotal,
+		MetricConsensusMisbehaviorDetected,
+		MetricConsensusUpstreamPunished,
+	} {
+		vec.DeletePartialMatch(labels)
+	}
+	if MetricUpstreamRequestDuration != nil {
+		MetricUpstreamRequestDuration.DeletePartialMatch(labels)
+	}
+}
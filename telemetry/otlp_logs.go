@@ -0,0 +1,121 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/rs/zerolog"
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+// OtlpLogWriter is meant to be added next to the regular log output (e.g. via zerolog.MultiLevelWriter).
+// It does nothing until an OpenTelemetry logger is set with SetOtlpLogger, after which every zerolog event
+// is forwarded as a log record, with the message as body and the remaining fields as attributes.
+var OtlpLogWriter zerolog.LevelWriter = &otlpLogWriter{}
+
+type otlpLogWriter struct {
+	logger atomic.Pointer[otlpLoggerHolder]
+}
+
+type otlpLoggerHolder struct {
+	logger otellog.Logger
+}
+
+// SetOtlpLogger enables (or with nil, disables) forwarding of logs written to OtlpLogWriter.
+func SetOtlpLogger(logger otellog.Logger) {
+	w := OtlpLogWriter.(*otlpLogWriter)
+	if logger == nil {
+		w.logger.Store(nil)
+		return
+	}
+	w.logger.Store(&otlpLoggerHolder{logger: logger})
+}
+
+func (w *otlpLogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *otlpLogWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	holder := w.logger.Load()
+	if holder == nil {
+		return len(p), nil
+	}
+
+	var fields map[string]interface{}
+	if err := sonic.Unmarshal(p, &fields); err != nil {
+		// Not a JSON event, forward as-is so nothing is silently dropped
+		fields = map[string]interface{}{zerolog.MessageFieldName: string(p)}
+	}
+
+	now := time.Now()
+	var record otellog.Record
+	record.SetTimestamp(now)
+	record.SetObservedTimestamp(now)
+	record.SetSeverity(zerologSeverity(level))
+	if level != zerolog.NoLevel {
+		record.SetSeverityText(level.String())
+	}
+
+	if msg, ok := fields[zerolog.MessageFieldName]; ok {
+		record.SetBody(otellog.StringValue(fmt.Sprint(msg)))
+	}
+	attrs := make([]otellog.KeyValue, 0, len(fields))
+	for k, v := range fields {
+		switch k {
+		case zerolog.MessageFieldName, zerolog.LevelFieldName, zerolog.TimestampFieldName:
+			continue
+		}
+		attrs = append(attrs, otellog.KeyValue{Key: k, Value: otlpLogValue(v)})
+	}
+	record.AddAttributes(attrs...)
+
+	holder.logger.Emit(context.Background(), record)
+	return len(p), nil
+}
+
+func otlpLogValue(v interface{}) otellog.Value {
+	switch tv := v.(type) {
+	case string:
+		return otellog.StringValue(tv)
+	case bool:
+		return otellog.BoolValue(tv)
+	case float64:
+		if tv == float64(int64(tv)) {
+			return otellog.Int64Value(int64(tv))
+		}
+		return otellog.Float64Value(tv)
+	case nil:
+		return otellog.Value{}
+	default:
+		// Nested objects and arrays are kept as their JSON representation
+		s, err := sonic.MarshalString(tv)
+		if err != nil {
+			return otellog.StringValue(fmt.Sprint(tv))
+		}
+		return otellog.StringValue(s)
+	}
+}
+
+func zerologSeverity(level zerolog.Level) otellog.Severity {
+	switch level {
+	case zerolog.TraceLevel:
+		return otellog.SeverityTrace
+	case zerolog.DebugLevel:
+		return otellog.SeverityDebug
+	case zerolog.InfoLevel:
+		return otellog.SeverityInfo
+	case zerolog.WarnLevel:
+		return otellog.SeverityWarn
+	case zerolog.ErrorLevel:
+		return otellog.SeverityError
+	case zerolog.FatalLevel:
+		return otellog.SeverityFatal
+	case zerolog.PanicLevel:
+		return otellog.SeverityFatal4
+	default:
+		return otellog.SeverityUndefined
+	}
+}
@@ -0,0 +1,245 @@
+package telemetry
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+var _ sdkmetric.Producer = (*PrometheusProducer)(nil)
+
+// PrometheusProducer exposes everything registered with the Prometheus client as OpenTelemetry metrics,
+// so the OTLP exporter pushes the exact same metric names and labels that are served on the /metrics endpoint.
+// Prometheus keeps cumulative values; when delta temporality is requested the difference since the previous
+// collection is computed here, as the SDK only applies temporality to its own instruments.
+type PrometheusProducer struct {
+	gatherer    prometheus.Gatherer
+	temporality metricdata.Temporality
+	startTime   time.Time
+
+	mu       sync.Mutex
+	lastTime time.Time
+	previous map[string]*previousPoint
+}
+
+type previousPoint struct {
+	value        float64
+	count        uint64
+	bucketCounts []uint64
+}
+
+func NewPrometheusProducer(gatherer prometheus.Gatherer, temporality metricdata.Temporality) *PrometheusProducer {
+	now := time.Now()
+	return &PrometheusProducer{
+		gatherer:    gatherer,
+		temporality: temporality,
+		startTime:   now,
+		lastTime:    now,
+		previous:    make(map[string]*previousPoint),
+	}
+}
+
+func (p *PrometheusProducer) Produce(ctx context.Context) ([]metricdata.ScopeMetrics, error) {
+	families, err := p.gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	startTime := p.startTime
+	if p.temporality == metricdata.DeltaTemporality {
+		startTime = p.lastTime
+	}
+	p.lastTime = now
+
+	// Only series seen in this collection are kept, so that deleted series (e.g. evicted consumers) do not
+	// pile up. On a partial gather the missing series are carried over to not report their total as a delta.
+	next := make(map[string]*previousPoint, len(p.previous))
+	if err != nil {
+		for key, point := range p.previous {
+			next[key] = point
+		}
+	}
+
+	metrics := make([]metricdata.Metrics, 0, len(families))
+	for _, family := range families {
+		var data metricdata.Aggregation
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			data = p.convertCounter(family, next, startTime, now)
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			data = convertGauge(family, now)
+		case dto.MetricType_HISTOGRAM:
+			data = p.convertHistogram(family, next, startTime, now)
+		case dto.MetricType_SUMMARY:
+			data = p.convertSummary(family, now)
+		default:
+			continue
+		}
+		metrics = append(metrics, metricdata.Metrics{
+			Name:        family.GetName(),
+			Description: family.GetHelp(),
+			Data:        data,
+		})
+	}
+
+	if p.temporality == metricdata.DeltaTemporality {
+		p.previous = next
+	}
+
+	return []metricdata.ScopeMetrics{
+		{
+			Scope:   instrumentation.Scope{Name: "github.com/erpc/erpc"},
+			Metrics: metrics,
+		},
+	}, err
+}
+
+func (p *PrometheusProducer) convertCounter(family *dto.MetricFamily, next map[string]*previousPoint, startTime, now time.Time) metricdata.Sum[float64] {
+	points := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+	for _, m := range family.GetMetric() {
+		value := m.GetCounter().GetValue()
+		if p.temporality == metricdata.DeltaTemporality {
+			key := pointKey(family.GetName(), m.GetLabel())
+			prev := p.previous[key]
+			next[key] = &previousPoint{value: value}
+			// A lower value means the counter was reset (e.g. re-registered), so the whole value is new
+			if prev != nil && value >= prev.value {
+				value -= prev.value
+			}
+		}
+		points = append(points, metricdata.DataPoint[float64]{
+			Attributes: labelsToAttributes(m.GetLabel()),
+			StartTime:  startTime,
+			Time:       now,
+			Value:      value,
+		})
+	}
+	return metricdata.Sum[float64]{
+		DataPoints:  points,
+		Temporality: p.temporality,
+		IsMonotonic: true,
+	}
+}
+
+func convertGauge(family *dto.MetricFamily, now time.Time) metricdata.Gauge[float64] {
+	points := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+	for _, m := range family.GetMetric() {
+		value := m.GetGauge().GetValue()
+		if family.GetType() == dto.MetricType_UNTYPED {
+			value = m.GetUntyped().GetValue()
+		}
+		points = append(points, metricdata.DataPoint[float64]{
+			Attributes: labelsToAttributes(m.GetLabel()),
+			Time:       now,
+			Value:      value,
+		})
+	}
+	return metricdata.Gauge[float64]{DataPoints: points}
+}
+
+func (p *PrometheusProducer) convertHistogram(family *dto.MetricFamily, next map[string]*previousPoint, startTime, now time.Time) metricdata.Histogram[float64] {
+	points := make([]metricdata.HistogramDataPoint[float64], 0, len(family.GetMetric()))
+	for _, m := range family.GetMetric() {
+		h := m.GetHistogram()
+
+		// Prometheus buckets are cumulative with an implicit +Inf bucket, OTLP expects per-bucket counts
+		// with one more count than bounds for the overflow bucket.
+		bounds := make([]float64, 0, len(h.GetBucket()))
+		counts := make([]uint64, 0, len(h.GetBucket())+1)
+		var cumulative uint64
+		for _, b := range h.GetBucket() {
+			if math.IsInf(b.GetUpperBound(), 1) {
+				continue
+			}
+			bounds = append(bounds, b.GetUpperBound())
+			counts = append(counts, b.GetCumulativeCount()-cumulative)
+			cumulative = b.GetCumulativeCount()
+		}
+		counts = append(counts, h.GetSampleCount()-cumulative)
+
+		count, sum := h.GetSampleCount(), h.GetSampleSum()
+		if p.temporality == metricdata.DeltaTemporality {
+			key := pointKey(family.GetName(), m.GetLabel())
+			prev := p.previous[key]
+			next[key] = &previousPoint{value: sum, count: count, bucketCounts: counts}
+			if prev != nil && count >= prev.count && len(prev.bucketCounts) == len(counts) {
+				count -= prev.count
+				sum -= prev.value
+				delta := make([]uint64, len(counts))
+				for i := range counts {
+					delta[i] = counts[i] - prev.bucketCounts[i]
+				}
+				counts = delta
+			}
+		}
+
+		points = append(points, metricdata.HistogramDataPoint[float64]{
+			Attributes:   labelsToAttributes(m.GetLabel()),
+			StartTime:    startTime,
+			Time:         now,
+			Count:        count,
+			Sum:          sum,
+			Bounds:       bounds,
+			BucketCounts: counts,
+		})
+	}
+	return metricdata.Histogram[float64]{
+		DataPoints:  points,
+		Temporality: p.temporality,
+	}
+}
+
+// convertSummary always reports cumulative values, as quantiles cannot be turned into deltas.
+func (p *PrometheusProducer) convertSummary(family *dto.MetricFamily, now time.Time) metricdata.Summary {
+	points := make([]metricdata.SummaryDataPoint, 0, len(family.GetMetric()))
+	for _, m := range family.GetMetric() {
+		s := m.GetSummary()
+		quantiles := make([]metricdata.QuantileValue, 0, len(s.GetQuantile()))
+		for _, q := range s.GetQuantile() {
+			quantiles = append(quantiles, metricdata.QuantileValue{
+				Quantile: q.GetQuantile(),
+				Value:    q.GetValue(),
+			})
+		}
+		points = append(points, metricdata.SummaryDataPoint{
+			Attributes:     labelsToAttributes(m.GetLabel()),
+			StartTime:      p.startTime,
+			Time:           now,
+			Count:          s.GetSampleCount(),
+			Sum:            s.GetSampleSum(),
+			QuantileValues: quantiles,
+		})
+	}
+	return metricdata.Summary{DataPoints: points}
+}
+
+func labelsToAttributes(labels []*dto.LabelPair) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, len(labels))
+	for _, l := range labels {
+		kvs = append(kvs, attribute.String(l.GetName(), l.GetValue()))
+	}
+	return attribute.NewSet(kvs...)
+}
+
+func pointKey(name string, labels []*dto.LabelPair) string {
+	parts := make([]string, 0, len(labels))
+	for _, l := range labels {
+		parts = append(parts, l.GetName()+"="+l.GetValue())
+	}
+	sort.Strings(parts)
+	return name + "{" + strings.Join(parts, ",") + "}"
+}
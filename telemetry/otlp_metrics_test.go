@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func producedMetric(t *testing.T, p *PrometheusProducer, name string) metricdata.Aggregation {
+	t.Helper()
+	scopes, err := p.Produce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, scopes, 1)
+	for _, m := range scopes[0].Metrics {
+		if m.Name == name {
+			return m.Data
+		}
+	}
+	t.Fatalf("metric %s not produced", name)
+	return nil
+}
+
+func TestPrometheusProducer(t *testing.T) {
+	t.Run("keeps names and labels with cumulative values", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		counter := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: "erpc", Name: "test_total"}, []string{"project"})
+		registry.MustRegister(counter)
+		counter.WithLabelValues("main").Add(3)
+
+		p := NewPrometheusProducer(registry, metricdata.CumulativeTemporality)
+		sum := producedMetric(t, p, "erpc_test_total").(metricdata.Sum[float64])
+		require.Len(t, sum.DataPoints, 1)
+		assert.Equal(t, 3.0, sum.DataPoints[0].Value)
+		assert.True(t, sum.IsMonotonic)
+		v, ok := sum.DataPoints[0].Attributes.Value(attribute.Key("project"))
+		assert.True(t, ok)
+		assert.Equal(t, "main", v.AsString())
+
+		counter.WithLabelValues("main").Add(2)
+		sum = producedMetric(t, p, "erpc_test_total").(metricdata.Sum[float64])
+		assert.Equal(t, 5.0, sum.DataPoints[0].Value)
+	})
+
+	t.Run("reports increases with delta temporality", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		counter := prometheus.NewCounter(prometheus.CounterOpts{Namespace: "erpc", Name: "test_total"})
+		histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Namespace: "erpc", Name: "test_seconds", Buckets: []float64{1, 5}})
+		registry.MustRegister(counter, histogram)
+
+		p := NewPrometheusProducer(registry, metricdata.DeltaTemporality)
+		counter.Add(3)
+		histogram.Observe(0.5)
+		histogram.Observe(10)
+		assert.Equal(t, 3.0, producedMetric(t, p, "erpc_test_total").(metricdata.Sum[float64]).DataPoints[0].Value)
+
+		counter.Add(2)
+		histogram.Observe(2)
+		scopes, err := p.Produce(context.Background())
+		require.NoError(t, err)
+		for _, m := range scopes[0].Metrics {
+			switch m.Name {
+			case "erpc_test_total":
+				assert.Equal(t, 2.0, m.Data.(metricdata.Sum[float64]).DataPoints[0].Value)
+			case "erpc_test_seconds":
+				dp := m.Data.(metricdata.Histogram[float64]).DataPoints[0]
+				assert.Equal(t, metricdata.DeltaTemporality, m.Data.(metricdata.Histogram[float64]).Temporality)
+				assert.Equal(t, uint64(1), dp.Count)
+				assert.Equal(t, 2.0, dp.Sum)
+				assert.Equal(t, []float64{1, 5}, dp.Bounds)
+				assert.Equal(t, []uint64{0, 1, 0}, dp.BucketCounts)
+			}
+		}
+	})
+
+	t.Run("forgets deleted series with delta temporality", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		counter := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: "erpc", Name: "test_total"}, []string{"consumer"})
+		registry.MustRegister(counter)
+
+		p := NewPrometheusProducer(registry, metricdata.DeltaTemporality)
+		counter.WithLabelValues("a").Add(3)
+		counter.WithLabelValues("b").Add(1)
+		producedMetric(t, p, "erpc_test_total")
+		assert.Len(t, p.previous, 2)
+
+		counter.DeleteLabelValues("a")
+		counter.WithLabelValues("b").Add(1)
+		sum := producedMetric(t, p, "erpc_test_total").(metricdata.Sum[float64])
+		require.Len(t, sum.DataPoints, 1)
+		assert.Equal(t, 1.0, sum.DataPoints[0].Value)
+		assert.Len(t, p.previous, 1)
+	})
+}
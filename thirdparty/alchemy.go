@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -77,8 +78,11 @@ type AlchemyVendor struct {
 	remoteDataLock          sync.Mutex
 	remoteData              map[string]map[int64]string
 	remoteDataLastFetchedAt map[string]time.Time
+	sharedRemoteData        *sharedRemoteData[map[int64]string]
 }
 
+var _ SharedStateAwareVendor = (*AlchemyVendor)(nil)
+
 func CreateAlchemyVendor() common.Vendor {
 	return &AlchemyVendor{
 		remoteData:              make(map[string]map[int64]string),
@@ -86,10 +90,20 @@ func CreateAlchemyVendor() common.Vendor {
 	}
 }
 
+func (v *AlchemyVendor) SetSharedState(registry data.SharedStateRegistry) {
+	v.remoteDataLock.Lock()
+	defer v.remoteDataLock.Unlock()
+	v.sharedRemoteData = newSharedRemoteData[map[int64]string](registry, "vendorRemoteData/alchemy")
+}
+
 func (v *AlchemyVendor) Name() string {
 	return "alchemy"
 }
 
+func (v *AlchemyVendor) ProprietaryMethods() []string {
+	return []string{"alchemy_*"}
+}
+
 func (v *AlchemyVendor) SupportsNetwork(ctx context.Context, logger *zerolog.Logger, settings common.VendorSettings, networkId string) (bool, error) {
 	if !strings.HasPrefix(networkId, "evm:") {
 		return false, nil
@@ -250,6 +264,13 @@ func (v *AlchemyVendor) ensureRemoteData(ctx context.Context, recheckInterval ti
 		return nil
 	}
 
+	// Only the elected instance refreshes; followers wait for its snapshot and fetch on their own only if it never shows up
+	if shared, fetchedAt, ok := v.sharedRemoteData.await(ctx, recheckInterval, sharedRemoteDataFollowerWait); ok {
+		v.remoteData[alchemyApiUrl] = shared
+		v.remoteDataLastFetchedAt[alchemyApiUrl] = fetchedAt
+		return nil
+	}
+
 	newData, err := v.fetchAlchemyNetworks(ctx)
 	if err != nil {
 		if _, ok := v.remoteData[alchemyApiUrl]; ok {
@@ -261,6 +282,7 @@ func (v *AlchemyVendor) ensureRemoteData(ctx context.Context, recheckInterval ti
 
 	v.remoteData[alchemyApiUrl] = newData
 	v.remoteDataLastFetchedAt[alchemyApiUrl] = time.Now()
+	v.sharedRemoteData.publish(ctx, newData)
 	return nil
 }
 
@@ -0,0 +1,272 @@
+package thirdparty
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/util"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const DefaultDiscoveryRefreshInterval = 30 * time.Second
+
+// DynamicVendor is implemented by vendors whose generated upstreams change at runtime (e.g. service discovery).
+// The upstreams registry regenerates their upstreams every refresh interval and registers or deregisters the difference.
+type DynamicVendor interface {
+	RefreshInterval(settings common.VendorSettings) time.Duration
+}
+
+// DiscoveryEndpoint is a single node as listed by the discovery source.
+type DiscoveryEndpoint struct {
+	Id       string            `json:"id"`
+	Endpoint string            `json:"endpoint"`
+	ChainId  int64             `json:"chainId"`
+	NodeType string            `json:"nodeType"`
+	Labels   map[string]string `json:"labels"`
+}
+
+// DiscoveryVendor generates upstreams for self-hosted nodes listed by a JSON document served over HTTP
+// (or a local file), for example by a service discovery sidecar. The list is re-fetched periodically and
+// upstreams of nodes that appear or disappear are registered or deregistered without a config change.
+type DiscoveryVendor struct {
+	common.Vendor
+
+	// local cache of remote data
+	remoteDataLock          sync.Mutex
+	remoteData              map[string][]*DiscoveryEndpoint
+	remoteDataLastFetchedAt map[string]time.Time
+}
+
+var _ DynamicVendor = &DiscoveryVendor{}
+
+func CreateDiscoveryVendor() common.Vendor {
+	return &DiscoveryVendor{
+		remoteData:              make(map[string][]*DiscoveryEndpoint),
+		remoteDataLastFetchedAt: make(map[string]time.Time),
+	}
+}
+
+func (v *DiscoveryVendor) Name() string {
+	return "discovery"
+}
+
+func (v *DiscoveryVendor) RefreshInterval(settings common.VendorSettings) time.Duration {
+	switch val := settings["refreshInterval"].(type) {
+	case time.Duration:
+		if val > 0 {
+			return val
+		}
+	case string:
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultDiscoveryRefreshInterval
+}
+
+func (v *DiscoveryVendor) SupportsNetwork(ctx context.Context, logger *zerolog.Logger, settings common.VendorSettings, networkId string) (bool, error) {
+	if !strings.HasPrefix(networkId, "evm:") {
+		return false, nil
+	}
+
+	chainID, err := strconv.ParseInt(strings.TrimPrefix(networkId, "evm:"), 10, 64)
+	if err != nil {
+		return false, err
+	}
+
+	endpoints, err := v.endpointsForChain(ctx, settings, chainID)
+	if err != nil {
+		return false, err
+	}
+
+	return len(endpoints) > 0, nil
+}
+
+func (v *DiscoveryVendor) GenerateConfigs(ctx context.Context, logger *zerolog.Logger, upstream *common.UpstreamConfig, settings common.VendorSettings) ([]*common.UpstreamConfig, error) {
+	if upstream.Evm == nil {
+		return nil, fmt.Errorf("discovery vendor requires upstream.evm to be defined")
+	}
+	if upstream.Evm.ChainId == 0 {
+		return nil, fmt.Errorf("discovery vendor requires upstream.evm.chainId to be defined")
+	}
+
+	endpoints, err := v.endpointsForChain(ctx, settings, upstream.Evm.ChainId)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty list is not an error so that the registry deregisters upstreams of nodes that went away
+	upsList := []*common.UpstreamConfig{}
+	for _, ep := range endpoints {
+		cfg := upstream.Copy()
+		// Generated upstreams are plain json-rpc nodes, they must not be re-generated by this vendor on creation
+		cfg.VendorName = ""
+		cfg.Type = common.UpstreamTypeEvm
+		cfg.Endpoint = ep.Endpoint
+		if ep.Id != "" {
+			cfg.Id = fmt.Sprintf("%s-%s", upstream.Id, ep.Id)
+		} else {
+			cfg.Id = fmt.Sprintf("%s-%s", upstream.Id, util.RedactEndpoint(ep.Endpoint))
+		}
+		if group, ok := ep.Labels["group"]; ok && cfg.Group == "" {
+			cfg.Group = group
+		}
+		if ep.NodeType != "" && (cfg.Evm.NodeType == "" || cfg.Evm.NodeType == common.EvmNodeTypeUnknown) {
+			cfg.Evm.NodeType = common.EvmNodeType(ep.NodeType)
+			// Re-applies defaults that depend on node type (e.g. max available recent blocks of full nodes)
+			if err := cfg.Evm.SetDefaults(nil); err != nil {
+				return nil, err
+			}
+		}
+		upsList = append(upsList, cfg)
+	}
+
+	log.Debug().Int64("chainId", upstream.Evm.ChainId).Interface("upstreams", upsList).Msg("generated upstreams from discovery provider")
+
+	return upsList, nil
+}
+
+func (v *DiscoveryVendor) GetVendorSpecificErrorIfAny(req *common.NormalizedRequest, resp *http.Response, jrr interface{}, details map[string]interface{}) error {
+	return nil
+}
+
+func (v *DiscoveryVendor) OwnsUpstream(ups *common.UpstreamConfig) bool {
+	return false
+}
+
+// endpointsForChain returns the valid endpoints of a chain that carry all labels required by the settings,
+// sorted by endpoint so that generated upstreams are stable across refreshes.
+func (v *DiscoveryVendor) endpointsForChain(ctx context.Context, settings common.VendorSettings, chainID int64) ([]*DiscoveryEndpoint, error) {
+	source, ok := settings["url"].(string)
+	if !ok || source == "" {
+		return nil, fmt.Errorf("discovery vendor requires settings.url to be defined")
+	}
+
+	// Cached for half the refresh interval so every refresh observes a fresh list
+	if err := v.ensureRemoteData(ctx, v.RefreshInterval(settings)/2, source); err != nil {
+		return nil, fmt.Errorf("unable to load discovery data: %w", err)
+	}
+
+	matchLabels := map[string]string{}
+	if lbls, ok := settings["labels"].(map[string]interface{}); ok {
+		for k, val := range lbls {
+			matchLabels[k] = fmt.Sprintf("%v", val)
+		}
+	} else if lbls, ok := settings["labels"].(map[string]string); ok {
+		matchLabels = lbls
+	}
+
+	v.remoteDataLock.Lock()
+	defer v.remoteDataLock.Unlock()
+
+	var result []*DiscoveryEndpoint
+	for _, ep := range v.remoteData[source] {
+		if ep.ChainId != chainID || !strings.HasPrefix(strings.ToLower(ep.Endpoint), "http") {
+			continue
+		}
+		matches := true
+		for k, val := range matchLabels {
+			if ep.Labels[k] != val {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, ep)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Endpoint < result[j].Endpoint
+	})
+
+	return result, nil
+}
+
+func (v *DiscoveryVendor) ensureRemoteData(ctx context.Context, recheckInterval time.Duration, source string) error {
+	v.remoteDataLock.Lock()
+	defer v.remoteDataLock.Unlock()
+
+	if ltm, ok := v.remoteDataLastFetchedAt[source]; ok && time.Since(ltm) < recheckInterval {
+		return nil
+	}
+
+	newData, err := fetchDiscoveryData(ctx, source)
+	if err != nil {
+		if _, ok := v.remoteData[source]; ok {
+			// if fetch fails, keep stale data rather than dropping all nodes
+			log.Warn().Err(err).Str("source", source).Msg("could not refresh discovery data; will use stale data")
+			return nil
+		}
+		return err
+	}
+
+	v.remoteData[source] = newData
+	v.remoteDataLastFetchedAt[source] = time.Now()
+	return nil
+}
+
+// fetchDiscoveryData reads the list of endpoints from an http(s) URL, a file:// URL or a local file path.
+// The document is either a JSON array of endpoints or an object with an "endpoints" array.
+func fetchDiscoveryData(ctx context.Context, source string) ([]*DiscoveryEndpoint, error) {
+	var bodyBytes []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		bodyBytes, err = fetchDiscoveryDocument(ctx, source)
+	} else {
+		bodyBytes, err = os.ReadFile(strings.TrimPrefix(source, "file://"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []*DiscoveryEndpoint
+	if err := common.SonicCfg.Unmarshal(bodyBytes, &endpoints); err != nil {
+		var wrapped struct {
+			Endpoints []*DiscoveryEndpoint `json:"endpoints"`
+		}
+		if err := common.SonicCfg.Unmarshal(bodyBytes, &wrapped); err != nil {
+			return nil, fmt.Errorf("failed to parse discovery data: %w", err)
+		}
+		endpoints = wrapped.Endpoints
+	}
+
+	return endpoints, nil
+}
+
+func fetchDiscoveryDocument(ctx context.Context, urlStr string) ([]byte, error) {
+	rctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(rctx, "GET", urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+	var httpClient = &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+		},
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("discovery fetch returned non-200 code: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
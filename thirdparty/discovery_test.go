@@ -0,0 +1,59 @@
+package thirdparty
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoveryVendor_GenerateConfigs(t *testing.T) {
+	logger := zerolog.Nop()
+	path := filepath.Join(t.TempDir(), "nodes.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"id": "node-2", "endpoint": "http://10.0.0.2:8545", "chainId": 1, "nodeType": "full", "labels": {"region": "eu", "group": "primary"}},
+		{"id": "node-1", "endpoint": "http://10.0.0.1:8545", "chainId": 1, "nodeType": "archive", "labels": {"region": "eu"}},
+		{"id": "node-3", "endpoint": "http://10.0.0.3:8545", "chainId": 1, "labels": {"region": "us"}},
+		{"id": "node-4", "endpoint": "http://10.0.0.4:8545", "chainId": 10, "labels": {"region": "eu"}}
+	]`), 0o600))
+
+	vendor := CreateDiscoveryVendor().(*DiscoveryVendor)
+	settings := common.VendorSettings{
+		"url":    "file://" + path,
+		"labels": map[string]interface{}{"region": "eu"},
+	}
+
+	ok, err := vendor.SupportsNetwork(context.Background(), &logger, settings, "evm:1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = vendor.SupportsNetwork(context.Background(), &logger, settings, "evm:137")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	base := &common.UpstreamConfig{Id: "fleet-evm:1", VendorName: "discovery", Evm: &common.EvmUpstreamConfig{ChainId: 1, NodeType: common.EvmNodeTypeUnknown}}
+	upsCfgs, err := vendor.GenerateConfigs(context.Background(), &logger, base, settings)
+	require.NoError(t, err)
+	require.Len(t, upsCfgs, 2)
+
+	assert.Equal(t, "fleet-evm:1-node-1", upsCfgs[0].Id)
+	assert.Equal(t, "http://10.0.0.1:8545", upsCfgs[0].Endpoint)
+	assert.Equal(t, common.EvmNodeTypeArchive, upsCfgs[0].Evm.NodeType)
+	assert.Empty(t, upsCfgs[0].VendorName)
+
+	assert.Equal(t, "fleet-evm:1-node-2", upsCfgs[1].Id)
+	assert.Equal(t, common.EvmNodeTypeFull, upsCfgs[1].Evm.NodeType)
+	assert.Equal(t, int64(128), upsCfgs[1].Evm.MaxAvailableRecentBlocks)
+	assert.Equal(t, "primary", upsCfgs[1].Group)
+	assert.Equal(t, common.EvmNodeTypeUnknown, base.Evm.NodeType)
+}
+
+func TestDiscoveryVendor_RefreshInterval(t *testing.T) {
+	vendor := CreateDiscoveryVendor().(*DiscoveryVendor)
+	assert.Equal(t, DefaultDiscoveryRefreshInterval, vendor.RefreshInterval(common.VendorSettings{}))
+	assert.Equal(t, "1m0s", vendor.RefreshInterval(common.VendorSettings{"refreshInterval": "1m"}).String())
+}
@@ -0,0 +1,283 @@
+package thirdparty
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erpc/erpc/clients"
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const DefaultDnsRefreshInterval = 30 * time.Second
+
+const (
+	// DnsModeA expands the endpoint host into one upstream per A/AAAA record
+	DnsModeA = "dns"
+	// DnsModeSrv expands the endpoint host (e.g. _rpc._tcp.nodes.internal) into one upstream per SRV record target
+	DnsModeSrv = "srv"
+)
+
+// Resolver functions, replaceable in tests
+var (
+	lookupIPAddr = net.DefaultResolver.LookupIPAddr
+	lookupSRV    = net.DefaultResolver.LookupSRV
+)
+
+// DnsVendor expands an endpoint whose host resolves to many nodes (round-robin A/AAAA records or SRV records)
+// into one upstream per node, so each node gets its own health metrics, score and state poller.
+// Records are re-resolved periodically and upstreams of nodes that appear or disappear are registered or deregistered.
+type DnsVendor struct {
+	common.Vendor
+
+	proxyPools *clients.ProxyPoolRegistry
+}
+
+// ProxyPoolAwareVendor is implemented by vendors that send their own requests to nodes and must honor proxy pools.
+type ProxyPoolAwareVendor interface {
+	SetProxyPools(registry *clients.ProxyPoolRegistry)
+}
+
+var _ DynamicVendor = &DnsVendor{}
+var _ ProxyPoolAwareVendor = &DnsVendor{}
+
+func CreateDnsVendor() common.Vendor {
+	return &DnsVendor{}
+}
+
+func (v *DnsVendor) Name() string {
+	return "dns"
+}
+
+func (v *DnsVendor) SetProxyPools(registry *clients.ProxyPoolRegistry) {
+	v.proxyPools = registry
+}
+
+func (v *DnsVendor) RefreshInterval(settings common.VendorSettings) time.Duration {
+	switch val := settings["refreshInterval"].(type) {
+	case time.Duration:
+		if val > 0 {
+			return val
+		}
+	case string:
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultDnsRefreshInterval
+}
+
+// SupportsNetwork asks one of the resolved nodes for its chain id, with the same headers and proxy pool as the
+// generated upstreams (settings.headers and settings.proxyPool). When evm.chainId is set on the upstream,
+// the provider is limited to that network (onlyNetworks) and this is not called.
+func (v *DnsVendor) SupportsNetwork(ctx context.Context, logger *zerolog.Logger, settings common.VendorSettings, networkId string) (bool, error) {
+	if !strings.HasPrefix(networkId, "evm:") {
+		return false, nil
+	}
+
+	chainId, err := strconv.ParseInt(strings.TrimPrefix(networkId, "evm:"), 10, 64)
+	if err != nil {
+		return false, err
+	}
+
+	endpoints, err := v.resolveEndpoints(ctx, settings)
+	if err != nil {
+		return false, err
+	}
+	httpClient, err := v.probeHttpClient(settings)
+	if err != nil {
+		return false, err
+	}
+	headers := probeHeaders(settings)
+
+	var lastErr error
+	for _, ep := range endpoints {
+		cid, err := fetchEvmChainId(ctx, httpClient, ep, headers)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return cid == chainId, nil
+	}
+
+	return false, lastErr
+}
+
+func (v *DnsVendor) GenerateConfigs(ctx context.Context, logger *zerolog.Logger, upstream *common.UpstreamConfig, settings common.VendorSettings) ([]*common.UpstreamConfig, error) {
+	endpoints, err := v.resolveEndpoints(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	upsList := []*common.UpstreamConfig{}
+	for _, ep := range endpoints {
+		parsed, err := url.Parse(ep)
+		if err != nil {
+			return nil, err
+		}
+		cfg := upstream.Copy()
+		// Generated upstreams are plain json-rpc nodes, they must not be re-generated by this vendor on creation
+		cfg.VendorName = ""
+		cfg.Endpoint = ep
+		cfg.Id = fmt.Sprintf("%s-%s", upstream.Id, parsed.Host)
+		upsList = append(upsList, cfg)
+	}
+
+	log.Debug().Interface("upstreams", upsList).Msg("generated upstreams from dns provider")
+
+	return upsList, nil
+}
+
+func (v *DnsVendor) GetVendorSpecificErrorIfAny(req *common.NormalizedRequest, resp *http.Response, jrr interface{}, details map[string]interface{}) error {
+	return nil
+}
+
+func (v *DnsVendor) OwnsUpstream(ups *common.UpstreamConfig) bool {
+	return false
+}
+
+// resolveEndpoints returns one endpoint per resolved node, with the host of the configured endpoint
+// replaced by the node address and everything else (scheme, path, query) kept as is.
+func (v *DnsVendor) resolveEndpoints(ctx context.Context, settings common.VendorSettings) ([]string, error) {
+	endpoint, ok := settings["endpoint"].(string)
+	if !ok || endpoint == "" {
+		return nil, fmt.Errorf("dns vendor requires settings.endpoint to be defined")
+	}
+	mode, _ := settings["mode"].(string)
+	if mode == "" {
+		mode = DnsModeA
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dns vendor endpoint: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("dns vendor endpoint must be http(s), got %s", parsed.Scheme)
+	}
+	if mode == DnsModeA && parsed.Scheme == "https" {
+		return nil, fmt.Errorf("dns vendor endpoint cannot be https in '%s' mode because nodes are called by IP, use '%s' mode instead", DnsModeA, DnsModeSrv)
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var hosts []string
+	switch mode {
+	case DnsModeA:
+		addrs, err := lookupIPAddr(rctx, parsed.Hostname())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", parsed.Hostname(), err)
+		}
+		for _, addr := range addrs {
+			if parsed.Port() != "" {
+				hosts = append(hosts, net.JoinHostPort(addr.IP.String(), parsed.Port()))
+			} else if addr.IP.To4() == nil {
+				hosts = append(hosts, "["+addr.IP.String()+"]")
+			} else {
+				hosts = append(hosts, addr.IP.String())
+			}
+		}
+	case DnsModeSrv:
+		_, records, err := lookupSRV(rctx, "", "", parsed.Hostname())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve srv records of %s: %w", parsed.Hostname(), err)
+		}
+		for _, rec := range records {
+			hosts = append(hosts, net.JoinHostPort(strings.TrimSuffix(rec.Target, "."), strconv.Itoa(int(rec.Port))))
+		}
+	default:
+		return nil, fmt.Errorf("dns vendor mode must be '%s' or '%s', got '%s'", DnsModeA, DnsModeSrv, mode)
+	}
+
+	// Sorted and de-duplicated so that generated upstreams are stable across refreshes
+	sort.Strings(hosts)
+	endpoints := make([]string, 0, len(hosts))
+	for i, host := range hosts {
+		if i > 0 && hosts[i-1] == host {
+			continue
+		}
+		u := *parsed
+		u.Host = host
+		endpoints = append(endpoints, u.String())
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no records found for %s", parsed.Hostname())
+	}
+
+	return endpoints, nil
+}
+
+// probeHttpClient returns a client of the proxy pool named in settings.proxyPool, or the default client without one.
+func (v *DnsVendor) probeHttpClient(settings common.VendorSettings) (*http.Client, error) {
+	poolId, _ := settings["proxyPool"].(string)
+	if poolId == "" {
+		return http.DefaultClient, nil
+	}
+	if v.proxyPools == nil {
+		return nil, fmt.Errorf("dns vendor proxy pool '%s' is not available", poolId)
+	}
+	pool, err := v.proxyPools.GetPool(poolId)
+	if err != nil {
+		return nil, err
+	}
+	return pool.GetClient()
+}
+
+// probeHeaders reads settings.headers, either as set from the upstream config or as decoded from yaml/json.
+func probeHeaders(settings common.VendorSettings) map[string]string {
+	switch val := settings["headers"].(type) {
+	case map[string]string:
+		return val
+	case map[string]interface{}:
+		headers := make(map[string]string, len(val))
+		for k, hv := range val {
+			if str, ok := hv.(string); ok {
+				headers[k] = str
+			}
+		}
+		return headers
+	}
+	return nil
+}
+
+func fetchEvmChainId(ctx context.Context, httpClient *http.Client, endpoint string, headers map[string]string) (int64, error) {
+	rctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(rctx, "POST", endpoint, bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, hv := range headers {
+		req.Header.Set(k, hv)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	var jrr struct {
+		Result string `json:"result"`
+	}
+	if err := common.SonicCfg.Unmarshal(body, &jrr); err != nil {
+		return 0, fmt.Errorf("failed to parse eth_chainId response: %w", err)
+	}
+
+	return common.HexToInt64(jrr.Result)
+}
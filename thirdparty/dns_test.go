@@ -0,0 +1,130 @@
+package thirdparty
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/erpc/erpc/clients"
+	"github.com/erpc/erpc/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDnsVendor_GenerateConfigs(t *testing.T) {
+	logger := zerolog.Nop()
+	origLookupIPAddr, origLookupSRV := lookupIPAddr, lookupSRV
+	t.Cleanup(func() {
+		lookupIPAddr, lookupSRV = origLookupIPAddr, origLookupSRV
+	})
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		assert.Equal(t, "eth-nodes.internal", host)
+		return []net.IPAddr{{IP: net.ParseIP("10.0.0.2")}, {IP: net.ParseIP("10.0.0.1")}, {IP: net.ParseIP("fd00::1")}, {IP: net.ParseIP("10.0.0.1")}}, nil
+	}
+	lookupSRV = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		assert.Equal(t, "_rpc._tcp.nodes.internal", name)
+		return "", []*net.SRV{{Target: "node-b.internal.", Port: 8545}, {Target: "node-a.internal.", Port: 8546}}, nil
+	}
+
+	vendor := CreateDnsVendor().(*DnsVendor)
+	base := &common.UpstreamConfig{Id: "eth-nodes-evm:1", VendorName: "dns", Evm: &common.EvmUpstreamConfig{ChainId: 1}}
+
+	t.Run("expands A/AAAA records into one upstream per address", func(t *testing.T) {
+		upsCfgs, err := vendor.GenerateConfigs(context.Background(), &logger, base, common.VendorSettings{
+			"mode":     "dns",
+			"endpoint": "http://eth-nodes.internal:8545/rpc?x=1",
+		})
+		require.NoError(t, err)
+		require.Len(t, upsCfgs, 3)
+		assert.Equal(t, "http://10.0.0.1:8545/rpc?x=1", upsCfgs[0].Endpoint)
+		assert.Equal(t, "eth-nodes-evm:1-10.0.0.1:8545", upsCfgs[0].Id)
+		assert.Equal(t, "http://10.0.0.2:8545/rpc?x=1", upsCfgs[1].Endpoint)
+		assert.Equal(t, "http://[fd00::1]:8545/rpc?x=1", upsCfgs[2].Endpoint)
+		assert.Empty(t, upsCfgs[0].VendorName)
+		assert.Equal(t, int64(1), upsCfgs[0].Evm.ChainId)
+	})
+
+	t.Run("expands SRV records into one upstream per target", func(t *testing.T) {
+		upsCfgs, err := vendor.GenerateConfigs(context.Background(), &logger, base, common.VendorSettings{
+			"mode":     "srv",
+			"endpoint": "https://_rpc._tcp.nodes.internal",
+		})
+		require.NoError(t, err)
+		require.Len(t, upsCfgs, 2)
+		assert.Equal(t, "https://node-a.internal:8546", upsCfgs[0].Endpoint)
+		assert.Equal(t, "https://node-b.internal:8545", upsCfgs[1].Endpoint)
+	})
+
+	t.Run("rejects unknown modes", func(t *testing.T) {
+		_, err := vendor.GenerateConfigs(context.Background(), &logger, base, common.VendorSettings{
+			"mode":     "txt",
+			"endpoint": "http://eth-nodes.internal",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects https endpoints in dns mode", func(t *testing.T) {
+		_, err := vendor.GenerateConfigs(context.Background(), &logger, base, common.VendorSettings{
+			"mode":     "dns",
+			"endpoint": "https://eth-nodes.internal",
+		})
+		assert.ErrorContains(t, err, "cannot be https")
+	})
+}
+
+func TestDnsVendor_SupportsNetwork(t *testing.T) {
+	logger := zerolog.Nop()
+	origLookupIPAddr := lookupIPAddr
+	t.Cleanup(func() {
+		lookupIPAddr = origLookupIPAddr
+	})
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+
+	t.Run("sends the upstream headers to the node", func(t *testing.T) {
+		node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+		}))
+		defer node.Close()
+		_, port, _ := net.SplitHostPort(strings.TrimPrefix(node.URL, "http://"))
+
+		vendor := CreateDnsVendor().(*DnsVendor)
+		ok, err := vendor.SupportsNetwork(context.Background(), &logger, common.VendorSettings{
+			"endpoint": "http://eth-nodes.internal:" + port,
+			"headers":  map[string]interface{}{"Authorization": "Bearer secret"},
+		}, "evm:1")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("goes through the upstream proxy pool", func(t *testing.T) {
+		var proxied atomic.Bool
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied.Store(true)
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x89"}`))
+		}))
+		defer proxy.Close()
+		pools, err := clients.NewProxyPoolRegistry([]*common.ProxyPoolConfig{{ID: "pool", Urls: []string{proxy.URL}}}, &logger)
+		require.NoError(t, err)
+
+		vendor := CreateDnsVendor().(*DnsVendor)
+		vendor.SetProxyPools(pools)
+		ok, err := vendor.SupportsNetwork(context.Background(), &logger, common.VendorSettings{
+			"endpoint":  "http://eth-nodes.internal:8545",
+			"proxyPool": "pool",
+		}, "evm:137")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, proxied.Load())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/erpc/erpc/common"
 	"github.com/rs/zerolog"
@@ -64,6 +65,15 @@ func (p *Provider) GenerateUpstreamConfigs(ctx context.Context, logger *zerolog.
 	return upsCfgs, nil
 }
 
+// RefreshInterval returns how often upstreams of this provider must be regenerated,
+// or 0 when the vendor generates a fixed set of upstreams.
+func (p *Provider) RefreshInterval() time.Duration {
+	if dv, ok := p.vendor.(DynamicVendor); ok {
+		return dv.RefreshInterval(p.config.Settings)
+	}
+	return 0
+}
+
 func (p *Provider) expandEnvVars(upsCfgs []*common.UpstreamConfig) {
 	for _, upsCfg := range upsCfgs {
 		upsCfg.Endpoint = os.ExpandEnv(upsCfg.Endpoint)
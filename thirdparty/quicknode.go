@@ -50,6 +50,10 @@ func (v *QuicknodeVendor) Name() string {
 	return "quicknode"
 }
 
+func (v *QuicknodeVendor) ProprietaryMethods() []string {
+	return []string{"qn_*"}
+}
+
 func (v *QuicknodeVendor) SupportsNetwork(ctx context.Context, logger *zerolog.Logger, settings common.VendorSettings, networkId string) (bool, error) {
 	if !strings.HasPrefix(networkId, "evm:") {
 		return false, nil
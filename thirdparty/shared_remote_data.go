@@ -0,0 +1,100 @@
+package thirdparty
+
+import (
+	"context"
+	"time"
+
+	"github.com/erpc/erpc/data"
+	"github.com/rs/zerolog/log"
+)
+
+// SharedStateAwareVendor is implemented by vendors that can share remote data across eRPC instances.
+type SharedStateAwareVendor interface {
+	SetSharedState(registry data.SharedStateRegistry)
+}
+
+// sharedRemoteDataFollowerWait bounds how long a follower waits for the leader to publish fresh data
+// before falling back to fetching it from the vendor API itself.
+const sharedRemoteDataFollowerWait = 5 * time.Second
+
+const sharedRemoteDataPollInterval = 100 * time.Millisecond
+
+type sharedRemoteDataSnapshot[T any] struct {
+	Data      T     `json:"data"`
+	FetchedAt int64 `json:"fetchedAt"` // unix milliseconds
+}
+
+// sharedRemoteData lets the elected instance publish vendor remote data (e.g. supported networks) so other
+// instances reuse it instead of each calling the vendor API. A nil *sharedRemoteData disables sharing.
+type sharedRemoteData[T any] struct {
+	value      *data.SharedValue[sharedRemoteDataSnapshot[T]]
+	leadership data.LeaderElection
+}
+
+func newSharedRemoteData[T any](registry data.SharedStateRegistry, key string) *sharedRemoteData[T] {
+	return &sharedRemoteData[T]{
+		value:      data.NewSharedValue[sharedRemoteDataSnapshot[T]](registry, key),
+		leadership: registry.GetLeaderElection(key),
+	}
+}
+
+// load returns the shared data if it was fetched within maxAge by any instance.
+func (s *sharedRemoteData[T]) load(maxAge time.Duration) (T, time.Time, bool) {
+	var empty T
+	if s == nil {
+		return empty, time.Time{}, false
+	}
+	snapshot, version, err := s.value.Get()
+	if err != nil || version == 0 {
+		return empty, time.Time{}, false
+	}
+	fetchedAt := time.UnixMilli(snapshot.FetchedAt)
+	if time.Since(fetchedAt) >= maxAge {
+		return empty, time.Time{}, false
+	}
+	return snapshot.Data, fetchedAt, true
+}
+
+// await returns fresh shared data, waiting up to timeout for the leader to publish it when this instance is a follower.
+// It returns false right away on the leader (or when sharing is disabled) so the caller fetches and publishes itself.
+func (s *sharedRemoteData[T]) await(ctx context.Context, maxAge time.Duration, timeout time.Duration) (T, time.Time, bool) {
+	if shared, fetchedAt, ok := s.load(maxAge); ok || s == nil || s.leadership.IsLeader() {
+		return shared, fetchedAt, ok
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(sharedRemoteDataPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			var empty T
+			return empty, time.Time{}, false
+		case <-timer.C:
+			var empty T
+			return empty, time.Time{}, false
+		case <-ticker.C:
+			if shared, fetchedAt, ok := s.load(maxAge); ok {
+				return shared, fetchedAt, true
+			}
+			if s.leadership.IsLeader() {
+				var empty T
+				return empty, time.Time{}, false
+			}
+		}
+	}
+}
+
+// publish shares freshly fetched data, only from the elected instance to avoid every replica writing the same data.
+func (s *sharedRemoteData[T]) publish(ctx context.Context, value T) {
+	if s == nil || !s.leadership.IsLeader() {
+		return
+	}
+	_, err := s.value.Update(ctx, func(sharedRemoteDataSnapshot[T]) (sharedRemoteDataSnapshot[T], error) {
+		return sharedRemoteDataSnapshot[T]{Data: value, FetchedAt: time.Now().UnixMilli()}, nil
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to publish vendor remote data to shared state")
+	}
+}
@@ -0,0 +1,67 @@
+package thirdparty
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type followerElection struct{}
+
+func (followerElection) IsLeader() bool                { return false }
+func (followerElection) OnLeadershipChange(func(bool)) {}
+func (followerElection) Release()                      {}
+
+func TestSharedRemoteData_FollowerWaitsForLeader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ssr, err := data.NewSharedStateRegistry(ctx, &log.Logger, &common.SharedStateConfig{
+		Connector: &common.ConnectorConfig{
+			Driver: "memory",
+			Memory: &common.MemoryConnectorConfig{MaxItems: 1000, MaxTotalSize: "10MB"},
+		},
+	})
+	require.NoError(t, err)
+
+	leader := newSharedRemoteData[map[int64]string](ssr, "vendorRemoteData/test")
+	follower := newSharedRemoteData[map[int64]string](ssr, "vendorRemoteData/test")
+	follower.leadership = followerElection{}
+
+	t.Run("LeaderDoesNotWait", func(t *testing.T) {
+		start := time.Now()
+		_, _, ok := leader.await(ctx, time.Hour, time.Second)
+		assert.False(t, ok)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("FollowerFallsBackAfterTimeout", func(t *testing.T) {
+		start := time.Now()
+		_, _, ok := follower.await(ctx, time.Hour, 200*time.Millisecond)
+		assert.False(t, ok)
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("FollowerReceivesLeaderSnapshot", func(t *testing.T) {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			leader.publish(ctx, map[int64]string{1: "eth-mainnet"})
+		}()
+		networks, _, ok := follower.await(ctx, time.Hour, 5*time.Second)
+		require.True(t, ok)
+		assert.Equal(t, map[int64]string{1: "eth-mainnet"}, networks)
+	})
+
+	t.Run("FollowerDoesNotPublish", func(t *testing.T) {
+		follower.publish(ctx, map[int64]string{2: "other"})
+		networks, _, ok := leader.load(time.Hour)
+		require.True(t, ok)
+		assert.Equal(t, map[int64]string{1: "eth-mainnet"}, networks)
+	})
+}
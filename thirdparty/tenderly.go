@@ -67,6 +67,10 @@ func (v *TenderlyVendor) Name() string {
 	return "tenderly"
 }
 
+func (v *TenderlyVendor) ProprietaryMethods() []string {
+	return []string{"tenderly_*"}
+}
+
 func (v *TenderlyVendor) SupportsNetwork(ctx context.Context, logger *zerolog.Logger, settings common.VendorSettings, networkId string) (bool, error) {
 	if !strings.HasPrefix(networkId, "evm:") {
 		return false, nil
@@ -1,6 +1,10 @@
 package thirdparty
 
-import "github.com/erpc/erpc/common"
+import (
+	"github.com/erpc/erpc/clients"
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/data"
+)
 
 type VendorsRegistry struct {
 	thirdparty []common.Vendor
@@ -22,6 +26,8 @@ func NewVendorsRegistry() *VendorsRegistry {
 	r.Register(CreateLlamaVendor())
 	r.Register(CreateThirdwebVendor())
 	r.Register(CreateRepositoryVendor())
+	r.Register(CreateDiscoveryVendor())
+	r.Register(CreateDnsVendor())
 	r.Register(CreateSuperchainVendor())
 	r.Register(CreateTenderlyVendor())
 	r.Register(CreateChainstackVendor())
@@ -68,6 +74,43 @@ func (r *VendorsRegistry) LookupByUpstream(ups *common.UpstreamConfig) common.Ve
 	return nil
 }
 
+// LookupByMethod returns the vendor owning a proprietary method (e.g. alchemy_getAssetTransfers), or nil for standard methods.
+func (r *VendorsRegistry) LookupByMethod(method string) common.Vendor {
+	if r == nil {
+		return nil
+	}
+	for _, vendor := range r.thirdparty {
+		v, ok := vendor.(common.VendorWithProprietaryMethods)
+		if !ok {
+			continue
+		}
+		for _, pattern := range v.ProprietaryMethods() {
+			if match, err := common.WildcardMatch(pattern, method); err == nil && match {
+				return vendor
+			}
+		}
+	}
+	return nil
+}
+
+// SetSharedState lets vendors that support it share remote data refreshes across instances.
+func (r *VendorsRegistry) SetSharedState(registry data.SharedStateRegistry) {
+	for _, vendor := range r.thirdparty {
+		if v, ok := vendor.(SharedStateAwareVendor); ok {
+			v.SetSharedState(registry)
+		}
+	}
+}
+
+// SetProxyPools lets vendors that call nodes directly (e.g. to detect their chain id) go through the configured proxy pools.
+func (r *VendorsRegistry) SetProxyPools(registry *clients.ProxyPoolRegistry) {
+	for _, vendor := range r.thirdparty {
+		if v, ok := vendor.(ProxyPoolAwareVendor); ok {
+			v.SetProxyPools(registry)
+		}
+	}
+}
+
 func (r *VendorsRegistry) Register(vendor common.Vendor) {
 	r.thirdparty = append(r.thirdparty, vendor)
 }
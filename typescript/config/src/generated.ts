@@ -62,6 +62,7 @@ export interface Config {
   metrics?: MetricsConfig;
   proxyPools?: (ProxyPoolConfig | undefined)[];
   tracing?: TracingConfig;
+  logs?: LogsConfig;
 }
 export interface ServerConfig {
   listenV4?: boolean;
@@ -78,6 +79,22 @@ export interface ServerConfig {
   waitBeforeShutdown?: Duration;
   waitAfterShutdown?: Duration;
   includeErrorDetails?: boolean;
+  grpc?: GrpcServerConfig;
+  /**
+   * MaxRequestBodySize bounds the (decompressed) size of incoming http request bodies, e.g. "16mb".
+   * Bodies are not limited when unset.
+   */
+  maxRequestBodySize?: string;
+}
+/**
+ * GrpcServerConfig exposes the blockchain-data-standards RPCQueryService over gRPC, requests are
+ * translated to JSON-RPC and go through the same pipeline (cache, failover, consensus) as http.
+ */
+export interface GrpcServerConfig {
+  enabled?: boolean;
+  host?: string;
+  port?: number /* int */;
+  defaultProjectId?: string;
 }
 export interface HealthCheckConfig {
   mode?: HealthCheckMode;
@@ -104,6 +121,31 @@ export interface TracingConfig {
   sampleRate?: number /* float64 */;
   detailed?: boolean;
   tls?: TLSConfig;
+  /**
+   * TailSampling records every request in-process and decides when it ends whether to export its spans,
+   * so failed, hedged, disputed or slow requests are always captured while others follow SampleRate.
+   */
+  tailSampling?: TracingTailSamplingConfig;
+}
+export interface TracingTailSamplingConfig {
+  enabled?: boolean;
+  latencyThreshold?: Duration;
+  maxSpansPerRequest?: number /* int */;
+}
+/**
+ * LogsConfig controls where logs are shipped besides the standard output.
+ */
+export interface LogsConfig {
+  otlp?: LogsOtlpConfig;
+}
+/**
+ * LogsOtlpConfig forwards every log event to an OTLP collector, using the same connection settings as tracing.
+ */
+export interface LogsOtlpConfig {
+  enabled?: boolean;
+  endpoint?: string;
+  protocol?: TracingProtocol;
+  tls?: TLSConfig;
 }
 export interface AdminConfig {
   auth?: AuthConfig;
@@ -127,6 +169,16 @@ export interface SharedStateConfig {
   connector?: ConnectorConfig;
   fallbackTimeout?: Duration;
   lockTtl?: Duration;
+  leaderElection?: LeaderElectionConfig;
+}
+/**
+ * LeaderElectionConfig makes designated background jobs (e.g. upstream state polling) run on a single
+ * elected instance per cluster, which publishes the results through shared state for the other instances.
+ */
+export interface LeaderElectionConfig {
+  enabled?: boolean;
+  leaseTtl?: Duration;
+  renewInterval?: Duration;
 }
 export interface CacheConfig {
   connectors?: TsConnectorConfig[];
@@ -244,10 +296,121 @@ export interface ProjectConfig {
   rateLimitBudget?: string;
   scoreMetricsWindowSize?: Duration;
   healthCheck?: DeprecatedProjectHealthCheckConfig;
+  disputeLog?: DisputeLogConfig;
+  firewall?: FirewallConfig;
+  batch?: BatchConfig;
+  capture?: CaptureConfig;
+}
+/**
+ * CaptureConfig samples forwarded requests with their responses (or errors), upstream, latency and
+ * finality into a JSONL file, so that traffic can be replayed via "erpc replay".
+ */
+export interface CaptureConfig {
+  /**
+   * SampleRate is the ratio (0 to 1) of matching requests that are captured.
+   */
+  sampleRate?: number /* float64 */;
+  /**
+   * Method pattern of requests to capture (e.g. "eth_call|eth_getLogs").
+   */
+  method?: string;
+  /**
+   * File is the path of the JSONL file records are appended to.
+   */
+  file?: string;
+  /**
+   * MaxResultSize is the max size in bytes of results kept in records, bigger results are captured
+   * with their hash but without their body.
+   */
+  maxResultSize?: number /* int */;
+  /**
+   * BufferSize is the max number of records waiting to be written, records are dropped when full.
+   */
+  bufferSize?: number /* int */;
+  redact?: (CaptureRedactRuleConfig | undefined)[];
+}
+/**
+ * CaptureRedactRuleConfig replaces values at the given paths of captured requests and responses of
+ * matching methods with a short hash, e.g. "params.0.from" or "result.*.input".
+ */
+export interface CaptureRedactRuleConfig {
+  method?: string;
+  paths?: string[];
+}
+/**
+ * BatchConfig governs json-rpc batch requests sent to a project. Each item of a batch is still
+ * authenticated, rate limited and forwarded individually.
+ */
+export interface BatchConfig {
+  /**
+   * MaxSize is the max number of items in one batch, bigger batches are rejected as a whole.
+   * 0 means unlimited.
+   */
+  maxSize?: number /* int */;
+  /**
+   * MaxConcurrency is the max number of items of one batch processed at the same time.
+   * 0 means unlimited.
+   */
+  maxConcurrency?: number /* int */;
+}
+/**
+ * FirewallConfig blocks abusive request patterns before they reach upstreams. Rules are evaluated
+ * in order and the first matching rule decides, requests matching no rule are allowed.
+ */
+export interface FirewallConfig {
+  rules: (FirewallRuleConfig | undefined)[];
+}
+export type FirewallAction = string;
+export const FirewallActionDeny: FirewallAction = "deny";
+export const FirewallActionAllow: FirewallAction = "allow";
+/**
+ * FirewallRuleConfig matches when all of its configured conditions match the request.
+ */
+export interface FirewallRuleConfig {
+  /**
+   * Id identifies the rule in errors, logs and metrics (default: rule-<index>).
+   */
+  id?: string;
+  action?: FirewallAction;
+  network?: string;
+  method?: string;
+  /**
+   * Params are matched the same way as cache policy params.
+   */
+  params?: any[];
+  /**
+   * Contracts are address patterns matched against the "to" of call objects (e.g. eth_call,
+   * debug_traceCall) or the "address" of log filters (e.g. eth_getLogs).
+   */
+  contracts?: string[];
+  /**
+   * MissingAddress matches log filters without an address (e.g. eth_getLogs over all contracts).
+   */
+  missingAddress?: boolean;
+  /**
+   * BlockRangeExceeds matches log filters spanning more than this many blocks, tags such as
+   * "latest" are resolved to the highest latest block of the network.
+   */
+  blockRangeExceeds?: number /* int64 */;
+  /**
+   * Message is returned to the client when the rule denies the request.
+   */
+  message?: string;
+}
+/**
+ * DisputeLogConfig persists evidence of consensus disputes (request, each participant's response hash
+ * and truncated body, the winner and punished upstreams) so they can be queried via the admin API.
+ */
+export interface DisputeLogConfig {
+  connector?: ConnectorConfig;
+  retention?: Duration;
+  maxEntries?: number /* int */;
+  maxBodySize?: number /* int */;
 }
 export interface NetworkDefaults {
   rateLimitBudget?: string;
   failsafe?: (FailsafeConfig | undefined)[];
+  retryBudget?: RetryBudgetConfig;
   selectionPolicy?: SelectionPolicyConfig;
   directiveDefaults?: DirectiveDefaultsConfig;
   evm?: TsEvmNetworkConfigForDefaults;
@@ -339,6 +502,12 @@ export interface JsonRpcUpstreamConfig {
   enableGzip?: boolean;
   headers?: { [key: string]: string};
   proxyPool?: string;
+  /**
+   * StreamingThreshold enables piping results bigger than this size (e.g. "16mb") straight from the upstream
+   * body to the client instead of buffering them in memory, only for StreamingMethods.
+   */
+  streamingThreshold?: string;
+  streamingMethods?: string[];
 }
 export interface EvmUpstreamConfig {
   chainId: number /* int64 */;
@@ -352,6 +521,17 @@ export interface EvmUpstreamConfig {
   getLogsMaxAllowedTopics?: number /* int64 */;
   getLogsSplitOnError?: boolean;
   skipWhenSyncing?: boolean;
+  headSubscription?: EvmHeadSubscriptionConfig;
+}
+/**
+ * EvmHeadSubscriptionConfig enables tracking the latest block of an upstream via an
+ * eth_subscribe("newHeads") websocket subscription, instead of polling eth_getBlockByNumber.
+ */
+export interface EvmHeadSubscriptionConfig {
+  endpoint?: string;
+  staleTimeout?: Duration;
+  maxReconnectDelay?: Duration;
+  pollerInterval?: Duration;
 }
 export interface FailsafeConfig {
   matchMethod?: string;
@@ -371,6 +551,15 @@ export interface RetryPolicyConfig {
   emptyResultConfidence?: AvailbilityConfidence;
   emptyResultIgnore?: string[];
 }
+/**
+ * RetryBudgetConfig caps the number of retries and hedges of a network relative to the total
+ * number of requests seen over a sliding window, so that outages do not turn into retry storms.
+ */
+export interface RetryBudgetConfig {
+  maxRatio: number /* float64 */;
+  window?: Duration;
+  minRetries?: number /* int */;
+}
 export interface CircuitBreakerPolicyConfig {
   failureThresholdCount: number /* uint */;
   failureThresholdCapacity: number /* uint */;
@@ -378,8 +567,18 @@ export interface CircuitBreakerPolicyConfig {
   successThresholdCount: number /* uint */;
   successThresholdCapacity: number /* uint */;
 }
+/**
+ * TimeoutPolicyConfig defines a static timeout (duration), or a dynamic one when quantile is set.
+ * In dynamic mode the timeout is the tracked response-time quantile of the method multiplied
+ * by the multiplier and clamped between minDuration and maxDuration, while duration is used
+ * until enough samples are collected.
+ */
 export interface TimeoutPolicyConfig {
   duration?: Duration;
+  quantile?: number /* float64 */;
+  multiplier?: number /* float64 */;
+  minDuration?: Duration;
+  maxDuration?: Duration;
 }
 export interface HedgePolicyConfig {
   delay?: Duration;
@@ -405,6 +604,25 @@ export interface ConsensusPolicyConfig {
   lowParticipantsBehavior?: ConsensusLowParticipantsBehavior;
   punishMisbehavior?: PunishMisbehaviorConfig;
   disputeLogLevel?: string; // "trace", "debug", "info", "warn", "error"
+  /**
+   * UpstreamWeights defines how many votes each upstream (by id) counts for when evaluating
+   * agreement, e.g. a trusted self-hosted node can weigh more than a public endpoint. Default is 1.
+   */
+  upstreamWeights?: { [key: string]: number /* int */};
+  /**
+   * ComparisonProfiles defines per method (exact name or wildcard) which fields of the result
+   * are compared between participants, instead of comparing the whole response.
+   */
+  comparisonProfiles?: { [key: string]: ConsensusComparisonProfileConfig | undefined};
+}
+/**
+ * ConsensusComparisonProfileConfig uses dot-separated field paths (with "*" for array items,
+ * e.g. "transactions.*.hash"). When includeFields is set only those fields are compared,
+ * and ignoreFields are then removed from what is compared.
+ */
+export interface ConsensusComparisonProfileConfig {
+  includeFields?: string[];
+  ignoreFields?: string[];
 }
 export interface PunishMisbehaviorConfig {
   disputeThreshold: number /* uint */;
@@ -439,6 +657,7 @@ export interface NetworkConfig {
   architecture: TsNetworkArchitecture;
   rateLimitBudget?: string;
   failsafe?: (FailsafeConfig | undefined)[];
+  retryBudget?: RetryBudgetConfig;
   evm?: EvmNetworkConfig;
   selectionPolicy?: SelectionPolicyConfig;
   directiveDefaults?: DirectiveDefaultsConfig;
@@ -462,6 +681,28 @@ export interface EvmNetworkConfig {
   fallbackFinalityDepth?: number /* int64 */;
   fallbackStatePollerDebounce?: Duration;
   integrity?: EvmIntegrityConfig;
+  /**
+   * TransactionBroadcast (opt-in) submits eth_sendRawTransaction to multiple upstreams in parallel
+   * instead of the regular single-winner forwarding.
+   */
+  transactionBroadcast?: EvmTransactionBroadcastConfig;
+}
+/**
+ * EvmTransactionBroadcastConfig defines how signed transactions are broadcasted to upstreams.
+ * The first successful submission is returned, while "already known" (or "nonce too low" for the same
+ * transaction hash) responses are also considered a success since the transaction is already in the mempool.
+ */
+export interface EvmTransactionBroadcastConfig {
+  enabled: boolean;
+  /**
+   * MaxUpstreams limits how many of the (score-sorted) upstreams receive the transaction, 0 means all.
+   */
+  maxUpstreams?: number /* int */;
+  /**
+   * Upstreams (ids or wildcards) always receive the transaction on top of MaxUpstreams,
+   * e.g. private or MEV-protected endpoints.
+   */
+  upstreams?: string[];
 }
 export interface EvmIntegrityConfig {
   enforceHighestBlock?: boolean;
@@ -480,6 +721,8 @@ export const AuthTypeSecret: AuthType = "secret";
 export const AuthTypeJwt: AuthType = "jwt";
 export const AuthTypeSiwe: AuthType = "siwe";
 export const AuthTypeNetwork: AuthType = "network";
+export const AuthTypeWebhook: AuthType = "webhook";
+export const AuthTypeMtls: AuthType = "mtls";
 export interface AuthConfig {
   strategies: TsAuthStrategyConfig[];
 }
@@ -487,14 +730,62 @@ export interface AuthStrategyConfig {
   ignoreMethods?: string[];
   allowMethods?: string[];
   rateLimitBudget?: string;
+  /**
+   * Authorization derives per-consumer budget, networks, methods and batch size from verified
+   * claims (only supported for jwt and siwe strategies), so one strategy can serve many tiers.
+   */
+  authorization?: ClaimsAuthorizationConfig;
   type: TsAuthType;
   network?: NetworkStrategyConfig;
   secret?: SecretStrategyConfig;
   jwt?: JwtStrategyConfig;
   siwe?: SiweStrategyConfig;
+  webhook?: WebhookStrategyConfig;
+  mtls?: MtlsStrategyConfig;
+}
+export interface ClaimsAuthorizationConfig {
+  /**
+   * TierClaim is the claim whose value is looked up in Tiers (e.g. "plan").
+   */
+  tierClaim?: string;
+  tiers?: { [key: string]: AuthorizationTierConfig | undefined};
+  /**
+   * Claims that directly carry the authorization values, these take precedence over the tier.
+   */
+  rateLimitBudgetClaim?: string;
+  allowedNetworksClaim?: string;
+  allowedMethodsClaim?: string;
+  maxBatchSizeClaim?: string;
+  /**
+   * MaxBatchConcurrencyClaim carries the max number of batch items of the consumer processed at the same time.
+   */
+  maxBatchConcurrencyClaim?: string;
+}
+export interface AuthorizationTierConfig {
+  rateLimitBudget?: string;
+  allowedNetworks?: string[];
+  allowedMethods?: string[];
+  maxBatchSize?: number /* int */;
+  /**
+   * MaxBatchConcurrency bounds batch items of this consumer processed at the same time, across its requests.
+   */
+  maxBatchConcurrency?: number /* int */;
 }
 export interface SecretStrategyConfig {
   value: string;
+  /**
+   * KeysFile is a yaml/json file of hashed secret keys with per-key metadata, reloaded when changed.
+   */
+  keysFile?: string;
+  /**
+   * KeysConnector is a data connector holding hashed secret keys (one record per key hash).
+   */
+  keysConnector?: ConnectorConfig;
+  /**
+   * KeysRefreshInterval is how often the keys file is checked for changes, and how long keys
+   * looked up from the connector are cached, i.e. max delay for a revocation to take effect.
+   */
+  keysRefreshInterval?: Duration;
 }
 export interface JwtStrategyConfig {
   allowedIssuers: string[];
@@ -502,6 +793,66 @@ export interface JwtStrategyConfig {
   allowedAlgorithms: string[];
   requiredClaims: string[];
   verificationKeys: { [key: string]: string};
+  /**
+   * JwksUrls are JSON Web Key Set endpoints of identity providers, keys are fetched and
+   * looked up by "kid" so that signing key rotations do not require a config change.
+   */
+  jwksUrls?: string[];
+  /**
+   * JwksRefreshInterval is how often cached JWKS keys are refreshed in the background.
+   */
+  jwksRefreshInterval?: Duration;
+  /**
+   * JwksMinRefreshInterval is the minimum time between two fetches of the same JWKS,
+   * used to rate-limit refreshes triggered by tokens carrying an unknown "kid".
+   */
+  jwksMinRefreshInterval?: Duration;
+}
+export interface WebhookStrategyConfig {
+  /**
+   * Url of the external authorization service, the auth payload, method and network are POSTed as JSON.
+   */
+  url: string;
+  headers?: { [key: string]: string};
+  timeout?: Duration;
+  /**
+   * CacheTtl is how long allow/deny decisions are cached per credential, method and network.
+   */
+  cacheTtl?: Duration;
+  /**
+   * FailOpen allows requests when the service is unreachable or errors (default is fail-closed).
+   */
+  failOpen?: boolean;
+  /**
+   * PayloadTypes limits which credentials are sent to the service, by default all payload types are.
+   */
+  payloadTypes?: TsAuthType[];
+}
+export interface MtlsStrategyConfig {
+  /**
+   * CAFiles are PEM bundles of the CAs that client certificates must chain to.
+   */
+  caFiles: string[];
+  /**
+   * Rules are matched against the certificate identity in order, the first matching rule authorizes
+   * the client. When no rules are defined any certificate issued by the CAs is accepted.
+   */
+  rules?: (MtlsRuleConfig | undefined)[];
+}
+export interface MtlsRuleConfig {
+  /**
+   * Subject is a wildcard pattern matched against the certificate subject common name.
+   */
+  subject?: string;
+  /**
+   * San is a wildcard pattern matched against DNS, URI (e.g. spiffe://) and email SANs.
+   */
+  san?: string;
+  rateLimitBudget?: string;
+  allowedNetworks?: string[];
+  allowedMethods?: string[];
+  maxBatchSize?: number /* int */;
+  maxBatchConcurrency?: number /* int */;
 }
 export interface SiweStrategyConfig {
   allowedDomains: string[];
@@ -524,6 +875,51 @@ export interface MetricsConfig {
   port?: number /* int */;
   errorLabelMode?: LabelMode;
   histogramBuckets?: string;
+  /**
+   * Otlp pushes the same metrics (names and labels) to an OTLP collector, independently of the Prometheus endpoint
+   */
+  otlp?: MetricsOtlpConfig;
+  /**
+   * ConsumerLabel adds a "consumer" label to network request metrics, limited to the most active consumers
+   */
+  consumerLabel?: MetricsConsumerLabelConfig;
+}
+export type ConsumerLabelSource = string;
+/**
+ * ConsumerLabelSourceAuth uses the authenticated identity (secret key owner, JWT subject, SIWE address, etc.)
+ */
+export const ConsumerLabelSourceAuth: ConsumerLabelSource = "auth";
+/**
+ * ConsumerLabelSourceHeader uses the value of a request header (e.g. X-Team)
+ */
+export const ConsumerLabelSourceHeader: ConsumerLabelSource = "header";
+/**
+ * ConsumerLabelSourceUserAgent uses the User-Agent family (e.g. viem, ethers, curl, browser)
+ */
+export const ConsumerLabelSourceUserAgent: ConsumerLabelSource = "userAgent";
+export interface MetricsConsumerLabelConfig {
+  enabled?: boolean;
+  source?: ConsumerLabelSource;
+  header?: string;
+  /**
+   * MaxValues is how many distinct consumers get their own label value, others are reported as "other"
+   */
+  maxValues?: number /* int */;
+  /**
+   * RankingWindow is how often the most active consumers are re-ranked
+   */
+  rankingWindow?: Duration;
+}
+export type MetricsTemporality = string;
+export const MetricsTemporalityCumulative: MetricsTemporality = "cumulative";
+export const MetricsTemporalityDelta: MetricsTemporality = "delta";
+export interface MetricsOtlpConfig {
+  enabled?: boolean;
+  endpoint?: string;
+  protocol?: TracingProtocol;
+  tls?: TLSConfig;
+  pushInterval?: Duration;
+  temporality?: MetricsTemporality;
 }
 
 //////////
@@ -26,6 +26,9 @@ export {
   CacheEmptyBehaviorIgnore,
   CacheEmptyBehaviorAllow,
   CacheEmptyBehaviorOnly,
+  // Firewall actions
+  FirewallActionDeny,
+  FirewallActionAllow,
   // Evm node type
   EvmNodeTypeFull,
   EvmNodeTypeArchive,
@@ -43,6 +46,8 @@ export {
   AuthTypeJwt,
   AuthTypeSiwe,
   AuthTypeNetwork,
+  AuthTypeWebhook,
+  AuthTypeMtls,
   // Consensus related
   ConsensusLowParticipantsBehaviorReturnError,
   ConsensusLowParticipantsBehaviorAcceptMostCommonValidResult,
@@ -63,6 +68,7 @@ export type {
   // Upstream related
   UpstreamConfig,
   EvmUpstreamConfig,
+  EvmHeadSubscriptionConfig,
   RoutingConfig,
   ScoreMultiplierConfig,
   RateLimitAutoTuneConfig,
@@ -83,9 +89,17 @@ export type {
   // DB related
   DatabaseConfig,
   CacheConfig,
+  SharedStateConfig,
+  LeaderElectionConfig,
   DataFinalityState,
   CacheEmptyBehavior,
   CachePolicyConfig,
+  FirewallConfig,
+  FirewallRuleConfig,
+  FirewallAction,
+  BatchConfig,
+  CaptureConfig,
+  CaptureRedactRuleConfig,
   MemoryConnectorConfig,
   RedisConnectorConfig,
   DynamoDBConnectorConfig,
@@ -96,6 +110,9 @@ export type {
   SecretStrategyConfig,
   JwtStrategyConfig,
   SiweStrategyConfig,
+  WebhookStrategyConfig,
+  MtlsStrategyConfig,
+  MtlsRuleConfig,
   NetworkStrategyConfig,
   // Rate limits related
   RateLimiterConfig,
@@ -103,8 +120,16 @@ export type {
   RateLimitRuleConfig,
   // Server config related
   ServerConfig,
+  GrpcServerConfig,
   CORSConfig,
   MetricsConfig,
+  TracingConfig,
+  TracingTailSamplingConfig,
+  MetricsOtlpConfig,
+  MetricsConsumerLabelConfig,
+  ConsumerLabelSource,
+  LogsConfig,
+  LogsOtlpConfig,
   AdminConfig,
   AliasingConfig,
   AliasingRuleConfig,
@@ -4,11 +4,13 @@ import type {
     AuthStrategyConfig as GenAuthStrategyConfig,
     JwtStrategyConfig,
     MemoryConnectorConfig,
+    MtlsStrategyConfig,
     NetworkStrategyConfig,
     PostgreSQLConnectorConfig,
     RedisConnectorConfig,
     SecretStrategyConfig,
     SiweStrategyConfig,
+    WebhookStrategyConfig,
   } from "../generated";
   
   /**
@@ -111,14 +113,14 @@ import type {
   /**
    * Supported auth type
    */
-  export type AuthType = "secret" | "jwt" | "siwe" | "network";
+  export type AuthType = "secret" | "jwt" | "siwe" | "network" | "webhook" | "mtls";
   
   /**
    * Connector config depending on the upstream type
    */
   export type AuthStrategyConfig = Omit<
     GenAuthStrategyConfig,
-    "type" | "network" | "secret" | "jwt" | "siwe"
+    "type" | "network" | "secret" | "jwt" | "siwe" | "webhook" | "mtls"
   > &
     (
       | {
@@ -137,6 +139,14 @@ import type {
           type: "siwe";
           secret: SiweStrategyConfig;
         }
+      | {
+          type: "webhook";
+          webhook: WebhookStrategyConfig;
+        }
+      | {
+          type: "mtls";
+          mtls: MtlsStrategyConfig;
+        }
     );
   
   /**
@@ -0,0 +1,117 @@
+package upstream
+
+import (
+	"context"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/failsafe-go/failsafe-go"
+	failsafeCommon "github.com/failsafe-go/failsafe-go/common"
+	"github.com/failsafe-go/failsafe-go/policy"
+	"github.com/failsafe-go/failsafe-go/timeout"
+	"github.com/rs/zerolog"
+)
+
+// resolvedTimeoutContextKey carries the timeout resolved for the current request at a given scope,
+// so that the failsafe timeout policy and the context deadline (used for actual upstream calls) agree.
+type resolvedTimeoutContextKey struct {
+	scope common.Scope
+}
+
+// WithResolvedTimeout stores the timeout computed for a request so that the dynamic
+// timeout policy of the same scope uses it instead of the static fallback duration.
+func WithResolvedTimeout(ctx context.Context, scope common.Scope, d time.Duration) context.Context {
+	return context.WithValue(ctx, resolvedTimeoutContextKey{scope: scope}, d)
+}
+
+// ResolveTimeout computes the effective timeout based on the tracked response-time quantile
+// of the method (multiplied). When there are no samples yet the static duration is used. Both are clamped
+// between min and max duration. The result never exceeds the remaining deadline of the parent context
+// (e.g. server's maxTimeout), so that a proper timeout error is produced before the request gets aborted.
+func ResolveTimeout(ctx context.Context, cfg *common.TimeoutPolicyConfig, mt common.TrackedMetrics) time.Duration {
+	d := cfg.Duration.Duration()
+	if cfg.Quantile > 0 && mt != nil {
+		qt := mt.GetResponseQuantiles()
+		if qt != nil {
+			if dr := qt.GetQuantile(cfg.Quantile); dr > 0 {
+				d = time.Duration(float64(dr) * cfg.Multiplier)
+			}
+		}
+	}
+	d = clampTimeout(cfg, d)
+
+	if ctx != nil {
+		if deadline, ok := ctx.Deadline(); ok {
+			if remaining := time.Until(deadline); remaining > 0 && remaining < d {
+				d = remaining
+			}
+		}
+	}
+
+	return d
+}
+
+func clampTimeout(cfg *common.TimeoutPolicyConfig, d time.Duration) time.Duration {
+	if d < cfg.MinDuration.Duration() {
+		d = cfg.MinDuration.Duration()
+	}
+	if cfg.MaxDuration > 0 && d > cfg.MaxDuration.Duration() {
+		d = cfg.MaxDuration.Duration()
+	}
+	return d
+}
+
+type dynamicTimeoutPolicy struct {
+	scope    common.Scope
+	fallback time.Duration
+	logger   *zerolog.Logger
+}
+
+var _ failsafe.Policy[*common.NormalizedResponse] = &dynamicTimeoutPolicy{}
+
+func createDynamicTimeoutPolicy(logger *zerolog.Logger, scope common.Scope, cfg *common.TimeoutPolicyConfig) failsafe.Policy[*common.NormalizedResponse] {
+	return &dynamicTimeoutPolicy{
+		scope:    scope,
+		fallback: clampTimeout(cfg, cfg.Duration.Duration()),
+		logger:   logger,
+	}
+}
+
+func (p *dynamicTimeoutPolicy) ToExecutor(_ *common.NormalizedResponse) any {
+	e := &dynamicTimeoutExecutor{
+		BaseExecutor:         &policy.BaseExecutor[*common.NormalizedResponse]{},
+		dynamicTimeoutPolicy: p,
+	}
+	e.Executor = e
+	return e
+}
+
+type dynamicTimeoutExecutor struct {
+	*policy.BaseExecutor[*common.NormalizedResponse]
+	*dynamicTimeoutPolicy
+}
+
+var _ policy.Executor[*common.NormalizedResponse] = &dynamicTimeoutExecutor{}
+
+// Apply resolves the time limit for every execution and delegates to a regular timeout executor,
+// so that timeout errors are exactly the same as the static timeout policy.
+func (e *dynamicTimeoutExecutor) Apply(innerFn func(failsafe.Execution[*common.NormalizedResponse]) *failsafeCommon.PolicyResult[*common.NormalizedResponse]) func(failsafe.Execution[*common.NormalizedResponse]) *failsafeCommon.PolicyResult[*common.NormalizedResponse] {
+	return func(exec failsafe.Execution[*common.NormalizedResponse]) *failsafeCommon.PolicyResult[*common.NormalizedResponse] {
+		d := e.fallback
+		if ctx := exec.Context(); ctx != nil {
+			if rd, ok := ctx.Value(resolvedTimeoutContextKey{scope: e.scope}).(time.Duration); ok && rd > 0 {
+				d = rd
+			}
+		}
+
+		builder := timeout.Builder[*common.NormalizedResponse](d)
+		if e.logger.GetLevel() == zerolog.TraceLevel {
+			builder.OnTimeoutExceeded(func(event failsafe.ExecutionDoneEvent[*common.NormalizedResponse]) {
+				e.logger.Trace().Msgf("failsafe dynamic timeout policy: %v (limit: %v, elapsed: %v, attempts: %d, retries: %d, hedges: %d)", event.Error, d.String(), event.ElapsedTime().String(), event.Attempts(), event.Retries(), event.Hedges())
+			})
+		}
+		te := builder.Build().ToExecutor(nil).(policy.Executor[*common.NormalizedResponse])
+
+		return te.Apply(innerFn)(exec)
+	}
+}
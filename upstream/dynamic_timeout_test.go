@@ -0,0 +1,103 @@
+package upstream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/health"
+	"github.com/failsafe-go/failsafe-go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTimeout(t *testing.T) {
+	cfg := &common.TimeoutPolicyConfig{
+		Duration:    common.Duration(5 * time.Second),
+		Quantile:    0.99,
+		Multiplier:  2,
+		MinDuration: common.Duration(300 * time.Millisecond),
+		MaxDuration: common.Duration(3 * time.Second),
+	}
+
+	t.Run("FallsBackToClampedDurationWithoutSamples", func(t *testing.T) {
+		mt := &health.TrackedMetrics{ResponseQuantiles: health.NewQuantileTracker()}
+		assert.Equal(t, 3*time.Second, ResolveTimeout(context.Background(), cfg, mt))
+		assert.Equal(t, 3*time.Second, ResolveTimeout(context.Background(), cfg, nil))
+
+		short := *cfg
+		short.Duration = common.Duration(100 * time.Millisecond)
+		assert.Equal(t, 300*time.Millisecond, ResolveTimeout(context.Background(), &short, nil))
+	})
+
+	t.Run("MultipliesQuantileAndClamps", func(t *testing.T) {
+		mt := &health.TrackedMetrics{ResponseQuantiles: health.NewQuantileTracker()}
+		for i := 0; i < 100; i++ {
+			mt.ResponseQuantiles.Add(0.5)
+		}
+		d := ResolveTimeout(context.Background(), cfg, mt)
+		assert.InDelta(t, float64(time.Second), float64(d), float64(50*time.Millisecond))
+
+		fast := &health.TrackedMetrics{ResponseQuantiles: health.NewQuantileTracker()}
+		for i := 0; i < 100; i++ {
+			fast.ResponseQuantiles.Add(0.01)
+		}
+		assert.Equal(t, 300*time.Millisecond, ResolveTimeout(context.Background(), cfg, fast))
+
+		slow := &health.TrackedMetrics{ResponseQuantiles: health.NewQuantileTracker()}
+		for i := 0; i < 100; i++ {
+			slow.ResponseQuantiles.Add(10)
+		}
+		assert.Equal(t, 3*time.Second, ResolveTimeout(context.Background(), cfg, slow))
+	})
+
+	t.Run("NeverExceedsParentDeadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		d := ResolveTimeout(ctx, cfg, nil)
+		assert.LessOrEqual(t, d, time.Second)
+		assert.Greater(t, d, 500*time.Millisecond)
+	})
+}
+
+func TestDynamicTimeoutPolicy_UsesResolvedTimeout(t *testing.T) {
+	logger := zerolog.Nop()
+	fsCfg := &common.FailsafeConfig{
+		MatchMethod: "*",
+		Timeout: &common.TimeoutPolicyConfig{
+			Duration:    common.Duration(5 * time.Second),
+			Quantile:    0.99,
+			Multiplier:  1,
+			MaxDuration: common.Duration(5 * time.Second),
+		},
+	}
+	policies, err := CreateFailSafePolicies(&logger, common.ScopeUpstream, "rpc1", fsCfg, nil)
+	require.NoError(t, err)
+	executor := failsafe.NewExecutor(ToPolicyArray(policies, "timeout")...)
+
+	ctx := WithResolvedTimeout(context.Background(), common.ScopeUpstream, 50*time.Millisecond)
+	start := time.Now()
+	_, execErr := executor.WithContext(ctx).GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (*common.NormalizedResponse, error) {
+		select {
+		case <-exec.Context().Done():
+			return nil, exec.Context().Err()
+		case <-time.After(time.Second):
+			return nil, nil
+		}
+	})
+	require.Error(t, execErr)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	translated := TranslateFailsafeError(common.ScopeUpstream, "rpc1", "eth_call", execErr, &start)
+	assert.True(t, common.HasErrorCode(translated, common.ErrCodeFailsafeTimeoutExceeded))
+
+	// A resolved timeout of another scope must not be picked up
+	ctx = WithResolvedTimeout(context.Background(), common.ScopeNetwork, 50*time.Millisecond)
+	_, execErr = executor.WithContext(ctx).GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (*common.NormalizedResponse, error) {
+		time.Sleep(100 * time.Millisecond)
+		return nil, nil
+	})
+	assert.NoError(t, execErr)
+}
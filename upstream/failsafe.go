@@ -9,6 +9,7 @@ import (
 	"github.com/erpc/erpc/architecture/evm"
 	"github.com/erpc/erpc/common"
 	"github.com/erpc/erpc/consensus"
+	"github.com/erpc/erpc/telemetry"
 	"github.com/failsafe-go/failsafe-go"
 	"github.com/failsafe-go/failsafe-go/circuitbreaker"
 	"github.com/failsafe-go/failsafe-go/hedgepolicy"
@@ -19,18 +20,24 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-func CreateFailSafePolicies(logger *zerolog.Logger, scope common.Scope, entity string, fsCfg *common.FailsafeConfig) (map[string]failsafe.Policy[*common.NormalizedResponse], error) {
+// CreateFailSafePolicies builds the policies of one failsafe entry, budget is shared by all entries of a network.
+func CreateFailSafePolicies(logger *zerolog.Logger, scope common.Scope, entity string, fsCfg *common.FailsafeConfig, budget *RetryBudget) (map[string]failsafe.Policy[*common.NormalizedResponse], error) {
 	// The order of policies below are important as per docs of failsafe-go
 	var policies = map[string]failsafe.Policy[*common.NormalizedResponse]{}
 
+	lg := logger.With().Str("scope", string(scope)).Str("entity", entity).Logger()
+
+	// Every request is recorded by the budget policy, even for entries without retries
+	if budget != nil {
+		policies["retryBudget"] = createRetryBudgetPolicy(&lg, scope, entity, budget)
+	}
+
 	if fsCfg == nil {
 		return policies, nil
 	}
 
-	lg := logger.With().Str("scope", string(scope)).Str("entity", entity).Logger()
-
 	if fsCfg.Timeout != nil {
-		plc, err := createTimeoutPolicy(logger, fsCfg.Timeout)
+		plc, err := createTimeoutPolicy(logger, scope, fsCfg.Timeout)
 		if err != nil {
 			return nil, common.NewErrFailsafeConfiguration(
 				err,
@@ -72,7 +79,7 @@ func CreateFailSafePolicies(logger *zerolog.Logger, scope common.Scope, entity s
 	}
 
 	if fsCfg.Hedge != nil && fsCfg.Hedge.MaxCount > 0 {
-		p, err := createHedgePolicy(&lg, fsCfg.Hedge)
+		p, err := createHedgePolicy(&lg, scope, entity, fsCfg.Hedge, budget)
 		if err != nil {
 			return nil, err
 		}
@@ -242,7 +249,7 @@ func createCircuitBreakerPolicy(logger *zerolog.Logger, cfg *common.CircuitBreak
 	return builder.Build(), nil
 }
 
-func createHedgePolicy(logger *zerolog.Logger, cfg *common.HedgePolicyConfig) (failsafe.Policy[*common.NormalizedResponse], error) {
+func createHedgePolicy(logger *zerolog.Logger, scope common.Scope, entity string, cfg *common.HedgePolicyConfig, budget *RetryBudget) (failsafe.Policy[*common.NormalizedResponse], error) {
 	var builder hedgepolicy.HedgePolicyBuilder[*common.NormalizedResponse]
 
 	delay := cfg.Delay.Duration()
@@ -326,6 +333,17 @@ func createHedgePolicy(logger *zerolog.Logger, cfg *common.HedgePolicyConfig) (f
 			}
 		}
 
+		// Hedges are extra upstream calls just like retries, so they must fit within the retry budget
+		if budget != nil && !budget.TryAcquire() {
+			span.SetAttributes(
+				attribute.Bool("hedge", false),
+				attribute.String("reason", "retry_budget_exhausted"),
+			)
+			telemetry.MetricRetryBudgetExhaustedTotal.WithLabelValues(string(scope), entity, method, "hedge").Inc()
+			logger.Debug().Str("method", method).Msgf("ignoring hedge because retry budget is exhausted")
+			return false
+		}
+
 		span.SetAttributes(
 			attribute.Bool("hedge", true),
 			attribute.String("reason", "allowed"),
@@ -398,6 +416,15 @@ func createRetryPolicy(scope common.Scope, cfg *common.RetryPolicyConfig) (fails
 			))
 		defer span.End()
 
+		// Retry budget of this same policy is exhausted -> No Retry
+		if _, ok := err.(*common.ErrFailsafeRetryBudgetExhausted); ok {
+			span.SetAttributes(
+				attribute.Bool("retry", false),
+				attribute.String("reason", "retry_budget_exhausted"),
+			)
+			return false
+		}
+
 		// Node-level execution exceptions (e.g. reverted eth_call) -> No Retry
 		if common.HasErrorCode(err, common.ErrCodeEndpointExecutionException) {
 			span.SetAttributes(
@@ -587,7 +614,11 @@ func createRetryPolicy(scope common.Scope, cfg *common.RetryPolicyConfig) (fails
 	return builder.Build(), nil
 }
 
-func createTimeoutPolicy(logger *zerolog.Logger, cfg *common.TimeoutPolicyConfig) (failsafe.Policy[*common.NormalizedResponse], error) {
+func createTimeoutPolicy(logger *zerolog.Logger, scope common.Scope, cfg *common.TimeoutPolicyConfig) (failsafe.Policy[*common.NormalizedResponse], error) {
+	if cfg.Quantile > 0 {
+		return createDynamicTimeoutPolicy(logger, scope, cfg), nil
+	}
+
 	builder := timeout.Builder[*common.NormalizedResponse](cfg.Duration.Duration())
 
 	if logger.GetLevel() == zerolog.TraceLevel {
@@ -611,6 +642,8 @@ func createConsensusPolicy(logger *zerolog.Logger, cfg *common.ConsensusPolicyCo
 	builder = builder.WithDisputeBehavior(cfg.DisputeBehavior)
 	builder = builder.WithPunishMisbehavior(cfg.PunishMisbehavior)
 	builder = builder.WithLowParticipantsBehavior(cfg.LowParticipantsBehavior)
+	builder = builder.WithUpstreamWeights(cfg.UpstreamWeights)
+	builder = builder.WithComparisonProfiles(cfg.ComparisonProfiles)
 	builder = builder.WithLogger(logger)
 
 	// Parse dispute log level if specified
@@ -637,7 +670,8 @@ func TranslateFailsafeError(scope common.Scope, upstreamId string, method string
 
 	// Our own standard error is returned when failsafe execution is returned and for example retry policy
 	// logic above decided it does not need to retry (e.g. reverted transaction error).
-	// Another case is an UpstreamExhausted error which is not going to be retried due to all errors being unretryable.
+	// Another case is an UpstreamExhausted error which is not going to be retried due to all errors being unretryable,
+	// or an ErrFailsafeRetryBudgetExhausted error when the retry budget denied another retry attempt.
 	// In those cases we return the standard error object as is.
 	if serr, ok := execErr.(common.StandardError); ok {
 		err = serr
@@ -19,6 +19,13 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// forkedUpstreamScorePenalty is multiplied into the score of upstreams whose head is on a minority fork
+const forkedUpstreamScorePenalty = 0.01
+
+// deregisteredUpstreamDrainPeriod is the longest a deregistered upstream keeps its client and state poller
+// running while waiting for requests already sent to it to complete
+var deregisteredUpstreamDrainPeriod = 60 * time.Second
+
 type UpstreamsRegistry struct {
 	appCtx               context.Context
 	prjId                string
@@ -45,6 +52,9 @@ type UpstreamsRegistry struct {
 	upstreamScores map[string]map[string]map[string]float64
 
 	onUpstreamRegistered func(ups *Upstream) error
+
+	// map of provider/network => struct{} for providers whose upstreams are periodically regenerated
+	providerRefreshers sync.Map
 }
 
 type UpstreamsHealth struct {
@@ -129,6 +139,10 @@ func (u *UpstreamsRegistry) GetProvidersRegistry() *thirdparty.ProvidersRegistry
 	return u.providersRegistry
 }
 
+func (u *UpstreamsRegistry) GetVendorsRegistry() *thirdparty.VendorsRegistry {
+	return u.vendorsRegistry
+}
+
 func (u *UpstreamsRegistry) PrepareUpstreamsForNetwork(ctx context.Context, networkId string) error {
 	networkMu := u.getNetworkMutex(networkId)
 	networkMu.Lock()
@@ -491,11 +505,130 @@ func (u *UpstreamsRegistry) buildProviderBootstrapTask(
 				lg.Error().Err(err).Msg("failed to bootstrap upstreams from provider")
 				return err
 			}
+			if interval := provider.RefreshInterval(); interval > 0 {
+				if _, exists := u.providerRefreshers.LoadOrStore(taskName, struct{}{}); !exists {
+					go u.refreshProviderUpstreams(provider, networkId, upsCfgs, interval)
+				}
+			}
 			return nil
 		},
 	)
 }
 
+// refreshProviderUpstreams periodically regenerates upstreams of a dynamic provider (e.g. service discovery),
+// registering newly listed upstreams and deregistering those no longer listed or whose endpoint changed.
+func (u *UpstreamsRegistry) refreshProviderUpstreams(provider *thirdparty.Provider, networkId string, current []*common.UpstreamConfig, interval time.Duration) {
+	lg := u.logger.With().Str("provider", provider.Id()).Str("networkId", networkId).Logger()
+
+	endpoints := make(map[string]string, len(current))
+	for _, c := range current {
+		endpoints[c.Id] = c.Endpoint
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-u.appCtx.Done():
+			return
+		case <-ticker.C:
+			upsCfgs, err := provider.GenerateUpstreamConfigs(u.appCtx, &lg, networkId)
+			if err != nil {
+				lg.Warn().Err(err).Msg("failed to refresh upstreams from provider; keeping current upstreams")
+				continue
+			}
+
+			listed := make(map[string]string, len(upsCfgs))
+			var added []*common.UpstreamConfig
+			for _, c := range upsCfgs {
+				listed[c.Id] = c.Endpoint
+				if ep, ok := endpoints[c.Id]; !ok || ep != c.Endpoint {
+					added = append(added, c)
+				}
+			}
+			for id, ep := range endpoints {
+				if lep, ok := listed[id]; !ok || lep != ep {
+					lg.Info().Str("upstreamId", id).Msg("deregistering upstream no longer listed by provider")
+					u.deregisterUpstream(id)
+				}
+			}
+			endpoints = listed
+
+			if len(added) > 0 {
+				lg.Info().Int("count", len(added)).Msg("registering upstream(s) newly listed by provider")
+				if err := u.registerUpstream(u.appCtx, added...); err != nil {
+					// Failed tasks are retried in background by the initializer
+					lg.Warn().Err(err).Msg("failed to bootstrap some upstreams newly listed by provider")
+				}
+			}
+		}
+	}
+}
+
+// deregisterUpstream removes an upstream from every list of the registry so it is no longer selected,
+// then stops its background work (state poller, leader election, shared state) and drops its metric series
+// once requests in flight on it complete.
+// Lists are rebuilt rather than modified in place as callers might still be iterating over them.
+func (u *UpstreamsRegistry) deregisterUpstream(upsId string) {
+	u.initializer.RemoveTask(fmt.Sprintf("upstream/%s", upsId))
+
+	u.upstreamsMu.Lock()
+	var ups *Upstream
+	for _, up := range u.allUpstreams {
+		if up.Id() == upsId {
+			ups = up
+			break
+		}
+	}
+	if ups == nil {
+		u.upstreamsMu.Unlock()
+		return
+	}
+	u.allUpstreams = withoutUpstream(u.allUpstreams, upsId)
+	networkId := ups.NetworkId()
+	u.networkUpstreams[networkId] = withoutUpstream(u.networkUpstreams[networkId], upsId)
+	u.networkShadowUpstreams[networkId] = withoutUpstream(u.networkShadowUpstreams[networkId], upsId)
+	for nid, methods := range u.sortedUpstreams {
+		for method, upsList := range methods {
+			u.sortedUpstreams[nid][method] = withoutUpstream(upsList, upsId)
+		}
+	}
+	delete(u.upstreamScores, upsId)
+	u.upstreamsMu.Unlock()
+
+	u.clientRegistry.RemoveClient(ups)
+	go func() {
+		ups.DrainAndShutdown(deregisteredUpstreamDrainPeriod)
+		if !u.hasUpstream(upsId) {
+			// Unless it was listed again in the meantime, its series would be stale forever
+			telemetry.DeleteUpstreamSeries(u.prjId, upsId)
+		}
+	}()
+
+	u.logger.Debug().Str("upstreamId", upsId).Str("networkId", networkId).Msg("upstream deregistered from registry")
+}
+
+func (u *UpstreamsRegistry) hasUpstream(upsId string) bool {
+	u.upstreamsMu.RLock()
+	defer u.upstreamsMu.RUnlock()
+	for _, ups := range u.allUpstreams {
+		if ups.Id() == upsId {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutUpstream(upsList []*Upstream, upsId string) []*Upstream {
+	result := make([]*Upstream, 0, len(upsList))
+	for _, ups := range upsList {
+		if ups.Id() != upsId {
+			result = append(result, ups)
+		}
+	}
+	return result
+}
+
 func (u *UpstreamsRegistry) doRegisterBootstrappedUpstream(ups *Upstream) {
 	networkId := ups.NetworkId()
 	cfg := ups.Config()
@@ -674,6 +807,7 @@ func (u *UpstreamsRegistry) updateScoresAndSort(ctx context.Context, networkId,
 	normTotalRequests := normalizeValues(totalRequests)
 	normBlockHeadLags := normalizeValuesLog(blockHeadLags)
 	normFinalizationLags := normalizeValuesLog(finalizationLags)
+	forked := u.detectForkedUpstreams(upsList)
 	for i, ups := range upsList {
 		upsId := ups.Id()
 		score := u.calculateScore(
@@ -687,6 +821,10 @@ func (u *UpstreamsRegistry) updateScoresAndSort(ctx context.Context, networkId,
 			normBlockHeadLags[i],
 			normFinalizationLags[i],
 		)
+		if forked[ups] {
+			// Demote upstreams that are most likely on a minority fork, they are still used as a last resort
+			score *= forkedUpstreamScorePenalty
+		}
 		// Upstream might not have scores initialized yet (especially when networkId is *)
 		// TODO add a test case to send request to network A when network B is defined in config but no requests sent yet
 		if upsc, ok := u.upstreamScores[upsId]; ok {
@@ -759,6 +897,77 @@ func (u *UpstreamsRegistry) calculateScore(
 	return score * mul.Overall
 }
 
+// detectForkedUpstreams compares latest block hashes of upstreams that are at the same height, and marks
+// upstreams whose hash disagrees with the strict majority. Without a majority (e.g. 1 vs 1) nothing is marked.
+func (u *UpstreamsRegistry) detectForkedUpstreams(upsList []*Upstream) map[*Upstream]bool {
+	type height struct {
+		networkId string
+		number    int64
+	}
+	hashes := make(map[height]map[string][]*Upstream)
+	for _, ups := range upsList {
+		sp := ups.EvmStatePoller()
+		if sp == nil || sp.IsObjectNull() {
+			continue
+		}
+		head := sp.LatestBlockHead()
+		if head == nil || head.Hash == "" || head.Number <= 0 {
+			continue
+		}
+		h := height{ups.NetworkId(), head.Number}
+		if hashes[h] == nil {
+			hashes[h] = make(map[string][]*Upstream)
+		}
+		hashes[h][head.Hash] = append(hashes[h][head.Hash], ups)
+	}
+
+	forked := make(map[*Upstream]bool)
+	for _, byHash := range hashes {
+		if len(byHash) < 2 {
+			continue
+		}
+		total, majorityHash, majority := 0, "", 0
+		for hash, ups := range byHash {
+			total += len(ups)
+			if len(ups) > majority {
+				majorityHash, majority = hash, len(ups)
+			}
+		}
+		if majority*2 <= total {
+			continue
+		}
+		for hash, ups := range byHash {
+			if hash == majorityHash {
+				continue
+			}
+			for _, up := range ups {
+				forked[up] = true
+			}
+		}
+	}
+
+	// Upstreams that are not candidates this round (e.g. stopped reporting a head hash) are reset too,
+	// so that a stale fork flag never keeps penalizing them.
+	for _, ups := range upsList {
+		isForked := forked[ups]
+		if ups.evmHeadForked.Swap(isForked) != isForked {
+			if isForked {
+				head := ups.EvmStatePoller().LatestBlockHead()
+				ups.logger.Warn().Int64("blockNumber", head.Number).Str("blockHash", head.Hash).Msg("upstream head hash disagrees with the majority of upstreams at the same height, demoting it")
+			} else {
+				ups.logger.Info().Msg("upstream is no longer considered on a fork of the majority of upstreams")
+			}
+		}
+		val := 0.0
+		if isForked {
+			val = 1
+		}
+		telemetry.MetricUpstreamHeadForked.WithLabelValues(u.prjId, ups.VendorName(), ups.NetworkId(), ups.Id()).Set(val)
+	}
+
+	return forked
+}
+
 func expCurve(x float64) float64 {
 	return math.Pow(x, 2.0)
 }
@@ -12,6 +12,8 @@ import (
 	"github.com/erpc/erpc/health"
 	"github.com/erpc/erpc/telemetry"
 	"github.com/erpc/erpc/thirdparty"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
@@ -356,6 +358,116 @@ func TestUpstreamsRegistry_Scoring(t *testing.T) {
 	}
 }
 
+func TestUpstreamsRegistry_DemotesForkedUpstreams(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	networkID := "evm:123"
+	method := "eth_call"
+	registry, metricsTracker := createTestRegistry(ctx, "test-project", &log.Logger, 10*time.Second)
+	l, _ := registry.GetSortedUpstreams(ctx, networkID, method)
+	upsList := getUpsByID(l, "upstream-a", "upstream-b", "upstream-c")
+
+	// upstream-a is the fastest but its head is on a different fork than the majority
+	pollers := map[string]*mockEvmStatePoller{
+		"upstream-a": {latestBlock: 100, latestBlockHash: "0xfork"},
+		"upstream-b": {latestBlock: 100, latestBlockHash: "0xcanonical"},
+		"upstream-c": {latestBlock: 100, latestBlockHash: "0xcanonical"},
+	}
+	for i, ups := range upsList {
+		ups.(*Upstream).evmStatePoller = pollers[ups.Id()]
+		simulateRequestsWithLatency(metricsTracker, ups, method, 10, 0.1*float64(i+1))
+	}
+
+	checkUpstreamScoreOrder(t, registry, networkID, method, []string{"upstream-b", "upstream-c", "upstream-a"})
+	assert.True(t, upsList[0].(*Upstream).EvmHeadForked())
+	assert.False(t, upsList[1].(*Upstream).EvmHeadForked())
+
+	// Without a majority at the same height, no upstream is demoted
+	pollers["upstream-c"].latestBlock = 101
+	checkUpstreamScoreOrder(t, registry, networkID, method, []string{"upstream-a", "upstream-b", "upstream-c"})
+	assert.False(t, upsList[0].(*Upstream).EvmHeadForked())
+
+	// An upstream that stops reporting a head hash is no longer considered forked
+	pollers["upstream-c"].latestBlock = 100
+	checkUpstreamScoreOrder(t, registry, networkID, method, []string{"upstream-b", "upstream-c", "upstream-a"})
+	assert.True(t, upsList[0].(*Upstream).EvmHeadForked())
+	pollers["upstream-a"].latestBlockHash = ""
+	checkUpstreamScoreOrder(t, registry, networkID, method, []string{"upstream-a", "upstream-b", "upstream-c"})
+	assert.False(t, upsList[0].(*Upstream).EvmHeadForked())
+}
+
+func TestUpstreamsRegistry_DeregisterUpstream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	networkID := "evm:123"
+	method := "eth_call"
+	registry, _ := createTestRegistry(ctx, "test-project", &log.Logger, 10*time.Second)
+	_, err := registry.GetSortedUpstreams(ctx, networkID, method)
+	assert.NoError(t, err)
+	networkUpstreams := registry.GetNetworkUpstreams(ctx, networkID)
+
+	registry.deregisterUpstream("upstream-b")
+
+	ids := func(upsList []*Upstream) []string {
+		var result []string
+		for _, ups := range upsList {
+			result = append(result, ups.Id())
+		}
+		return result
+	}
+	assert.ElementsMatch(t, []string{"upstream-a", "upstream-c"}, ids(registry.GetNetworkUpstreams(ctx, networkID)))
+	assert.ElementsMatch(t, []string{"upstream-a", "upstream-c"}, ids(registry.GetAllUpstreams()))
+	assert.ElementsMatch(t, []string{"upstream-a", "upstream-c"}, ids(registry.sortedUpstreams[networkID][method]))
+	assert.NotContains(t, registry.upstreamScores, "upstream-b")
+	// Lists previously handed out are left untouched
+	assert.Len(t, networkUpstreams, 3)
+
+	// The same upstream can be registered again, e.g. when it is listed again by a discovery provider
+	err = registry.registerUpstream(ctx, &common.UpstreamConfig{
+		Id: "upstream-b", Endpoint: "http://upstream-b.localhost", Type: common.UpstreamTypeEvm, Evm: &common.EvmUpstreamConfig{ChainId: 123},
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"upstream-a", "upstream-b", "upstream-c"}, ids(registry.GetNetworkUpstreams(ctx, networkID)))
+}
+
+func TestUpstreamsRegistry_DeregisterUpstreamDropsMetricSeries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	networkID := "evm:123"
+	registry, _ := createTestRegistry(ctx, "test-project", &log.Logger, 10*time.Second)
+	_, err := registry.GetSortedUpstreams(ctx, networkID, "eth_call")
+	assert.NoError(t, err)
+
+	telemetry.MetricUpstreamLatestBlockNumber.WithLabelValues("test-project", "vendor", networkID, "upstream-b").Set(100)
+	telemetry.MetricUpstreamLatestBlockNumber.WithLabelValues("other-project", "vendor", networkID, "upstream-b").Set(100)
+	defer telemetry.MetricUpstreamLatestBlockNumber.DeleteLabelValues("other-project", "vendor", networkID, "upstream-b")
+
+	hasSeries := func(project string) bool {
+		ch := make(chan prometheus.Metric, 1024)
+		telemetry.MetricUpstreamLatestBlockNumber.Collect(ch)
+		close(ch)
+		for m := range ch {
+			var pb dto.Metric
+			_ = m.Write(&pb)
+			labels := map[string]string{}
+			for _, lp := range pb.GetLabel() {
+				labels[lp.GetName()] = lp.GetValue()
+			}
+			if labels["project"] == project && labels["upstream"] == "upstream-b" {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, hasSeries("test-project"))
+
+	registry.deregisterUpstream("upstream-b")
+
+	assert.Eventually(t, func() bool { return !hasSeries("test-project") }, time.Second, 10*time.Millisecond)
+	// Same upstream id in another project is left untouched
+	assert.True(t, hasSeries("other-project"))
+}
+
 func TestUpstreamsRegistry_DynamicScenarios(t *testing.T) {
 	registry := &UpstreamsRegistry{
 		scoreRefreshInterval: time.Second,
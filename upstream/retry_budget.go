@@ -0,0 +1,157 @@
+package upstream
+
+import (
+	"sync"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/telemetry"
+	"github.com/failsafe-go/failsafe-go"
+	failsafeCommon "github.com/failsafe-go/failsafe-go/common"
+	"github.com/failsafe-go/failsafe-go/policy"
+	"github.com/rs/zerolog"
+)
+
+const retryBudgetBucketsCount = 10
+
+// RetryBudget tracks total requests vs. retries (including hedges) over a sliding window
+// and only allows a new retry when the ratio stays below the configured maximum.
+// A minimum number of retries per window is always allowed so that low-traffic
+// networks are not starved of retries.
+type RetryBudget struct {
+	maxRatio   float64
+	minRetries int64
+	bucketSize time.Duration
+
+	mu      sync.Mutex
+	buckets [retryBudgetBucketsCount]retryBudgetBucket
+	nowFn   func() time.Time
+}
+
+type retryBudgetBucket struct {
+	slot     int64
+	requests int64
+	retries  int64
+}
+
+func NewRetryBudget(cfg *common.RetryBudgetConfig) *RetryBudget {
+	bucketSize := cfg.Window.Duration() / retryBudgetBucketsCount
+	if bucketSize <= 0 {
+		bucketSize = time.Millisecond
+	}
+	return &RetryBudget{
+		maxRatio:   cfg.MaxRatio,
+		minRetries: int64(cfg.MinRetries),
+		bucketSize: bucketSize,
+		nowFn:      time.Now,
+	}
+}
+
+// RecordRequest counts a new (non-retry) request towards the budget.
+func (b *RetryBudget) RecordRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.currentBucket().requests++
+}
+
+// TryAcquire returns true and consumes from the budget if one more retry (or hedge) is allowed.
+func (b *RetryBudget) TryAcquire() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := b.currentBucket()
+	requests, retries := b.totals(current.slot)
+	if retries >= b.minRetries && float64(retries+1) > b.maxRatio*float64(requests) {
+		return false
+	}
+	current.retries++
+	return true
+}
+
+func (b *RetryBudget) currentBucket() *retryBudgetBucket {
+	slot := b.nowFn().UnixNano() / int64(b.bucketSize)
+	bucket := &b.buckets[slot%retryBudgetBucketsCount]
+	if bucket.slot != slot {
+		bucket.slot = slot
+		bucket.requests = 0
+		bucket.retries = 0
+	}
+	return bucket
+}
+
+func (b *RetryBudget) totals(currentSlot int64) (requests int64, retries int64) {
+	for i := range b.buckets {
+		bucket := &b.buckets[i]
+		if currentSlot-bucket.slot < retryBudgetBucketsCount {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	return requests, retries
+}
+
+// retryBudgetPolicy sits right inside the retry policy so it observes every retry attempt
+// (but not hedges, which are accounted for by the hedge policy via the same budget).
+type retryBudgetPolicy struct {
+	budget *RetryBudget
+	scope  common.Scope
+	entity string
+	logger *zerolog.Logger
+}
+
+var _ failsafe.Policy[*common.NormalizedResponse] = &retryBudgetPolicy{}
+
+func createRetryBudgetPolicy(logger *zerolog.Logger, scope common.Scope, entity string, budget *RetryBudget) failsafe.Policy[*common.NormalizedResponse] {
+	return &retryBudgetPolicy{
+		budget: budget,
+		scope:  scope,
+		entity: entity,
+		logger: logger,
+	}
+}
+
+func (p *retryBudgetPolicy) ToExecutor(_ *common.NormalizedResponse) any {
+	e := &retryBudgetExecutor{
+		BaseExecutor:      &policy.BaseExecutor[*common.NormalizedResponse]{},
+		retryBudgetPolicy: p,
+	}
+	e.Executor = e
+	return e
+}
+
+type retryBudgetExecutor struct {
+	*policy.BaseExecutor[*common.NormalizedResponse]
+	*retryBudgetPolicy
+}
+
+var _ policy.Executor[*common.NormalizedResponse] = &retryBudgetExecutor{}
+
+func (e *retryBudgetExecutor) Apply(innerFn func(failsafe.Execution[*common.NormalizedResponse]) *failsafeCommon.PolicyResult[*common.NormalizedResponse]) func(failsafe.Execution[*common.NormalizedResponse]) *failsafeCommon.PolicyResult[*common.NormalizedResponse] {
+	return func(exec failsafe.Execution[*common.NormalizedResponse]) *failsafeCommon.PolicyResult[*common.NormalizedResponse] {
+		if exec.Retries() == 0 {
+			e.budget.RecordRequest()
+		} else if !e.budget.TryAcquire() {
+			method := retryBudgetMethodFromContext(exec)
+			telemetry.MetricRetryBudgetExhaustedTotal.WithLabelValues(string(e.scope), e.entity, method, "retry").Inc()
+			e.logger.Debug().Str("method", method).Int("retries", exec.Retries()).Msg("retry budget exhausted, giving up on further retries")
+			return &failsafeCommon.PolicyResult[*common.NormalizedResponse]{
+				Error: common.NewErrFailsafeRetryBudgetExhausted(e.scope, exec.LastError()),
+				Done:  true,
+			}
+		}
+		return innerFn(exec)
+	}
+}
+
+func retryBudgetMethodFromContext(exec failsafe.ExecutionAttempt[*common.NormalizedResponse]) string {
+	ctx := exec.Context()
+	if ctx == nil {
+		return "unknown"
+	}
+	if req, ok := ctx.Value(common.RequestContextKey).(*common.NormalizedRequest); ok && req != nil {
+		if m, _ := req.Method(); m != "" {
+			return m
+		}
+	}
+	return "unknown"
+}
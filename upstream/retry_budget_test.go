@@ -0,0 +1,115 @@
+package upstream
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erpc/erpc/common"
+	"github.com/failsafe-go/failsafe-go"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudget_RatioAndMinRetries(t *testing.T) {
+	budget := NewRetryBudget(&common.RetryBudgetConfig{
+		MaxRatio:   0.1,
+		Window:     common.Duration(10 * time.Second),
+		MinRetries: 2,
+	})
+	now := time.Unix(1000, 0)
+	budget.nowFn = func() time.Time { return now }
+
+	// Min retries are always allowed even without any recorded requests
+	assert.True(t, budget.TryAcquire())
+	assert.True(t, budget.TryAcquire())
+	assert.False(t, budget.TryAcquire())
+
+	// 40 requests allow up to 4 retries in total (10%)
+	for i := 0; i < 40; i++ {
+		budget.RecordRequest()
+	}
+	assert.True(t, budget.TryAcquire())
+	assert.True(t, budget.TryAcquire())
+	assert.False(t, budget.TryAcquire())
+
+	// Once the window slides past the recorded buckets, the budget resets
+	now = now.Add(11 * time.Second)
+	assert.True(t, budget.TryAcquire())
+}
+
+func TestRetryBudget_PolicyStopsRetries(t *testing.T) {
+	logger := zerolog.Nop()
+	fsCfg := &common.FailsafeConfig{
+		MatchMethod: "*",
+		Retry: &common.RetryPolicyConfig{
+			MaxAttempts: 5,
+		},
+	}
+	budget := NewRetryBudget(&common.RetryBudgetConfig{
+		MaxRatio:   0.5,
+		Window:     common.Duration(10 * time.Second),
+		MinRetries: 1,
+	})
+	policies, err := CreateFailSafePolicies(&logger, common.ScopeNetwork, "test/evm:1", fsCfg, budget)
+	require.NoError(t, err)
+	require.Contains(t, policies, "retryBudget")
+
+	executor := failsafe.NewExecutor(ToPolicyArray(policies, "retry", "retryBudget")...)
+	upstreamErr := common.NewErrEndpointServerSideException(errors.New("boom"), nil, 500)
+
+	attempts := 0
+	_, execErr := executor.GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (*common.NormalizedResponse, error) {
+		attempts = exec.Attempts()
+		return nil, upstreamErr
+	})
+
+	// Only one request was recorded so far, so only the minimum of 1 retry is allowed
+	assert.Equal(t, 2, attempts)
+	assert.True(t, common.HasErrorCode(execErr, common.ErrCodeFailsafeRetryBudgetExhausted))
+
+	translated := TranslateFailsafeError(common.ScopeNetwork, "", "eth_call", execErr, nil)
+	assert.True(t, common.HasErrorCode(translated, common.ErrCodeFailsafeRetryBudgetExhausted))
+	assert.True(t, common.HasErrorCode(translated, common.ErrCodeEndpointServerSideException))
+}
+
+func TestRetryBudget_SharedByAllFailsafeEntriesOfNetwork(t *testing.T) {
+	logger := zerolog.Nop()
+	budget := NewRetryBudget(&common.RetryBudgetConfig{
+		MaxRatio:   0.5,
+		Window:     common.Duration(10 * time.Second),
+		MinRetries: 0,
+	})
+	withRetry, err := CreateFailSafePolicies(&logger, common.ScopeNetwork, "test/evm:1", &common.FailsafeConfig{
+		MatchMethod: "eth_call",
+		Retry:       &common.RetryPolicyConfig{MaxAttempts: 5},
+	}, budget)
+	require.NoError(t, err)
+	withoutRetry, err := CreateFailSafePolicies(&logger, common.ScopeNetwork, "test/evm:1", &common.FailsafeConfig{
+		MatchMethod: "eth_getLogs",
+	}, budget)
+	require.NoError(t, err)
+	require.Contains(t, withoutRetry, "retryBudget")
+
+	// Requests of an entry without retries still count towards the network-wide ratio
+	plain := failsafe.NewExecutor(ToPolicyArray(withoutRetry, "retry", "retryBudget")...)
+	for i := 0; i < 4; i++ {
+		_, err := plain.Get(func() (*common.NormalizedResponse, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+	}
+
+	retrying := failsafe.NewExecutor(ToPolicyArray(withRetry, "retry", "retryBudget")...)
+	upstreamErr := common.NewErrEndpointServerSideException(errors.New("boom"), nil, 500)
+	attempts := 0
+	_, execErr := retrying.GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (*common.NormalizedResponse, error) {
+		attempts = exec.Attempts()
+		return nil, upstreamErr
+	})
+
+	// 5 requests in total allow 2 retries (50%) for the whole network
+	assert.Equal(t, 3, attempts)
+	assert.True(t, common.HasErrorCode(execErr, common.ErrCodeFailsafeRetryBudgetExhausted))
+}
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bytedance/sonic"
@@ -34,17 +35,21 @@ type FailsafeExecutor struct {
 	finalities []common.DataFinalityState
 	executor   failsafe.Executor[*common.NormalizedResponse]
 	timeout    *time.Duration
+
+	// dynamicTimeout is set when timeout is derived from method response-time quantiles
+	dynamicTimeout *common.TimeoutPolicyConfig
 }
 
 type Upstream struct {
 	ProjectId string
 	Client    clients.ClientInterface
 
-	appCtx context.Context
-	logger *zerolog.Logger
-	config *common.UpstreamConfig
-	cfgMu  sync.RWMutex
-	vendor common.Vendor
+	appCtx       context.Context
+	appCtxCancel context.CancelFunc
+	logger       *zerolog.Logger
+	config       *common.UpstreamConfig
+	cfgMu        sync.RWMutex
+	vendor       common.Vendor
 
 	networkId            string
 	supportedMethods     sync.Map
@@ -54,6 +59,15 @@ type Upstream struct {
 	rateLimitersRegistry *RateLimitersRegistry
 	rateLimiterAutoTuner *RateLimitAutoTuner
 	evmStatePoller       common.EvmStatePoller
+
+	// Used to find the owner of vendor-proprietary methods (e.g. alchemy_*)
+	vendorsRegistry *thirdparty.VendorsRegistry
+
+	// Set by the registry when head hash of this upstream disagrees with the majority at the same height
+	evmHeadForked atomic.Bool
+
+	// Requests being forwarded, a deregistered upstream is only shut down once they complete
+	inflight atomic.Int64
 }
 
 func NewUpstream(
@@ -73,15 +87,19 @@ func NewUpstream(
 	var failsafeExecutors []*FailsafeExecutor
 	if len(cfg.Failsafe) > 0 {
 		for _, fsCfg := range cfg.Failsafe {
-			policiesMap, err := CreateFailSafePolicies(&lg, common.ScopeUpstream, cfg.Id, fsCfg)
+			policiesMap, err := CreateFailSafePolicies(&lg, common.ScopeUpstream, cfg.Id, fsCfg, nil)
 			if err != nil {
 				return nil, err
 			}
-			policiesArray := ToPolicyArray(policiesMap, "retry", "circuitBreaker", "hedge", "timeout")
+			policiesArray := ToPolicyArray(policiesMap, "retry", "retryBudget", "circuitBreaker", "hedge", "timeout")
 
 			var timeoutDuration *time.Duration
+			var dynamicTimeout *common.TimeoutPolicyConfig
 			if fsCfg.Timeout != nil {
 				timeoutDuration = fsCfg.Timeout.Duration.DurationPtr()
+				if fsCfg.Timeout.Quantile > 0 {
+					dynamicTimeout = fsCfg.Timeout
+				}
 			}
 
 			method := fsCfg.MatchMethod
@@ -93,6 +111,8 @@ func NewUpstream(
 				finalities: fsCfg.MatchFinality,
 				executor:   failsafe.NewExecutor(policiesArray...),
 				timeout:    timeoutDuration,
+
+				dynamicTimeout: dynamicTimeout,
 			})
 		}
 	}
@@ -106,13 +126,18 @@ func NewUpstream(
 
 	vn := vr.LookupByUpstream(cfg)
 
+	// Background work of the upstream (state poller, client) stops when it is shut down, e.g. deregistered by discovery
+	appCtx, appCtxCancel := context.WithCancel(appCtx)
+
 	pup := &Upstream{
 		ProjectId: projectId,
 
 		logger:               &lg,
 		appCtx:               appCtx,
+		appCtxCancel:         appCtxCancel,
 		config:               cfg,
 		vendor:               vn,
+		vendorsRegistry:      vr,
 		metricsTracker:       mt,
 		sharedStateRegistry:  ssr,
 		failsafeExecutors:    failsafeExecutors,
@@ -177,6 +202,33 @@ func (u *Upstream) Bootstrap(ctx context.Context) error {
 	return nil
 }
 
+// Shutdown stops background work of the upstream once it is removed from the registry.
+func (u *Upstream) Shutdown() {
+	if u.appCtxCancel != nil {
+		u.appCtxCancel()
+	}
+	if u.evmStatePoller != nil && !u.evmStatePoller.IsObjectNull() {
+		u.evmStatePoller.Shutdown()
+	}
+}
+
+// DrainAndShutdown waits (up to maxWait) for requests being forwarded to complete before shutting down the upstream,
+// since in-flight requests of its client (e.g. batches) are bound to the upstream context cancelled by Shutdown.
+func (u *Upstream) DrainAndShutdown(maxWait time.Duration) {
+	deadline := time.Now().Add(maxWait)
+	ticker := time.NewTicker(upstreamDrainCheckInterval)
+	defer ticker.Stop()
+	for u.inflight.Load() > 0 && time.Now().Before(deadline) {
+		<-ticker.C
+	}
+	if n := u.inflight.Load(); n > 0 {
+		u.logger.Warn().Int64("inflight", n).Msg("shutting down deregistered upstream with requests still in flight")
+	}
+	u.Shutdown()
+}
+
+const upstreamDrainCheckInterval = 100 * time.Millisecond
+
 func (u *Upstream) Id() string {
 	if u == nil {
 		return ""
@@ -283,6 +335,8 @@ func (u *Upstream) Forward(ctx context.Context, nrq *common.NormalizedRequest, b
 	// TODO Should we move byPassMethodExclusion to directives? How do we prevent clients from setting it?
 	startTime := time.Now()
 	cfg := u.Config()
+	u.inflight.Add(1)
+	defer u.inflight.Add(-1)
 
 	method, err := nrq.Method()
 	ctx, span := common.StartSpan(ctx, "Upstream.Forward",
@@ -481,6 +535,19 @@ func (u *Upstream) Forward(ctx context.Context, nrq *common.NormalizedRequest, b
 			return nil, fmt.Errorf("no failsafe executor found for request")
 		}
 
+		timeoutDuration := failsafeExecutor.timeout
+		if failsafeExecutor.dynamicTimeout != nil {
+			var mt common.TrackedMetrics
+			if u.metricsTracker != nil {
+				if umt := u.metricsTracker.GetUpstreamMethodMetrics(u, method); umt != nil {
+					mt = umt
+				}
+			}
+			d := ResolveTimeout(ctx, failsafeExecutor.dynamicTimeout, mt)
+			ctx = WithResolvedTimeout(ctx, common.ScopeUpstream, d)
+			timeoutDuration = &d
+		}
+
 		resp, execErr := failsafeExecutor.executor.
 			WithContext(ctx).
 			GetWithExecution(func(exec failsafe.Execution[*common.NormalizedResponse]) (*common.NormalizedResponse, error) {
@@ -511,7 +578,7 @@ func (u *Upstream) Forward(ctx context.Context, nrq *common.NormalizedRequest, b
 						return nil, ctxErr
 					}
 				}
-				if failsafeExecutor.timeout != nil {
+				if timeoutDuration != nil {
 					var cancelFn context.CancelFunc
 					ectx, cancelFn = context.WithTimeout(
 						ectx,
@@ -519,7 +586,7 @@ func (u *Upstream) Forward(ctx context.Context, nrq *common.NormalizedRequest, b
 						//      Is there a way to do this cleanly? e.g. if failsafe lib works via context rather than Ticker?
 						//      5ms is a workaround to ensure context carries the timeout deadline (used when calling upstreams),
 						//      but allow the failsafe execution to fail with timeout first for proper error handling.
-						*failsafeExecutor.timeout+5*time.Millisecond,
+						*timeoutDuration+5*time.Millisecond,
 					)
 					defer cancelFn()
 				}
@@ -660,6 +727,12 @@ func (u *Upstream) EvmStatePoller() common.EvmStatePoller {
 	return u.evmStatePoller
 }
 
+// EvmHeadForked returns true when the latest block hash of this upstream disagrees with
+// the majority of upstreams on the same network at the same height.
+func (u *Upstream) EvmHeadForked() bool {
+	return u.evmHeadForked.Load()
+}
+
 // TODO move to evm package?
 // EvmAssertBlockAvailability checks if the upstream is supposed to have the data for a certain block number.
 // For full nodes it will check the first available block number, and for archive nodes it will check if the block is less than the latest block number.
@@ -873,6 +946,12 @@ func (u *Upstream) ShouldHandleMethod(method string) (v bool, err error) {
 
 	v = true
 
+	// Proprietary methods of a vendor (e.g. alchemy_getAssetTransfers) are only sent to upstreams of that vendor,
+	// unless explicitly allowed below (e.g. a self-hosted proxy in front of the vendor).
+	if owner := u.vendorsRegistry.LookupByMethod(method); owner != nil && owner.Name() != u.VendorName() {
+		v = false
+	}
+
 	// First check if method is ignored, and then check if it is explicitly mentioned to be allowed.
 	// This order allows an upstream for example to define "ignore all except eth_getLogs".
 
@@ -1069,10 +1148,20 @@ func (u *Upstream) getScoreMultipliers(networkId, method string) *common.ScoreMu
 }
 
 func (u *Upstream) MarshalJSON() ([]byte, error) {
+	type upstreamEvmState struct {
+		LatestBlock          int64  `json:"latestBlock"`
+		LatestBlockHash      string `json:"latestBlockHash,omitempty"`
+		LatestBlockTimestamp int64  `json:"latestBlockTimestamp,omitempty"`
+		SafeBlock            int64  `json:"safeBlock"`
+		FinalizedBlock       int64  `json:"finalizedBlock"`
+		Syncing              string `json:"syncing"`
+		Forked               bool   `json:"forked"`
+	}
 	type upstreamPublic struct {
 		Id        string                            `json:"id"`
 		Metrics   map[string]*health.TrackedMetrics `json:"metrics"`
 		NetworkId string                            `json:"networkId"`
+		EvmState  *upstreamEvmState                 `json:"evmState,omitempty"`
 	}
 
 	metrics := u.metricsTracker.GetUpstreamMetrics(u)
@@ -1082,6 +1171,20 @@ func (u *Upstream) MarshalJSON() ([]byte, error) {
 		Metrics:   metrics,
 		NetworkId: u.NetworkId(),
 	}
+	if sp := u.evmStatePoller; sp != nil && !sp.IsObjectNull() {
+		head := sp.LatestBlockHead()
+		uppub.EvmState = &upstreamEvmState{
+			LatestBlock:    sp.LatestBlock(),
+			SafeBlock:      sp.SafeBlock(),
+			FinalizedBlock: sp.FinalizedBlock(),
+			Syncing:        sp.SyncingState().String(),
+			Forked:         u.EvmHeadForked(),
+		}
+		if head != nil && head.Number == uppub.EvmState.LatestBlock {
+			uppub.EvmState.LatestBlockHash = head.Hash
+			uppub.EvmState.LatestBlockTimestamp = head.Timestamp
+		}
+	}
 
 	return sonic.Marshal(uppub)
 }
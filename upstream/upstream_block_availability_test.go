@@ -42,6 +42,13 @@ func (m *mockEvmStatePollerEnhanced) PollFinalizedBlockNumber(ctx context.Contex
 	}
 	return m.finalizedBlock, nil
 }
+func (m *mockEvmStatePollerEnhanced) PollSafeBlockNumber(ctx context.Context) (int64, error) {
+	return m.finalizedBlock, nil
+}
+func (m *mockEvmStatePollerEnhanced) SafeBlock() int64 { return m.finalizedBlock }
+func (m *mockEvmStatePollerEnhanced) LatestBlockHead() *common.EvmBlockHead {
+	return &common.EvmBlockHead{Number: m.latestBlock}
+}
 func (m *mockEvmStatePollerEnhanced) SyncingState() common.EvmSyncingState {
 	return common.EvmSyncingStateNotSyncing
 }
@@ -57,6 +64,7 @@ func (m *mockEvmStatePollerEnhanced) IsBlockFinalized(blockNumber int64) (bool,
 func (m *mockEvmStatePollerEnhanced) SuggestFinalizedBlock(blockNumber int64)       {}
 func (m *mockEvmStatePollerEnhanced) SuggestLatestBlock(blockNumber int64)          {}
 func (m *mockEvmStatePollerEnhanced) SetNetworkConfig(cfg *common.EvmNetworkConfig) {}
+func (m *mockEvmStatePollerEnhanced) Shutdown()                                     {}
 func (m *mockEvmStatePollerEnhanced) IsObjectNull() bool                            { return m.isNull }
 
 func TestEvmAssertBlockAvailability_EdgeCases(t *testing.T) {
@@ -589,6 +597,13 @@ func (m *mockEvmStatePollerWithCustomBehavior) PollLatestBlockNumber(ctx context
 func (m *mockEvmStatePollerWithCustomBehavior) PollFinalizedBlockNumber(ctx context.Context) (int64, error) {
 	return m.finalizedBlock, nil
 }
+func (m *mockEvmStatePollerWithCustomBehavior) PollSafeBlockNumber(ctx context.Context) (int64, error) {
+	return m.finalizedBlock, nil
+}
+func (m *mockEvmStatePollerWithCustomBehavior) SafeBlock() int64 { return m.finalizedBlock }
+func (m *mockEvmStatePollerWithCustomBehavior) LatestBlockHead() *common.EvmBlockHead {
+	return &common.EvmBlockHead{Number: m.getLatestBlock()}
+}
 func (m *mockEvmStatePollerWithCustomBehavior) SyncingState() common.EvmSyncingState {
 	return common.EvmSyncingStateNotSyncing
 }
@@ -603,6 +618,7 @@ func (m *mockEvmStatePollerWithCustomBehavior) IsBlockFinalized(blockNumber int6
 func (m *mockEvmStatePollerWithCustomBehavior) SuggestFinalizedBlock(blockNumber int64)       {}
 func (m *mockEvmStatePollerWithCustomBehavior) SuggestLatestBlock(blockNumber int64)          {}
 func (m *mockEvmStatePollerWithCustomBehavior) SetNetworkConfig(cfg *common.EvmNetworkConfig) {}
+func (m *mockEvmStatePollerWithCustomBehavior) Shutdown()                                     {}
 func (m *mockEvmStatePollerWithCustomBehavior) IsObjectNull() bool                            { return false }
 
 func TestEvmAssertBlockAvailability_Metrics(t *testing.T) {
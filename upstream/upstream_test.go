@@ -3,8 +3,10 @@ package upstream
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/erpc/erpc/common"
+	"github.com/erpc/erpc/thirdparty"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,6 +48,41 @@ func TestUpstream_SkipLogic(t *testing.T) {
 		assert.Nil(t, reason)
 	})
 
+	t.Run("VendorProprietaryMethods", func(t *testing.T) {
+		vr := thirdparty.NewVendorsRegistry()
+		alchemyUps := &Upstream{
+			config:          &common.UpstreamConfig{Id: "alchemy", VendorName: "alchemy"},
+			vendor:          vr.LookupByName("alchemy"),
+			vendorsRegistry: vr,
+			logger:          &zerolog.Logger{},
+		}
+		selfHostedUps := &Upstream{
+			config:          &common.UpstreamConfig{Id: "self-hosted", VendorName: "localhost"},
+			vendorsRegistry: vr,
+			logger:          &zerolog.Logger{},
+		}
+		proxyUps := &Upstream{
+			config:          &common.UpstreamConfig{Id: "proxy", VendorName: "localhost", AllowMethods: []string{"alchemy_*"}},
+			vendorsRegistry: vr,
+			logger:          &zerolog.Logger{},
+		}
+
+		req := `{"method":"alchemy_getAssetTransfers"}`
+		_, skip := alchemyUps.shouldSkip(context.TODO(), common.NewNormalizedRequest([]byte(req)))
+		assert.False(t, skip)
+		reason, skip := selfHostedUps.shouldSkip(context.TODO(), common.NewNormalizedRequest([]byte(req)))
+		assert.True(t, skip)
+		assert.Contains(t, reason.Error(), "ErrUpstreamMethodIgnored")
+		_, skip = proxyUps.shouldSkip(context.TODO(), common.NewNormalizedRequest([]byte(req)))
+		assert.False(t, skip)
+
+		// Proprietary methods of other vendors and standard methods are unaffected
+		_, skip = alchemyUps.shouldSkip(context.TODO(), common.NewNormalizedRequest([]byte(`{"method":"qn_getWalletTokenBalance"}`)))
+		assert.True(t, skip)
+		_, skip = selfHostedUps.shouldSkip(context.TODO(), common.NewNormalizedRequest([]byte(`{"method":"eth_call"}`)))
+		assert.False(t, skip)
+	})
+
 	t.Run("MultipleMethods", func(t *testing.T) {
 		upstream := &Upstream{
 			config: &common.UpstreamConfig{
@@ -271,9 +308,10 @@ func TestUpstream_SkipLogic(t *testing.T) {
 
 // Mock EVM state poller for testing
 type mockEvmStatePoller struct {
-	latestBlock    int64
-	finalizedBlock int64
-	isNull         bool
+	latestBlock     int64
+	latestBlockHash string
+	finalizedBlock  int64
+	isNull          bool
 }
 
 func (m *mockEvmStatePoller) Bootstrap(ctx context.Context) error { return nil }
@@ -284,6 +322,13 @@ func (m *mockEvmStatePoller) PollLatestBlockNumber(ctx context.Context) (int64,
 func (m *mockEvmStatePoller) PollFinalizedBlockNumber(ctx context.Context) (int64, error) {
 	return m.finalizedBlock, nil
 }
+func (m *mockEvmStatePoller) PollSafeBlockNumber(ctx context.Context) (int64, error) {
+	return m.finalizedBlock, nil
+}
+func (m *mockEvmStatePoller) SafeBlock() int64 { return m.finalizedBlock }
+func (m *mockEvmStatePoller) LatestBlockHead() *common.EvmBlockHead {
+	return &common.EvmBlockHead{Number: m.latestBlock, Hash: m.latestBlockHash}
+}
 func (m *mockEvmStatePoller) SyncingState() common.EvmSyncingState {
 	return common.EvmSyncingStateNotSyncing
 }
@@ -296,6 +341,7 @@ func (m *mockEvmStatePoller) IsBlockFinalized(blockNumber int64) (bool, error) {
 func (m *mockEvmStatePoller) SuggestFinalizedBlock(blockNumber int64)       {}
 func (m *mockEvmStatePoller) SuggestLatestBlock(blockNumber int64)          {}
 func (m *mockEvmStatePoller) SetNetworkConfig(cfg *common.EvmNetworkConfig) {}
+func (m *mockEvmStatePoller) Shutdown()                                     {}
 func (m *mockEvmStatePoller) IsObjectNull() bool                            { return m.isNull }
 
 func TestUpstream_EvmCanHandleBlock(t *testing.T) {
@@ -581,6 +627,13 @@ func (m *mockEvmStatePollerWithUpdate) PollLatestBlockNumber(ctx context.Context
 func (m *mockEvmStatePollerWithUpdate) PollFinalizedBlockNumber(ctx context.Context) (int64, error) {
 	return m.polledLatest - 10, nil
 }
+func (m *mockEvmStatePollerWithUpdate) PollSafeBlockNumber(ctx context.Context) (int64, error) {
+	return m.polledLatest - 5, nil
+}
+func (m *mockEvmStatePollerWithUpdate) SafeBlock() int64 { return m.LatestBlock() - 5 }
+func (m *mockEvmStatePollerWithUpdate) LatestBlockHead() *common.EvmBlockHead {
+	return &common.EvmBlockHead{Number: m.LatestBlock()}
+}
 func (m *mockEvmStatePollerWithUpdate) SyncingState() common.EvmSyncingState {
 	return common.EvmSyncingStateNotSyncing
 }
@@ -598,6 +651,7 @@ func (m *mockEvmStatePollerWithUpdate) IsBlockFinalized(blockNumber int64) (bool
 func (m *mockEvmStatePollerWithUpdate) SuggestFinalizedBlock(blockNumber int64)       {}
 func (m *mockEvmStatePollerWithUpdate) SuggestLatestBlock(blockNumber int64)          {}
 func (m *mockEvmStatePollerWithUpdate) SetNetworkConfig(cfg *common.EvmNetworkConfig) {}
+func (m *mockEvmStatePollerWithUpdate) Shutdown()                                     {}
 func (m *mockEvmStatePollerWithUpdate) IsObjectNull() bool                            { return false }
 
 func TestUpstream_EvmCanHandleBlock_Metrics(t *testing.T) {
@@ -759,3 +813,39 @@ func TestUpstream_EvmAssertBlockAvailability_Finalized(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestUpstream_DrainAndShutdown(t *testing.T) {
+	t.Run("WaitsForInflightRequests", func(t *testing.T) {
+		appCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		upsCtx, upsCancel := context.WithCancel(appCtx)
+		ups := &Upstream{appCtx: upsCtx, appCtxCancel: upsCancel, logger: &zerolog.Logger{}}
+		ups.inflight.Add(1)
+
+		done := make(chan struct{})
+		go func() {
+			ups.DrainAndShutdown(5 * time.Second)
+			close(done)
+		}()
+
+		time.Sleep(300 * time.Millisecond)
+		assert.NoError(t, upsCtx.Err(), "upstream context must stay alive while a request is in flight")
+
+		ups.inflight.Add(-1)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("upstream was not shut down once drained")
+		}
+		assert.Error(t, upsCtx.Err())
+	})
+
+	t.Run("ShutsDownAfterMaxWait", func(t *testing.T) {
+		upsCtx, upsCancel := context.WithCancel(context.Background())
+		ups := &Upstream{appCtx: upsCtx, appCtxCancel: upsCancel, logger: &zerolog.Logger{}}
+		ups.inflight.Add(1)
+
+		ups.DrainAndShutdown(200 * time.Millisecond)
+		assert.Error(t, upsCtx.Err())
+	})
+}
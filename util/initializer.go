@@ -406,6 +406,14 @@ func (i *Initializer) MarkTaskAsFailed(name string, err error) {
 	i.ensureAutoRetryIfEnabled()
 }
 
+// RemoveTask forgets a task so that executing a task with the same name later runs it again
+// (e.g. an upstream deregistered and then discovered again), and stops auto-retrying it if it had failed.
+func (i *Initializer) RemoveTask(name string) {
+	i.tasksMu.Lock()
+	defer i.tasksMu.Unlock()
+	i.tasks.Delete(name)
+}
+
 func (i *Initializer) Stop(destroyFn func() error) error {
 	i.logger.Debug().Msg("stopping initializer")
 
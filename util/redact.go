@@ -34,3 +34,10 @@ func RedactEndpoint(endpoint string) string {
 
 	return redactedEndpoint
 }
+
+// RedactValue replaces a sensitive value with a short hash (same format as RedactEndpoint), so that
+// equal values can still be correlated without exposing them.
+func RedactValue(value string) string {
+	hash := sha256.Sum256(S2Bytes(value))
+	return "redacted=" + hex.EncodeToString(hash[:])[:5]
+}
@@ -39,7 +39,7 @@ func ConfigureTestLogger() {
 }
 
 const (
-	EvmBlockTrackerMocks = 8
+	EvmBlockTrackerMocks = 10
 )
 
 func SetupMocksForEvmStatePoller() {
@@ -73,6 +73,15 @@ func SetupMocksForEvmStatePoller() {
 		}).
 		Reply(200).
 		JSON([]byte(`{"result":{"number":"0x11117777"},"_note":"evm state poller expected mock for finalized block"}`))
+	gock.New("http://rpc1.localhost").
+		Post("").
+		Persist().
+		Filter(func(request *http.Request) bool {
+			body := SafeReadBody(request)
+			return strings.Contains(body, "eth_getBlockByNumber") && strings.Contains(body, `"safe"`)
+		}).
+		Reply(200).
+		JSON([]byte(`{"result":{"number":"0x11117f77"},"_note":"evm state poller expected mock for safe block"}`))
 	gock.New("http://rpc1.localhost").
 		Post("").
 		Persist().
@@ -107,6 +116,15 @@ func SetupMocksForEvmStatePoller() {
 		}).
 		Reply(200).
 		JSON([]byte(`{"result":{"number":"0x22227777"},"_note":"evm state poller expected mock for finalized block"}`))
+	gock.New("http://rpc2.localhost").
+		Post("").
+		Persist().
+		Filter(func(request *http.Request) bool {
+			body := SafeReadBody(request)
+			return strings.Contains(body, "eth_getBlockByNumber") && strings.Contains(body, `"safe"`)
+		}).
+		Reply(200).
+		JSON([]byte(`{"result":{"number":"0x22227f77"},"_note":"evm state poller expected mock for safe block"}`))
 	gock.New("http://rpc2.localhost").
 		Post("").
 		Persist().